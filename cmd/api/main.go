@@ -2,25 +2,33 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
-	"time"
 
 	"github.com/Pesokrava/product_reviewer/internal/config"
 	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
 	httpDelivery "github.com/Pesokrava/product_reviewer/internal/delivery/http"
 	"github.com/Pesokrava/product_reviewer/internal/delivery/http/handler"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/cache"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/idgen"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/sanitize"
 	cacheRepo "github.com/Pesokrava/product_reviewer/internal/repository/cache"
 	"github.com/Pesokrava/product_reviewer/internal/repository/postgres"
 	"github.com/Pesokrava/product_reviewer/internal/usecase/product"
 	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+	"github.com/Pesokrava/product_reviewer/internal/worker"
 
 	_ "github.com/Pesokrava/product_reviewer/docs"
 )
@@ -47,17 +55,40 @@ import (
 // @tag.name Reviews
 // @tag.description Review management endpoints
 
+// @tag.name Admin
+// @tag.description Operator endpoints guarded by the admin API key
+
+// @securityDefinitions.apikey AdminKey
+// @in header
+// @name X-Admin-Key
+
 func main() {
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config:\n%v", err)
+	}
 
 	appLogger := logger.New(cfg.Env)
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		appLogger.Fatal("Invalid LOG_LEVEL", err)
+	}
 	appLogger.Info("Starting Product Reviews API...")
+	appLogger.WithFields(cfg.LogFields()).Info("Effective configuration")
+	appLogger.Infof("Review text HTML sanitization: %s", cfg.Review.SanitizeText)
+
+	domain.SetMaxRating(cfg.Review.MaxRating)
+	domain.SetHalfStarRatings(cfg.Review.HalfStarRatings)
+	domain.SetAllowedRatings(cfg.Review.AllowedRatings)
+	appLogger.Infof("Rating scale: 1-%d", cfg.Review.MaxRating)
+	if len(cfg.Review.AllowedRatings) > 0 {
+		appLogger.Infof("Only these ratings are accepted: %v", domain.AllowedRatings())
+	}
 
 	appLogger.Info("Connecting to PostgreSQL...")
-	db, err := database.WaitForDB(cfg, 10, 2*time.Second)
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to database", err)
 	}
@@ -69,7 +100,7 @@ func main() {
 	appLogger.Info("Connected to PostgreSQL successfully")
 
 	appLogger.Info("Connecting to Redis...")
-	redisClient, err := cache.WaitForRedis(cfg, 10, 2*time.Second)
+	redisClient, err := cache.WaitForRedis(cfg, cfg.Redis.ConnectRetry)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to Redis", err)
 	}
@@ -80,28 +111,131 @@ func main() {
 	}()
 	appLogger.Info("Connected to Redis successfully")
 
-	appLogger.Info("Connecting to NATS...")
-	publisher, err := events.NewPublisher(cfg, appLogger)
+	healthChecks := map[string]handler.HealthCheck{
+		"postgres": db.PingContext,
+		"redis": func(ctx context.Context) error {
+			return redisClient.Ping(ctx).Err()
+		},
+	}
+
+	appLogger.Infof("Event transport: %s", cfg.Events.Transport)
+	// Events that can't reach NATS wait in Redis for replay instead of being dropped.
+	// Never with the outbox: the relay must see failed publishes so events stay in the table.
+	var eventBuffer events.EventBuffer
+	if cfg.Events.BufferMaxSize > 0 && !cfg.Events.Outbox {
+		eventBuffer = events.NewRedisBuffer(redisClient, cfg.Events.BufferMaxSize)
+	}
+	publisher, err := events.NewPublisherFromConfig(cfg, db, eventBuffer, appLogger)
 	if err != nil {
-		appLogger.Fatal("Failed to create NATS publisher", err)
+		appLogger.Fatal("Failed to create event publisher", err)
 	}
 	defer publisher.Close()
 
-	productRepo := postgres.NewProductRepository(db)
-	reviewRepo := postgres.NewReviewRepository(db)
+	// Stream statistics and the NATS health check exist only on JetStream
+	var streams handler.StreamInspector
+	if natsPublisher, ok := publisher.(*events.Publisher); ok {
+		streams = events.NewStreamConfig(natsPublisher.JetStream(), cfg.NATS.AckWait, cfg.NATS.SubjectPrefix, appLogger)
+		healthChecks["nats"] = func(ctx context.Context) error {
+			if !natsPublisher.IsConnected() {
+				return errors.New("not connected")
+			}
+			return nil
+		}
+	}
+
+	slowQueries := postgres.NewSlowQueryLogger(cfg.Database.SlowQueryThreshold, appLogger)
+	// DB_ID_STRATEGY=v7 issues time-ordered IDs for better index locality
+	newID := idgen.New(cfg.Database.IDStrategy)
+	productRepo := postgres.NewProductRepository(db, slowQueries, newID)
+	reviewRepo := postgres.NewReviewRepository(db, slowQueries, newID)
+	auditRepo := postgres.NewAuditRepository(db, slowQueries)
+	transactor := postgres.NewTransactor(db, cfg.Database.SerializationRetries)
+
+	cacheCodec, err := cacheRepo.NewCodec(cfg.Cache.Codec)
+	if err != nil {
+		appLogger.Fatal("Invalid CACHE_CODEC", err)
+	}
 	redisCache := cacheRepo.NewRedisCache(
 		redisClient,
 		cfg.Cache.ProductRatingTTL,
 		cfg.Cache.ReviewsListTTL,
+		cfg.Cache.RecentReviewsTTL,
+		cfg.Cache.TTLJitter,
+		cfg.Cache.BreakerThreshold,
+		cfg.Cache.BreakerCooldown,
+		cacheCodec,
+		cfg.Cache.CompressThreshold,
+		appLogger,
 	)
 
-	productService := product.NewService(productRepo, reviewRepo, appLogger)
-	reviewService := review.NewService(reviewRepo, redisCache, publisher, appLogger)
+	// With EVENT_OUTBOX, review events commit with the change they describe and a relay
+	// publishes them from the table: this one, unless cmd/outbox-relay is deployed instead
+	var outbox domain.OutboxRepository
+	var outboxInspector handler.OutboxInspector
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	if cfg.Events.Outbox {
+		outboxRepo := postgres.NewOutboxRepository(db, slowQueries)
+		outbox, outboxInspector = outboxRepo, outboxRepo
+	}
+	if cfg.Events.Outbox && cfg.Events.OutboxEmbeddedRelay {
+		relay := worker.NewOutboxRelay(outbox, publisher, cfg.Events.OutboxBatchSize, cfg.Events.OutboxRetention, clock.New(), appLogger)
+		go func() {
+			defer close(relayDone)
+			relay.Run(relayCtx, cfg.Events.OutboxPollInterval)
+		}()
+	} else {
+		close(relayDone)
+	}
 
-	productHandler := handler.NewProductHandler(productService, appLogger)
-	reviewHandler := handler.NewReviewHandler(reviewService, appLogger)
+	productService := product.NewService(productRepo, reviewRepo, transactor, auditRepo, redisCache, cfg.Product.EnforceUniqueName, appLogger)
+	reviewService := review.NewService(
+		reviewRepo,
+		redisCache,
+		publisher,
+		transactor,
+		auditRepo,
+		review.Options{
+			Products:                     productRepo,
+			Outbox:                       outbox,
+			SanitizeText:                 cfg.Review.SanitizeText == sanitize.ModeStore,
+			DetectLanguage:               cfg.Review.DetectLanguage,
+			Throttle:                     review.Throttle{Limit: cfg.Review.ThrottleLimit, Window: cfg.Review.ThrottleWindow},
+			FlagThreshold:                cfg.Review.FlagThreshold,
+			DefaultSort:                  cfg.Review.DefaultSort,
+			PublishTimeout:               cfg.Events.PublishTimeout,
+			PublishWithinRequestDeadline: cfg.Events.PublishDeadline == config.PublishDeadlineRequest,
+		},
+		appLogger,
+	)
 
-	router := httpDelivery.NewRouter(productHandler, reviewHandler, cfg, appLogger)
+	productHandler := handler.NewProductHandler(productService, request.PaginationConfig(cfg.Product.Pagination), cfg.Product.CompareMaxIDs, cfg.Product.MinReviewsForRating, appLogger)
+	reviewHandler := handler.NewReviewHandler(
+		reviewService,
+		cfg.Review.DefaultSource,
+		request.PaginationConfig(cfg.Review.Pagination),
+		appLogger,
+	)
+	detailHandler := handler.NewProductDetailHandler(productService, reviewService, cfg.Product.MinReviewsForRating, appLogger)
+	adminHandler := handler.NewAdminHandler(streams, redisCache, auditRepo, worker.NewCalculator(db, cfg.Review.RatingRoundingMode, cfg.Database.SerializationRetries, appLogger), appLogger)
+
+	healthHandler := handler.NewHealthHandler(
+		healthChecks,
+		streams,
+		cfg.NATS.LagDegradedThreshold,
+		outboxInspector,
+		cfg.Events.OutboxLagDegradedThreshold,
+		appLogger,
+	)
+
+	// Served on /metrics alongside /readyz, so on ADMIN_PORT when one is set
+	reg := metrics.NewRegistry()
+	metrics.RegisterRuntime(reg)
+	if outboxInspector != nil {
+		worker.RegisterOutboxMetrics(reg, outboxInspector, clock.New(), appLogger)
+	}
+
+	router := httpDelivery.NewRouter(productHandler, reviewHandler, detailHandler, adminHandler, healthHandler, reg, cfg, appLogger)
 	httpHandler := router.Setup()
 
 	server := &http.Server{
@@ -118,6 +252,31 @@ func main() {
 		}
 	}()
 
+	// Optional admin listener keeps readiness, pprof and admin routes off the public port
+	var adminServer *http.Server
+	if cfg.Server.AdminPort != "" {
+		adminServer = &http.Server{
+			Addr:        fmt.Sprintf(":%s", cfg.Server.AdminPort),
+			Handler:     router.SetupAdmin(),
+			ReadTimeout: cfg.Server.ReadTimeout,
+			// No WriteTimeout: pprof CPU profiles and traces stream for longer than API requests
+		}
+
+		go func() {
+			appLogger.Infof("Admin HTTP server listening on port %s", cfg.Server.AdminPort)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("Admin HTTP server failed", err)
+			}
+		}()
+	}
+
+	// SIGHUP re-reads CONFIG_FILE and applies the settings that are safe to change live
+	stopReload := config.WatchReload(cfg, appLogger, func(next *config.Config) {
+		reviewService.SetThrottle(review.Throttle{Limit: next.Review.ThrottleLimit, Window: next.Review.ThrottleWindow})
+		redisCache.SetTTLs(next.Cache.ProductRatingTTL, next.Cache.ReviewsListTTL, next.Cache.RecentReviewsTTL)
+	})
+	defer stopReload()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
@@ -127,9 +286,38 @@ func main() {
 	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
 	defer cancel()
 
+	// Shut down both listeners concurrently so they share the shutdown budget
+	var wg sync.WaitGroup
+	if adminServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := adminServer.Shutdown(ctx); err != nil {
+				appLogger.Error("Admin server forced to shutdown", err)
+			}
+		}()
+	}
+
 	if err := server.Shutdown(ctx); err != nil {
 		appLogger.Fatal("Server forced to shutdown", err)
 	}
+	wg.Wait()
+
+	// Requests have finished, but their background publishes may not have.
+	// Drain them before the deferred publisher.Close() runs.
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.NATS.PublishDrainTimeout)
+	defer drainCancel()
+	if err := reviewService.Shutdown(drainCtx); err != nil {
+		appLogger.Error("Event publishes did not drain, some events may be lost", err)
+	}
+
+	// Stop the relay before the publisher goes; unsent events wait in the outbox
+	stopRelay()
+	select {
+	case <-relayDone:
+	case <-drainCtx.Done():
+		appLogger.Warn("Timed out waiting for the outbox relay to stop")
+	}
 
 	appLogger.Info("Server stopped gracefully")
 }
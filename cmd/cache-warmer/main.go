@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/cache"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	cacheRepo "github.com/Pesokrava/product_reviewer/internal/repository/cache"
+	"github.com/Pesokrava/product_reviewer/internal/repository/postgres"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+	"github.com/Pesokrava/product_reviewer/internal/warmer"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config:\n%v", err)
+	}
+
+	appLogger := logger.New(cfg.Env)
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		appLogger.Fatal("Invalid LOG_LEVEL", err)
+	}
+	appLogger.Info("Starting cache warmer...")
+	appLogger.WithFields(cfg.LogFields()).Info("Effective configuration")
+
+	// The review overview's rating distribution has one entry per point on the scale
+	domain.SetMaxRating(cfg.Review.MaxRating)
+	domain.SetHalfStarRatings(cfg.Review.HalfStarRatings)
+	domain.SetAllowedRatings(cfg.Review.AllowedRatings)
+
+	appLogger.Info("Connecting to PostgreSQL...")
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
+	if err != nil {
+		appLogger.Fatal("Failed to connect to database", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			appLogger.Error("Failed to close database connection", err)
+		}
+	}()
+
+	appLogger.Info("Connected to database")
+
+	// Unlike the rating worker, the warmer has nothing to do without Redis
+	appLogger.Info("Connecting to Redis...")
+	redisClient, err := cache.WaitForRedis(cfg, cfg.Redis.ConnectRetry)
+	if err != nil {
+		appLogger.Fatal("Failed to connect to Redis", err)
+	}
+	defer func() {
+		if err := redisClient.Close(); err != nil {
+			appLogger.Error("Failed to close Redis connection", err)
+		}
+	}()
+
+	appLogger.Info("Connected to Redis")
+
+	slowQueries := postgres.NewSlowQueryLogger(cfg.Database.SlowQueryThreshold, appLogger)
+	productRepo := postgres.NewProductRepository(db, slowQueries, nil)
+	reviewRepo := postgres.NewReviewRepository(db, slowQueries, nil)
+
+	cacheCodec, err := cacheRepo.NewCodec(cfg.Cache.Codec)
+	if err != nil {
+		appLogger.Fatal("Invalid CACHE_CODEC", err)
+	}
+	redisCache := cacheRepo.NewRedisCache(
+		redisClient,
+		cfg.Cache.ProductRatingTTL,
+		cfg.Cache.ReviewsListTTL,
+		cfg.Cache.RecentReviewsTTL,
+		cfg.Cache.TTLJitter,
+		cfg.Cache.BreakerThreshold,
+		cfg.Cache.BreakerCooldown,
+		cacheCodec,
+		cfg.Cache.CompressThreshold,
+		appLogger,
+	)
+
+	// Warming goes through the review service's read path so cached entries have exactly
+	// the keys and shape the API reads. Only reads are used, hence no publisher,
+	// transactor or audit log.
+	reviewService := review.NewService(
+		reviewRepo,
+		redisCache,
+		nil,
+		nil,
+		nil,
+		review.Options{
+			Products:    productRepo,
+			DefaultSort: cfg.Review.DefaultSort,
+		},
+		appLogger,
+	)
+
+	cacheWarmer := warmer.NewWarmer(
+		reviewService,
+		productRepo,
+		redisCache,
+		warmer.NewReviewCountRanker(db),
+		cfg.Warmer.Delay,
+		cfg.Warmer.TopN,
+		cfg.Review.Pagination.DefaultLimit,
+		clock.New(),
+		appLogger,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Warm the popular products up front so a fresh Redis doesn't wait for review traffic
+	if err := cacheWarmer.RefreshHot(ctx); err != nil {
+		appLogger.Error("Failed to load popular products", err)
+	}
+	go cacheWarmer.WarmHot(ctx)
+
+	if cfg.Warmer.TopN > 0 {
+		go func() {
+			ticker := time.NewTicker(cfg.Warmer.RefreshInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if err := cacheWarmer.RefreshHot(ctx); err != nil {
+						appLogger.Error("Failed to refresh popular products", err)
+					}
+				}
+			}
+		}()
+	}
+
+	consumer, err := events.NewConsumerFromConfig(cfg, appLogger)
+	if err != nil {
+		appLogger.Fatal("Failed to create event consumer", err)
+	}
+	defer consumer.Close()
+
+	if err := consumer.Subscribe(review.EventSubject, cacheWarmer.HandleEvent); err != nil {
+		appLogger.Fatal("Failed to subscribe to reviews.events", err)
+	}
+
+	appLogger.WithFields(map[string]any{
+		"delay": cfg.Warmer.Delay.String(),
+		"top_n": cfg.Warmer.TopN,
+	}).Info("Cache warmer started and listening for events...")
+
+	// SIGHUP re-reads CONFIG_FILE and applies the settings that are safe to change live
+	stopReload := config.WatchReload(cfg, appLogger, func(next *config.Config) {
+		redisCache.SetTTLs(next.Cache.ProductRatingTTL, next.Cache.ReviewsListTTL, next.Cache.RecentReviewsTTL)
+	})
+	defer stopReload()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	<-sigCh
+	appLogger.Info("Received shutdown signal")
+	cancel()
+
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), cfg.Worker.ShutdownTimeout)
+	defer shutdownCancel()
+
+	if err := cacheWarmer.Shutdown(shutdownCtx); err != nil {
+		appLogger.Error("Error during shutdown", err)
+	}
+
+	appLogger.Info("Cache warmer stopped")
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	httpDelivery "github.com/Pesokrava/product_reviewer/internal/delivery/http"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/handler"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/cache"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/idgen"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/sanitize"
+	cacheRepo "github.com/Pesokrava/product_reviewer/internal/repository/cache"
+	"github.com/Pesokrava/product_reviewer/internal/repository/postgres"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/product"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+	"github.com/Pesokrava/product_reviewer/internal/worker"
+
+	_ "github.com/Pesokrava/product_reviewer/docs"
+)
+
+// The monolith runs the API and the rating worker in one process, connected by an
+// in-memory event bus instead of NATS. It needs only Postgres and Redis, and ignores
+// EVENT_TRANSPORT. Events still queued when the process dies are lost; ratings catch up
+// on the product's next review.
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config:\n%v", err)
+	}
+
+	appLogger := logger.New(cfg.Env)
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		appLogger.Fatal("Invalid LOG_LEVEL", err)
+	}
+	appLogger.Info("Starting Product Reviews monolith (API + rating worker)...")
+	appLogger.WithFields(cfg.LogFields()).Info("Effective configuration")
+	appLogger.Infof("Review text HTML sanitization: %s", cfg.Review.SanitizeText)
+
+	domain.SetMaxRating(cfg.Review.MaxRating)
+	domain.SetHalfStarRatings(cfg.Review.HalfStarRatings)
+	domain.SetAllowedRatings(cfg.Review.AllowedRatings)
+	appLogger.Infof("Rating scale: 1-%d", cfg.Review.MaxRating)
+	if len(cfg.Review.AllowedRatings) > 0 {
+		appLogger.Infof("Only these ratings are accepted: %v", domain.AllowedRatings())
+	}
+
+	appLogger.Info("Connecting to PostgreSQL...")
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
+	if err != nil {
+		appLogger.Fatal("Failed to connect to database", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			appLogger.Error("Failed to close database connection", err)
+		}
+	}()
+	appLogger.Info("Connected to PostgreSQL successfully")
+
+	appLogger.Info("Connecting to Redis...")
+	redisClient, err := cache.WaitForRedis(cfg, cfg.Redis.ConnectRetry)
+	if err != nil {
+		appLogger.Fatal("Failed to connect to Redis", err)
+	}
+	defer func() {
+		if err := redisClient.Close(); err != nil {
+			appLogger.Error("Failed to close Redis connection", err)
+		}
+	}()
+	appLogger.Info("Connected to Redis successfully")
+
+	slowQueries := postgres.NewSlowQueryLogger(cfg.Database.SlowQueryThreshold, appLogger)
+	// DB_ID_STRATEGY=v7 issues time-ordered IDs for better index locality
+	newID := idgen.New(cfg.Database.IDStrategy)
+	productRepo := postgres.NewProductRepository(db, slowQueries, newID)
+	reviewRepo := postgres.NewReviewRepository(db, slowQueries, newID)
+	auditRepo := postgres.NewAuditRepository(db, slowQueries)
+	transactor := postgres.NewTransactor(db, cfg.Database.SerializationRetries)
+
+	cacheCodec, err := cacheRepo.NewCodec(cfg.Cache.Codec)
+	if err != nil {
+		appLogger.Fatal("Invalid CACHE_CODEC", err)
+	}
+	redisCache := cacheRepo.NewRedisCache(
+		redisClient,
+		cfg.Cache.ProductRatingTTL,
+		cfg.Cache.ReviewsListTTL,
+		cfg.Cache.RecentReviewsTTL,
+		cfg.Cache.TTLJitter,
+		cfg.Cache.BreakerThreshold,
+		cfg.Cache.BreakerCooldown,
+		cacheCodec,
+		cfg.Cache.CompressThreshold,
+		appLogger,
+	)
+
+	// Rating worker, fed by the bus instead of a JetStream consumer
+	bus := events.NewInMemoryBus(appLogger)
+	// Shared with the admin reconcile endpoint
+	calculator := worker.NewCalculator(db, cfg.Review.RatingRoundingMode, cfg.Database.SerializationRetries, appLogger)
+	ratingWorker := worker.NewRatingWorker(calculator, redisCache, cfg.Worker.WarmRatingCache, clock.New(), appLogger)
+	ratingWorker.SetDebounceWindow(cfg.Worker.DebounceWindow)
+	if err := bus.Subscribe(review.EventSubject, ratingWorker.HandleEvent); err != nil {
+		appLogger.Fatal("Failed to subscribe the rating worker to review events", err)
+	}
+
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+	if cfg.Worker.RetentionPeriod > 0 {
+		purger := worker.NewPurger(db, cfg.Worker.RetentionPeriod, cfg.Worker.PurgeBatchSize, clock.New(), appLogger)
+		go func() {
+			defer close(purgeDone)
+			purger.Run(purgeCtx, cfg.Worker.PurgeInterval)
+		}()
+	} else {
+		close(purgeDone)
+	}
+
+	// With EVENT_OUTBOX, review events commit with the change they describe and the relay
+	// publishes them from the table. Relaying always happens here: an external relay can't
+	// reach the in-process bus.
+	var outbox domain.OutboxRepository
+	var outboxInspector handler.OutboxInspector
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	if cfg.Events.Outbox {
+		outboxRepo := postgres.NewOutboxRepository(db, slowQueries)
+		outbox, outboxInspector = outboxRepo, outboxRepo
+		relay := worker.NewOutboxRelay(outboxRepo, bus, cfg.Events.OutboxBatchSize, cfg.Events.OutboxRetention, clock.New(), appLogger)
+		go func() {
+			defer close(relayDone)
+			relay.Run(relayCtx, cfg.Events.OutboxPollInterval)
+		}()
+	} else {
+		close(relayDone)
+	}
+
+	productService := product.NewService(productRepo, reviewRepo, transactor, auditRepo, redisCache, cfg.Product.EnforceUniqueName, appLogger)
+	reviewService := review.NewService(
+		reviewRepo,
+		redisCache,
+		bus,
+		transactor,
+		auditRepo,
+		review.Options{
+			Products:                     productRepo,
+			Outbox:                       outbox,
+			SanitizeText:                 cfg.Review.SanitizeText == sanitize.ModeStore,
+			DetectLanguage:               cfg.Review.DetectLanguage,
+			Throttle:                     review.Throttle{Limit: cfg.Review.ThrottleLimit, Window: cfg.Review.ThrottleWindow},
+			FlagThreshold:                cfg.Review.FlagThreshold,
+			DefaultSort:                  cfg.Review.DefaultSort,
+			PublishTimeout:               cfg.Events.PublishTimeout,
+			PublishWithinRequestDeadline: cfg.Events.PublishDeadline == config.PublishDeadlineRequest,
+		},
+		appLogger,
+	)
+
+	productHandler := handler.NewProductHandler(productService, request.PaginationConfig(cfg.Product.Pagination), cfg.Product.CompareMaxIDs, cfg.Product.MinReviewsForRating, appLogger)
+	reviewHandler := handler.NewReviewHandler(
+		reviewService,
+		cfg.Review.DefaultSource,
+		request.PaginationConfig(cfg.Review.Pagination),
+		appLogger,
+	)
+	detailHandler := handler.NewProductDetailHandler(productService, reviewService, cfg.Product.MinReviewsForRating, appLogger)
+	// No JetStream, so no stream statistics or event lag to report
+	adminHandler := handler.NewAdminHandler(nil, redisCache, auditRepo, calculator, appLogger)
+	healthHandler := handler.NewHealthHandler(
+		map[string]handler.HealthCheck{
+			"postgres": db.PingContext,
+			"redis": func(ctx context.Context) error {
+				return redisClient.Ping(ctx).Err()
+			},
+		},
+		nil,
+		cfg.NATS.LagDegradedThreshold,
+		outboxInspector,
+		cfg.Events.OutboxLagDegradedThreshold,
+		appLogger,
+	)
+
+	// Served on /metrics alongside /readyz, and on WORKER_METRICS_PORT when one is set
+	reg := metrics.NewRegistry()
+	metrics.RegisterRuntime(reg)
+	ratingWorker.RegisterMetrics(reg)
+	if outboxInspector != nil {
+		worker.RegisterOutboxMetrics(reg, outboxInspector, clock.New(), appLogger)
+	}
+
+	router := httpDelivery.NewRouter(productHandler, reviewHandler, detailHandler, adminHandler, healthHandler, reg, cfg, appLogger)
+
+	server := &http.Server{
+		Addr:         fmt.Sprintf(":%s", cfg.Server.Port),
+		Handler:      router.Setup(),
+		ReadTimeout:  cfg.Server.ReadTimeout,
+		WriteTimeout: cfg.Server.WriteTimeout,
+	}
+
+	go func() {
+		appLogger.Infof("HTTP server listening on port %s", cfg.Server.Port)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			appLogger.Fatal("HTTP server failed", err)
+		}
+	}()
+
+	var adminServer *http.Server
+	if cfg.Server.AdminPort != "" {
+		adminServer = &http.Server{
+			Addr:        fmt.Sprintf(":%s", cfg.Server.AdminPort),
+			Handler:     router.SetupAdmin(),
+			ReadTimeout: cfg.Server.ReadTimeout,
+		}
+
+		go func() {
+			appLogger.Infof("Admin HTTP server listening on port %s", cfg.Server.AdminPort)
+			if err := adminServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("Admin HTTP server failed", err)
+			}
+		}()
+	}
+
+	var metricsServer *http.Server
+	if cfg.Worker.MetricsPort != "" {
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", reg)
+		metricsServer = &http.Server{
+			Addr:        fmt.Sprintf(":%s", cfg.Worker.MetricsPort),
+			Handler:     mux,
+			ReadTimeout: cfg.Server.ReadTimeout,
+		}
+
+		go func() {
+			appLogger.Infof("Metrics server listening on port %s", cfg.Worker.MetricsPort)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("Metrics server failed", err)
+			}
+		}()
+	}
+
+	// SIGHUP re-reads CONFIG_FILE and applies the settings that are safe to change live
+	stopReload := config.WatchReload(cfg, appLogger, func(next *config.Config) {
+		reviewService.SetThrottle(review.Throttle{Limit: next.Review.ThrottleLimit, Window: next.Review.ThrottleWindow})
+		redisCache.SetTTLs(next.Cache.ProductRatingTTL, next.Cache.ReviewsListTTL, next.Cache.RecentReviewsTTL)
+		ratingWorker.SetDebounceWindow(next.Worker.DebounceWindow)
+	})
+	defer stopReload()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	appLogger.Info("Shutting down monolith...")
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	if adminServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := adminServer.Shutdown(ctx); err != nil {
+				appLogger.Error("Admin server forced to shutdown", err)
+			}
+		}()
+	}
+
+	if metricsServer != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := metricsServer.Shutdown(ctx); err != nil {
+				appLogger.Error("Metrics server forced to shutdown", err)
+			}
+		}()
+	}
+
+	if err := server.Shutdown(ctx); err != nil {
+		appLogger.Error("Server forced to shutdown", err)
+	}
+	wg.Wait()
+
+	// Stop in pipeline order so no event is dropped between stages: background publishes
+	// onto the bus, then the bus into the worker. As in the standalone worker, debounced
+	// recalculations that haven't started are cancelled and in-flight ones finish.
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), cfg.NATS.PublishDrainTimeout)
+	defer drainCancel()
+	if err := reviewService.Shutdown(drainCtx); err != nil {
+		appLogger.Error("Event publishes did not drain, some events may be lost", err)
+	}
+
+	// Stop the relay before the publisher goes; unsent events wait in the outbox
+	stopRelay()
+	select {
+	case <-relayDone:
+	case <-drainCtx.Done():
+		appLogger.Warn("Timed out waiting for the outbox relay to stop")
+	}
+	bus.Close()
+
+	workerCtx, workerCancel := context.WithTimeout(context.Background(), cfg.Worker.ShutdownTimeout)
+	defer workerCancel()
+	if err := ratingWorker.Shutdown(workerCtx); err != nil {
+		appLogger.Error("Rating worker did not finish pending recalculations", err)
+	}
+
+	stopPurge()
+	select {
+	case <-purgeDone:
+	case <-workerCtx.Done():
+		appLogger.Warn("Timed out waiting for the purge to stop")
+	}
+
+	appLogger.Info("Monolith stopped gracefully")
+}
@@ -8,7 +8,9 @@ import (
 
 	"github.com/Pesokrava/product_reviewer/internal/config"
 	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/httpclient"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
 )
 
 func main() {
@@ -16,22 +18,40 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config:\n%v", err)
+	}
 
 	appLogger := logger.New(cfg.Env)
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		appLogger.Fatal("Invalid LOG_LEVEL", err)
+	}
 	appLogger.Info("Starting notifier service...")
+	appLogger.WithFields(cfg.LogFields()).Info("Effective configuration")
 
-	consumer, err := events.NewConsumer(cfg, appLogger)
+	consumer, err := events.NewConsumerFromConfig(cfg, appLogger)
 	if err != nil {
-		appLogger.Fatal("Failed to create NATS consumer", err)
+		appLogger.Fatal("Failed to create event consumer", err)
 	}
 	defer consumer.Close()
 
-	if err := consumer.Subscribe("reviews.events", events.LoggingHandler(appLogger)); err != nil {
+	handler := events.LoggingHandler(appLogger)
+	if cfg.Notifier.WebhookURL != "" {
+		client := httpclient.New(cfg.Notifier.HTTPClient, appLogger)
+		handler = events.MultiHandler(handler, events.WebhookHandler(client, cfg.Notifier.WebhookURL))
+		appLogger.Info("Webhook delivery enabled")
+	}
+
+	if err := consumer.Subscribe(review.EventSubject, handler); err != nil {
 		appLogger.Fatal("Failed to subscribe to reviews.events", err)
 	}
 
 	appLogger.Info("Notifier service started and listening for events...")
 
+	// SIGHUP re-reads CONFIG_FILE; the notifier only has LOG_LEVEL to apply
+	stopReload := config.WatchReload(cfg, appLogger, nil)
+	defer stopReload()
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
 	<-quit
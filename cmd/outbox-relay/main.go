@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/repository/postgres"
+	"github.com/Pesokrava/product_reviewer/internal/worker"
+	_ "github.com/lib/pq"
+)
+
+func main() {
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config:\n%v", err)
+	}
+
+	appLogger := logger.New(cfg.Env)
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		appLogger.Fatal("Invalid LOG_LEVEL", err)
+	}
+
+	appLogger.Info("Starting outbox relay...")
+	appLogger.WithFields(cfg.LogFields()).Info("Effective configuration")
+
+	if !cfg.Events.Outbox {
+		appLogger.Fatal("Outbox relay needs EVENT_OUTBOX=true", fmt.Errorf("the API publishes events itself, so the outbox stays empty"))
+	}
+	// noop would mark events sent while dropping them, and inmemory events never leave this process
+	switch cfg.Events.Transport {
+	case config.EventTransportNATS, config.EventTransportPostgres:
+	default:
+		appLogger.Fatal("Outbox relay needs EVENT_TRANSPORT=nats or postgres", fmt.Errorf("%s delivers no events to other services", cfg.Events.Transport))
+	}
+
+	appLogger.Info("Connecting to PostgreSQL...")
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
+	if err != nil {
+		appLogger.Fatal("Failed to connect to database", err)
+	}
+	defer func() {
+		if err := db.Close(); err != nil {
+			appLogger.Error("Failed to close database connection", err)
+		}
+	}()
+
+	appLogger.Info("Connected to database")
+
+	// No Redis buffer: events the relay can't publish simply stay in the outbox
+	publisher, err := events.NewPublisherFromConfig(cfg, db, nil, appLogger)
+	if err != nil {
+		appLogger.Fatal("Failed to create event publisher", err)
+	}
+	defer publisher.Close()
+
+	slowQueries := postgres.NewSlowQueryLogger(cfg.Database.SlowQueryThreshold, appLogger)
+	relay := worker.NewOutboxRelay(
+		postgres.NewOutboxRepository(db, slowQueries),
+		publisher,
+		cfg.Events.OutboxBatchSize,
+		cfg.Events.OutboxRetention,
+		clock.New(),
+		appLogger,
+	)
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		relay.Run(relayCtx, cfg.Events.OutboxPollInterval)
+	}()
+
+	appLogger.WithFields(map[string]any{
+		"transport":     cfg.Events.Transport,
+		"poll_interval": cfg.Events.OutboxPollInterval.String(),
+		"batch_size":    cfg.Events.OutboxBatchSize,
+	}).Info("Outbox relay started")
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	<-sigCh
+	appLogger.Info("Received shutdown signal")
+
+	// Cancelling rolls back the batch in flight; its events are published again by the next relay
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.NATS.PublishDrainTimeout)
+	defer cancel()
+
+	stopRelay()
+	select {
+	case <-relayDone:
+	case <-shutdownCtx.Done():
+		appLogger.Warn("Timed out waiting for the outbox relay to stop")
+	}
+
+	appLogger.Info("Outbox relay stopped")
+}
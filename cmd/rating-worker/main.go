@@ -3,35 +3,56 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/cache"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
+	cacheRepo "github.com/Pesokrava/product_reviewer/internal/repository/cache"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
 	"github.com/Pesokrava/product_reviewer/internal/worker"
 	_ "github.com/lib/pq"
 	"github.com/nats-io/nats.go"
 )
 
+const (
+	// Fetch error backoff doubles up to the cap so a NATS outage doesn't flood the logs
+	initialFetchBackoff = 1 * time.Second
+	maxFetchBackoff     = 30 * time.Second
+)
+
 func main() {
 	// Load configuration
 	cfg, err := config.Load()
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("Invalid config:\n%v", err)
+	}
 
 	// Initialize logger
 	appLogger := logger.New(cfg.Env)
+	if err := logger.SetLevel(cfg.LogLevel); err != nil {
+		appLogger.Fatal("Invalid LOG_LEVEL", err)
+	}
 
 	appLogger.Info("Starting rating worker...")
+	appLogger.WithFields(cfg.LogFields()).Info("Effective configuration")
 
 	// Connect to database
 	appLogger.Info("Connecting to PostgreSQL...")
-	db, err := database.WaitForDB(cfg, 10, 2*time.Second)
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to database", err)
 	}
@@ -43,19 +64,187 @@ func main() {
 
 	appLogger.Info("Connected to database")
 
+	// Connect to Redis so recalculated ratings invalidate the API cache
+	// Non-fatal: without Redis the worker still updates ratings, cache refreshes on TTL expiry
+	var productCache worker.ProductCache
+	var redisCache *cacheRepo.RedisCache
+	appLogger.Info("Connecting to Redis...")
+	redisClient, err := cache.WaitForRedis(cfg, cfg.Redis.ConnectRetry)
+	if err != nil {
+		appLogger.WithFields(map[string]any{
+			"error": err.Error(),
+		}).Warn("Redis unavailable, running without cache invalidation")
+	} else {
+		defer func() {
+			if err := redisClient.Close(); err != nil {
+				appLogger.Error("Failed to close Redis connection", err)
+			}
+		}()
+
+		appLogger.Info("Connected to Redis")
+
+		cacheCodec, err := cacheRepo.NewCodec(cfg.Cache.Codec)
+		if err != nil {
+			appLogger.Fatal("Invalid CACHE_CODEC", err)
+		}
+		redisCache = cacheRepo.NewRedisCache(
+			redisClient,
+			cfg.Cache.ProductRatingTTL,
+			cfg.Cache.ReviewsListTTL,
+			cfg.Cache.RecentReviewsTTL,
+			cfg.Cache.TTLJitter,
+			cfg.Cache.BreakerThreshold,
+			cfg.Cache.BreakerCooldown,
+			cacheCodec,
+			cfg.Cache.CompressThreshold,
+			appLogger,
+		)
+		productCache = redisCache
+	}
+
 	// Create rating calculator
-	calculator := worker.NewCalculator(db, appLogger)
+	calculator := worker.NewCalculator(db, cfg.Review.RatingRoundingMode, cfg.Database.SerializationRetries, appLogger)
 
 	// Create rating worker
-	ratingWorker := worker.NewRatingWorker(calculator, appLogger)
+	ratingWorker := worker.NewRatingWorker(calculator, productCache, cfg.Worker.WarmRatingCache, clock.New(), appLogger)
+	ratingWorker.SetDebounceWindow(cfg.Worker.DebounceWindow)
+
+	// Expose the debounce counters for Prometheus; off unless WORKER_METRICS_PORT is set
+	var metricsServer *http.Server
+	if cfg.Worker.MetricsPort != "" {
+		reg := metrics.NewRegistry()
+		ratingWorker.RegisterMetrics(reg)
+		mux := http.NewServeMux()
+		mux.Handle("GET /metrics", reg)
+		metricsServer = &http.Server{
+			Addr:        fmt.Sprintf(":%s", cfg.Worker.MetricsPort),
+			Handler:     mux,
+			ReadTimeout: cfg.Server.ReadTimeout,
+		}
+
+		go func() {
+			appLogger.Infof("Metrics server listening on port %s", cfg.Worker.MetricsPort)
+			if err := metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				appLogger.Fatal("Metrics server failed", err)
+			}
+		}()
+	}
 
-	// Connect to NATS JetStream
+	// Hard-delete long soft-deleted rows in the background; off unless RETENTION_PERIOD is set
+	purgeCtx, stopPurge := context.WithCancel(context.Background())
+	purgeDone := make(chan struct{})
+	if cfg.Worker.RetentionPeriod > 0 {
+		purger := worker.NewPurger(db, cfg.Worker.RetentionPeriod, cfg.Worker.PurgeBatchSize, clock.New(), appLogger)
+		go func() {
+			defer close(purgeDone)
+			purger.Run(purgeCtx, cfg.Worker.PurgeInterval)
+		}()
+
+		appLogger.WithFields(map[string]any{
+			"retention": cfg.Worker.RetentionPeriod.String(),
+			"interval":  cfg.Worker.PurgeInterval.String(),
+		}).Info("Soft-delete purge enabled")
+	} else {
+		close(purgeDone)
+	}
+
+	// Feed review events to the worker from the configured transport
+	var stopConsuming func()
+	switch cfg.Events.Transport {
+	case config.EventTransportNATS:
+		stopConsuming = consumeJetStream(cfg, ratingWorker, appLogger)
+	case config.EventTransportPostgres:
+		consumer := events.NewPGConsumer(cfg, appLogger)
+		if err := consumer.Subscribe(review.EventSubject, ratingWorker.HandleEvent); err != nil {
+			appLogger.Fatal("Failed to listen for review events", err)
+		}
+		stopConsuming = consumer.Close
+	default:
+		appLogger.Fatal("Rating worker needs EVENT_TRANSPORT=nats or postgres", fmt.Errorf("%s delivers no events to other services", cfg.Events.Transport))
+	}
+	defer stopConsuming()
+
+	// SIGHUP re-reads CONFIG_FILE and applies the settings that are safe to change live
+	stopReload := config.WatchReload(cfg, appLogger, func(next *config.Config) {
+		ratingWorker.SetDebounceWindow(next.Worker.DebounceWindow)
+		if redisCache != nil {
+			redisCache.SetTTLs(next.Cache.ProductRatingTTL, next.Cache.ReviewsListTTL, next.Cache.RecentReviewsTTL)
+		}
+	})
+	defer stopReload()
+
+	// Wait for interrupt signal
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+
+	<-sigCh
+	appLogger.Info("Received shutdown signal")
+
+	// Graceful shutdown with timeout
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Worker.ShutdownTimeout)
+	defer cancel()
+
+	if err := ratingWorker.Shutdown(shutdownCtx); err != nil {
+		appLogger.WithFields(map[string]any{
+			"error": err.Error(),
+		}).Error("Error during shutdown", err)
+	}
+
+	// Cancelling rolls back the purge batch in flight; committed batches stay deleted
+	stopPurge()
+	select {
+	case <-purgeDone:
+	case <-shutdownCtx.Done():
+		appLogger.Warn("Timed out waiting for the purge to stop")
+	}
+
+	// Stopped last so a final scrape can still see the shutdown's counters
+	if metricsServer != nil {
+		if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+			appLogger.Error("Metrics server forced to shutdown", err)
+		}
+	}
+
+	appLogger.Info("Rating worker stopped")
+}
+
+// consumeJetStream feeds the rating worker from the durable JetStream consumer, acking
+// handled events and NAKing failures for redelivery. The returned func stops fetching
+// and closes the connection.
+func consumeJetStream(cfg *config.Config, ratingWorker *worker.RatingWorker, appLogger *logger.Logger) func() {
 	appLogger.Info("Connecting to NATS JetStream...")
-	nc, err := nats.Connect(cfg.NATS.URL)
+
+	// Signals the fetch loop to resume as soon as the connection is back
+	reconnectedCh := make(chan struct{}, 1)
+
+	nc, err := nats.Connect(
+		cfg.NATS.URL,
+		// Keep reconnecting forever - the worker is useless without NATS and shouldn't give up
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			fields := map[string]any{}
+			if err != nil {
+				fields["error"] = err.Error()
+			}
+			appLogger.WithFields(fields).Warn("Disconnected from NATS, pausing message fetching")
+		}),
+		nats.ReconnectHandler(func(conn *nats.Conn) {
+			appLogger.WithFields(map[string]any{
+				"url": conn.ConnectedUrl(),
+			}).Info("Reconnected to NATS, resuming message fetching")
+
+			select {
+			case reconnectedCh <- struct{}{}:
+			default:
+			}
+		}),
+		nats.ClosedHandler(func(_ *nats.Conn) {
+			appLogger.Warn("NATS connection closed")
+		}),
+	)
 	if err != nil {
 		appLogger.Fatal("Failed to connect to NATS", err)
 	}
-	defer nc.Close()
 
 	// Create JetStream context
 	js, err := nc.JetStream()
@@ -69,7 +258,7 @@ func main() {
 
 	// Initialize stream and consumer
 	appLogger.Info("Initializing JetStream stream and consumer...")
-	streamConfig := worker.NewStreamConfig(js, appLogger)
+	streamConfig := worker.NewStreamConfig(js, cfg.NATS.AckWait, cfg.NATS.SubjectPrefix, appLogger)
 
 	if err := streamConfig.EnsureStream(); err != nil {
 		appLogger.Fatal("Failed to ensure stream", err)
@@ -81,38 +270,60 @@ func main() {
 
 	// Subscribe to review events using durable consumer
 	// JetStream ensures exactly-once delivery with ack tracking
-	sub, err := js.PullSubscribe("reviews.events", "rating-worker", nats.ManualAck())
+	sub, err := js.PullSubscribe(streamConfig.Subject(), events.ConsumerName, nats.ManualAck(), nats.BindStream(streamConfig.Stream()))
 	if err != nil {
 		appLogger.Fatal("Failed to subscribe to JetStream consumer", err)
 	}
-	defer func() {
-		if err := sub.Unsubscribe(); err != nil {
-			appLogger.Error("Failed to unsubscribe from JetStream", err)
-		}
-	}()
 
 	appLogger.WithFields(map[string]any{
-		"stream":   "REVIEWS",
-		"consumer": "rating-worker",
+		"stream":   streamConfig.Stream(),
+		"consumer": events.ConsumerName,
 	}).Info("Subscribed to JetStream consumer")
 
 	// Process messages in a goroutine
+	done := make(chan struct{})
 	go func() {
+		backoff := initialFetchBackoff
+
 		for {
+			// Fetch can only fail while disconnected, so wait for the reconnect instead of spinning
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			if !nc.IsConnected() {
+				select {
+				case <-reconnectedCh:
+				case <-time.After(maxFetchBackoff):
+				}
+				continue
+			}
+
 			// Fetch messages in batches (up to 10 at a time)
 			msgs, err := sub.Fetch(10, nats.MaxWait(5*time.Second))
 			if err != nil {
 				if errors.Is(err, nats.ErrTimeout) {
 					// No messages available, continue polling
+					backoff = initialFetchBackoff
 					continue
 				}
 				appLogger.WithFields(map[string]any{
-					"error": err.Error(),
+					"error":      err.Error(),
+					"backoff_ms": backoff.Milliseconds(),
 				}).Error("Failed to fetch messages from JetStream", err)
-				time.Sleep(5 * time.Second)
+
+				select {
+				case <-time.After(backoff):
+				case <-reconnectedCh:
+				}
+				backoff = min(backoff*2, maxFetchBackoff)
 				continue
 			}
 
+			backoff = initialFetchBackoff
+
 			for _, msg := range msgs {
 				// Process the message
 				if err := ratingWorker.HandleEvent(msg.Data); err != nil {
@@ -120,6 +331,17 @@ func main() {
 						"error": err.Error(),
 					}).Error("Failed to handle event", err)
 
+					// A malformed message fails the same way on every delivery, so stop
+					// redelivering it rather than spend its MaxDeliver attempts
+					if errors.Is(err, worker.ErrPermanent) {
+						if termErr := msg.Term(); termErr != nil {
+							appLogger.WithFields(map[string]any{
+								"error": termErr.Error(),
+							}).Error("Failed to terminate message", termErr)
+						}
+						continue
+					}
+
 					// Negative acknowledgment - message will be redelivered with exponential backoff
 					// After 3 failed attempts (MaxDeliver), message is discarded
 					// This is acceptable: next review event will trigger full recalculation
@@ -141,22 +363,11 @@ func main() {
 		}
 	}()
 
-	// Wait for interrupt signal
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
-
-	<-sigCh
-	appLogger.Info("Received shutdown signal")
-
-	// Graceful shutdown with timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := ratingWorker.Shutdown(shutdownCtx); err != nil {
-		appLogger.WithFields(map[string]any{
-			"error": err.Error(),
-		}).Error("Error during shutdown", err)
+	return func() {
+		close(done)
+		if err := sub.Unsubscribe(); err != nil {
+			appLogger.Error("Failed to unsubscribe from JetStream", err)
+		}
+		nc.Close()
 	}
-
-	appLogger.Info("Rating worker stopped")
 }
@@ -24,87 +24,73 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/products": {
+        "/admin/audit": {
             "get": {
-                "description": "Get a paginated list of products",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
                 ],
+                "description": "Who created, updated, deleted or anonymized an entity, with before/after snapshots, newest first. Anonymizing a review also scrubs the name from its earlier snapshots. Requires the admin API key.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "List all products",
+                "summary": "Get the audit trail of a product or review",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product or review ID (UUID)",
+                        "name": "entity_id",
+                        "in": "query",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "Number of items per page (max 100)",
+                        "default": 50,
+                        "description": "Number of entries per page (max 500)",
                         "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "integer",
                         "default": 0,
-                        "description": "Number of items to skip",
+                        "description": "Number of entries to skip",
                         "name": "offset",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated list of products",
+                        "description": "Paginated list of audit entries",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "400": {
+                        "description": "Missing or invalid entity_id",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            },
-            "post": {
-                "description": "Create a new product with name, description, and price",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Products"
-                ],
-                "summary": "Create a new product",
-                "parameters": [
-                    {
-                        "description": "Product details",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_delivery_http_handler.CreateProductRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "201": {
-                        "description": "Product created successfully",
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin key",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request body",
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -124,38 +110,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/products/{id}": {
-            "get": {
-                "description": "Get detailed information about a product including average rating",
-                "consumes": [
-                    "application/json"
+        "/admin/cache/flush": {
+            "post": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
                 ],
+                "description": "Remove every product and review cache entry, e.g. after a bulk import or schema change. Scans the cache's key namespace in batches rather than flushing the whole Redis database. Requires the admin API key.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
-                    "Products"
-                ],
-                "summary": "Get a product by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "summary": "Flush the product and review cache",
                 "responses": {
                     "200": {
-                        "description": "Product details",
+                        "description": "Number of keys removed",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/internal_delivery_http_handler.CacheFlushResponse"
                         }
                     },
-                    "400": {
-                        "description": "Invalid product ID",
+                    "401": {
+                        "description": "Missing or invalid admin key",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -163,8 +142,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Product not found",
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -172,8 +151,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "Cache unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -182,19 +161,24 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update product details (name, description, price). Requires version field for optimistic locking. If another client modifies the product between GET and PUT, you'll receive 409 Conflict. Fetch latest version and retry.",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/cache/products/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
                 ],
+                "description": "Remove the cached rating, review pages and review overview of a single product, e.g. after fixing its rows by hand. Cheaper than a full flush, which cold-starts the cache for every product. Requires the admin API key.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
-                    "Products"
+                    "Admin"
                 ],
-                "summary": "Update a product",
+                "summary": "Invalidate one product's cache",
                 "parameters": [
                     {
                         "type": "string",
@@ -202,27 +186,23 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated product details",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_delivery_http_handler.UpdateProductRequest"
-                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Product updated successfully",
+                    "204": {
+                        "description": "Cache entries removed (or none existed)"
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": true
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Invalid request",
+                    "401": {
+                        "description": "Missing or invalid admin key",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -230,8 +210,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "409": {
-                        "description": "Version conflict - product was modified. Fetch latest version and retry.",
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -239,8 +219,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "Cache unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -249,34 +229,33 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Soft delete a product and all its reviews",
-                "consumes": [
-                    "application/json"
+            }
+        },
+        "/admin/health/detailed": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
                 ],
+                "description": "Per-dependency status and probe latency, plus rating-worker lag (pending JetStream events), outbox relay lag (unsent events and the oldest one's age, with EVENT_OUTBOX) and the running build's version, commit, build time and uptime. Reports \"degraded\" when either lag exceeds its configured threshold and \"down\" (503) when a dependency is unreachable. Requires the admin API key.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
-                    "Products"
-                ],
-                "summary": "Delete a product",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "summary": "Get detailed dependency health",
                 "responses": {
-                    "204": {
-                        "description": "Product deleted successfully"
+                    "200": {
+                        "description": "All dependencies reachable (status ok or degraded)",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.DetailedHealth"
+                        }
                     },
-                    "400": {
-                        "description": "Invalid product ID",
+                    "401": {
+                        "description": "Missing or invalid admin key",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -284,8 +263,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Product not found",
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -293,43 +272,36 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "503": {
+                        "description": "At least one dependency is down",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/internal_delivery_http_handler.DetailedHealth"
                         }
                     }
                 }
             }
         },
-        "/products/{id}/reviews": {
+        "/admin/products/unreviewed": {
             "get": {
-                "description": "Get a paginated list of reviews for a specific product. Results are cached.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
                 ],
+                "description": "Paginated list of products with a review_count of 0, newest first, so merchandising can solicit reviews for them. Uses the review count kept by the rating worker, so a product reviewed moments ago may still be listed. Requires the admin API key.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Admin"
                 ],
-                "summary": "Get reviews for a product",
+                "summary": "List products without reviews",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Product ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "integer",
                         "default": 20,
-                        "description": "Number of items per page (max 100)",
+                        "description": "Number of items per page (default PRODUCTS_PAGE_SIZE_DEFAULT, max PRODUCTS_PAGE_SIZE_MAX)",
                         "name": "limit",
                         "in": "query"
                     },
@@ -343,14 +315,23 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Paginated list of reviews",
+                        "description": "Paginated list of products",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Invalid product ID",
+                    "401": {
+                        "description": "Missing or invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -370,40 +351,58 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviews": {
+        "/admin/products/{id}/reconcile": {
             "post": {
-                "description": "Create a new review for a product. Automatically updates product's average rating and publishes event.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
                 ],
+                "description": "Rebuild the product's rating totals with a full scan of its live reviews instead of trusting the incrementally maintained ones, store the result and invalidate the product's cache. The escape hatch for a rating that drifted or missed an event. Requires the admin API key.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Admin"
                 ],
-                "summary": "Create a new review",
+                "summary": "Recalculate a product's rating from its reviews",
                 "parameters": [
                     {
-                        "description": "Review details",
-                        "name": "review",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/internal_delivery_http_handler.CreateReviewRequest"
-                        }
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Review created successfully",
+                    "200": {
+                        "description": "Rating and review count before and after",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/internal_delivery_http_handler.ReconcileResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request body or product not found",
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -432,47 +431,48 @@ const docTemplate = `{
                 }
             }
         },
-        "/reviews/{id}": {
-            "put": {
-                "description": "Update review details. Automatically recalculates product's average rating and publishes event.",
+        "/admin/reviews/bulk-delete": {
+            "post": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ],
+                "description": "Soft delete up to 1000 reviews in one transaction, e.g. to clear a spam wave. Caches are invalidated and one product.rating.recalc event is published per affected product instead of a review.deleted event per review. IDs that don't exist or are already deleted are reported in not_found rather than failing the request. Requires the admin API key.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
-                    "Reviews"
+                    "Admin"
                 ],
-                "summary": "Update a review",
+                "summary": "Delete reviews in bulk",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Review ID (UUID)",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated review details",
-                        "name": "review",
+                        "description": "Review IDs (UUIDs) to delete",
+                        "name": "ids",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_delivery_http_handler.UpdateReviewRequest"
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Review updated successfully",
+                        "description": "Number of reviews deleted and IDs that were skipped",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/internal_delivery_http_handler.BulkDeleteReviewsResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid body, invalid review ID or batch size out of range",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -480,8 +480,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Review not found",
+                    "401": {
+                        "description": "Missing or invalid admin key",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -489,24 +489,1239 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal server error",
+                    "403": {
+                        "description": "Admin API is disabled",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            },
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reviews/flagged": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ],
+                "description": "Reviews flagged more than REVIEW_FLAG_THRESHOLD times, most flagged first, with the reviewer's full name. Requires the admin API key.",
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List the review moderation queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page (default REVIEWS_PAGE_SIZE_DEFAULT, max REVIEWS_PAGE_SIZE_MAX)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of flagged reviews",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/stream-info": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ],
+                "description": "Live JetStream stream backlog and rating-worker consumer counters (pending, redelivered, ack pending). Requires the admin API key.",
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Get review event stream statistics",
+                "responses": {
+                    "200": {
+                        "description": "Stream and consumer statistics",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "JetStream unavailable, or EVENT_TRANSPORT isn't nats",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products": {
+            "get": {
+                "description": "Get a paginated list of products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "List all products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page (default PRODUCTS_PAGE_SIZE_DEFAULT, max PRODUCTS_PAGE_SIZE_MAX)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of products",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new product with name, description, and price. Set reviews_enabled to false to open it with reviews frozen.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "description": "Product details",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.CreateProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for validation messages (en, de)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Product created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Name already used by another product (when ENFORCE_UNIQUE_PRODUCT_NAME is on)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/compare": {
+            "get": {
+                "description": "Get several products with their average rating, review count and rating distribution (count per star, 1 to MAX_RATING) in one call, in the order requested. Duplicate IDs are ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Compare products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated product IDs (UUIDs), at most PRODUCTS_COMPARE_MAX_IDS",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Products with their rating distributions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_delivery_http_handler.ProductComparisonResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Missing, invalid or too many product IDs",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "One of the products was not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}": {
+            "get": {
+                "description": "Get detailed information about a product including average rating",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product details",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update product details (name, description, price, reviews_enabled). Omitted reviews_enabled keeps the current value. Requires version field for optimistic locking. If another client modifies the product between GET and PUT, you'll receive 409 Conflict. Fetch latest version and retry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated product details",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.UpdateProductRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for validation messages (en, de)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Version conflict (CONFLICT) or name already used by another product (ALREADY_EXISTS)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Soft delete a product and all its reviews",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Product deleted successfully"
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/detail": {
+            "get": {
+                "description": "Get a product, its first page of reviews (newest first), total review count and rating distribution (count per star, 1 to MAX_RATING) in one call. The review portion is cached; the product itself is always read fresh.",
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product with its reviews",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of reviews to include (max 100)",
+                        "name": "reviews_limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product with reviews",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ProductDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/reviews": {
+            "get": {
+                "description": "Get a paginated list of reviews for a specific product, ordered by DEFAULT_REVIEW_SORT (newest first unless configured). Reviewers are shown by display name (first name and last initial). Results are cached. A product without reviews returns an empty list; an unknown or deleted product returns 404.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Get reviews for a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of items per page (default REVIEWS_PAGE_SIZE_DEFAULT, max REVIEWS_PAGE_SIZE_MAX)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Number of items to skip",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated review fields to return, e.g. id,rating,review_text (default: all)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only reviews in this language (ISO 639-1 code, e.g. en); reviews without a language are left out",
+                        "name": "language",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Paginated list of reviews",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID, unknown field or invalid language",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/reviews/import": {
+            "post": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ],
+                "description": "Create up to 1000 reviews (or MAX_BATCH_ITEMS, if lower) for one product in a single transaction; either all are created or none. Source defaults to \"import\". Publishes one product.rating.recalc event instead of a review.created event per review. Requires the admin API key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Bulk import reviews for a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reviews to import",
+                        "name": "reviews",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_delivery_http_handler.ImportReviewRequest"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for validation messages (en, de)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Number of reviews imported",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ImportReviewsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID, invalid review or batch size out of range",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled, or reviews are disabled for this product",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/products/{id}/reviews/summary": {
+            "get": {
+                "description": "Get a product's average rating, review count, rating distribution (count per star, 1 to MAX_RATING) and an excerpt of its newest review (null when there are none). A lighter, fully cached alternative to /detail for product cards in listing grids.",
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Products"
+                ],
+                "summary": "Get a product's review summary",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review summary",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ReviewSummaryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid product ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews": {
+            "post": {
+                "description": "Create a new review for a product. Automatically updates product's average rating and publishes event. Source (web, mobile, import, api) is taken from the body, then the X-Review-Source header, then the server default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Create a new review",
+                "parameters": [
+                    {
+                        "description": "Review details",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.CreateReviewRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Review source when not set in the body (web, mobile, import, api)",
+                        "name": "X-Review-Source",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for validation messages (en, de)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Review created successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Reviews are disabled for this product",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too many reviews for this product from the client IP (when REVIEW_THROTTLE_LIMIT is set)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/changes": {
+            "get": {
+                "security": [
+                    {
+                        "AdminKey": []
+                    }
+                ],
+                "description": "Reviews created, updated or soft-deleted (deleted=true) after the given time, oldest change first. Pass next_cursor back as cursor to fetch the next page; an empty next_cursor means the feed is caught up, so store the last cursor (or the newest updated_at) for the next sync. Requires the admin API key.",
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "List review changes for incremental sync",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Return changes after this RFC 3339 time (required unless cursor is set)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Opaque cursor from a previous page's next_cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Number of changes per page (max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Page of review changes",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ReviewChangesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or invalid since/cursor",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Missing or invalid admin key",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Admin API is disabled",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/recent": {
+            "get": {
+                "description": "The most recent reviews on any product, newest first, each with its product name, for a \"recently reviewed\" feed. Cached for CACHE_TTL_RECENT_REVIEWS, so new reviews can take that long to appear.",
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "List the newest reviews across all products",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Number of reviews (max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Recent reviews",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/internal_delivery_http_handler.RecentReviewResponse"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "put": {
+                "description": "Update review details. Automatically recalculates product's average rating and publishes event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Update a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated review details",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.UpdateReviewRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for validation messages (en, de)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Review updated successfully",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "413": {
+                        "description": "Request body too large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
             "delete": {
                 "description": "Soft delete a review. Automatically recalculates product's average rating and publishes event.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
                 ],
                 "tags": [
                     "Reviews"
@@ -554,61 +1769,710 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "internal_delivery_http_handler.CreateProductRequest": {
+        },
+        "/reviews/{id}/anonymize": {
+            "post": {
+                "description": "Replace the reviewer's first and last name with \"Anonymous\" for right-to-be-forgotten requests. Rating and text are kept, so unlike delete the review still counts toward the product rating. Publishes a review.anonymized event.",
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Anonymize a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Anonymized review",
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.ReviewResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid review ID",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/flag": {
+            "post": {
+                "description": "Report a review as breaking the rules. Each client IP may flag a review once. A review flagged more than REVIEW_FLAG_THRESHOLD times (default 3) enters the moderation queue, and the flag that puts it there publishes a review.flagged event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/vnd.productreviews.v1+json"
+                ],
+                "tags": [
+                    "Reviews"
+                ],
+                "summary": "Flag a review",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Review ID (UUID)",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Why the review is flagged",
+                        "name": "flag",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_delivery_http_handler.FlagReviewRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language for validation messages (en, de)",
+                        "name": "Accept-Language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Flag recorded"
+                    },
+                    "400": {
+                        "description": "Invalid review ID or request body, or the client IP couldn't be resolved",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Review not found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Review already flagged from this address",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_Pesokrava_product_reviewer_internal_pkg_version.Info": {
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "type": "string",
+                    "example": "2026-01-15T10:00:00Z"
+                },
+                "commit": {
+                    "type": "string",
+                    "example": "3f2c1a9"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "uptime_seconds": {
+                    "description": "UptimeSeconds is whole seconds since the process started",
+                    "type": "integer",
+                    "example": 3600
+                },
+                "version": {
+                    "type": "string",
+                    "example": "v1.2.0"
+                }
+            }
+        },
+        "internal_delivery_http_handler.BulkDeleteReviewsResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "not_found": {
+                    "description": "NotFound lists requested IDs that don't exist or were already deleted",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "internal_delivery_http_handler.CacheFlushResponse": {
+            "type": "object",
+            "properties": {
+                "keys_removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_delivery_http_handler.CreateProductRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "price"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "price": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "reviews_enabled": {
+                    "description": "ReviewsEnabled false rejects new reviews for the product; omitted means true",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "internal_delivery_http_handler.CreateReviewRequest": {
+            "type": "object",
+            "required": [
+                "first_name",
+                "last_name",
+                "product_id",
+                "rating",
+                "review_text"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Optional; only its Gravatar hash is stored, for the avatar",
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "jane@example.com"
+                },
+                "first_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "language": {
+                    "description": "Optional ISO 639-1 code; detected from review_text when omitted and DETECT_REVIEW_LANGUAGE is on",
+                    "type": "string",
+                    "example": "en"
+                },
+                "last_name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "description": "1 to MAX_RATING (default 5), in steps of 0.5 with HALF_STAR_RATINGS, or one of ALLOWED_RATINGS when set",
+                    "type": "number",
+                    "minimum": 1,
+                    "example": 5
+                },
+                "review_text": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "web",
+                        "mobile",
+                        "import",
+                        "api"
+                    ]
+                },
+                "title": {
+                    "description": "Optional headline",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Solid and quiet"
+                }
+            }
+        },
+        "internal_delivery_http_handler.DependencyHealth": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "latency_ms": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http_handler.DetailedHealth": {
+            "type": "object",
+            "properties": {
+                "build": {
+                    "description": "Build identifies the running binary, so a health report also says which build it is about",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/github_com_Pesokrava_product_reviewer_internal_pkg_version.Info"
+                        }
+                    ]
+                },
+                "dependencies": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/internal_delivery_http_handler.DependencyHealth"
+                    }
+                },
+                "event_lag": {
+                    "$ref": "#/definitions/internal_delivery_http_handler.EventLagHealth"
+                },
+                "outbox_lag": {
+                    "$ref": "#/definitions/internal_delivery_http_handler.OutboxLagHealth"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http_handler.EventLagHealth": {
             "type": "object",
-            "required": [
-                "name",
-                "price"
-            ],
             "properties": {
-                "description": {
+                "ack_pending": {
+                    "type": "integer"
+                },
+                "error": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 1
+                "pending": {
+                    "type": "integer"
                 },
-                "price": {
-                    "type": "number",
-                    "minimum": 0
+                "status": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "integer"
                 }
             }
         },
-        "internal_delivery_http_handler.CreateReviewRequest": {
+        "internal_delivery_http_handler.FlagReviewRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "Spam"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ImportReviewRequest": {
             "type": "object",
             "required": [
                 "first_name",
                 "last_name",
-                "product_id",
                 "rating",
                 "review_text"
             ],
             "properties": {
+                "email": {
+                    "description": "Optional; only its Gravatar hash is stored, for the avatar",
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "jane@example.com"
+                },
                 "first_name": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
                 },
+                "language": {
+                    "description": "Optional ISO 639-1 code; detected from review_text when omitted and DETECT_REVIEW_LANGUAGE is on",
+                    "type": "string",
+                    "example": "en"
+                },
                 "last_name": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
                 },
+                "rating": {
+                    "description": "1 to MAX_RATING (default 5), in steps of 0.5 with HALF_STAR_RATINGS, or one of ALLOWED_RATINGS when set",
+                    "type": "number",
+                    "minimum": 1,
+                    "example": 5
+                },
+                "review_text": {
+                    "type": "string",
+                    "minLength": 1
+                },
+                "source": {
+                    "type": "string",
+                    "enum": [
+                        "web",
+                        "mobile",
+                        "import",
+                        "api"
+                    ]
+                },
+                "title": {
+                    "description": "Optional headline",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Solid and quiet"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ImportReviewsResponse": {
+            "type": "object",
+            "properties": {
+                "imported": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_delivery_http_handler.OutboxLagHealth": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "oldest_age_seconds": {
+                    "type": "number"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "threshold_seconds": {
+                    "type": "number"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ProductComparisonResponse": {
+            "type": "object",
+            "properties": {
+                "product": {
+                    "$ref": "#/definitions/internal_delivery_http_handler.ProductResponse"
+                },
+                "rating_distribution": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "internal_delivery_http_handler.ProductDetailResponse": {
+            "type": "object",
+            "properties": {
+                "product": {
+                    "$ref": "#/definitions/internal_delivery_http_handler.ProductResponse"
+                },
+                "rating_distribution": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "reviews": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_delivery_http_handler.ReviewResponse"
+                    }
+                },
+                "total_reviews": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ProductResponse": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "description": "AverageRating is null until the product has MIN_REVIEWS_FOR_RATING reviews",
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "review_count": {
+                    "type": "integer"
+                },
+                "reviews_enabled": {
+                    "description": "ReviewsEnabled false means new reviews are rejected with 403",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "Version must be sent back on update (optimistic locking)",
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_delivery_http_handler.RatingSnapshot": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "type": "number"
+                },
+                "review_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "internal_delivery_http_handler.RecentReviewResponse": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
                 "product_id": {
                     "type": "string"
                 },
+                "product_name": {
+                    "type": "string"
+                },
                 "rating": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+                    "type": "number"
                 },
                 "review_text": {
-                    "type": "string",
-                    "minLength": 1
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ReconcileResponse": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "$ref": "#/definitions/internal_delivery_http_handler.RatingSnapshot"
+                },
+                "before": {
+                    "$ref": "#/definitions/internal_delivery_http_handler.RatingSnapshot"
+                },
+                "cache_invalidated": {
+                    "description": "CacheInvalidated is false when Redis was unavailable; the cached rating then lives until its TTL",
+                    "type": "boolean"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "stats_drifted": {
+                    "description": "StatsDrifted is true when the incremental totals disagreed with the reviews and were corrected",
+                    "type": "boolean"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ReviewChange": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "deleted": {
+                    "type": "boolean"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "review_text": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ReviewChangesResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/internal_delivery_http_handler.ReviewChange"
+                    }
+                },
+                "next_cursor": {
+                    "description": "NextCursor resumes after the last change; empty once the feed is caught up",
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ReviewResponse": {
+            "type": "object",
+            "properties": {
+                "avatar_url": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "display_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "rating": {
+                    "type": "number"
+                },
+                "review_text": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_delivery_http_handler.ReviewSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "average_rating": {
+                    "description": "AverageRating is null until the product has MIN_REVIEWS_FOR_RATING reviews",
+                    "type": "number"
+                },
+                "distribution": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "latest_review_excerpt": {
+                    "type": "string"
+                },
+                "review_count": {
+                    "type": "integer"
                 }
             }
         },
@@ -632,6 +2496,11 @@ const docTemplate = `{
                     "type": "number",
                     "minimum": 0
                 },
+                "reviews_enabled": {
+                    "description": "ReviewsEnabled freezes or reopens reviews; omitted keeps the stored value",
+                    "type": "boolean",
+                    "example": true
+                },
                 "version": {
                     "type": "integer",
                     "minimum": 1
@@ -647,28 +2516,53 @@ const docTemplate = `{
                 "review_text"
             ],
             "properties": {
+                "email": {
+                    "description": "Optional; only its Gravatar hash is stored, for the avatar",
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "jane@example.com"
+                },
                 "first_name": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
                 },
+                "language": {
+                    "description": "Optional ISO 639-1 code; detected from review_text when omitted and DETECT_REVIEW_LANGUAGE is on",
+                    "type": "string",
+                    "example": "en"
+                },
                 "last_name": {
                     "type": "string",
                     "maxLength": 100,
                     "minLength": 1
                 },
                 "rating": {
-                    "type": "integer",
-                    "maximum": 5,
-                    "minimum": 1
+                    "description": "1 to MAX_RATING (default 5), in steps of 0.5 with HALF_STAR_RATINGS, or one of ALLOWED_RATINGS when set",
+                    "type": "number",
+                    "minimum": 1,
+                    "example": 5
                 },
                 "review_text": {
                     "type": "string",
                     "minLength": 1
+                },
+                "title": {
+                    "description": "Optional headline",
+                    "type": "string",
+                    "maxLength": 200,
+                    "example": "Solid and quiet"
                 }
             }
         }
     },
+    "securityDefinitions": {
+        "AdminKey": {
+            "type": "apiKey",
+            "name": "X-Admin-Key",
+            "in": "header"
+        }
+    },
     "tags": [
         {
             "description": "Product management endpoints",
@@ -677,6 +2571,10 @@ const docTemplate = `{
         {
             "description": "Review management endpoints",
             "name": "Reviews"
+        },
+        {
+            "description": "Operator endpoints guarded by the admin API key",
+            "name": "Admin"
         }
     ]
 }`
@@ -0,0 +1,272 @@
+// Package client is a typed Go client for the Product Reviews API.
+// Request bodies reuse the handler request types so the client can't drift
+// from what the server decodes.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/handler"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/middleware"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// DefaultTimeout bounds each request when no custom timeout or HTTP client is configured
+const DefaultTimeout = 10 * time.Second
+
+// Client calls the Product Reviews API over HTTP
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	adminKey   string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithTimeout sets the per-request timeout of the default HTTP client
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.httpClient.Timeout = timeout
+	}
+}
+
+// WithHTTPClient replaces the HTTP client, e.g. to share a transport or inject tracing
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAdminKey sets the key sent as X-Admin-Key on admin endpoints
+func WithAdminKey(key string) Option {
+	return func(c *Client) {
+		c.adminKey = key
+	}
+}
+
+// NewClient creates a client for the API served at baseURL (e.g. "http://localhost:8080")
+func NewClient(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/") + "/api/v1",
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Pagination is the pagination metadata returned by list endpoints
+type Pagination struct {
+	Total  int `json:"total"`
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+}
+
+// APIError is a non-2xx response from the API
+type APIError struct {
+	StatusCode int               `json:"-"`
+	Code       string            `json:"code"`
+	Message    string            `json:"error"`
+	Fields     map[string]string `json:"fields,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// Is maps API error codes onto domain errors so callers can use errors.Is(err, domain.ErrNotFound)
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case domain.ErrNotFound:
+		return e.Code == response.CodeNotFound
+	case domain.ErrInvalidInput:
+		return e.Code == response.CodeValidationFailed
+	case domain.ErrAlreadyExists:
+		return e.Code == response.CodeAlreadyExists
+	case domain.ErrConflict:
+		return e.Code == response.CodeConflict
+	default:
+		return false
+	}
+}
+
+// envelope is the standard success body
+type envelope struct {
+	Data       json.RawMessage `json:"data"`
+	Pagination *Pagination     `json:"pagination,omitempty"`
+}
+
+// CreateProduct creates a product
+func (c *Client) CreateProduct(ctx context.Context, req handler.CreateProductRequest) (*handler.ProductResponse, error) {
+	var product handler.ProductResponse
+	if _, err := c.do(ctx, http.MethodPost, "/products", nil, req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProduct retrieves a product by ID
+func (c *Client) GetProduct(ctx context.Context, id uuid.UUID) (*handler.ProductResponse, error) {
+	var product handler.ProductResponse
+	if _, err := c.do(ctx, http.MethodGet, "/products/"+id.String(), nil, nil, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// GetProductDetail retrieves a product with its first reviews and rating distribution
+func (c *Client) GetProductDetail(ctx context.Context, id uuid.UUID, reviewsLimit int) (*handler.ProductDetailResponse, error) {
+	query := url.Values{}
+	if reviewsLimit > 0 {
+		query.Set("reviews_limit", strconv.Itoa(reviewsLimit))
+	}
+
+	var detail handler.ProductDetailResponse
+	if _, err := c.do(ctx, http.MethodGet, "/products/"+id.String()+"/detail", query, nil, &detail); err != nil {
+		return nil, err
+	}
+	return &detail, nil
+}
+
+// ListProducts retrieves a page of products
+func (c *Client) ListProducts(ctx context.Context, limit, offset int) ([]*handler.ProductResponse, *Pagination, error) {
+	var products []*handler.ProductResponse
+	pagination, err := c.do(ctx, http.MethodGet, "/products", pageQuery(limit, offset), nil, &products)
+	if err != nil {
+		return nil, nil, err
+	}
+	return products, pagination, nil
+}
+
+// UpdateProduct updates a product; req.Version must match the current version
+func (c *Client) UpdateProduct(ctx context.Context, id uuid.UUID, req handler.UpdateProductRequest) (*handler.ProductResponse, error) {
+	var product handler.ProductResponse
+	if _, err := c.do(ctx, http.MethodPut, "/products/"+id.String(), nil, req, &product); err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// DeleteProduct soft-deletes a product and its reviews
+func (c *Client) DeleteProduct(ctx context.Context, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, "/products/"+id.String(), nil, nil, nil)
+	return err
+}
+
+// CreateReview creates a review
+func (c *Client) CreateReview(ctx context.Context, req handler.CreateReviewRequest) (*handler.ReviewResponse, error) {
+	var review handler.ReviewResponse
+	if _, err := c.do(ctx, http.MethodPost, "/reviews", nil, req, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// ListReviews retrieves a page of reviews for a product, newest first
+func (c *Client) ListReviews(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*handler.ReviewResponse, *Pagination, error) {
+	var reviews []*handler.ReviewResponse
+	pagination, err := c.do(ctx, http.MethodGet, "/products/"+productID.String()+"/reviews", pageQuery(limit, offset), nil, &reviews)
+	if err != nil {
+		return nil, nil, err
+	}
+	return reviews, pagination, nil
+}
+
+// UpdateReview updates a review
+func (c *Client) UpdateReview(ctx context.Context, id uuid.UUID, req handler.UpdateReviewRequest) (*handler.ReviewResponse, error) {
+	var review handler.ReviewResponse
+	if _, err := c.do(ctx, http.MethodPut, "/reviews/"+id.String(), nil, req, &review); err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// DeleteReview soft-deletes a review
+func (c *Client) DeleteReview(ctx context.Context, id uuid.UUID) error {
+	_, err := c.do(ctx, http.MethodDelete, "/reviews/"+id.String(), nil, nil, nil)
+	return err
+}
+
+func pageQuery(limit, offset int) url.Values {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+	return query
+}
+
+// do sends a request and decodes the envelope's data into out (skipped when out is nil)
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) (*Pagination, error) {
+	endpoint := c.baseURL + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request: %w", err)
+		}
+		reqBody = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build request: %w", err)
+	}
+	req.Header.Set("Accept", response.MediaTypeV1)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.adminKey != "" {
+		req.Header.Set(middleware.AdminKeyHeader, c.adminKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request %s %s failed: %w", method, path, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		apiErr := &APIError{StatusCode: resp.StatusCode}
+		if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return nil, apiErr
+	}
+
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		return nil, nil
+	}
+
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if err := json.Unmarshal(env.Data, out); err != nil {
+		return nil, fmt.Errorf("failed to decode response data: %w", err)
+	}
+
+	return env.Pagination, nil
+}
@@ -0,0 +1,110 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/handler"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+func TestClient_CreateReview(t *testing.T) {
+	productID := uuid.New()
+	reviewID := uuid.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v1/reviews", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, response.MediaTypeV1, r.Header.Get("Accept"))
+
+		var req handler.CreateReviewRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+
+		response.Created(w, handler.ReviewResponse{
+			ID:          reviewID,
+			ProductID:   productID,
+			DisplayName: req.FirstName + " " + req.LastName[:1] + ".",
+			Rating:      req.Rating,
+		})
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	review, err := c.CreateReview(context.Background(), handler.CreateReviewRequest{
+		ProductID:  productID.String(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Great",
+		Rating:     5,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, reviewID, review.ID)
+	assert.Equal(t, "John D.", review.DisplayName)
+	assert.Equal(t, 5.0, review.Rating)
+}
+
+func TestClient_ListReviews_Pagination(t *testing.T) {
+	productID := uuid.New()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/products/"+productID.String()+"/reviews", r.URL.Path)
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+		assert.Equal(t, "4", r.URL.Query().Get("offset"))
+
+		response.Paginated(w, []handler.ReviewResponse{{ID: uuid.New()}, {ID: uuid.New()}}, 9, 2, 4)
+	}))
+	defer server.Close()
+
+	reviews, pagination, err := NewClient(server.URL).ListReviews(context.Background(), productID, 2, 4)
+
+	require.NoError(t, err)
+	assert.Len(t, reviews, 2)
+	assert.Equal(t, &Pagination{Total: 9, Limit: 2, Offset: 4}, pagination)
+}
+
+func TestClient_ErrorResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			response.ErrorWithCode(w, http.StatusNotFound, response.CodeNotFound, "Product not found")
+		default:
+			response.ValidationError(w, map[string]string{"name": "name is a required field"})
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+
+	_, err := c.GetProduct(context.Background(), uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	_, err = c.CreateProduct(context.Background(), handler.CreateProductRequest{})
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Equal(t, "name is a required field", apiErr.Fields["name"])
+}
+
+func TestClient_RespectsTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		response.NoContent(w)
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL, WithTimeout(20*time.Millisecond)).DeleteReview(context.Background(), uuid.New())
+
+	assert.Error(t, err)
+}
@@ -2,19 +2,74 @@ package config
 
 import (
 	"fmt"
+	"math"
+	"net"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/idgen"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/sanitize"
+)
+
+// subjectPrefixPattern matches one or more dot-separated NATS subject tokens without wildcards
+var subjectPrefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+(\.[A-Za-z0-9_-]+)*$`)
+
+// Event transports selectable with EVENT_TRANSPORT
+const (
+	// EventTransportNATS publishes to NATS JetStream; the rating worker consumes durably with acks
+	EventTransportNATS = "nats"
+	// EventTransportPostgres uses Postgres LISTEN/NOTIFY, removing NATS from small deployments.
+	// Delivery is at-most-once: events sent while no listener is connected are lost.
+	EventTransportPostgres = "postgres"
+	// EventTransportNoop drops events, for running the API without any event infrastructure
+	EventTransportNoop = "noop"
+	// EventTransportInMemory records events in the publishing process, for tests and local runs
+	EventTransportInMemory = "inmemory"
+)
+
+// Publish deadline modes selectable with EVENT_PUBLISH_DEADLINE
+const (
+	// PublishDeadlineDetached bounds event publishes by EVENT_PUBLISH_TIMEOUT alone, so an event
+	// goes out even when the request that caused it was about to time out
+	PublishDeadlineDetached = "detached"
+	// PublishDeadlineRequest also caps each publish at the request's deadline, failing fast
+	PublishDeadlineRequest = "request"
+)
+
+// Startup connection backoff strategies selectable with DB_CONNECT_BACKOFF and REDIS_CONNECT_BACKOFF
+const (
+	// ConnectBackoffFixed waits the same delay between every attempt
+	ConnectBackoffFixed = "fixed"
+	// ConnectBackoffExponential doubles the delay after each attempt, up to the max delay
+	ConnectBackoffExponential = "exponential"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Env      string
+	Env string
+	// File is the CONFIG_FILE that was read; empty when configured by env vars alone
+	File string
+	// LogLevel is debug, info, warn or error; LOG_LEVEL defaults to debug in development
+	// and info elsewhere
+	LogLevel string
 	Server   ServerConfig
 	Database DatabaseConfig
 	Redis    RedisConfig
 	NATS     NATSConfig
+	Events   EventsConfig
 	Cache    CacheConfig
+	Worker   WorkerConfig
+	Admin    AdminConfig
+	Review   ReviewConfig
+	Product  ProductConfig
+	Notifier NotifierConfig
+	Warmer   WarmerConfig
 }
 
 // ServerConfig holds HTTP server configuration
@@ -23,6 +78,18 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
+	// EnablePprof mounts net/http/pprof under /debug/pprof; off by default since profiles leak internals
+	EnablePprof bool
+	// AdminPort moves readiness, pprof and admin routes to a separate listener; empty serves them on Port
+	AdminPort string
+	// MaxRequestBodySize is the default JSON body limit in bytes; routes may override it
+	MaxRequestBodySize int64
+	// MaxBatchItems caps the items in any JSON array body or ID list, on top of per-endpoint limits
+	MaxBatchItems int
+	// StrictContentType rejects JSON bodies sent without Content-Type: application/json (415)
+	StrictContentType bool
+	// TrustedProxies are the peers whose X-Forwarded-For header is believed when resolving client IPs
+	TrustedProxies []*net.IPNet
 }
 
 // DatabaseConfig holds PostgreSQL configuration
@@ -36,6 +103,13 @@ type DatabaseConfig struct {
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
+	// SlowQueryThreshold is the duration above which repository queries are logged; zero disables
+	SlowQueryThreshold time.Duration
+	// SerializationRetries is how many times a transaction that hit a serialization failure is rerun
+	SerializationRetries int
+	// IDStrategy picks how IDs of new products and reviews are generated: v4 (random) or v7 (time-ordered)
+	IDStrategy   string
+	ConnectRetry ConnectRetryConfig
 }
 
 // RedisConfig holds Redis configuration
@@ -44,17 +118,202 @@ type RedisConfig struct {
 	Port     string
 	Password string
 	DB       int
+	// ConnectRetry governs waiting for Redis at startup
+	ConnectRetry ConnectRetryConfig
 }
 
 // NATSConfig holds NATS configuration
 type NATSConfig struct {
-	URL string
+	URL     string
+	AckWait time.Duration
+	// LagDegradedThreshold is the pending rating-worker event count above which health reports degraded
+	LagDegradedThreshold uint64
+	// PublishDrainTimeout bounds how long API shutdown waits for in-flight event publishes
+	PublishDrainTimeout time.Duration
+	// SubjectPrefix namespaces subjects and the stream name so environments can share a cluster
+	SubjectPrefix string
+}
+
+// EventsConfig holds event transport configuration
+type EventsConfig struct {
+	// Transport is one of the EventTransport* values
+	Transport string
+	// PublishTimeout bounds each background review event publish
+	PublishTimeout time.Duration
+	// PublishDeadline is one of the PublishDeadline* values
+	PublishDeadline string
+	// BreakerThreshold is how many consecutive JetStream publish failures open the publisher's
+	// circuit breaker, after which publishes fail fast for BreakerCooldown; 0 disables it
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// BufferMaxSize is how many unpublished events are kept in Redis for replay once NATS is
+	// back; the oldest are dropped beyond it, and 0 disables buffering
+	BufferMaxSize int64
+	// Outbox writes review events to the events_outbox table in the mutation's transaction,
+	// and a relay publishes them, so an event is never lost to a crash after commit
+	Outbox bool
+	// OutboxPollInterval is how often the relay looks for unsent events
+	OutboxPollInterval time.Duration
+	// OutboxBatchSize is how many events the relay publishes per poll
+	OutboxBatchSize int
+	// OutboxRetention is how long sent events are kept before the relay deletes them
+	OutboxRetention time.Duration
+	// OutboxEmbeddedRelay runs a relay inside the API; turn it off when cmd/outbox-relay does
+	// the publishing. The monolith always relays itself, since its events stay in the process.
+	OutboxEmbeddedRelay bool
+	// OutboxLagDegradedThreshold is how long the oldest unsent event may wait before
+	// detailed health reports degraded
+	OutboxLagDegradedThreshold time.Duration
 }
 
 // CacheConfig holds caching TTL configuration
 type CacheConfig struct {
 	ProductRatingTTL time.Duration
 	ReviewsListTTL   time.Duration
+	// RecentReviewsTTL is how long the cross-product recent reviews feed is cached;
+	// it isn't invalidated on writes, so it stays short
+	RecentReviewsTTL time.Duration
+	// TTLJitter randomizes cache TTLs by up to ±this fraction so entries don't expire in lockstep
+	TTLJitter float64
+	// BreakerThreshold is how many consecutive Redis failures open the cache circuit breaker,
+	// after which cache calls are skipped for BreakerCooldown; 0 disables the breaker
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+	// Codec serializes cached review pages: json (readable) or gob (compact); see cache.NewCodec
+	Codec string
+	// CompressThreshold is the encoded size in bytes from which cached reviews lists are
+	// gzipped; 0 disables compression
+	CompressThreshold int
+}
+
+// WorkerConfig holds rating worker configuration
+type WorkerConfig struct {
+	WarmRatingCache bool
+	// DebounceWindow is how long the worker collects events for a product before one
+	// recalculation; longer windows mean fewer queries but staler ratings
+	DebounceWindow time.Duration
+	// ShutdownTimeout bounds how long the worker waits for pending recalculations on SIGTERM
+	ShutdownTimeout time.Duration
+	// RetentionPeriod is how long soft-deleted products and reviews are kept before the
+	// worker hard-deletes them; 0 disables purging
+	RetentionPeriod time.Duration
+	PurgeInterval   time.Duration
+	PurgeBatchSize  int
+	// MetricsPort serves the debounce counters on /metrics in Prometheus format; empty disables it
+	MetricsPort string
+}
+
+// AdminConfig holds admin API configuration
+type AdminConfig struct {
+	// APIKey guards /api/v1/admin endpoints; empty disables them
+	APIKey string
+}
+
+// ReviewConfig holds review submission configuration
+type ReviewConfig struct {
+	// DefaultSource applies when neither the request body nor the X-Review-Source header sets one
+	DefaultSource string
+	// SanitizeText is the SANITIZE_REVIEW_TEXT mode: off, store (strip HTML before saving)
+	// or output (strip HTML from review_text in API responses)
+	SanitizeText string
+	// DetectLanguage tags reviews submitted without a language with the one detected
+	// from their text; reviews it can't place stay untagged
+	DetectLanguage bool
+	// ThrottleLimit is how many reviews one client IP may submit per product per
+	// ThrottleWindow; 0 disables throttling
+	ThrottleLimit  int
+	ThrottleWindow time.Duration
+	// FlagThreshold is how many flags a review may collect before it enters the moderation
+	// queue; 0 queues a review on its first flag
+	FlagThreshold int
+	// MaxRating is the top of the rating scale (ratings run 1 to MaxRating)
+	MaxRating int
+	// RatingRoundingMode is how average ratings are rounded to one decimal: half_up, half_even or floor
+	RatingRoundingMode string
+	// DefaultSort orders the reviews list and product overview: newest, oldest,
+	// highest_rating or lowest_rating
+	DefaultSort string
+	// HalfStarRatings accepts ratings in steps of 0.5 (4.5) instead of whole stars only;
+	// needs migration 000011, which widens reviews.rating to NUMERIC
+	HalfStarRatings bool
+	// AllowedRatings restricts ratings to these values (e.g. 1, 3, 5) instead of the whole
+	// 1 to MaxRating scale; empty accepts the scale
+	AllowedRatings []float64
+	Pagination     PaginationConfig
+}
+
+// ProductConfig holds product catalog rules
+type ProductConfig struct {
+	// EnforceUniqueName rejects a product whose name matches another non-deleted product.
+	// The service checks it; there is no unique index, so catalogs with duplicates can leave it off
+	EnforceUniqueName bool
+	// CompareMaxIDs caps how many products GET /products/compare accepts in one call
+	CompareMaxIDs int
+	// MinReviewsForRating is the review count below which responses show average_rating
+	// as null; 0 always shows it
+	MinReviewsForRating int
+	Pagination          PaginationConfig
+}
+
+// PaginationConfig holds the page size rules for one list endpoint
+type PaginationConfig struct {
+	// DefaultLimit applies when the request omits limit or asks for more than MaxLimit
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// NotifierConfig holds outbound notification configuration for the notifier service
+type NotifierConfig struct {
+	// WebhookURL receives every review event as a JSON POST; empty disables the webhook
+	WebhookURL string
+	HTTPClient HTTPClientConfig
+}
+
+// WarmerConfig holds cache-warmer service configuration
+type WarmerConfig struct {
+	// Delay is how long after an event a product is warmed; it should outlast the rating
+	// worker's recalculation, whose cache invalidation would discard an earlier warm-up
+	Delay time.Duration
+	// TopN limits warming to the TopN most reviewed products; 0 warms every product with events
+	TopN            int
+	RefreshInterval time.Duration
+}
+
+// ConnectRetryConfig holds how a service waits for a dependency at startup
+type ConnectRetryConfig struct {
+	// MaxRetries is the number of connection attempts, the first included
+	MaxRetries int
+	// Delay is the wait after the first failed attempt; fixed backoff keeps it throughout
+	Delay time.Duration
+	// Backoff is one of the ConnectBackoff* values
+	Backoff string
+	// MaxDelay caps each wait under exponential backoff
+	MaxDelay time.Duration
+}
+
+// Wait returns how long to wait after the failed attempt numbered attempt, counting from 0
+func (c ConnectRetryConfig) Wait(attempt int) time.Duration {
+	if c.Backoff != ConnectBackoffExponential {
+		return c.Delay
+	}
+	// Stop doubling once past the cap so the shift can't overflow
+	wait := c.Delay
+	for range attempt {
+		if wait >= c.MaxDelay {
+			break
+		}
+		wait *= 2
+	}
+	return min(wait, c.MaxDelay)
+}
+
+// HTTPClientConfig holds the shared outbound HTTP client configuration
+type HTTPClientConfig struct {
+	Timeout time.Duration
+	// MaxRetries is the number of retries after the first attempt on network errors and 5xx responses
+	MaxRetries   int
+	RetryBackoff time.Duration
+	MaxIdleConns int
 }
 
 // Load reads configuration from environment variables and returns a Config struct
@@ -62,11 +321,18 @@ func Load() (*Config, error) {
 	viper.AutomaticEnv()
 
 	// Set defaults
-	viper.SetDefault("ENV", "development")
+	viper.SetDefault("ENV", EnvDevelopment)
+	viper.SetDefault("LOG_LEVEL", "")
 	viper.SetDefault("SERVER_PORT", "8080")
 	viper.SetDefault("SERVER_READ_TIMEOUT", "10s")
 	viper.SetDefault("SERVER_WRITE_TIMEOUT", "10s")
 	viper.SetDefault("SERVER_SHUTDOWN_TIMEOUT", "30s")
+	viper.SetDefault("ENABLE_PPROF", false)
+	viper.SetDefault("ADMIN_PORT", "")
+	viper.SetDefault("MAX_REQUEST_BODY_SIZE", 1<<20)
+	viper.SetDefault("MAX_BATCH_ITEMS", 1000)
+	viper.SetDefault("STRICT_CONTENT_TYPE", true)
+	viper.SetDefault("TRUSTED_PROXIES", "")
 
 	viper.SetDefault("DB_HOST", "localhost")
 	viper.SetDefault("DB_PORT", "5432")
@@ -77,16 +343,95 @@ func Load() (*Config, error) {
 	viper.SetDefault("DB_MAX_OPEN_CONNS", 25)
 	viper.SetDefault("DB_MAX_IDLE_CONNS", 5)
 	viper.SetDefault("DB_CONN_MAX_LIFETIME", "5m")
+	viper.SetDefault("DB_SLOW_QUERY_THRESHOLD", "200ms")
+	viper.SetDefault("DB_SERIALIZATION_RETRIES", 3)
+	viper.SetDefault("DB_ID_STRATEGY", idgen.StrategyV4)
+	viper.SetDefault("DB_CONNECT_MAX_RETRIES", 10)
+	viper.SetDefault("DB_CONNECT_RETRY_DELAY", "2s")
+	viper.SetDefault("DB_CONNECT_BACKOFF", ConnectBackoffFixed)
+	viper.SetDefault("DB_CONNECT_MAX_RETRY_DELAY", "30s")
 
 	viper.SetDefault("REDIS_HOST", "localhost")
 	viper.SetDefault("REDIS_PORT", "6379")
 	viper.SetDefault("REDIS_PASSWORD", "")
 	viper.SetDefault("REDIS_DB", 0)
+	viper.SetDefault("REDIS_CONNECT_MAX_RETRIES", 10)
+	viper.SetDefault("REDIS_CONNECT_RETRY_DELAY", "2s")
+	viper.SetDefault("REDIS_CONNECT_BACKOFF", ConnectBackoffFixed)
+	viper.SetDefault("REDIS_CONNECT_MAX_RETRY_DELAY", "30s")
 
 	viper.SetDefault("NATS_URL", "nats://localhost:4222")
+	viper.SetDefault("NATS_ACK_WAIT", "30s")
+	viper.SetDefault("NATS_LAG_DEGRADED_THRESHOLD", 1000)
+	viper.SetDefault("NATS_PUBLISH_DRAIN_TIMEOUT", "10s")
+	viper.SetDefault("NATS_SUBJECT_PREFIX", "")
+
+	viper.SetDefault("EVENT_TRANSPORT", EventTransportNATS)
+	viper.SetDefault("EVENT_PUBLISH_TIMEOUT", "5s")
+	viper.SetDefault("EVENT_PUBLISH_DEADLINE", PublishDeadlineDetached)
+	viper.SetDefault("EVENT_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("EVENT_BREAKER_COOLDOWN", "10s")
+	viper.SetDefault("EVENT_BUFFER_MAX_SIZE", 0)
+	viper.SetDefault("EVENT_OUTBOX", false)
+	viper.SetDefault("EVENT_OUTBOX_POLL_INTERVAL", "1s")
+	viper.SetDefault("EVENT_OUTBOX_BATCH_SIZE", 100)
+	viper.SetDefault("EVENT_OUTBOX_RETENTION", "24h")
+	viper.SetDefault("EVENT_OUTBOX_EMBEDDED_RELAY", true)
+	viper.SetDefault("EVENT_OUTBOX_LAG_DEGRADED_THRESHOLD", "1m")
 
 	viper.SetDefault("CACHE_TTL_PRODUCT_RATING", "300s")
 	viper.SetDefault("CACHE_TTL_REVIEWS_LIST", "120s")
+	viper.SetDefault("CACHE_TTL_RECENT_REVIEWS", "30s")
+	viper.SetDefault("CACHE_TTL_JITTER", 0.1)
+	viper.SetDefault("CACHE_BREAKER_THRESHOLD", 5)
+	viper.SetDefault("CACHE_BREAKER_COOLDOWN", "10s")
+	viper.SetDefault("CACHE_CODEC", "json")
+	viper.SetDefault("CACHE_COMPRESS_THRESHOLD", 0)
+
+	viper.SetDefault("WORKER_WARM_RATING_CACHE", true)
+	viper.SetDefault("WORKER_DEBOUNCE_WINDOW", "1s")
+	viper.SetDefault("WORKER_SHUTDOWN_TIMEOUT", "30s")
+	viper.SetDefault("RETENTION_PERIOD", "0")
+	viper.SetDefault("PURGE_INTERVAL", "1h")
+	viper.SetDefault("PURGE_BATCH_SIZE", 500)
+	viper.SetDefault("WORKER_METRICS_PORT", "")
+
+	viper.SetDefault("ADMIN_API_KEY", "")
+
+	viper.SetDefault("REVIEW_DEFAULT_SOURCE", domain.ReviewSourceWeb)
+	viper.SetDefault("REVIEWS_PAGE_SIZE_DEFAULT", 20)
+	viper.SetDefault("REVIEWS_PAGE_SIZE_MAX", 100)
+	viper.SetDefault("SANITIZE_REVIEW_TEXT", sanitize.ModeOff)
+	viper.SetDefault("DETECT_REVIEW_LANGUAGE", false)
+	viper.SetDefault("REVIEW_THROTTLE_LIMIT", 0)
+	viper.SetDefault("REVIEW_THROTTLE_WINDOW", "1h")
+	viper.SetDefault("REVIEW_FLAG_THRESHOLD", 3)
+	viper.SetDefault("MAX_RATING", domain.DefaultMaxRating)
+	viper.SetDefault("RATING_ROUNDING_MODE", domain.RatingRoundingHalfUp)
+	viper.SetDefault("HALF_STAR_RATINGS", false)
+	viper.SetDefault("ALLOWED_RATINGS", "")
+	viper.SetDefault("DEFAULT_REVIEW_SORT", domain.DefaultReviewSort)
+
+	viper.SetDefault("ENFORCE_UNIQUE_PRODUCT_NAME", false)
+	viper.SetDefault("PRODUCTS_PAGE_SIZE_DEFAULT", 20)
+	viper.SetDefault("PRODUCTS_PAGE_SIZE_MAX", 100)
+	viper.SetDefault("PRODUCTS_COMPARE_MAX_IDS", 10)
+	viper.SetDefault("MIN_REVIEWS_FOR_RATING", 0)
+
+	viper.SetDefault("NOTIFIER_WEBHOOK_URL", "")
+	viper.SetDefault("HTTP_CLIENT_TIMEOUT", "10s")
+	viper.SetDefault("HTTP_CLIENT_MAX_RETRIES", 3)
+	viper.SetDefault("HTTP_CLIENT_RETRY_BACKOFF", "500ms")
+	viper.SetDefault("HTTP_CLIENT_MAX_IDLE_CONNS", 10)
+
+	viper.SetDefault("CACHE_WARMER_DELAY", "5s")
+	viper.SetDefault("CACHE_WARMER_TOP_N", 100)
+	viper.SetDefault("CACHE_WARMER_REFRESH_INTERVAL", "5m")
+
+	configFile, err := readConfigFile(viper.GetString("CONFIG_FILE"))
+	if err != nil {
+		return nil, err
+	}
 
 	readTimeout, err := time.ParseDuration(viper.GetString("SERVER_READ_TIMEOUT"))
 	if err != nil {
@@ -103,11 +448,209 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid SERVER_SHUTDOWN_TIMEOUT: %w", err)
 	}
 
+	workerShutdownTimeout, err := time.ParseDuration(viper.GetString("WORKER_SHUTDOWN_TIMEOUT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WORKER_SHUTDOWN_TIMEOUT: %w", err)
+	}
+	if workerShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("invalid WORKER_SHUTDOWN_TIMEOUT: must be positive, got %s", workerShutdownTimeout)
+	}
+
+	debounceWindow, err := time.ParseDuration(viper.GetString("WORKER_DEBOUNCE_WINDOW"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid WORKER_DEBOUNCE_WINDOW: %w", err)
+	}
+	if debounceWindow <= 0 {
+		return nil, fmt.Errorf("invalid WORKER_DEBOUNCE_WINDOW: must be positive, got %s", debounceWindow)
+	}
+
+	env := viper.GetString("ENV")
+	logLevel := strings.ToLower(viper.GetString("LOG_LEVEL"))
+	switch logLevel {
+	case "":
+		logLevel = "info"
+		if env == EnvDevelopment {
+			logLevel = "debug"
+		}
+	case "debug", "info", "warn", "error":
+	default:
+		return nil, fmt.Errorf("invalid LOG_LEVEL: %q (debug, info, warn or error)", logLevel)
+	}
+
+	maxRating := viper.GetInt("MAX_RATING")
+	if maxRating < 2 || maxRating > domain.MaxRatingCeiling {
+		return nil, fmt.Errorf("invalid MAX_RATING: must be between 2 and %d, got %d", domain.MaxRatingCeiling, maxRating)
+	}
+
+	allowedRatings, err := parseRatingList(viper.GetString("ALLOWED_RATINGS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ALLOWED_RATINGS: %w", err)
+	}
+
+	defaultReviewSort := viper.GetString("DEFAULT_REVIEW_SORT")
+	if !domain.IsValidReviewSort(defaultReviewSort) {
+		return nil, fmt.Errorf("invalid DEFAULT_REVIEW_SORT: %q (newest, oldest, highest_rating or lowest_rating)", defaultReviewSort)
+	}
+
+	ratingRoundingMode := viper.GetString("RATING_ROUNDING_MODE")
+	if !domain.IsValidRatingRounding(ratingRoundingMode) {
+		return nil, fmt.Errorf("invalid RATING_ROUNDING_MODE: %q (half_up, half_even or floor)", ratingRoundingMode)
+	}
+
+	retentionPeriod, err := time.ParseDuration(viper.GetString("RETENTION_PERIOD"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid RETENTION_PERIOD: %w", err)
+	}
+	if retentionPeriod < 0 {
+		return nil, fmt.Errorf("invalid RETENTION_PERIOD: must not be negative, got %s", retentionPeriod)
+	}
+
+	purgeInterval, err := time.ParseDuration(viper.GetString("PURGE_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PURGE_INTERVAL: %w", err)
+	}
+	if purgeInterval <= 0 {
+		return nil, fmt.Errorf("invalid PURGE_INTERVAL: must be positive, got %s", purgeInterval)
+	}
+
+	purgeBatchSize := viper.GetInt("PURGE_BATCH_SIZE")
+	if purgeBatchSize <= 0 {
+		return nil, fmt.Errorf("invalid PURGE_BATCH_SIZE: must be positive, got %d", purgeBatchSize)
+	}
+
+	warmerDelay, err := time.ParseDuration(viper.GetString("CACHE_WARMER_DELAY"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_WARMER_DELAY: %w", err)
+	}
+	if warmerDelay < 0 {
+		return nil, fmt.Errorf("invalid CACHE_WARMER_DELAY: must not be negative, got %s", warmerDelay)
+	}
+
+	warmerTopN := viper.GetInt("CACHE_WARMER_TOP_N")
+	if warmerTopN < 0 {
+		return nil, fmt.Errorf("invalid CACHE_WARMER_TOP_N: must not be negative, got %d", warmerTopN)
+	}
+
+	warmerRefreshInterval, err := time.ParseDuration(viper.GetString("CACHE_WARMER_REFRESH_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_WARMER_REFRESH_INTERVAL: %w", err)
+	}
+	if warmerRefreshInterval <= 0 {
+		return nil, fmt.Errorf("invalid CACHE_WARMER_REFRESH_INTERVAL: must be positive, got %s", warmerRefreshInterval)
+	}
+
 	connMaxLifetime, err := time.ParseDuration(viper.GetString("DB_CONN_MAX_LIFETIME"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid DB_CONN_MAX_LIFETIME: %w", err)
 	}
 
+	slowQueryThreshold, err := time.ParseDuration(viper.GetString("DB_SLOW_QUERY_THRESHOLD"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DB_SLOW_QUERY_THRESHOLD: %w", err)
+	}
+
+	serializationRetries := viper.GetInt("DB_SERIALIZATION_RETRIES")
+	if serializationRetries < 0 {
+		return nil, fmt.Errorf("invalid DB_SERIALIZATION_RETRIES: must not be negative, got %d", serializationRetries)
+	}
+
+	idStrategy := strings.ToLower(viper.GetString("DB_ID_STRATEGY"))
+	if !idgen.IsValidStrategy(idStrategy) {
+		return nil, fmt.Errorf("invalid DB_ID_STRATEGY: %q (v4 or v7)", idStrategy)
+	}
+
+	dbConnectRetry, err := loadConnectRetry("DB")
+	if err != nil {
+		return nil, err
+	}
+
+	redisConnectRetry, err := loadConnectRetry("REDIS")
+	if err != nil {
+		return nil, err
+	}
+
+	ackWait, err := time.ParseDuration(viper.GetString("NATS_ACK_WAIT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS_ACK_WAIT: %w", err)
+	}
+
+	publishDrainTimeout, err := time.ParseDuration(viper.GetString("NATS_PUBLISH_DRAIN_TIMEOUT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid NATS_PUBLISH_DRAIN_TIMEOUT: %w", err)
+	}
+
+	// The prefix becomes subject tokens and part of the stream name, so keep to characters valid in both
+	subjectPrefix := viper.GetString("NATS_SUBJECT_PREFIX")
+	if subjectPrefix != "" && !subjectPrefixPattern.MatchString(subjectPrefix) {
+		return nil, fmt.Errorf("invalid NATS_SUBJECT_PREFIX: %q (letters, digits, - and _ in dot-separated tokens)", subjectPrefix)
+	}
+
+	eventTransport := viper.GetString("EVENT_TRANSPORT")
+	switch eventTransport {
+	case EventTransportNATS, EventTransportPostgres, EventTransportNoop, EventTransportInMemory:
+	default:
+		return nil, fmt.Errorf("invalid EVENT_TRANSPORT: %q (nats, postgres, noop or inmemory)", eventTransport)
+	}
+
+	eventPublishTimeout, err := time.ParseDuration(viper.GetString("EVENT_PUBLISH_TIMEOUT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENT_PUBLISH_TIMEOUT: %w", err)
+	}
+
+	publishDeadline := viper.GetString("EVENT_PUBLISH_DEADLINE")
+	switch publishDeadline {
+	case PublishDeadlineDetached, PublishDeadlineRequest:
+	default:
+		return nil, fmt.Errorf("invalid EVENT_PUBLISH_DEADLINE: %q (detached or request)", publishDeadline)
+	}
+
+	eventBreakerThreshold := viper.GetInt("EVENT_BREAKER_THRESHOLD")
+	if eventBreakerThreshold < 0 {
+		return nil, fmt.Errorf("invalid EVENT_BREAKER_THRESHOLD: must not be negative, got %d", eventBreakerThreshold)
+	}
+
+	eventBreakerCooldown, err := time.ParseDuration(viper.GetString("EVENT_BREAKER_COOLDOWN"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENT_BREAKER_COOLDOWN: %w", err)
+	}
+	if eventBreakerCooldown <= 0 {
+		return nil, fmt.Errorf("invalid EVENT_BREAKER_COOLDOWN: must be positive, got %s", eventBreakerCooldown)
+	}
+
+	eventBufferMaxSize := viper.GetInt64("EVENT_BUFFER_MAX_SIZE")
+	if eventBufferMaxSize < 0 {
+		return nil, fmt.Errorf("invalid EVENT_BUFFER_MAX_SIZE: must not be negative, got %d", eventBufferMaxSize)
+	}
+
+	outboxPollInterval, err := time.ParseDuration(viper.GetString("EVENT_OUTBOX_POLL_INTERVAL"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENT_OUTBOX_POLL_INTERVAL: %w", err)
+	}
+	if outboxPollInterval <= 0 {
+		return nil, fmt.Errorf("invalid EVENT_OUTBOX_POLL_INTERVAL: must be positive, got %s", outboxPollInterval)
+	}
+
+	outboxBatchSize := viper.GetInt("EVENT_OUTBOX_BATCH_SIZE")
+	if outboxBatchSize <= 0 {
+		return nil, fmt.Errorf("invalid EVENT_OUTBOX_BATCH_SIZE: must be positive, got %d", outboxBatchSize)
+	}
+
+	outboxRetention, err := time.ParseDuration(viper.GetString("EVENT_OUTBOX_RETENTION"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENT_OUTBOX_RETENTION: %w", err)
+	}
+	if outboxRetention <= 0 {
+		return nil, fmt.Errorf("invalid EVENT_OUTBOX_RETENTION: must be positive, got %s", outboxRetention)
+	}
+
+	outboxLagThreshold, err := time.ParseDuration(viper.GetString("EVENT_OUTBOX_LAG_DEGRADED_THRESHOLD"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVENT_OUTBOX_LAG_DEGRADED_THRESHOLD: %w", err)
+	}
+	if outboxLagThreshold <= 0 {
+		return nil, fmt.Errorf("invalid EVENT_OUTBOX_LAG_DEGRADED_THRESHOLD: must be positive, got %s", outboxLagThreshold)
+	}
+
 	productRatingTTL, err := time.ParseDuration(viper.GetString("CACHE_TTL_PRODUCT_RATING"))
 	if err != nil {
 		return nil, fmt.Errorf("invalid CACHE_TTL_PRODUCT_RATING: %w", err)
@@ -118,43 +661,379 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid CACHE_TTL_REVIEWS_LIST: %w", err)
 	}
 
+	recentReviewsTTL, err := time.ParseDuration(viper.GetString("CACHE_TTL_RECENT_REVIEWS"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_TTL_RECENT_REVIEWS: %w", err)
+	}
+
+	ttlJitter := viper.GetFloat64("CACHE_TTL_JITTER")
+	if ttlJitter < 0 || ttlJitter >= 1 {
+		return nil, fmt.Errorf("invalid CACHE_TTL_JITTER: must be in [0, 1), got %v", ttlJitter)
+	}
+
+	cacheBreakerThreshold := viper.GetInt("CACHE_BREAKER_THRESHOLD")
+	if cacheBreakerThreshold < 0 {
+		return nil, fmt.Errorf("invalid CACHE_BREAKER_THRESHOLD: must not be negative, got %d", cacheBreakerThreshold)
+	}
+
+	cacheBreakerCooldown, err := time.ParseDuration(viper.GetString("CACHE_BREAKER_COOLDOWN"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid CACHE_BREAKER_COOLDOWN: %w", err)
+	}
+	if cacheBreakerCooldown <= 0 {
+		return nil, fmt.Errorf("invalid CACHE_BREAKER_COOLDOWN: must be positive, got %s", cacheBreakerCooldown)
+	}
+
+	cacheCodec := strings.ToLower(viper.GetString("CACHE_CODEC"))
+	if cacheCodec != "json" && cacheCodec != "gob" {
+		return nil, fmt.Errorf("invalid CACHE_CODEC: %q (json or gob)", cacheCodec)
+	}
+
+	cacheCompressThreshold := viper.GetInt("CACHE_COMPRESS_THRESHOLD")
+	if cacheCompressThreshold < 0 {
+		return nil, fmt.Errorf("invalid CACHE_COMPRESS_THRESHOLD: must not be negative, got %d", cacheCompressThreshold)
+	}
+
+	maxRequestBodySize := viper.GetInt64("MAX_REQUEST_BODY_SIZE")
+	if maxRequestBodySize <= 0 {
+		return nil, fmt.Errorf("invalid MAX_REQUEST_BODY_SIZE: must be positive, got %d", maxRequestBodySize)
+	}
+
+	maxBatchItems := viper.GetInt("MAX_BATCH_ITEMS")
+	if maxBatchItems <= 0 {
+		return nil, fmt.Errorf("invalid MAX_BATCH_ITEMS: must be positive, got %d", maxBatchItems)
+	}
+
+	trustedProxies, err := parseCIDRList(viper.GetString("TRUSTED_PROXIES"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
+	adminPort := viper.GetString("ADMIN_PORT")
+	if adminPort != "" && adminPort == viper.GetString("SERVER_PORT") {
+		return nil, fmt.Errorf("invalid ADMIN_PORT: must differ from SERVER_PORT %s", adminPort)
+	}
+
+	// The monolith runs the worker next to the API, so its metrics listener can't share their ports
+	metricsPort := viper.GetString("WORKER_METRICS_PORT")
+	if metricsPort != "" && (metricsPort == viper.GetString("SERVER_PORT") || metricsPort == adminPort) {
+		return nil, fmt.Errorf("invalid WORKER_METRICS_PORT: must differ from SERVER_PORT and ADMIN_PORT, got %s", metricsPort)
+	}
+
+	httpClientTimeout, err := time.ParseDuration(viper.GetString("HTTP_CLIENT_TIMEOUT"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_CLIENT_TIMEOUT: %w", err)
+	}
+
+	httpClientRetryBackoff, err := time.ParseDuration(viper.GetString("HTTP_CLIENT_RETRY_BACKOFF"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid HTTP_CLIENT_RETRY_BACKOFF: %w", err)
+	}
+
+	httpClientMaxRetries := viper.GetInt("HTTP_CLIENT_MAX_RETRIES")
+	if httpClientMaxRetries < 0 {
+		return nil, fmt.Errorf("invalid HTTP_CLIENT_MAX_RETRIES: must not be negative, got %d", httpClientMaxRetries)
+	}
+
+	defaultReviewSource := viper.GetString("REVIEW_DEFAULT_SOURCE")
+	if !domain.IsValidReviewSource(defaultReviewSource) {
+		return nil, fmt.Errorf("invalid REVIEW_DEFAULT_SOURCE: %q", defaultReviewSource)
+	}
+
+	sanitizeReviewText := viper.GetString("SANITIZE_REVIEW_TEXT")
+	if !sanitize.IsValidMode(sanitizeReviewText) {
+		return nil, fmt.Errorf("invalid SANITIZE_REVIEW_TEXT: %q (off, store or output)", sanitizeReviewText)
+	}
+
+	reviewThrottleLimit := viper.GetInt("REVIEW_THROTTLE_LIMIT")
+	if reviewThrottleLimit < 0 {
+		return nil, fmt.Errorf("invalid REVIEW_THROTTLE_LIMIT: must not be negative, got %d", reviewThrottleLimit)
+	}
+
+	reviewThrottleWindow, err := time.ParseDuration(viper.GetString("REVIEW_THROTTLE_WINDOW"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid REVIEW_THROTTLE_WINDOW: %w", err)
+	}
+	if reviewThrottleWindow <= 0 {
+		return nil, fmt.Errorf("invalid REVIEW_THROTTLE_WINDOW: must be positive, got %s", reviewThrottleWindow)
+	}
+
+	reviewFlagThreshold := viper.GetInt("REVIEW_FLAG_THRESHOLD")
+	if reviewFlagThreshold < 0 {
+		return nil, fmt.Errorf("invalid REVIEW_FLAG_THRESHOLD: must not be negative, got %d", reviewFlagThreshold)
+	}
+
+	compareMaxIDs := viper.GetInt("PRODUCTS_COMPARE_MAX_IDS")
+	if compareMaxIDs <= 0 {
+		return nil, fmt.Errorf("invalid PRODUCTS_COMPARE_MAX_IDS: must be positive, got %d", compareMaxIDs)
+	}
+
+	minReviewsForRating := viper.GetInt("MIN_REVIEWS_FOR_RATING")
+	if minReviewsForRating < 0 {
+		return nil, fmt.Errorf("invalid MIN_REVIEWS_FOR_RATING: must not be negative, got %d", minReviewsForRating)
+	}
+
+	productPagination, err := loadPagination("PRODUCTS")
+	if err != nil {
+		return nil, err
+	}
+
+	reviewPagination, err := loadPagination("REVIEWS")
+	if err != nil {
+		return nil, err
+	}
+
 	config := &Config{
-		Env: viper.GetString("ENV"),
+		Env:      env,
+		File:     configFile,
+		LogLevel: logLevel,
 		Server: ServerConfig{
-			Port:            viper.GetString("SERVER_PORT"),
-			ReadTimeout:     readTimeout,
-			WriteTimeout:    writeTimeout,
-			ShutdownTimeout: shutdownTimeout,
+			Port:               viper.GetString("SERVER_PORT"),
+			ReadTimeout:        readTimeout,
+			WriteTimeout:       writeTimeout,
+			ShutdownTimeout:    shutdownTimeout,
+			EnablePprof:        viper.GetBool("ENABLE_PPROF"),
+			AdminPort:          adminPort,
+			MaxRequestBodySize: maxRequestBodySize,
+			MaxBatchItems:      maxBatchItems,
+			TrustedProxies:     trustedProxies,
+			StrictContentType:  viper.GetBool("STRICT_CONTENT_TYPE"),
 		},
 		Database: DatabaseConfig{
-			Host:            viper.GetString("DB_HOST"),
-			Port:            viper.GetString("DB_PORT"),
-			User:            viper.GetString("DB_USER"),
-			Password:        viper.GetString("DB_PASSWORD"),
-			Name:            viper.GetString("DB_NAME"),
-			SSLMode:         viper.GetString("DB_SSLMODE"),
-			MaxOpenConns:    viper.GetInt("DB_MAX_OPEN_CONNS"),
-			MaxIdleConns:    viper.GetInt("DB_MAX_IDLE_CONNS"),
-			ConnMaxLifetime: connMaxLifetime,
+			Host:                 viper.GetString("DB_HOST"),
+			Port:                 viper.GetString("DB_PORT"),
+			User:                 viper.GetString("DB_USER"),
+			Password:             viper.GetString("DB_PASSWORD"),
+			Name:                 viper.GetString("DB_NAME"),
+			SSLMode:              viper.GetString("DB_SSLMODE"),
+			MaxOpenConns:         viper.GetInt("DB_MAX_OPEN_CONNS"),
+			MaxIdleConns:         viper.GetInt("DB_MAX_IDLE_CONNS"),
+			ConnMaxLifetime:      connMaxLifetime,
+			SlowQueryThreshold:   slowQueryThreshold,
+			SerializationRetries: serializationRetries,
+			IDStrategy:           idStrategy,
+			ConnectRetry:         dbConnectRetry,
 		},
 		Redis: RedisConfig{
-			Host:     viper.GetString("REDIS_HOST"),
-			Port:     viper.GetString("REDIS_PORT"),
-			Password: viper.GetString("REDIS_PASSWORD"),
-			DB:       viper.GetInt("REDIS_DB"),
+			Host:         viper.GetString("REDIS_HOST"),
+			Port:         viper.GetString("REDIS_PORT"),
+			Password:     viper.GetString("REDIS_PASSWORD"),
+			DB:           viper.GetInt("REDIS_DB"),
+			ConnectRetry: redisConnectRetry,
 		},
 		NATS: NATSConfig{
-			URL: viper.GetString("NATS_URL"),
+			URL:                  viper.GetString("NATS_URL"),
+			AckWait:              ackWait,
+			LagDegradedThreshold: viper.GetUint64("NATS_LAG_DEGRADED_THRESHOLD"),
+			PublishDrainTimeout:  publishDrainTimeout,
+			SubjectPrefix:        subjectPrefix,
+		},
+		Events: EventsConfig{
+			Transport:        eventTransport,
+			PublishTimeout:   eventPublishTimeout,
+			PublishDeadline:  publishDeadline,
+			BreakerThreshold: eventBreakerThreshold,
+			BreakerCooldown:  eventBreakerCooldown,
+			BufferMaxSize:    eventBufferMaxSize,
+
+			Outbox:                     viper.GetBool("EVENT_OUTBOX"),
+			OutboxPollInterval:         outboxPollInterval,
+			OutboxBatchSize:            outboxBatchSize,
+			OutboxRetention:            outboxRetention,
+			OutboxEmbeddedRelay:        viper.GetBool("EVENT_OUTBOX_EMBEDDED_RELAY"),
+			OutboxLagDegradedThreshold: outboxLagThreshold,
 		},
 		Cache: CacheConfig{
-			ProductRatingTTL: productRatingTTL,
-			ReviewsListTTL:   reviewsListTTL,
+			ProductRatingTTL:  productRatingTTL,
+			ReviewsListTTL:    reviewsListTTL,
+			RecentReviewsTTL:  recentReviewsTTL,
+			TTLJitter:         ttlJitter,
+			BreakerThreshold:  cacheBreakerThreshold,
+			BreakerCooldown:   cacheBreakerCooldown,
+			Codec:             cacheCodec,
+			CompressThreshold: cacheCompressThreshold,
+		},
+		Worker: WorkerConfig{
+			WarmRatingCache: viper.GetBool("WORKER_WARM_RATING_CACHE"),
+			DebounceWindow:  debounceWindow,
+			ShutdownTimeout: workerShutdownTimeout,
+			RetentionPeriod: retentionPeriod,
+			PurgeInterval:   purgeInterval,
+			PurgeBatchSize:  purgeBatchSize,
+			MetricsPort:     metricsPort,
+		},
+		Admin: AdminConfig{
+			APIKey: viper.GetString("ADMIN_API_KEY"),
+		},
+		Review: ReviewConfig{
+			DefaultSource:      defaultReviewSource,
+			SanitizeText:       sanitizeReviewText,
+			DetectLanguage:     viper.GetBool("DETECT_REVIEW_LANGUAGE"),
+			ThrottleLimit:      reviewThrottleLimit,
+			ThrottleWindow:     reviewThrottleWindow,
+			FlagThreshold:      reviewFlagThreshold,
+			MaxRating:          maxRating,
+			RatingRoundingMode: ratingRoundingMode,
+			DefaultSort:        defaultReviewSort,
+			HalfStarRatings:    viper.GetBool("HALF_STAR_RATINGS"),
+			AllowedRatings:     allowedRatings,
+			Pagination:         reviewPagination,
+		},
+		Product: ProductConfig{
+			EnforceUniqueName:   viper.GetBool("ENFORCE_UNIQUE_PRODUCT_NAME"),
+			CompareMaxIDs:       compareMaxIDs,
+			MinReviewsForRating: minReviewsForRating,
+			Pagination:          productPagination,
+		},
+		Notifier: NotifierConfig{
+			WebhookURL: viper.GetString("NOTIFIER_WEBHOOK_URL"),
+			HTTPClient: HTTPClientConfig{
+				Timeout:      httpClientTimeout,
+				MaxRetries:   httpClientMaxRetries,
+				RetryBackoff: httpClientRetryBackoff,
+				MaxIdleConns: viper.GetInt("HTTP_CLIENT_MAX_IDLE_CONNS"),
+			},
+		},
+		Warmer: WarmerConfig{
+			Delay:           warmerDelay,
+			TopN:            warmerTopN,
+			RefreshInterval: warmerRefreshInterval,
 		},
 	}
 
 	return config, nil
 }
 
+// readConfigFile merges the YAML or TOML file at path under the env vars, which viper
+// consults first. Keys are the env var names in any case (db_host: db). An empty path keeps
+// env-only configuration.
+func readConfigFile(path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml", ".toml":
+	default:
+		return "", fmt.Errorf("invalid CONFIG_FILE: %q must end in .yaml, .yml or .toml", path)
+	}
+
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		return "", fmt.Errorf("invalid CONFIG_FILE: %w", err)
+	}
+	return path, nil
+}
+
+// loadPagination reads <prefix>_PAGE_SIZE_DEFAULT and <prefix>_PAGE_SIZE_MAX
+func loadPagination(prefix string) (PaginationConfig, error) {
+	cfg := PaginationConfig{
+		DefaultLimit: viper.GetInt(prefix + "_PAGE_SIZE_DEFAULT"),
+		MaxLimit:     viper.GetInt(prefix + "_PAGE_SIZE_MAX"),
+	}
+
+	if cfg.MaxLimit <= 0 || cfg.MaxLimit > domain.MaxPageSize {
+		return PaginationConfig{}, fmt.Errorf("invalid %s_PAGE_SIZE_MAX: must be between 1 and %d, got %d", prefix, domain.MaxPageSize, cfg.MaxLimit)
+	}
+	if cfg.DefaultLimit <= 0 || cfg.DefaultLimit > cfg.MaxLimit {
+		return PaginationConfig{}, fmt.Errorf("invalid %s_PAGE_SIZE_DEFAULT: must be between 1 and %s_PAGE_SIZE_MAX (%d), got %d", prefix, prefix, cfg.MaxLimit, cfg.DefaultLimit)
+	}
+
+	return cfg, nil
+}
+
+// loadConnectRetry reads <prefix>_CONNECT_MAX_RETRIES, <prefix>_CONNECT_RETRY_DELAY,
+// <prefix>_CONNECT_BACKOFF and <prefix>_CONNECT_MAX_RETRY_DELAY
+func loadConnectRetry(prefix string) (ConnectRetryConfig, error) {
+	cfg := ConnectRetryConfig{
+		MaxRetries: viper.GetInt(prefix + "_CONNECT_MAX_RETRIES"),
+		Backoff:    viper.GetString(prefix + "_CONNECT_BACKOFF"),
+	}
+
+	if cfg.MaxRetries <= 0 {
+		return ConnectRetryConfig{}, fmt.Errorf("invalid %s_CONNECT_MAX_RETRIES: must be positive, got %d", prefix, cfg.MaxRetries)
+	}
+
+	switch cfg.Backoff {
+	case ConnectBackoffFixed, ConnectBackoffExponential:
+	default:
+		return ConnectRetryConfig{}, fmt.Errorf("invalid %s_CONNECT_BACKOFF: %q (fixed or exponential)", prefix, cfg.Backoff)
+	}
+
+	var err error
+	cfg.Delay, err = time.ParseDuration(viper.GetString(prefix + "_CONNECT_RETRY_DELAY"))
+	if err != nil {
+		return ConnectRetryConfig{}, fmt.Errorf("invalid %s_CONNECT_RETRY_DELAY: %w", prefix, err)
+	}
+	if cfg.Delay < 0 {
+		return ConnectRetryConfig{}, fmt.Errorf("invalid %s_CONNECT_RETRY_DELAY: must not be negative, got %s", prefix, cfg.Delay)
+	}
+
+	cfg.MaxDelay, err = time.ParseDuration(viper.GetString(prefix + "_CONNECT_MAX_RETRY_DELAY"))
+	if err != nil {
+		return ConnectRetryConfig{}, fmt.Errorf("invalid %s_CONNECT_MAX_RETRY_DELAY: %w", prefix, err)
+	}
+	if cfg.MaxDelay < cfg.Delay {
+		return ConnectRetryConfig{}, fmt.Errorf("invalid %s_CONNECT_MAX_RETRY_DELAY: must be at least %s_CONNECT_RETRY_DELAY (%s), got %s", prefix, prefix, cfg.Delay, cfg.MaxDelay)
+	}
+
+	return cfg, nil
+}
+
+// parseRatingList parses a comma-separated list of ratings. Each must be a whole or half
+// star of at least 1, as stored ratings and distribution buckets assume; whether they fit
+// MAX_RATING is checked by Validate.
+func parseRatingList(value string) ([]float64, error) {
+	var ratings []float64
+	for item := range strings.SplitSeq(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		rating, err := strconv.ParseFloat(item, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", item)
+		}
+		if rating < 1 || rating*2 != math.Trunc(rating*2) {
+			return nil, fmt.Errorf("%q must be a whole or half star of at least 1", item)
+		}
+		ratings = append(ratings, rating)
+	}
+	return ratings, nil
+}
+
+// parseCIDRList parses comma-separated CIDRs; a bare IP is treated as a single-address network
+func parseCIDRList(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		if !strings.Contains(item, "/") {
+			ip := net.ParseIP(item)
+			if ip == nil {
+				return nil, fmt.Errorf("%q is not an IP or CIDR", item)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(item)
+		if err != nil {
+			return nil, err
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
 // GetDSN returns the PostgreSQL connection string
 func (c *Config) GetDSN() string {
 	return fmt.Sprintf(
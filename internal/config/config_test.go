@@ -0,0 +1,370 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadFresh runs Load against a clean viper, since Load configures the global instance
+func loadFresh(t *testing.T) (*Config, error) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	return Load()
+}
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad_WithoutConfigFile(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+
+	require.NoError(t, err)
+	assert.Empty(t, cfg.File)
+	assert.Equal(t, "localhost", cfg.Database.Host)
+}
+
+func TestLoad_YAMLConfigFile(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+db_host: db.internal
+DB_PORT: "6543"
+cache_ttl_product_rating: 10m
+max_rating: 10
+`)
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("DB_PORT", "7654")
+
+	cfg, err := loadFresh(t)
+
+	require.NoError(t, err)
+	assert.Equal(t, path, cfg.File)
+	assert.Equal(t, "db.internal", cfg.Database.Host)
+	assert.Equal(t, "7654", cfg.Database.Port, "env vars should take precedence over the file")
+	assert.Equal(t, "10m0s", cfg.Cache.ProductRatingTTL.String())
+	assert.Equal(t, 10, cfg.Review.MaxRating)
+	assert.Equal(t, "6379", cfg.Redis.Port, "keys missing from the file should keep their defaults")
+}
+
+func TestLoad_TOMLConfigFile(t *testing.T) {
+	path := writeConfigFile(t, "config.toml", `
+redis_host = "cache.internal"
+event_transport = "postgres"
+`)
+	t.Setenv("CONFIG_FILE", path)
+
+	cfg, err := loadFresh(t)
+
+	require.NoError(t, err)
+	assert.Equal(t, "cache.internal", cfg.Redis.Host)
+	assert.Equal(t, EventTransportPostgres, cfg.Events.Transport)
+}
+
+func TestLoad_ConfigFileValuesAreValidated(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "event_transport: kafka\n")
+	t.Setenv("CONFIG_FILE", path)
+
+	_, err := loadFresh(t)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid EVENT_TRANSPORT")
+}
+
+func TestLoad_DefaultReviewSort(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, "newest", cfg.Review.DefaultSort)
+
+	t.Setenv("DEFAULT_REVIEW_SORT", "highest_rating")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, "highest_rating", cfg.Review.DefaultSort)
+
+	t.Setenv("DEFAULT_REVIEW_SORT", "rating")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid DEFAULT_REVIEW_SORT")
+}
+
+func TestLoad_PublishDeadline(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, PublishDeadlineDetached, cfg.Events.PublishDeadline)
+
+	t.Setenv("EVENT_PUBLISH_DEADLINE", "request")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, PublishDeadlineRequest, cfg.Events.PublishDeadline)
+
+	t.Setenv("EVENT_PUBLISH_DEADLINE", "blocking")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid EVENT_PUBLISH_DEADLINE")
+}
+
+func TestLoad_AllowedRatings(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Review.AllowedRatings)
+
+	t.Setenv("ALLOWED_RATINGS", "1, 3,5")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 3, 5}, cfg.Review.AllowedRatings)
+
+	for _, value := range []string{"1,three", "0,1", "1,2.3"} {
+		t.Setenv("ALLOWED_RATINGS", value)
+		_, err = loadFresh(t)
+		require.Error(t, err, value)
+		assert.Contains(t, err.Error(), "invalid ALLOWED_RATINGS")
+	}
+}
+
+func TestLoad_ConnectRetry(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	defaults := ConnectRetryConfig{MaxRetries: 10, Delay: 2 * time.Second, Backoff: ConnectBackoffFixed, MaxDelay: 30 * time.Second}
+	assert.Equal(t, defaults, cfg.Database.ConnectRetry)
+	assert.Equal(t, defaults, cfg.Redis.ConnectRetry)
+
+	t.Setenv("REDIS_CONNECT_MAX_RETRIES", "20")
+	t.Setenv("REDIS_CONNECT_BACKOFF", "exponential")
+	t.Setenv("REDIS_CONNECT_RETRY_DELAY", "500ms")
+	t.Setenv("REDIS_CONNECT_MAX_RETRY_DELAY", "10s")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, ConnectRetryConfig{MaxRetries: 20, Delay: 500 * time.Millisecond, Backoff: ConnectBackoffExponential, MaxDelay: 10 * time.Second}, cfg.Redis.ConnectRetry)
+	assert.Equal(t, defaults, cfg.Database.ConnectRetry, "each dependency is configured separately")
+
+	tests := map[string]string{
+		"DB_CONNECT_MAX_RETRIES":     "0",
+		"DB_CONNECT_BACKOFF":         "linear",
+		"DB_CONNECT_RETRY_DELAY":     "-1s",
+		"DB_CONNECT_MAX_RETRY_DELAY": "1s",
+	}
+	for key, value := range tests {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			_, err := loadFresh(t)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid "+key)
+		})
+	}
+}
+
+func TestLoad_CacheBreaker(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.Cache.BreakerThreshold)
+	assert.Equal(t, 10*time.Second, cfg.Cache.BreakerCooldown)
+
+	t.Setenv("CACHE_BREAKER_THRESHOLD", "-1")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid CACHE_BREAKER_THRESHOLD")
+
+	t.Setenv("CACHE_BREAKER_THRESHOLD", "0")
+	t.Setenv("CACHE_BREAKER_COOLDOWN", "0s")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid CACHE_BREAKER_COOLDOWN")
+}
+
+func TestLoad_IDStrategy(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, "v4", cfg.Database.IDStrategy, "random IDs stay the default")
+
+	t.Setenv("DB_ID_STRATEGY", "V7")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, "v7", cfg.Database.IDStrategy)
+
+	t.Setenv("DB_ID_STRATEGY", "v1")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid DB_ID_STRATEGY")
+}
+
+func TestLoad_WorkerMetricsPort(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.Worker.MetricsPort, "metrics are opt-in")
+
+	t.Setenv("WORKER_METRICS_PORT", "9091")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, "9091", cfg.Worker.MetricsPort)
+
+	t.Setenv("ADMIN_PORT", "9091")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid WORKER_METRICS_PORT")
+}
+
+func TestLoad_CacheCodec(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, "json", cfg.Cache.Codec, "readable entries by default")
+
+	t.Setenv("CACHE_CODEC", "gob")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, "gob", cfg.Cache.Codec)
+
+	t.Setenv("CACHE_CODEC", "msgpack")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid CACHE_CODEC")
+}
+
+func TestLoad_CacheCompressThreshold(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Zero(t, cfg.Cache.CompressThreshold, "compression is opt-in")
+
+	t.Setenv("CACHE_COMPRESS_THRESHOLD", "8192")
+	cfg, err = loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, 8192, cfg.Cache.CompressThreshold)
+
+	t.Setenv("CACHE_COMPRESS_THRESHOLD", "-1")
+	_, err = loadFresh(t)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid CACHE_COMPRESS_THRESHOLD")
+}
+
+func TestLoad_EventBreaker(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.Equal(t, 5, cfg.Events.BreakerThreshold)
+	assert.Equal(t, 10*time.Second, cfg.Events.BreakerCooldown)
+	assert.Zero(t, cfg.Events.BufferMaxSize, "buffering is opt-in")
+
+	for key, value := range map[string]string{
+		"EVENT_BREAKER_THRESHOLD": "-1",
+		"EVENT_BREAKER_COOLDOWN":  "0s",
+		"EVENT_BUFFER_MAX_SIZE":   "-1",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			_, err := loadFresh(t)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid "+key)
+		})
+	}
+}
+
+func TestLoad_EventOutbox(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+	assert.False(t, cfg.Events.Outbox, "the outbox is opt-in")
+	assert.Equal(t, time.Second, cfg.Events.OutboxPollInterval)
+	assert.Equal(t, 100, cfg.Events.OutboxBatchSize)
+	assert.Equal(t, 24*time.Hour, cfg.Events.OutboxRetention)
+	assert.True(t, cfg.Events.OutboxEmbeddedRelay)
+	assert.Equal(t, time.Minute, cfg.Events.OutboxLagDegradedThreshold)
+
+	for key, value := range map[string]string{
+		"EVENT_OUTBOX_POLL_INTERVAL":          "0s",
+		"EVENT_OUTBOX_BATCH_SIZE":             "0",
+		"EVENT_OUTBOX_RETENTION":              "forever",
+		"EVENT_OUTBOX_LAG_DEGRADED_THRESHOLD": "-1m",
+	} {
+		t.Run(key, func(t *testing.T) {
+			t.Setenv(key, value)
+			_, err := loadFresh(t)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "invalid "+key)
+		})
+	}
+}
+
+func TestConnectRetryConfig_Wait(t *testing.T) {
+	fixed := ConnectRetryConfig{Delay: time.Second, Backoff: ConnectBackoffFixed, MaxDelay: 5 * time.Second}
+	exponential := ConnectRetryConfig{Delay: time.Second, Backoff: ConnectBackoffExponential, MaxDelay: 5 * time.Second}
+
+	for attempt := range 4 {
+		assert.Equal(t, time.Second, fixed.Wait(attempt))
+	}
+
+	assert.Equal(t, time.Second, exponential.Wait(0))
+	assert.Equal(t, 2*time.Second, exponential.Wait(1))
+	assert.Equal(t, 4*time.Second, exponential.Wait(2))
+	assert.Equal(t, 5*time.Second, exponential.Wait(3), "capped at MaxDelay")
+	assert.Equal(t, 5*time.Second, exponential.Wait(200), "no overflow on long waits")
+}
+
+func TestLoad_InvalidConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    func(t *testing.T) string
+		wantErr string
+	}{
+		{
+			name: "missing file",
+			path: func(t *testing.T) string {
+				return filepath.Join(t.TempDir(), "missing.yaml")
+			},
+			wantErr: "invalid CONFIG_FILE",
+		},
+		{
+			name: "unsupported format",
+			path: func(t *testing.T) string {
+				return writeConfigFile(t, "config.json", "{}")
+			},
+			wantErr: "must end in .yaml, .yml or .toml",
+		},
+		{
+			name: "malformed YAML",
+			path: func(t *testing.T) string {
+				return writeConfigFile(t, "config.yml", "db_host: [unterminated\n")
+			},
+			wantErr: "invalid CONFIG_FILE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", tt.path(t))
+
+			_, err := loadFresh(t)
+
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
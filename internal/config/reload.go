@@ -0,0 +1,121 @@
+package config
+
+import (
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// ReloadableKeys are the settings running services apply on SIGHUP, as LogFields keys.
+// A binary applies the ones it uses; every other setting needs a restart.
+var ReloadableKeys = []string{
+	"LOG_LEVEL",
+	"WORKER_DEBOUNCE_WINDOW",
+	"REVIEW_THROTTLE_LIMIT",
+	"REVIEW_THROTTLE_WINDOW",
+	"CACHE_TTL_PRODUCT_RATING",
+	"CACHE_TTL_REVIEWS_LIST",
+	"CACHE_TTL_RECENT_REVIEWS",
+}
+
+// WatchReload reloads the configuration on every SIGHUP, applies LOG_LEVEL and passes it
+// to apply (which may be nil) to push the other ReloadableKeys into the running services.
+// Env vars can't change under a running process, so only edits to CONFIG_FILE take
+// effect. A config that fails to load or validate is logged and the running settings are
+// kept. The returned func stops watching.
+func WatchReload(current *Config, log *logger.Logger, apply func(next *Config)) (stop func()) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		for {
+			select {
+			case <-done:
+				return
+			case <-sighup:
+				next := reload(current, log)
+				if next == nil {
+					continue
+				}
+				if err := logger.SetLevel(next.LogLevel); err != nil {
+					log.Error("Failed to apply LOG_LEVEL", err)
+				}
+				if apply != nil {
+					apply(next)
+				}
+				current = next
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sighup)
+		close(done)
+		<-stopped
+	}
+}
+
+// reload loads and validates the configuration, logging what changed; nil means keep current
+func reload(current *Config, log *logger.Logger) *Config {
+	next, err := Load()
+	if err == nil {
+		err = next.Validate()
+	}
+	if err != nil {
+		log.Error("Config reload failed, keeping the running settings", err)
+		return nil
+	}
+
+	// Settings that need a restart are kept at their running values, so later reloads
+	// compare against what is actually running
+	applied, changed, ignored := current.merge(next)
+	if len(ignored) > 0 {
+		log.WithFields(map[string]any{
+			"settings": ignored,
+		}).Warn("Config reload ignored settings that need a restart")
+	}
+	if len(changed) == 0 {
+		log.Info("Config reloaded, no live settings changed")
+		return nil
+	}
+
+	log.WithFields(map[string]any{
+		"settings": changed,
+	}).Info("Config reloaded")
+	return applied
+}
+
+// merge returns current with next's reloadable settings, the reloadable keys that changed
+// and the other keys that changed but can't be applied without a restart
+func (c *Config) merge(next *Config) (merged *Config, changed, ignored []string) {
+	before, after := c.LogFields(), next.LogFields()
+	for key, value := range after {
+		if before[key] == value {
+			continue
+		}
+		if slices.Contains(ReloadableKeys, key) {
+			changed = append(changed, key)
+		} else {
+			ignored = append(ignored, key)
+		}
+	}
+	slices.Sort(changed)
+	slices.Sort(ignored)
+
+	merged = new(Config)
+	*merged = *c
+	merged.LogLevel = next.LogLevel
+	merged.Worker.DebounceWindow = next.Worker.DebounceWindow
+	merged.Review.ThrottleLimit = next.Review.ThrottleLimit
+	merged.Review.ThrottleWindow = next.Review.ThrottleWindow
+	merged.Cache.ProductRatingTTL = next.Cache.ProductRatingTTL
+	merged.Cache.ReviewsListTTL = next.Cache.ReviewsListTTL
+	merged.Cache.RecentReviewsTTL = next.Cache.RecentReviewsTTL
+	return merged, changed, ignored
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+func TestConfig_Merge(t *testing.T) {
+	current := validConfig()
+	current.LogLevel = "info"
+	next := validConfig()
+	next.LogLevel = "debug"
+	next.Cache.ProductRatingTTL = 10 * time.Minute
+	next.Database.Host = "other-db"
+	next.Database.MaxOpenConns = 50
+
+	merged, changed, ignored := current.merge(next)
+
+	assert.Equal(t, []string{"CACHE_TTL_PRODUCT_RATING", "LOG_LEVEL"}, changed)
+	assert.Equal(t, []string{"DB_HOST", "DB_MAX_OPEN_CONNS"}, ignored)
+	assert.Equal(t, "debug", merged.LogLevel)
+	assert.Equal(t, 10*time.Minute, merged.Cache.ProductRatingTTL)
+	assert.Equal(t, current.Database, merged.Database, "settings needing a restart should keep their running values")
+	assert.Equal(t, 5*time.Minute, current.Cache.ProductRatingTTL, "current should not be modified")
+}
+
+func TestWatchReload(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", "log_level: info\ncache_ttl_product_rating: 5m\n")
+	t.Setenv("CONFIG_FILE", path)
+	cfg, err := loadFresh(t)
+	require.NoError(t, err)
+
+	applied := make(chan *Config, 1)
+	stop := WatchReload(cfg, logger.New("test"), func(next *Config) {
+		applied <- next
+	})
+	defer stop()
+
+	sighup := func() {
+		require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	}
+
+	t.Run("applies reloadable settings", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("log_level: info\ncache_ttl_product_rating: 10m\ndb_host: other-db\n"), 0o600))
+		sighup()
+
+		select {
+		case next := <-applied:
+			assert.Equal(t, 10*time.Minute, next.Cache.ProductRatingTTL)
+			assert.Equal(t, cfg.Database.Host, next.Database.Host, "DB_HOST needs a restart")
+		case <-time.After(5 * time.Second):
+			t.Fatal("config was not reloaded")
+		}
+	})
+
+	t.Run("keeps running settings when the file is invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("cache_ttl_product_rating: soon\n"), 0o600))
+		sighup()
+
+		select {
+		case next := <-applied:
+			t.Fatalf("invalid config was applied: %+v", next.Cache)
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// EnvDevelopment is the default ENV; it relaxes the checks that only matter for shared deployments
+const EnvDevelopment = "development"
+
+// redacted replaces secret values in logged configuration
+const redacted = "[REDACTED]"
+
+// Validate checks invariants that span several settings or that Load accepts because each
+// value parses on its own. It reports every problem at once so a broken deployment is fixed
+// in one pass rather than one restart per setting.
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	// database/sql treats a zero MaxOpenConns as unlimited and silently lowers MaxIdleConns to
+	// MaxOpenConns, so a larger idle count is a typo rather than a working setting
+	if c.Database.MaxOpenConns < 0 {
+		invalid("invalid DB_MAX_OPEN_CONNS: must not be negative, got %d", c.Database.MaxOpenConns)
+	}
+	if c.Database.MaxIdleConns < 0 {
+		invalid("invalid DB_MAX_IDLE_CONNS: must not be negative, got %d", c.Database.MaxIdleConns)
+	}
+	if c.Database.MaxOpenConns > 0 && c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		invalid("invalid DB_MAX_IDLE_CONNS: %d exceeds DB_MAX_OPEN_CONNS (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		invalid("invalid DB_CONN_MAX_LIFETIME: must not be negative, got %s", c.Database.ConnMaxLifetime)
+	}
+	if c.Env != EnvDevelopment && c.Database.Password == "" {
+		invalid("invalid DB_PASSWORD: must be set when ENV is %q", c.Env)
+	}
+
+	// Distributions have buckets for 1 to MAX_RATING only, so an allowed rating above it
+	// would be accepted but never counted
+	for _, rating := range c.Review.AllowedRatings {
+		if rating > float64(c.Review.MaxRating) {
+			invalid("invalid ALLOWED_RATINGS: %v exceeds MAX_RATING (%d)", rating, c.Review.MaxRating)
+		}
+	}
+
+	// Redis treats a zero expiration as "never expire", which would turn a TTL typo into a
+	// cache that only write invalidation ever clears
+	if c.Cache.ProductRatingTTL <= 0 {
+		invalid("invalid CACHE_TTL_PRODUCT_RATING: must be positive, got %s", c.Cache.ProductRatingTTL)
+	}
+	if c.Cache.ReviewsListTTL <= 0 {
+		invalid("invalid CACHE_TTL_REVIEWS_LIST: must be positive, got %s", c.Cache.ReviewsListTTL)
+	}
+	if c.Cache.RecentReviewsTTL <= 0 {
+		invalid("invalid CACHE_TTL_RECENT_REVIEWS: must be positive, got %s", c.Cache.RecentReviewsTTL)
+	}
+
+	if c.Server.ReadTimeout <= 0 {
+		invalid("invalid SERVER_READ_TIMEOUT: must be positive, got %s", c.Server.ReadTimeout)
+	}
+	if c.Server.WriteTimeout <= 0 {
+		invalid("invalid SERVER_WRITE_TIMEOUT: must be positive, got %s", c.Server.WriteTimeout)
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		invalid("invalid SERVER_SHUTDOWN_TIMEOUT: must be positive, got %s", c.Server.ShutdownTimeout)
+	}
+
+	if c.NATS.AckWait <= 0 {
+		invalid("invalid NATS_ACK_WAIT: must be positive, got %s", c.NATS.AckWait)
+	}
+	if c.NATS.PublishDrainTimeout <= 0 {
+		invalid("invalid NATS_PUBLISH_DRAIN_TIMEOUT: must be positive, got %s", c.NATS.PublishDrainTimeout)
+	}
+	if c.Events.PublishTimeout <= 0 {
+		invalid("invalid EVENT_PUBLISH_TIMEOUT: must be positive, got %s", c.Events.PublishTimeout)
+	}
+	// Only the JetStream publisher has a breaker to buffer behind; elsewhere the setting would
+	// silently do nothing
+	if c.Events.BufferMaxSize > 0 && c.Events.Transport != EventTransportNATS {
+		invalid("invalid EVENT_BUFFER_MAX_SIZE: buffering needs EVENT_TRANSPORT=%s, got %q", EventTransportNATS, c.Events.Transport)
+	}
+	// The outbox relay marks an event sent once Publish returns; a buffered publish returns
+	// before the event reaches NATS, so a lost buffer would lose outbox events too
+	if c.Events.BufferMaxSize > 0 && c.Events.Outbox {
+		invalid("invalid EVENT_BUFFER_MAX_SIZE: buffering can't be combined with EVENT_OUTBOX, which already keeps unsent events")
+	}
+
+	if c.Notifier.HTTPClient.Timeout <= 0 {
+		invalid("invalid HTTP_CLIENT_TIMEOUT: must be positive, got %s", c.Notifier.HTTPClient.Timeout)
+	}
+	if c.Notifier.HTTPClient.RetryBackoff < 0 {
+		invalid("invalid HTTP_CLIENT_RETRY_BACKOFF: must not be negative, got %s", c.Notifier.HTTPClient.RetryBackoff)
+	}
+
+	return errors.Join(errs...)
+}
+
+// LogFields returns the effective configuration keyed by environment variable, for logging
+// at startup. Passwords and the admin key are redacted, as are credentials embedded in URLs.
+func (c *Config) LogFields() map[string]any {
+	proxies := make([]string, 0, len(c.Server.TrustedProxies))
+	for _, network := range c.Server.TrustedProxies {
+		proxies = append(proxies, network.String())
+	}
+	allowedRatings := make([]string, 0, len(c.Review.AllowedRatings))
+	for _, rating := range c.Review.AllowedRatings {
+		allowedRatings = append(allowedRatings, strconv.FormatFloat(rating, 'f', -1, 64))
+	}
+
+	return map[string]any{
+		"ENV":         c.Env,
+		"CONFIG_FILE": c.File,
+		"LOG_LEVEL":   c.LogLevel,
+
+		"SERVER_PORT":             c.Server.Port,
+		"SERVER_READ_TIMEOUT":     c.Server.ReadTimeout.String(),
+		"SERVER_WRITE_TIMEOUT":    c.Server.WriteTimeout.String(),
+		"SERVER_SHUTDOWN_TIMEOUT": c.Server.ShutdownTimeout.String(),
+		"ENABLE_PPROF":            c.Server.EnablePprof,
+		"ADMIN_PORT":              c.Server.AdminPort,
+		"MAX_REQUEST_BODY_SIZE":   c.Server.MaxRequestBodySize,
+		"MAX_BATCH_ITEMS":         c.Server.MaxBatchItems,
+		"STRICT_CONTENT_TYPE":     c.Server.StrictContentType,
+		"TRUSTED_PROXIES":         strings.Join(proxies, ","),
+
+		"DB_HOST":                    c.Database.Host,
+		"DB_PORT":                    c.Database.Port,
+		"DB_USER":                    c.Database.User,
+		"DB_PASSWORD":                redactSecret(c.Database.Password),
+		"DB_NAME":                    c.Database.Name,
+		"DB_SSLMODE":                 c.Database.SSLMode,
+		"DB_MAX_OPEN_CONNS":          c.Database.MaxOpenConns,
+		"DB_MAX_IDLE_CONNS":          c.Database.MaxIdleConns,
+		"DB_CONN_MAX_LIFETIME":       c.Database.ConnMaxLifetime.String(),
+		"DB_SLOW_QUERY_THRESHOLD":    c.Database.SlowQueryThreshold.String(),
+		"DB_SERIALIZATION_RETRIES":   c.Database.SerializationRetries,
+		"DB_ID_STRATEGY":             c.Database.IDStrategy,
+		"DB_CONNECT_MAX_RETRIES":     c.Database.ConnectRetry.MaxRetries,
+		"DB_CONNECT_RETRY_DELAY":     c.Database.ConnectRetry.Delay.String(),
+		"DB_CONNECT_BACKOFF":         c.Database.ConnectRetry.Backoff,
+		"DB_CONNECT_MAX_RETRY_DELAY": c.Database.ConnectRetry.MaxDelay.String(),
+
+		"REDIS_HOST":                    c.Redis.Host,
+		"REDIS_PORT":                    c.Redis.Port,
+		"REDIS_PASSWORD":                redactSecret(c.Redis.Password),
+		"REDIS_DB":                      c.Redis.DB,
+		"REDIS_CONNECT_MAX_RETRIES":     c.Redis.ConnectRetry.MaxRetries,
+		"REDIS_CONNECT_RETRY_DELAY":     c.Redis.ConnectRetry.Delay.String(),
+		"REDIS_CONNECT_BACKOFF":         c.Redis.ConnectRetry.Backoff,
+		"REDIS_CONNECT_MAX_RETRY_DELAY": c.Redis.ConnectRetry.MaxDelay.String(),
+
+		"NATS_URL":                            redactURL(c.NATS.URL),
+		"NATS_ACK_WAIT":                       c.NATS.AckWait.String(),
+		"NATS_LAG_DEGRADED_THRESHOLD":         c.NATS.LagDegradedThreshold,
+		"NATS_PUBLISH_DRAIN_TIMEOUT":          c.NATS.PublishDrainTimeout.String(),
+		"NATS_SUBJECT_PREFIX":                 c.NATS.SubjectPrefix,
+		"EVENT_TRANSPORT":                     c.Events.Transport,
+		"EVENT_PUBLISH_TIMEOUT":               c.Events.PublishTimeout.String(),
+		"EVENT_PUBLISH_DEADLINE":              c.Events.PublishDeadline,
+		"EVENT_BREAKER_THRESHOLD":             c.Events.BreakerThreshold,
+		"EVENT_BREAKER_COOLDOWN":              c.Events.BreakerCooldown.String(),
+		"EVENT_BUFFER_MAX_SIZE":               c.Events.BufferMaxSize,
+		"EVENT_OUTBOX":                        c.Events.Outbox,
+		"EVENT_OUTBOX_POLL_INTERVAL":          c.Events.OutboxPollInterval.String(),
+		"EVENT_OUTBOX_BATCH_SIZE":             c.Events.OutboxBatchSize,
+		"EVENT_OUTBOX_RETENTION":              c.Events.OutboxRetention.String(),
+		"EVENT_OUTBOX_EMBEDDED_RELAY":         c.Events.OutboxEmbeddedRelay,
+		"EVENT_OUTBOX_LAG_DEGRADED_THRESHOLD": c.Events.OutboxLagDegradedThreshold.String(),
+
+		"CACHE_TTL_PRODUCT_RATING": c.Cache.ProductRatingTTL.String(),
+		"CACHE_TTL_REVIEWS_LIST":   c.Cache.ReviewsListTTL.String(),
+		"CACHE_TTL_RECENT_REVIEWS": c.Cache.RecentReviewsTTL.String(),
+		"CACHE_TTL_JITTER":         c.Cache.TTLJitter,
+		"CACHE_BREAKER_THRESHOLD":  c.Cache.BreakerThreshold,
+		"CACHE_BREAKER_COOLDOWN":   c.Cache.BreakerCooldown.String(),
+		"CACHE_CODEC":              c.Cache.Codec,
+		"CACHE_COMPRESS_THRESHOLD": c.Cache.CompressThreshold,
+
+		"WORKER_WARM_RATING_CACHE": c.Worker.WarmRatingCache,
+		"WORKER_DEBOUNCE_WINDOW":   c.Worker.DebounceWindow.String(),
+		"WORKER_SHUTDOWN_TIMEOUT":  c.Worker.ShutdownTimeout.String(),
+		"RETENTION_PERIOD":         c.Worker.RetentionPeriod.String(),
+		"PURGE_INTERVAL":           c.Worker.PurgeInterval.String(),
+		"PURGE_BATCH_SIZE":         c.Worker.PurgeBatchSize,
+		"WORKER_METRICS_PORT":      c.Worker.MetricsPort,
+
+		"ADMIN_API_KEY": redactSecret(c.Admin.APIKey),
+
+		"REVIEW_DEFAULT_SOURCE":     c.Review.DefaultSource,
+		"SANITIZE_REVIEW_TEXT":      c.Review.SanitizeText,
+		"DETECT_REVIEW_LANGUAGE":    c.Review.DetectLanguage,
+		"REVIEW_THROTTLE_LIMIT":     c.Review.ThrottleLimit,
+		"REVIEW_THROTTLE_WINDOW":    c.Review.ThrottleWindow.String(),
+		"REVIEW_FLAG_THRESHOLD":     c.Review.FlagThreshold,
+		"MAX_RATING":                c.Review.MaxRating,
+		"RATING_ROUNDING_MODE":      c.Review.RatingRoundingMode,
+		"HALF_STAR_RATINGS":         c.Review.HalfStarRatings,
+		"ALLOWED_RATINGS":           strings.Join(allowedRatings, ","),
+		"DEFAULT_REVIEW_SORT":       c.Review.DefaultSort,
+		"REVIEWS_PAGE_SIZE_DEFAULT": c.Review.Pagination.DefaultLimit,
+		"REVIEWS_PAGE_SIZE_MAX":     c.Review.Pagination.MaxLimit,
+
+		"ENFORCE_UNIQUE_PRODUCT_NAME": c.Product.EnforceUniqueName,
+		"PRODUCTS_COMPARE_MAX_IDS":    c.Product.CompareMaxIDs,
+		"MIN_REVIEWS_FOR_RATING":      c.Product.MinReviewsForRating,
+		"PRODUCTS_PAGE_SIZE_DEFAULT":  c.Product.Pagination.DefaultLimit,
+		"PRODUCTS_PAGE_SIZE_MAX":      c.Product.Pagination.MaxLimit,
+
+		"NOTIFIER_WEBHOOK_URL":       redactURL(c.Notifier.WebhookURL),
+		"HTTP_CLIENT_TIMEOUT":        c.Notifier.HTTPClient.Timeout.String(),
+		"HTTP_CLIENT_MAX_RETRIES":    c.Notifier.HTTPClient.MaxRetries,
+		"HTTP_CLIENT_RETRY_BACKOFF":  c.Notifier.HTTPClient.RetryBackoff.String(),
+		"HTTP_CLIENT_MAX_IDLE_CONNS": c.Notifier.HTTPClient.MaxIdleConns,
+
+		"CACHE_WARMER_DELAY":            c.Warmer.Delay.String(),
+		"CACHE_WARMER_TOP_N":            c.Warmer.TopN,
+		"CACHE_WARMER_REFRESH_INTERVAL": c.Warmer.RefreshInterval.String(),
+	}
+}
+
+// redactSecret hides a secret but still shows whether it is set
+func redactSecret(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redacted
+}
+
+// redactURL hides the password in a URL's user info. Webhook URLs often carry a token in
+// their path or query (Slack, Discord), so those are hidden too. NATS_URL may list several
+// servers separated by commas.
+func redactURL(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	urls := strings.Split(value, ",")
+	for i, raw := range urls {
+		u, err := url.Parse(strings.TrimSpace(raw))
+		if err != nil {
+			urls[i] = redacted
+			continue
+		}
+		hidePath := (u.Path != "" && u.Path != "/") || u.RawQuery != ""
+		u.Path, u.RawPath, u.RawQuery, u.Fragment = "", "", "", ""
+		urls[i] = u.Redacted()
+		if hidePath {
+			urls[i] += "/" + redacted
+		}
+	}
+	return strings.Join(urls, ",")
+}
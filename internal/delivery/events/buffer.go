@@ -0,0 +1,92 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// bufferKey is the Redis list holding unpublished events. It sits outside the cache's
+// product: namespace so an admin cache flush doesn't discard pending events.
+const bufferKey = "events:publish_buffer"
+
+// EventBuffer holds events the publisher couldn't send, oldest first, for replay
+type EventBuffer interface {
+	// Push appends an event, dropping the oldest ones beyond the buffer's capacity
+	Push(ctx context.Context, msg Message) error
+	// Pop removes and returns the oldest event, or nil when the buffer is empty
+	Pop(ctx context.Context) (*Message, error)
+	// Requeue puts back an event Pop returned but that couldn't be published, ahead of the rest
+	Requeue(ctx context.Context, msg Message) error
+}
+
+// bufferedMessage is a Message as stored in Redis
+type bufferedMessage struct {
+	Subject string `json:"subject"`
+	Data    []byte `json:"data"`
+}
+
+// RedisBuffer is an EventBuffer on a Redis list, so buffered events survive an API restart
+// and any API instance can replay them
+type RedisBuffer struct {
+	client  *redis.Client
+	maxSize int64
+}
+
+// NewRedisBuffer creates a buffer that keeps at most maxSize events
+func NewRedisBuffer(client *redis.Client, maxSize int64) *RedisBuffer {
+	return &RedisBuffer{
+		client:  client,
+		maxSize: maxSize,
+	}
+}
+
+// Push appends msg and trims the list to the newest maxSize events in one transaction
+func (b *RedisBuffer) Push(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(bufferedMessage{Subject: msg.Subject, Data: msg.Data})
+	if err != nil {
+		return err
+	}
+
+	pipe := b.client.TxPipeline()
+	pipe.RPush(ctx, bufferKey, data)
+	pipe.LTrim(ctx, bufferKey, -b.maxSize, -1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to buffer event: %w", err)
+	}
+	return nil
+}
+
+// Pop removes and returns the oldest buffered event
+func (b *RedisBuffer) Pop(ctx context.Context) (*Message, error) {
+	data, err := b.client.LPop(ctx, bufferKey).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read buffered event: %w", err)
+	}
+
+	var buffered bufferedMessage
+	if err := json.Unmarshal(data, &buffered); err != nil {
+		return nil, fmt.Errorf("failed to decode buffered event: %w", err)
+	}
+	return &Message{Subject: buffered.Subject, Data: buffered.Data}, nil
+}
+
+// Requeue puts msg back at the head of the list. It isn't trimmed: the event was already
+// counted against the capacity when it was pushed.
+func (b *RedisBuffer) Requeue(ctx context.Context, msg Message) error {
+	data, err := json.Marshal(bufferedMessage{Subject: msg.Subject, Data: msg.Data})
+	if err != nil {
+		return err
+	}
+
+	if err := b.client.LPush(ctx, bufferKey, data).Err(); err != nil {
+		return fmt.Errorf("failed to requeue buffered event: %w", err)
+	}
+	return nil
+}
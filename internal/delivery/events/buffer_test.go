@@ -0,0 +1,117 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listHook serves RPUSH, LPUSH, LPOP and LTRIM on one in-memory list instead of Redis
+type listHook struct {
+	list []string
+}
+
+func (h *listHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in tests")
+	}
+}
+
+func (h *listHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.apply(cmd)
+		return cmd.Err()
+	}
+}
+
+func (h *listHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		for _, cmd := range cmds {
+			h.apply(cmd)
+		}
+		return nil
+	}
+}
+
+func (h *listHook) apply(cmd redis.Cmder) {
+	args := cmd.Args()
+	switch cmd.Name() {
+	case "rpush":
+		h.list = append(h.list, string(args[2].([]byte)))
+	case "lpush":
+		h.list = append([]string{string(args[2].([]byte))}, h.list...)
+	case "lpop":
+		if len(h.list) == 0 {
+			cmd.SetErr(redis.Nil)
+			return
+		}
+		cmd.(*redis.StringCmd).SetVal(h.list[0])
+		h.list = h.list[1:]
+	case "ltrim":
+		// Only the negative start the buffer uses: keep the last -start elements
+		start, _ := strconv.Atoi(toString(args[2]))
+		if keep := -start; len(h.list) > keep {
+			h.list = h.list[len(h.list)-keep:]
+		}
+	}
+}
+
+func toString(arg any) string {
+	if n, ok := arg.(int64); ok {
+		return strconv.FormatInt(n, 10)
+	}
+	return arg.(string)
+}
+
+func newTestRedisBuffer(t *testing.T, maxSize int64) (*RedisBuffer, *listHook) {
+	hook := &listHook{}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	t.Cleanup(func() { _ = client.Close() })
+	return NewRedisBuffer(client, maxSize), hook
+}
+
+func TestRedisBuffer_FIFOWithRequeue(t *testing.T) {
+	buffer, _ := newTestRedisBuffer(t, 10)
+	ctx := context.Background()
+
+	require.NoError(t, buffer.Push(ctx, Message{Subject: "reviews.events", Data: []byte(`{"n":1}`)}))
+	require.NoError(t, buffer.Push(ctx, Message{Subject: "reviews.events", Data: []byte(`{"n":2}`)}))
+
+	msg, err := buffer.Pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, Message{Subject: "reviews.events", Data: []byte(`{"n":1}`)}, *msg)
+
+	require.NoError(t, buffer.Requeue(ctx, *msg))
+	msg, err = buffer.Pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, `{"n":1}`, string(msg.Data), "a requeued event is replayed first")
+
+	msg, err = buffer.Pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, `{"n":2}`, string(msg.Data))
+
+	msg, err = buffer.Pop(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, msg, "an empty buffer returns nil")
+}
+
+func TestRedisBuffer_DropsOldestBeyondMaxSize(t *testing.T) {
+	buffer, hook := newTestRedisBuffer(t, 2)
+	ctx := context.Background()
+
+	for n := range 3 {
+		require.NoError(t, buffer.Push(ctx, Message{Subject: "reviews.events", Data: []byte(strconv.Itoa(n))}))
+	}
+
+	require.Len(t, hook.list, 2)
+	msg, err := buffer.Pop(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(msg.Data))
+}
@@ -0,0 +1,119 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// busBufferSize is how many events may wait for dispatch before Publish blocks.
+// Review events are published from background goroutines, so blocking only delays them.
+const busBufferSize = 1024
+
+// ErrBusClosed is returned by InMemoryBus.Publish after Close
+var ErrBusClosed = errors.New("event bus closed")
+
+// InMemoryBus delivers events to subscribers in the same process, for running the API
+// and rating worker as one binary (cmd/monolith) without NATS. It is both an
+// EventPublisher and an EventConsumer. Events are dispatched one at a time in publish
+// order and are lost if the process exits before they are dispatched.
+type InMemoryBus struct {
+	queue  chan Message
+	logger *logger.Logger
+
+	mu       sync.RWMutex
+	handlers map[string][]func(data []byte) error
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	stopped   chan struct{}
+}
+
+// NewInMemoryBus creates a bus and starts its dispatcher
+func NewInMemoryBus(log *logger.Logger) *InMemoryBus {
+	b := &InMemoryBus{
+		queue:    make(chan Message, busBufferSize),
+		logger:   log,
+		handlers: make(map[string][]func(data []byte) error),
+		closed:   make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+
+	go b.run()
+
+	return b
+}
+
+// Publish queues the event for the subject's subscribers.
+// Blocks while the queue is full, until ctx is done or the bus is closed.
+func (b *InMemoryBus) Publish(ctx context.Context, subject string, data []byte) error {
+	msg := Message{Subject: subject, Data: append([]byte(nil), data...)}
+
+	// Checked first so a closed bus never accepts an event it won't dispatch
+	select {
+	case <-b.closed:
+		return ErrBusClosed
+	default:
+	}
+
+	select {
+	case b.queue <- msg:
+		return nil
+	case <-b.closed:
+		return ErrBusClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Subscribe adds handler for the subject; every subscriber gets every event
+func (b *InMemoryBus) Subscribe(subject string, handler func(data []byte) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.handlers[subject] = append(b.handlers[subject], handler)
+	return nil
+}
+
+// Close stops accepting events, dispatches the ones already queued and waits for that
+// to finish. Call it after the publishers have drained and before the subscribers stop.
+func (b *InMemoryBus) Close() {
+	b.closeOnce.Do(func() {
+		close(b.closed)
+	})
+	<-b.stopped
+}
+
+func (b *InMemoryBus) run() {
+	defer close(b.stopped)
+
+	for {
+		select {
+		case msg := <-b.queue:
+			b.dispatch(msg)
+		case <-b.closed:
+			for {
+				select {
+				case msg := <-b.queue:
+					b.dispatch(msg)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *InMemoryBus) dispatch(msg Message) {
+	b.mu.RLock()
+	handlers := b.handlers[msg.Subject]
+	b.mu.RUnlock()
+
+	for _, handler := range handlers {
+		if err := handler(msg.Data); err != nil {
+			b.logger.Errorf(err, "Failed to handle event on %s", msg.Subject)
+		}
+	}
+}
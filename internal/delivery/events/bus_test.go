@@ -0,0 +1,85 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// Compile-time checks: the bus stands in for both sides of a transport
+var (
+	_ EventPublisher = (*InMemoryBus)(nil)
+	_ EventConsumer  = (*InMemoryBus)(nil)
+)
+
+func TestInMemoryBus_DeliversToSubscribers(t *testing.T) {
+	bus := NewInMemoryBus(logger.New("test"))
+
+	var mu sync.Mutex
+	var first, second []string
+	require.NoError(t, bus.Subscribe("reviews.events", func(data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		first = append(first, string(data))
+		return nil
+	}))
+	require.NoError(t, bus.Subscribe("reviews.events", func(data []byte) error {
+		mu.Lock()
+		defer mu.Unlock()
+		second = append(second, string(data))
+		return assert.AnError // a failing subscriber doesn't stop delivery
+	}))
+	require.NoError(t, bus.Subscribe("other", func(data []byte) error {
+		t.Errorf("unexpected event on other subject: %s", data)
+		return nil
+	}))
+
+	for _, event := range []string{"a", "b", "c"} {
+		require.NoError(t, bus.Publish(context.Background(), "reviews.events", []byte(event)))
+	}
+
+	// Close dispatches everything already queued before returning
+	bus.Close()
+
+	assert.Equal(t, []string{"a", "b", "c"}, first)
+	assert.Equal(t, []string{"a", "b", "c"}, second)
+}
+
+func TestInMemoryBus_PublishAfterClose(t *testing.T) {
+	bus := NewInMemoryBus(logger.New("test"))
+	bus.Close()
+	bus.Close() // idempotent
+
+	err := bus.Publish(context.Background(), "reviews.events", []byte("late"))
+
+	assert.ErrorIs(t, err, ErrBusClosed)
+}
+
+func TestInMemoryBus_PublishBlocksUntilContextDone(t *testing.T) {
+	bus := NewInMemoryBus(logger.New("test"))
+	defer bus.Close()
+
+	// A blocked subscriber stops dispatch, so the queue fills up
+	release := make(chan struct{})
+	defer close(release)
+	require.NoError(t, bus.Subscribe("reviews.events", func(data []byte) error {
+		<-release
+		return nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	var err error
+	for i := 0; i <= busBufferSize+1 && err == nil; i++ {
+		err = bus.Publish(ctx, "reviews.events", []byte("event"))
+	}
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -10,9 +10,34 @@ import (
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
 )
 
+// EventConsumer delivers the events published on a subject to a handler.
+// Consumer (NATS) and PGConsumer (Postgres LISTEN) implement it.
+type EventConsumer interface {
+	Subscribe(subject string, handler func(data []byte) error) error
+	Close()
+}
+
+// NewConsumerFromConfig returns the consumer for EVENT_TRANSPORT.
+// noop and inmemory events never leave the publishing process, so they have no consumer.
+func NewConsumerFromConfig(cfg *config.Config, log *logger.Logger) (EventConsumer, error) {
+	switch cfg.Events.Transport {
+	case config.EventTransportPostgres:
+		return NewPGConsumer(cfg, log), nil
+	case config.EventTransportNoop, config.EventTransportInMemory:
+		return nil, fmt.Errorf("EVENT_TRANSPORT=%s delivers no events to other services; use nats or postgres", cfg.Events.Transport)
+	}
+
+	consumer, err := NewConsumer(cfg, log)
+	if err != nil {
+		return nil, err
+	}
+	return consumer, nil
+}
+
 // Consumer handles consuming events from NATS
 type Consumer struct {
 	nc     *nats.Conn
+	prefix string
 	logger *logger.Logger
 	sub    *nats.Subscription
 }
@@ -28,12 +53,16 @@ func NewConsumer(cfg *config.Config, log *logger.Logger) (*Consumer, error) {
 
 	return &Consumer{
 		nc:     nc,
+		prefix: cfg.NATS.SubjectPrefix,
 		logger: log,
 	}, nil
 }
 
 // Subscribe subscribes to a NATS subject and processes messages
+// The subject is the logical one; NATS_SUBJECT_PREFIX is applied here, as in Publisher.Publish
 func (c *Consumer) Subscribe(subject string, handler func(data []byte) error) error {
+	subject = PrefixSubject(c.prefix, subject)
+
 	sub, err := c.nc.Subscribe(subject, func(msg *nats.Msg) {
 		c.logger.Debugf("Received message on subject %s", subject)
 
@@ -0,0 +1,61 @@
+package events
+
+import (
+	"context"
+	"sync"
+)
+
+// NoopPublisher discards every event (EVENT_TRANSPORT=noop), for running the API alone.
+// Nothing recalculates ratings, so average_rating stays where it was.
+type NoopPublisher struct{}
+
+// NewNoopPublisher creates a publisher that drops events
+func NewNoopPublisher() *NoopPublisher {
+	return &NoopPublisher{}
+}
+
+// Publish discards the event
+func (NoopPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	return nil
+}
+
+// Close does nothing
+func (NoopPublisher) Close() {}
+
+// Message is an event recorded by InMemoryPublisher
+type Message struct {
+	Subject string
+	Data    []byte
+}
+
+// InMemoryPublisher records events instead of sending them (EVENT_TRANSPORT=inmemory),
+// so tests and local runs can inspect what would have been published
+type InMemoryPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+// NewInMemoryPublisher creates an empty recording publisher
+func NewInMemoryPublisher() *InMemoryPublisher {
+	return &InMemoryPublisher{}
+}
+
+// Publish records a copy of the event
+func (p *InMemoryPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.messages = append(p.messages, Message{Subject: subject, Data: append([]byte(nil), data...)})
+	return nil
+}
+
+// Messages returns the recorded events in publish order
+func (p *InMemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Message(nil), p.messages...)
+}
+
+// Close does nothing; recorded messages stay readable
+func (p *InMemoryPublisher) Close() {}
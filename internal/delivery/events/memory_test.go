@@ -0,0 +1,58 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+func TestInMemoryPublisher_RecordsMessages(t *testing.T) {
+	publisher := NewInMemoryPublisher()
+
+	data := []byte(`{"event_type":"review.created"}`)
+	require.NoError(t, publisher.Publish(context.Background(), "reviews.events", data))
+	require.NoError(t, publisher.Publish(context.Background(), "reviews.events", []byte(`{"event_type":"review.deleted"}`)))
+
+	// The recorded copy must not change when the caller reuses its buffer
+	data[2] = 'X'
+
+	messages := publisher.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, "reviews.events", messages[0].Subject)
+	assert.JSONEq(t, `{"event_type":"review.created"}`, string(messages[0].Data))
+	assert.JSONEq(t, `{"event_type":"review.deleted"}`, string(messages[1].Data))
+}
+
+func TestNewPublisherFromConfig(t *testing.T) {
+	log := logger.New("test")
+
+	for transport, want := range map[string]EventPublisher{
+		config.EventTransportNoop:     &NoopPublisher{},
+		config.EventTransportInMemory: &InMemoryPublisher{},
+		config.EventTransportPostgres: &PGPublisher{},
+	} {
+		t.Run(transport, func(t *testing.T) {
+			cfg := &config.Config{Events: config.EventsConfig{Transport: transport}}
+
+			publisher, err := NewPublisherFromConfig(cfg, nil, nil, log)
+
+			require.NoError(t, err)
+			assert.IsType(t, want, publisher)
+		})
+	}
+}
+
+func TestNewConsumerFromConfig_InProcessTransports(t *testing.T) {
+	for _, transport := range []string{config.EventTransportNoop, config.EventTransportInMemory} {
+		cfg := &config.Config{Events: config.EventsConfig{Transport: transport}}
+
+		_, err := NewConsumerFromConfig(cfg, logger.New("test"))
+
+		assert.Error(t, err, transport)
+	}
+}
@@ -0,0 +1,227 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+const (
+	// maxNotifyPayload is Postgres' NOTIFY payload limit; payloads must be shorter than 8000 bytes
+	maxNotifyPayload = 8000
+
+	// Listener reconnect backoff, doubling from the minimum up to the maximum
+	minListenerReconnect = 1 * time.Second
+	maxListenerReconnect = 30 * time.Second
+
+	// listenerPingInterval checks an idle listener connection, which otherwise only
+	// notices a dead server when the next notification fails to arrive
+	listenerPingInterval = 90 * time.Second
+)
+
+// Execer runs a statement; satisfied by *sql.DB and *sqlx.DB
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// PGPublisher publishes events with Postgres NOTIFY (EVENT_TRANSPORT=postgres).
+// The subject is used as the channel name as is: NATS_SUBJECT_PREFIX doesn't apply,
+// since environments don't share a database.
+type PGPublisher struct {
+	db     Execer
+	logger *logger.Logger
+}
+
+// NewPGPublisher creates a publisher that sends events through db
+func NewPGPublisher(db Execer, log *logger.Logger) *PGPublisher {
+	return &PGPublisher{
+		db:     db,
+		logger: log,
+	}
+}
+
+// Publish sends data as a notification on the subject's channel.
+// Payloads over the NOTIFY limit are cut down to their top-level scalar fields
+// (event type, IDs, timestamps), which is all the rating worker reads.
+func (p *PGPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	payload, err := notifyPayload(data)
+	if err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", subject, err)
+	}
+	if len(payload) != len(data) {
+		p.logger.WithFields(map[string]any{
+			"subject": subject,
+			"size":    len(data),
+		}).Warn("Event too large for NOTIFY, publishing its top-level fields only")
+	}
+
+	if _, err := p.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", subject, string(payload)); err != nil {
+		p.logger.WithFields(map[string]any{
+			"subject": subject,
+			"error":   err.Error(),
+		}).Error("Failed to publish notification", err)
+		return fmt.Errorf("failed to notify %s: %w", subject, err)
+	}
+
+	p.logger.Debugf("Published notification on %s", subject)
+	return nil
+}
+
+// Close does nothing: the database connection belongs to the caller
+func (p *PGPublisher) Close() {}
+
+// notifyPayload returns data if it fits in a notification, otherwise data without its
+// nested objects and arrays
+func notifyPayload(data []byte) ([]byte, error) {
+	if len(data) < maxNotifyPayload {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("payload of %d bytes exceeds the NOTIFY limit and isn't a JSON object: %w", len(data), err)
+	}
+	for name, value := range fields {
+		if len(value) > 0 && (value[0] == '{' || value[0] == '[') {
+			delete(fields, name)
+		}
+	}
+
+	trimmed, err := json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	if len(trimmed) >= maxNotifyPayload {
+		return nil, fmt.Errorf("payload of %d bytes exceeds the NOTIFY limit", len(trimmed))
+	}
+	return trimmed, nil
+}
+
+// PGConsumer receives events with Postgres LISTEN on a dedicated connection.
+// Unlike JetStream there is no redelivery: a failed handler is only logged, and events
+// sent while the listener is disconnected are lost.
+type PGConsumer struct {
+	listener *pq.Listener
+	logger   *logger.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]func(data []byte) error
+
+	done    chan struct{}
+	stopped chan struct{}
+}
+
+// NewPGConsumer opens a listener connection using the database config.
+// The connection is established in the background and re-established after failures.
+func NewPGConsumer(cfg *config.Config, log *logger.Logger) *PGConsumer {
+	c := &PGConsumer{
+		logger:   log,
+		handlers: make(map[string]func(data []byte) error),
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	c.listener = pq.NewListener(cfg.GetDSN(), minListenerReconnect, maxListenerReconnect, c.onListenerEvent)
+
+	go c.run()
+
+	return c
+}
+
+// Subscribe listens on the subject's channel and passes each notification to handler
+func (c *PGConsumer) Subscribe(subject string, handler func(data []byte) error) error {
+	c.mu.Lock()
+	c.handlers[subject] = handler
+	c.mu.Unlock()
+
+	if err := c.listener.Listen(subject); err != nil {
+		c.mu.Lock()
+		delete(c.handlers, subject)
+		c.mu.Unlock()
+		return fmt.Errorf("failed to listen on %s: %w", subject, err)
+	}
+
+	c.logger.Infof("Listening for Postgres notifications on %s", subject)
+	return nil
+}
+
+// Close stops dispatching and closes the listener connection
+func (c *PGConsumer) Close() {
+	close(c.done)
+	<-c.stopped
+
+	if err := c.listener.Close(); err != nil {
+		c.logger.Warnf("Failed to close Postgres listener: %v", err)
+		return
+	}
+	c.logger.Info("Postgres listener connection closed")
+}
+
+// run dispatches notifications until Close
+func (c *PGConsumer) run() {
+	defer close(c.stopped)
+
+	ticker := time.NewTicker(listenerPingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			return
+		case n := <-c.listener.Notify:
+			// nil follows a reconnect; the outage is logged by onListenerEvent
+			if n != nil {
+				c.dispatch(n)
+			}
+		case <-ticker.C:
+			if err := c.listener.Ping(); err != nil {
+				c.logger.Warnf("Postgres listener ping failed: %v", err)
+			}
+		}
+	}
+}
+
+func (c *PGConsumer) dispatch(n *pq.Notification) {
+	c.mu.RLock()
+	handler, ok := c.handlers[n.Channel]
+	c.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	c.logger.Debugf("Received notification on %s", n.Channel)
+	if err := handler([]byte(n.Extra)); err != nil {
+		c.logger.Errorf(err, "Failed to handle notification on %s", n.Channel)
+	}
+}
+
+func (c *PGConsumer) onListenerEvent(event pq.ListenerEventType, err error) {
+	switch event {
+	case pq.ListenerEventConnected:
+		c.logger.Info("Postgres listener connected")
+	case pq.ListenerEventDisconnected:
+		c.logger.WithFields(map[string]any{
+			"error": errString(err),
+		}).Warn("Postgres listener disconnected, notifications are lost until it reconnects")
+	case pq.ListenerEventReconnected:
+		c.logger.Info("Postgres listener reconnected")
+	case pq.ListenerEventConnectionAttemptFailed:
+		c.logger.WithFields(map[string]any{
+			"error": errString(err),
+		}).Warn("Postgres listener connection attempt failed")
+	}
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
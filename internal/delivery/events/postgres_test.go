@@ -0,0 +1,80 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+func TestPGPublisher_Publish(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	payload := `{"event_type":"review.created","product_id":"p1"}`
+	mock.ExpectExec(`SELECT pg_notify\(\$1, \$2\)`).
+		WithArgs("reviews.events", payload).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	publisher := NewPGPublisher(db, logger.New("test"))
+	err = publisher.Publish(context.Background(), "reviews.events", []byte(payload))
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPGPublisher_Publish_Error(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(`SELECT pg_notify`).WillReturnError(assert.AnError)
+
+	publisher := NewPGPublisher(db, logger.New("test"))
+	err = publisher.Publish(context.Background(), "reviews.events", []byte(`{}`))
+
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestNotifyPayload(t *testing.T) {
+	small := []byte(`{"event_type":"review.created"}`)
+	got, err := notifyPayload(small)
+	require.NoError(t, err)
+	assert.Equal(t, small, got, "payloads under the limit are sent unchanged")
+
+	large, err := json.Marshal(map[string]any{
+		"event_type": "review.created",
+		"product_id": "p1",
+		"timestamp":  "2024-01-01T00:00:00Z",
+		"review":     map[string]any{"review_text": strings.Repeat("é", maxNotifyPayload)},
+		"tags":       []string{"a"},
+	})
+	require.NoError(t, err)
+
+	got, err = notifyPayload(large)
+	require.NoError(t, err)
+	assert.Less(t, len(got), maxNotifyPayload)
+
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(got, &fields))
+	assert.Equal(t, map[string]any{
+		"event_type": "review.created",
+		"product_id": "p1",
+		"timestamp":  "2024-01-01T00:00:00Z",
+	}, fields)
+}
+
+func TestNotifyPayload_TooLarge(t *testing.T) {
+	_, err := notifyPayload([]byte(`"` + strings.Repeat("x", maxNotifyPayload) + `"`))
+	assert.Error(t, err, "non-object payloads can't be trimmed")
+
+	_, err = notifyPayload([]byte(`{"text":"` + strings.Repeat("x", maxNotifyPayload) + `"}`))
+	assert.Error(t, err, "oversized scalar fields can't be dropped")
+}
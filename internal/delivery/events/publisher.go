@@ -3,22 +3,67 @@ package events
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/nats-io/nats.go"
 
 	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/breaker"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
 )
 
-// Publisher handles publishing events to NATS JetStream
+// bufferOpTimeout bounds each buffer call. Buffering runs after a publish already failed,
+// often with the caller's deadline spent, so it gets its own budget.
+const bufferOpTimeout = 2 * time.Second
+
+// EventPublisher sends events on a subject; it satisfies review.EventPublisher.
+// Close releases the transport's connection, if it owns one.
+type EventPublisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+	Close()
+}
+
+// NewPublisherFromConfig returns the publisher for EVENT_TRANSPORT.
+// db is only used by the postgres transport, buffer (which may be nil) only by nats.
+func NewPublisherFromConfig(cfg *config.Config, db Execer, buffer EventBuffer, log *logger.Logger) (EventPublisher, error) {
+	switch cfg.Events.Transport {
+	case config.EventTransportPostgres:
+		return NewPGPublisher(db, log), nil
+	case config.EventTransportNoop:
+		return NewNoopPublisher(), nil
+	case config.EventTransportInMemory:
+		return NewInMemoryPublisher(), nil
+	default:
+		publisher, err := NewPublisher(cfg, buffer, log)
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	}
+}
+
+// Publisher handles publishing events to NATS JetStream.
+// During a NATS outage its circuit breaker fails publishes immediately instead of letting
+// each wait out its timeout. With a buffer, failed events are kept there instead and
+// replayed every breaker cooldown, the first replay serving as the breaker's probe.
 type Publisher struct {
-	nc     *nats.Conn
-	js     nats.JetStreamContext
-	logger *logger.Logger
+	nc      *nats.Conn
+	js      nats.JetStreamContext
+	prefix  string
+	breaker *breaker.Breaker
+	buffer  EventBuffer
+	// replayTimeout bounds each replayed publish, like EVENT_PUBLISH_TIMEOUT bounds live ones
+	replayTimeout time.Duration
+	logger        *logger.Logger
+
+	done     chan struct{}
+	replayWG sync.WaitGroup
 }
 
-// NewPublisher creates a new NATS JetStream publisher
-func NewPublisher(cfg *config.Config, log *logger.Logger) (*Publisher, error) {
+// NewPublisher creates a new NATS JetStream publisher; buffer may be nil to drop events
+// that can't be published
+func NewPublisher(cfg *config.Config, buffer EventBuffer, log *logger.Logger) (*Publisher, error) {
 	nc, err := nats.Connect(cfg.NATS.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
@@ -35,16 +80,63 @@ func NewPublisher(cfg *config.Config, log *logger.Logger) (*Publisher, error) {
 		"url": cfg.NATS.URL,
 	}).Info("Connected to NATS JetStream")
 
+	p := newPublisher(
+		nc,
+		js,
+		cfg.NATS.SubjectPrefix,
+		breaker.New("NATS publisher", cfg.Events.BreakerThreshold, cfg.Events.BreakerCooldown, log),
+		buffer,
+		cfg.Events.PublishTimeout,
+		log,
+	)
+	if buffer != nil {
+		p.startReplay(cfg.Events.BreakerCooldown)
+	}
+	return p, nil
+}
+
+func newPublisher(
+	nc *nats.Conn,
+	js nats.JetStreamContext,
+	prefix string,
+	b *breaker.Breaker,
+	buffer EventBuffer,
+	replayTimeout time.Duration,
+	log *logger.Logger,
+) *Publisher {
 	return &Publisher{
-		nc:     nc,
-		js:     js,
-		logger: log,
-	}, nil
+		nc:            nc,
+		js:            js,
+		prefix:        prefix,
+		breaker:       b,
+		buffer:        buffer,
+		replayTimeout: replayTimeout,
+		logger:        log,
+		done:          make(chan struct{}),
+	}
 }
 
 // Publish publishes a message to a NATS JetStream subject
 // JetStream ensures message durability and delivery guarantees
+// The subject is the logical one (e.g. "reviews.events"); NATS_SUBJECT_PREFIX is applied here
+// An event that can't be published but was buffered for replay counts as sent.
 func (p *Publisher) Publish(ctx context.Context, subject string, data []byte) error {
+	if !p.breaker.Allow() {
+		return p.bufferOrFail(ctx, Message{Subject: subject, Data: data}, fmt.Errorf("failed to publish to JetStream: %w", breaker.ErrOpen))
+	}
+
+	err := p.publish(ctx, subject, data)
+	p.breaker.Record(err)
+	if err != nil {
+		return p.bufferOrFail(ctx, Message{Subject: subject, Data: data}, err)
+	}
+	return nil
+}
+
+// publish sends one message to JetStream and waits for its acknowledgement
+func (p *Publisher) publish(ctx context.Context, subject string, data []byte) error {
+	subject = PrefixSubject(p.prefix, subject)
+
 	// Publish with acknowledgment - ensures message is stored before returning
 	pubAck, err := p.js.Publish(subject, data, nats.Context(ctx))
 	if err != nil {
@@ -64,8 +156,121 @@ func (p *Publisher) Publish(ctx context.Context, subject string, data []byte) er
 	return nil
 }
 
-// Close closes the NATS connection
+// bufferOrFail keeps msg for replay, or returns cause when there's no buffer or it fails too
+func (p *Publisher) bufferOrFail(ctx context.Context, msg Message, cause error) error {
+	if p.buffer == nil {
+		return cause
+	}
+
+	bufferCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), bufferOpTimeout)
+	defer cancel()
+	if err := p.buffer.Push(bufferCtx, msg); err != nil {
+		p.logger.WithFields(map[string]any{
+			"subject": msg.Subject,
+			"error":   err.Error(),
+		}).Error("Failed to buffer unpublished event, dropping it", err)
+		return cause
+	}
+
+	p.logger.WithFields(map[string]any{
+		"subject": msg.Subject,
+		"cause":   cause.Error(),
+	}).Debug("Buffered event for replay")
+	return nil
+}
+
+// startReplay replays buffered events every interval until Close
+func (p *Publisher) startReplay(interval time.Duration) {
+	p.replayWG.Add(1)
+	go func() {
+		defer p.replayWG.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.done:
+				return
+			case <-ticker.C:
+				p.replayBuffered()
+			}
+		}
+	}()
+}
+
+// replayBuffered publishes buffered events oldest first until the buffer is empty, the
+// breaker refuses or a publish fails. Replayed events may arrive after newer ones published
+// directly; consumers already tolerate that, as the rating worker recalculates from the database.
+func (p *Publisher) replayBuffered() {
+	replayed := 0
+	defer func() {
+		if replayed > 0 {
+			p.logger.Infof("Replayed %d buffered events", replayed)
+		}
+	}()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		default:
+		}
+
+		bufferCtx, cancel := context.WithTimeout(context.Background(), bufferOpTimeout)
+		msg, err := p.buffer.Pop(bufferCtx)
+		cancel()
+		if err != nil {
+			p.logger.Error("Failed to read event buffer", err)
+			return
+		}
+		if msg == nil {
+			return
+		}
+
+		if !p.breaker.Allow() {
+			p.requeue(*msg)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), p.replayTimeout)
+		err = p.publish(ctx, msg.Subject, msg.Data)
+		cancel()
+		p.breaker.Record(err)
+		if err != nil {
+			p.requeue(*msg)
+			return
+		}
+		replayed++
+	}
+}
+
+// requeue puts an event that couldn't be replayed back at the head of the buffer
+func (p *Publisher) requeue(msg Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), bufferOpTimeout)
+	defer cancel()
+	if err := p.buffer.Requeue(ctx, msg); err != nil {
+		p.logger.WithFields(map[string]any{
+			"subject": msg.Subject,
+		}).Error("Failed to requeue buffered event, dropping it", err)
+	}
+}
+
+// JetStream returns the underlying JetStream context for stream inspection
+func (p *Publisher) JetStream() nats.JetStreamContext {
+	return p.js
+}
+
+// IsConnected reports whether the underlying NATS connection is currently up
+func (p *Publisher) IsConnected() bool {
+	return p.nc != nil && p.nc.IsConnected()
+}
+
+// Close stops replaying buffered events and closes the NATS connection.
+// Events still buffered stay in Redis for the next API instance to replay.
 func (p *Publisher) Close() {
+	close(p.done)
+	p.replayWG.Wait()
+
 	if p.nc != nil {
 		p.nc.Close()
 		p.logger.Info("NATS publisher connection closed")
@@ -0,0 +1,147 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/breaker"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// fakeJetStream acknowledges publishes, or fails them while err is set
+type fakeJetStream struct {
+	nats.JetStreamContext
+
+	mu        sync.Mutex
+	err       error
+	attempts  int
+	published []string
+}
+
+func (f *fakeJetStream) Publish(subject string, data []byte, opts ...nats.PubOpt) (*nats.PubAck, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.attempts++
+	if f.err != nil {
+		return nil, f.err
+	}
+	f.published = append(f.published, string(data))
+	return &nats.PubAck{Stream: "REVIEWS", Sequence: uint64(len(f.published))}, nil
+}
+
+// memoryBuffer is an EventBuffer on a slice
+type memoryBuffer struct {
+	messages []Message
+}
+
+func (b *memoryBuffer) Push(ctx context.Context, msg Message) error {
+	b.messages = append(b.messages, msg)
+	return nil
+}
+
+func (b *memoryBuffer) Pop(ctx context.Context) (*Message, error) {
+	if len(b.messages) == 0 {
+		return nil, nil
+	}
+	msg := b.messages[0]
+	b.messages = b.messages[1:]
+	return &msg, nil
+}
+
+func (b *memoryBuffer) Requeue(ctx context.Context, msg Message) error {
+	b.messages = append([]Message{msg}, b.messages...)
+	return nil
+}
+
+// newTestPublisher opens its breaker after breakerThreshold failures for an hour; 0 disables it
+func newTestPublisher(js *fakeJetStream, buffer EventBuffer, breakerThreshold int) *Publisher {
+	log := logger.New("test")
+	return newPublisher(nil, js, "", breaker.New("NATS publisher", breakerThreshold, time.Hour, log), buffer, time.Second, log)
+}
+
+func TestPublisher_BreakerFailsFastDuringOutage(t *testing.T) {
+	js := &fakeJetStream{err: nats.ErrTimeout}
+	p := newTestPublisher(js, nil, 2)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, p.Publish(ctx, "reviews.events", []byte("1")), nats.ErrTimeout)
+	assert.ErrorIs(t, p.Publish(ctx, "reviews.events", []byte("2")), nats.ErrTimeout)
+
+	err := p.Publish(ctx, "reviews.events", []byte("3"))
+
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.Equal(t, 2, js.attempts, "no publish should reach NATS while the breaker is open")
+}
+
+func TestPublisher_BuffersAndReplays(t *testing.T) {
+	js := &fakeJetStream{err: nats.ErrTimeout}
+	buffer := &memoryBuffer{}
+	p := newTestPublisher(js, buffer, 0)
+	ctx := context.Background()
+
+	for _, data := range []string{"1", "2", "3"} {
+		require.NoError(t, p.Publish(ctx, "reviews.events", []byte(data)), "a buffered event counts as sent")
+	}
+	require.Len(t, buffer.messages, 3)
+	assert.Equal(t, "reviews.events", buffer.messages[0].Subject)
+
+	// Still down: the replay fails and the event goes back to the head of the buffer
+	p.replayBuffered()
+	assert.Equal(t, []string{"1", "2", "3"}, bufferedData(buffer))
+
+	js.err = nil
+	p.replayBuffered()
+
+	assert.Empty(t, buffer.messages)
+	assert.Equal(t, []string{"1", "2", "3"}, js.published, "replayed oldest first")
+}
+
+func TestPublisher_ReplayWaitsForBreaker(t *testing.T) {
+	js := &fakeJetStream{err: nats.ErrTimeout}
+	buffer := &memoryBuffer{}
+	p := newTestPublisher(js, buffer, 2)
+
+	require.NoError(t, p.Publish(context.Background(), "reviews.events", []byte("1")))
+	require.NoError(t, p.Publish(context.Background(), "reviews.events", []byte("2")))
+	attempts := js.attempts
+
+	js.err = nil
+	p.replayBuffered()
+
+	assert.Equal(t, attempts, js.attempts, "an open breaker holds replays until its cooldown passes")
+	assert.Equal(t, []string{"1", "2"}, bufferedData(buffer))
+}
+
+// failingBuffer rejects every event
+type failingBuffer struct {
+	memoryBuffer
+}
+
+func (b *failingBuffer) Push(ctx context.Context, msg Message) error {
+	return errors.New("redis down")
+}
+
+func TestPublisher_BufferFailureReturnsPublishError(t *testing.T) {
+	js := &fakeJetStream{err: nats.ErrTimeout}
+	p := newTestPublisher(js, &failingBuffer{}, 2)
+
+	err := p.Publish(context.Background(), "reviews.events", []byte("1"))
+
+	assert.ErrorIs(t, err, nats.ErrTimeout)
+}
+
+func bufferedData(buffer *memoryBuffer) []string {
+	data := make([]string, 0, len(buffer.messages))
+	for _, msg := range buffer.messages {
+		data = append(data, string(msg.Data))
+	}
+	return data
+}
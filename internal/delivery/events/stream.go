@@ -3,6 +3,7 @@ package events
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/nats-io/nats.go"
@@ -11,10 +12,10 @@ import (
 )
 
 const (
-	// StreamName is the JetStream stream for review events
+	// StreamName is the JetStream stream for review events, before any NATS_SUBJECT_PREFIX
 	StreamName = "REVIEWS"
 
-	// StreamSubjects defines the subjects this stream listens to
+	// StreamSubjects defines the subjects this stream listens to, before any NATS_SUBJECT_PREFIX
 	StreamSubjects = "reviews.events"
 
 	// ConsumerName is the durable consumer for rating worker
@@ -24,24 +25,90 @@ const (
 	// After 3 failed attempts, message is discarded - next review event will recalculate
 	MaxDeliveryAttempts = 3
 
-	// AckWait is how long to wait for acknowledgment before redelivery
-	AckWait = 30 * time.Second
+	// DefaultAckWait is how long to wait for acknowledgment before redelivery when not configured
+	DefaultAckWait = 30 * time.Second
 )
 
+// PrefixSubject prepends the environment's subject prefix (NATS_SUBJECT_PREFIX), if any
+func PrefixSubject(prefix, subject string) string {
+	if prefix == "" {
+		return subject
+	}
+	return prefix + "." + subject
+}
+
+// PrefixStreamName derives the environment's stream name from the subject prefix.
+// Stream names are global to the account, so two environments on one cluster need
+// distinct streams as well as distinct subjects; dots aren't allowed in stream names.
+func PrefixStreamName(prefix string) string {
+	if prefix == "" {
+		return StreamName
+	}
+	return strings.ToUpper(strings.ReplaceAll(prefix, ".", "_")) + "_" + StreamName
+}
+
 // StreamConfig holds the JetStream stream configuration
 type StreamConfig struct {
-	js     nats.JetStreamContext
-	logger *logger.Logger
+	js      nats.JetStreamContext
+	ackWait time.Duration
+	stream  string
+	subject string
+	logger  *logger.Logger
 }
 
 // NewStreamConfig creates a new stream configuration helper
-func NewStreamConfig(js nats.JetStreamContext, log *logger.Logger) *StreamConfig {
+// A non-positive ackWait falls back to DefaultAckWait; subjectPrefix may be empty
+func NewStreamConfig(js nats.JetStreamContext, ackWait time.Duration, subjectPrefix string, log *logger.Logger) *StreamConfig {
+	if ackWait <= 0 {
+		ackWait = DefaultAckWait
+	}
+
 	return &StreamConfig{
-		js:     js,
-		logger: log,
+		js:      js,
+		ackWait: ackWait,
+		stream:  PrefixStreamName(subjectPrefix),
+		subject: PrefixSubject(subjectPrefix, StreamSubjects),
+		logger:  log,
 	}
 }
 
+// Stream returns the stream name for this environment
+func (s *StreamConfig) Stream() string {
+	return s.stream
+}
+
+// Subject returns the review events subject for this environment
+func (s *StreamConfig) Subject() string {
+	return s.subject
+}
+
+// StreamStats is a point-in-time snapshot of the review events stream and its consumer
+type StreamStats struct {
+	Stream   StreamState   `json:"stream"`
+	Consumer ConsumerState `json:"consumer"`
+}
+
+// StreamState describes the stored backlog of the stream
+type StreamState struct {
+	Name          string `json:"name"`
+	Messages      uint64 `json:"messages"`
+	Bytes         uint64 `json:"bytes"`
+	FirstSequence uint64 `json:"first_sequence"`
+	LastSequence  uint64 `json:"last_sequence"`
+	Consumers     int    `json:"consumers"`
+}
+
+// ConsumerState describes delivery progress of the rating worker consumer
+type ConsumerState struct {
+	Name           string `json:"name"`
+	NumPending     uint64 `json:"num_pending"`
+	NumRedelivered int    `json:"num_redelivered"`
+	NumAckPending  int    `json:"num_ack_pending"`
+	NumWaiting     int    `json:"num_waiting"`
+	AckWait        string `json:"ack_wait"`
+	MaxDeliver     int    `json:"max_deliver"`
+}
+
 // generateExponentialBackoff creates a backoff schedule for NATS redeliveries
 // Pattern: 1s, 2s, 4s, 8s, ... (2^n seconds)
 // MaxDeliver N requires N-1 backoff durations (first delivery is immediate)
@@ -64,18 +131,18 @@ func generateExponentialBackoff(maxDeliveryAttempts int) []time.Duration {
 // - Replicas: 1 (single node)
 // - MaxAge: 24 hours (stale events are not useful for recalculation)
 func (s *StreamConfig) EnsureStream() error {
-	stream, err := s.js.StreamInfo(StreamName)
+	stream, err := s.js.StreamInfo(s.stream)
 
 	if errors.Is(err, nats.ErrStreamNotFound) {
 		// Create new stream
 		s.logger.WithFields(map[string]any{
-			"stream":   StreamName,
-			"subjects": StreamSubjects,
+			"stream":   s.stream,
+			"subjects": s.subject,
 		}).Info("Creating JetStream stream")
 
 		_, err = s.js.AddStream(&nats.StreamConfig{
-			Name:        StreamName,
-			Subjects:    []string{StreamSubjects},
+			Name:        s.stream,
+			Subjects:    []string{s.subject},
 			Retention:   nats.WorkQueuePolicy, // Messages deleted after ack
 			Storage:     nats.FileStorage,     // Persisted to disk
 			Replicas:    1,
@@ -110,28 +177,28 @@ func (s *StreamConfig) EnsureStream() error {
 // - Durable: Survives worker restarts
 // - AckExplicit: Worker must explicitly acknowledge messages
 // - MaxDeliver: 3 attempts then discard (next review event will recalculate)
-// - AckWait: 30 seconds to process and ack
+// - AckWait: configurable time to process and ack (default 30 seconds)
 // - BackOff: Exponential backoff between retries (dynamically generated)
 //
 // Note: Messages that fail after 3 attempts are discarded, not sent to DLQ.
 // This is acceptable because rating calculation is idempotent and based on
 // database state - the next review event will trigger a full recalculation.
 func (s *StreamConfig) EnsureConsumer() error {
-	consumerInfo, err := s.js.ConsumerInfo(StreamName, ConsumerName)
+	consumerInfo, err := s.js.ConsumerInfo(s.stream, ConsumerName)
 
 	if errors.Is(err, nats.ErrConsumerNotFound) {
 		// Create new consumer
 		s.logger.WithFields(map[string]any{
-			"stream":   StreamName,
+			"stream":   s.stream,
 			"consumer": ConsumerName,
 		}).Info("Creating JetStream consumer")
 
-		_, err = s.js.AddConsumer(StreamName, &nats.ConsumerConfig{
+		_, err = s.js.AddConsumer(s.stream, &nats.ConsumerConfig{
 			Durable:       ConsumerName,
 			AckPolicy:     nats.AckExplicitPolicy, // Require explicit ack
-			AckWait:       AckWait,
+			AckWait:       s.ackWait,
 			MaxDeliver:    MaxDeliveryAttempts,
-			FilterSubject: StreamSubjects,
+			FilterSubject: s.subject,
 			BackOff:       generateExponentialBackoff(MaxDeliveryAttempts),
 			Description:   "Rating worker consumer for processing review events",
 		})
@@ -147,6 +214,22 @@ func (s *StreamConfig) EnsureConsumer() error {
 		return fmt.Errorf("failed to get consumer info: %w", err)
 	}
 
+	// Apply a changed ack timeout without requiring the consumer to be recreated
+	if consumerInfo.Config.AckWait != s.ackWait {
+		updated := consumerInfo.Config
+		updated.AckWait = s.ackWait
+
+		if _, err := s.js.UpdateConsumer(s.stream, &updated); err != nil {
+			return fmt.Errorf("failed to update consumer ack wait: %w", err)
+		}
+
+		s.logger.WithFields(map[string]any{
+			"consumer":     consumerInfo.Name,
+			"old_ack_wait": consumerInfo.Config.AckWait.String(),
+			"new_ack_wait": s.ackWait.String(),
+		}).Info("Updated JetStream consumer ack wait")
+	}
+
 	// Consumer exists
 	s.logger.WithFields(map[string]any{
 		"consumer":    consumerInfo.Name,
@@ -157,3 +240,36 @@ func (s *StreamConfig) EnsureConsumer() error {
 
 	return nil
 }
+
+// Stats queries JetStream live for the stream backlog and consumer redelivery counters
+func (s *StreamConfig) Stats() (*StreamStats, error) {
+	stream, err := s.js.StreamInfo(s.stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stream info: %w", err)
+	}
+
+	consumer, err := s.js.ConsumerInfo(s.stream, ConsumerName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumer info: %w", err)
+	}
+
+	return &StreamStats{
+		Stream: StreamState{
+			Name:          stream.Config.Name,
+			Messages:      stream.State.Msgs,
+			Bytes:         stream.State.Bytes,
+			FirstSequence: stream.State.FirstSeq,
+			LastSequence:  stream.State.LastSeq,
+			Consumers:     stream.State.Consumers,
+		},
+		Consumer: ConsumerState{
+			Name:           consumer.Name,
+			NumPending:     consumer.NumPending,
+			NumRedelivered: consumer.NumRedelivered,
+			NumAckPending:  consumer.NumAckPending,
+			NumWaiting:     consumer.NumWaiting,
+			AckWait:        consumer.Config.AckWait.String(),
+			MaxDeliver:     consumer.Config.MaxDeliver,
+		},
+	}, nil
+}
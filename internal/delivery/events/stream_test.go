@@ -0,0 +1,26 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrefixSubject(t *testing.T) {
+	assert.Equal(t, "reviews.events", PrefixSubject("", StreamSubjects))
+	assert.Equal(t, "staging.reviews.events", PrefixSubject("staging", StreamSubjects))
+	assert.Equal(t, "eu.staging.reviews.events", PrefixSubject("eu.staging", StreamSubjects))
+}
+
+func TestPrefixStreamName(t *testing.T) {
+	assert.Equal(t, "REVIEWS", PrefixStreamName(""))
+	assert.Equal(t, "STAGING_REVIEWS", PrefixStreamName("staging"))
+	assert.Equal(t, "EU_STAGING_REVIEWS", PrefixStreamName("eu.staging"))
+}
+
+func TestNewStreamConfig_AppliesPrefix(t *testing.T) {
+	s := NewStreamConfig(nil, 0, "pr-42", nil)
+
+	assert.Equal(t, "PR-42_REVIEWS", s.Stream())
+	assert.Equal(t, "pr-42.reviews.events", s.Subject())
+}
@@ -0,0 +1,59 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/httpclient"
+)
+
+// webhookDeliveryTimeout bounds one delivery including retries; the subscription
+// handles messages one at a time, so a hung endpoint would stall every later event
+const webhookDeliveryTimeout = 30 * time.Second
+
+// WebhookHandler creates a handler that POSTs each event, unchanged, to url.
+// The notifier subscribes with core NATS, so a delivery that still fails after
+// retries is logged by the consumer and dropped.
+func WebhookHandler(client *httpclient.Client, url string) func(data []byte) error {
+	return func(data []byte) error {
+		ctx, cancel := context.WithTimeout(context.Background(), webhookDeliveryTimeout)
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to build webhook request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("webhook delivery failed: %w", err)
+		}
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+		}
+
+		return nil
+	}
+}
+
+// MultiHandler runs every handler for each event, returning the first error
+// after all have run so one failing sink doesn't starve the others
+func MultiHandler(handlers ...func(data []byte) error) func(data []byte) error {
+	return func(data []byte) error {
+		var firstErr error
+		for _, handler := range handlers {
+			if err := handler(data); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return firstErr
+	}
+}
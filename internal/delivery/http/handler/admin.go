@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/worker"
+)
+
+// StreamInspector reports live JetStream stream and consumer statistics
+type StreamInspector interface {
+	Stats() (*events.StreamStats, error)
+}
+
+// CacheAdmin is the cache maintenance surface used by operators
+type CacheAdmin interface {
+	// FlushAll removes all product and review cache entries
+	FlushAll(ctx context.Context) (int64, error)
+	// InvalidateAllProductCache removes one product's rating and review entries
+	InvalidateAllProductCache(ctx context.Context, productID uuid.UUID) error
+}
+
+// AuditReader reads the audit trail
+type AuditReader interface {
+	ListByEntityID(ctx context.Context, entityID uuid.UUID, limit, offset int) ([]*domain.AuditEntry, error)
+	CountByEntityID(ctx context.Context, entityID uuid.UUID) (int, error)
+}
+
+// RatingReconciler rebuilds a product's rating from a full scan of its reviews
+type RatingReconciler interface {
+	Reconcile(ctx context.Context, productID uuid.UUID) (worker.Reconciliation, bool, error)
+}
+
+// auditPagination keeps an entity's full history reachable in a few pages
+var auditPagination = request.PaginationConfig{DefaultLimit: 50, MaxLimit: 500}
+
+// AdminHandler handles operator-facing HTTP requests
+type AdminHandler struct {
+	streams StreamInspector
+	cache   CacheAdmin
+	audits  AuditReader
+	ratings RatingReconciler
+	logger  *logger.Logger
+}
+
+// NewAdminHandler creates a new admin handler.
+// streams is nil when events don't go through JetStream; StreamInfo then reports 503.
+func NewAdminHandler(streams StreamInspector, cache CacheAdmin, audits AuditReader, ratings RatingReconciler, log *logger.Logger) *AdminHandler {
+	return &AdminHandler{
+		streams: streams,
+		cache:   cache,
+		audits:  audits,
+		ratings: ratings,
+		logger:  log,
+	}
+}
+
+// CacheFlushResponse reports the outcome of a cache flush
+type CacheFlushResponse struct {
+	KeysRemoved int64 `json:"keys_removed"`
+}
+
+// RatingSnapshot is a product's stored rating and review count
+type RatingSnapshot struct {
+	AverageRating float64 `json:"average_rating"`
+	ReviewCount   int     `json:"review_count"`
+}
+
+// ReconcileResponse reports a product's rating before and after a full recalculation
+type ReconcileResponse struct {
+	ProductID uuid.UUID      `json:"product_id"`
+	Before    RatingSnapshot `json:"before"`
+	After     RatingSnapshot `json:"after"`
+	// StatsDrifted is true when the incremental totals disagreed with the reviews and were corrected
+	StatsDrifted bool `json:"stats_drifted"`
+	// CacheInvalidated is false when Redis was unavailable; the cached rating then lives until its TTL
+	CacheInvalidated bool `json:"cache_invalidated"`
+}
+
+// StreamInfo handles GET /api/v1/admin/stream-info
+// @Summary Get review event stream statistics
+// @Description Live JetStream stream backlog and rating-worker consumer counters (pending, redelivered, ack pending). Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Success 200 {object} map[string]any "Stream and consumer statistics"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 503 {object} map[string]string "JetStream unavailable, or EVENT_TRANSPORT isn't nats"
+// @Router /admin/stream-info [get]
+func (h *AdminHandler) StreamInfo(w http.ResponseWriter, r *http.Request) {
+	if h.streams == nil {
+		response.Error(w, http.StatusServiceUnavailable, "No event stream: EVENT_TRANSPORT is not nats")
+		return
+	}
+
+	stats, err := h.streams.Stats()
+	if err != nil {
+		h.logger.Error("Failed to get JetStream stream info", err)
+		response.Error(w, http.StatusServiceUnavailable, "Stream info unavailable")
+		return
+	}
+
+	response.Success(w, stats)
+}
+
+// FlushCache handles POST /api/v1/admin/cache/flush
+// @Summary Flush the product and review cache
+// @Description Remove every product and review cache entry, e.g. after a bulk import or schema change. Scans the cache's key namespace in batches rather than flushing the whole Redis database. Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Success 200 {object} CacheFlushResponse "Number of keys removed"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 503 {object} map[string]string "Cache unavailable"
+// @Router /admin/cache/flush [post]
+func (h *AdminHandler) FlushCache(w http.ResponseWriter, r *http.Request) {
+	removed, err := h.cache.FlushAll(r.Context())
+	if err != nil {
+		// Keys removed before the failure stay removed; a retry finishes the job
+		h.logger.WithFields(map[string]any{"keys_removed": removed}).Error("Failed to flush cache", err)
+		response.Error(w, http.StatusServiceUnavailable, "Cache flush failed")
+		return
+	}
+
+	response.Success(w, CacheFlushResponse{KeysRemoved: removed})
+}
+
+// InvalidateProductCache handles DELETE /api/v1/admin/cache/products/:id
+// @Summary Invalidate one product's cache
+// @Description Remove the cached rating, review pages and review overview of a single product, e.g. after fixing its rows by hand. Cheaper than a full flush, which cold-starts the cache for every product. Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param id path string true "Product ID (UUID)"
+// @Success 204 "Cache entries removed (or none existed)"
+// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 503 {object} map[string]string "Cache unavailable"
+// @Router /admin/cache/products/{id} [delete]
+func (h *AdminHandler) InvalidateProductCache(w http.ResponseWriter, r *http.Request) {
+	id, err := request.GetUUIDParam(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	if err := h.cache.InvalidateAllProductCache(r.Context(), id); err != nil {
+		h.logger.With("product_id", id).Error("Failed to invalidate product cache", err)
+		response.Error(w, http.StatusServiceUnavailable, "Cache invalidation failed")
+		return
+	}
+
+	h.logger.With("product_id", id).Info("Product cache invalidated by operator")
+	response.NoContent(w)
+}
+
+// Audit handles GET /api/v1/admin/audit
+// @Summary Get the audit trail of a product or review
+// @Description Who created, updated, deleted or anonymized an entity, with before/after snapshots, newest first. Anonymizing a review also scrubs the name from its earlier snapshots. Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param entity_id query string true "Product or review ID (UUID)"
+// @Param limit query int false "Number of entries per page (max 500)" default(50)
+// @Param offset query int false "Number of entries to skip" default(0)
+// @Success 200 {object} map[string]any "Paginated list of audit entries"
+// @Failure 400 {object} map[string]string "Missing or invalid entity_id"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/audit [get]
+func (h *AdminHandler) Audit(w http.ResponseWriter, r *http.Request) {
+	entityID, err := uuid.Parse(r.URL.Query().Get("entity_id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid entity_id")
+		return
+	}
+
+	limit, offset := request.GetPaginationParamsWithConfig(r, auditPagination)
+
+	entries, err := h.audits.ListByEntityID(r.Context(), entityID, limit, offset)
+	if err != nil {
+		h.logger.With("entity_id", entityID).Error("Failed to list audit entries", err)
+		response.ErrorWithCode(w, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
+		return
+	}
+
+	total, err := h.audits.CountByEntityID(r.Context(), entityID)
+	if err != nil {
+		h.logger.With("entity_id", entityID).Error("Failed to count audit entries", err)
+		response.ErrorWithCode(w, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
+		return
+	}
+
+	response.Paginated(w, entries, total, limit, offset)
+}
+
+// ReconcileProduct handles POST /api/v1/admin/products/:id/reconcile
+// @Summary Recalculate a product's rating from its reviews
+// @Description Rebuild the product's rating totals with a full scan of its live reviews instead of trusting the incrementally maintained ones, store the result and invalidate the product's cache. The escape hatch for a rating that drifted or missed an event. Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param id path string true "Product ID (UUID)"
+// @Success 200 {object} ReconcileResponse "Rating and review count before and after"
+// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/products/{id}/reconcile [post]
+func (h *AdminHandler) ReconcileProduct(w http.ResponseWriter, r *http.Request) {
+	id, err := request.GetUUIDParam(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	result, updated, err := h.ratings.Reconcile(r.Context(), id)
+	if err != nil {
+		h.logger.With("product_id", id).Error("Failed to reconcile product rating", err)
+		response.ErrorWithCode(w, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
+		return
+	}
+	if !updated {
+		response.Error(w, http.StatusNotFound, "Product not found")
+		return
+	}
+
+	// Non-fatal: the rating is already fixed in the database, so report the stale cache instead of failing
+	cacheInvalidated := true
+	if err := h.cache.InvalidateAllProductCache(r.Context(), id); err != nil {
+		h.logger.WithFields(map[string]any{
+			"product_id": id,
+			"error":      err.Error(),
+		}).Warn("Failed to invalidate product cache after reconciliation")
+		cacheInvalidated = false
+	}
+
+	h.logger.WithFields(map[string]any{
+		"product_id":    id,
+		"stats_drifted": result.Drifted(),
+	}).Info("Product rating reconciled by operator")
+
+	response.Success(w, ReconcileResponse{
+		ProductID:        id,
+		Before:           RatingSnapshot{AverageRating: result.PreviousRating, ReviewCount: result.PreviousCount},
+		After:            RatingSnapshot{AverageRating: result.Rating, ReviewCount: result.Actual.CountRatings},
+		StatsDrifted:     result.Drifted(),
+		CacheInvalidated: cacheInvalidated,
+	})
+}
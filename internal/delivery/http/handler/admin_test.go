@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/middleware"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
+	"github.com/Pesokrava/product_reviewer/internal/worker"
+)
+
+// fakeStreamInspector returns canned stream statistics
+type fakeStreamInspector struct {
+	stats *events.StreamStats
+	err   error
+}
+
+func (f *fakeStreamInspector) Stats() (*events.StreamStats, error) {
+	return f.stats, f.err
+}
+
+// fakeCacheAdmin returns canned cache maintenance results and records invalidations
+type fakeCacheAdmin struct {
+	removed     int64
+	err         error
+	invalidated []uuid.UUID
+}
+
+func (f *fakeCacheAdmin) FlushAll(ctx context.Context) (int64, error) {
+	return f.removed, f.err
+}
+
+func (f *fakeCacheAdmin) InvalidateAllProductCache(ctx context.Context, productID uuid.UUID) error {
+	f.invalidated = append(f.invalidated, productID)
+	return f.err
+}
+
+func TestAdminHandler_StreamInfo_Success(t *testing.T) {
+	inspector := &fakeStreamInspector{stats: &events.StreamStats{
+		Stream:   events.StreamState{Name: events.StreamName, Messages: 7},
+		Consumer: events.ConsumerState{Name: events.ConsumerName, NumPending: 5, NumRedelivered: 2, NumAckPending: 1},
+	}}
+	handler := NewAdminHandler(inspector, nil, nil, nil, logger.New("test"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/stream-info", nil)
+	w := httptest.NewRecorder()
+
+	handler.StreamInfo(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data events.StreamStats `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, uint64(5), response.Data.Consumer.NumPending)
+	assert.Equal(t, 2, response.Data.Consumer.NumRedelivered)
+	assert.Equal(t, 1, response.Data.Consumer.NumAckPending)
+}
+
+func TestAdminHandler_StreamInfo_Unavailable(t *testing.T) {
+	handler := NewAdminHandler(&fakeStreamInspector{err: assert.AnError}, nil, nil, nil, logger.New("test"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/stream-info", nil)
+	w := httptest.NewRecorder()
+
+	handler.StreamInfo(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestAdminHandler_StreamInfo_NoStream(t *testing.T) {
+	// EVENT_TRANSPORT=postgres has no JetStream to inspect
+	handler := NewAdminHandler(nil, nil, nil, nil, logger.New("test"))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/stream-info", nil)
+	w := httptest.NewRecorder()
+
+	handler.StreamInfo(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestAdminHandler_StreamInfo_RequiresAdminKey(t *testing.T) {
+	handler := NewAdminHandler(&fakeStreamInspector{stats: &events.StreamStats{}}, nil, nil, nil, logger.New("test"))
+
+	tests := []struct {
+		name       string
+		configured string
+		provided   string
+		wantStatus int
+	}{
+		{name: "disabled", configured: "", provided: "", wantStatus: http.StatusForbidden},
+		{name: "missing key", configured: "secret", provided: "", wantStatus: http.StatusUnauthorized},
+		{name: "wrong key", configured: "secret", provided: "nope", wantStatus: http.StatusUnauthorized},
+		{name: "valid key", configured: "secret", provided: "secret", wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			protected := middleware.AdminAuth(tt.configured)(http.HandlerFunc(handler.StreamInfo))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/stream-info", nil)
+			if tt.provided != "" {
+				req.Header.Set(middleware.AdminKeyHeader, tt.provided)
+			}
+			w := httptest.NewRecorder()
+
+			protected.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+}
+
+func TestAdminHandler_FlushCache(t *testing.T) {
+	tests := []struct {
+		name       string
+		flusher    *fakeCacheAdmin
+		wantStatus int
+	}{
+		{name: "success", flusher: &fakeCacheAdmin{removed: 42}, wantStatus: http.StatusOK},
+		{name: "redis down", flusher: &fakeCacheAdmin{removed: 3, err: assert.AnError}, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAdminHandler(nil, tt.flusher, nil, nil, logger.New("test"))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/cache/flush", nil)
+			w := httptest.NewRecorder()
+
+			handler.FlushCache(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response struct {
+					Data CacheFlushResponse `json:"data"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, int64(42), response.Data.KeysRemoved)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_InvalidateProductCache(t *testing.T) {
+	productID := uuid.New()
+
+	tests := []struct {
+		name       string
+		param      string
+		cache      *fakeCacheAdmin
+		wantStatus int
+	}{
+		{name: "success", param: productID.String(), cache: &fakeCacheAdmin{}, wantStatus: http.StatusNoContent},
+		{name: "invalid id", param: "not-a-uuid", cache: &fakeCacheAdmin{}, wantStatus: http.StatusBadRequest},
+		{name: "redis down", param: productID.String(), cache: &fakeCacheAdmin{err: assert.AnError}, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAdminHandler(nil, tt.cache, nil, nil, logger.New("test"))
+
+			req := httptest.NewRequest(http.MethodDelete, "/api/v1/admin/cache/products/"+tt.param, nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.param)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.InvalidateProductCache(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusBadRequest {
+				assert.Equal(t, []uuid.UUID{productID}, tt.cache.invalidated)
+			}
+		})
+	}
+}
+
+func TestAdminHandler_Audit(t *testing.T) {
+	entityID := uuid.New()
+	audits := &fakes.AuditRepository{Entries: []*domain.AuditEntry{
+		{ID: uuid.New(), Actor: "admin", Action: domain.AuditActionUpdate, EntityType: domain.AuditEntityReview, EntityID: entityID},
+		{ID: uuid.New(), Actor: "anonymous", Action: domain.AuditActionCreate, EntityType: domain.AuditEntityReview, EntityID: uuid.New()},
+	}}
+
+	tests := []struct {
+		name       string
+		query      string
+		audits     *fakes.AuditRepository
+		wantStatus int
+		wantTotal  int
+	}{
+		{name: "success", query: "?entity_id=" + entityID.String(), audits: audits, wantStatus: http.StatusOK, wantTotal: 1},
+		{name: "missing entity_id", query: "", audits: audits, wantStatus: http.StatusBadRequest},
+		{name: "invalid entity_id", query: "?entity_id=nope", audits: audits, wantStatus: http.StatusBadRequest},
+		{name: "database down", query: "?entity_id=" + entityID.String(), audits: &fakes.AuditRepository{Err: assert.AnError}, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAdminHandler(nil, nil, tt.audits, nil, logger.New("test"))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/audit"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			handler.Audit(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var response struct {
+					Data       []domain.AuditEntry `json:"data"`
+					Pagination struct {
+						Total int `json:"total"`
+					} `json:"pagination"`
+				}
+				assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+				assert.Equal(t, tt.wantTotal, response.Pagination.Total)
+				if assert.Len(t, response.Data, 1) {
+					assert.Equal(t, domain.AuditActionUpdate, response.Data[0].Action)
+				}
+			}
+		})
+	}
+}
+
+// fakeRatingReconciler returns a canned reconciliation
+type fakeRatingReconciler struct {
+	result  worker.Reconciliation
+	updated bool
+	err     error
+}
+
+func (f *fakeRatingReconciler) Reconcile(ctx context.Context, productID uuid.UUID) (worker.Reconciliation, bool, error) {
+	return f.result, f.updated, f.err
+}
+
+func TestAdminHandler_ReconcileProduct(t *testing.T) {
+	productID := uuid.New()
+	drifted := worker.Reconciliation{
+		Stored:         worker.ReviewStats{SumRatings: 5, CountRatings: 1},
+		Actual:         worker.ReviewStats{SumRatings: 9, CountRatings: 2},
+		PreviousRating: 5,
+		PreviousCount:  1,
+		Rating:         4.5,
+	}
+
+	tests := []struct {
+		name                 string
+		param                string
+		ratings              *fakeRatingReconciler
+		cache                *fakeCacheAdmin
+		wantStatus           int
+		wantCacheInvalidated bool
+	}{
+		{name: "success", param: productID.String(), ratings: &fakeRatingReconciler{result: drifted, updated: true}, cache: &fakeCacheAdmin{}, wantStatus: http.StatusOK, wantCacheInvalidated: true},
+		{name: "redis down", param: productID.String(), ratings: &fakeRatingReconciler{result: drifted, updated: true}, cache: &fakeCacheAdmin{err: assert.AnError}, wantStatus: http.StatusOK},
+		{name: "invalid id", param: "not-a-uuid", ratings: &fakeRatingReconciler{}, cache: &fakeCacheAdmin{}, wantStatus: http.StatusBadRequest},
+		{name: "product not found", param: productID.String(), ratings: &fakeRatingReconciler{}, cache: &fakeCacheAdmin{}, wantStatus: http.StatusNotFound},
+		{name: "database down", param: productID.String(), ratings: &fakeRatingReconciler{err: assert.AnError}, cache: &fakeCacheAdmin{}, wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAdminHandler(nil, tt.cache, nil, tt.ratings, logger.New("test"))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/products/"+tt.param+"/reconcile", nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", tt.param)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.ReconcileProduct(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus != http.StatusOK {
+				assert.Empty(t, tt.cache.invalidated, "nothing was reconciled, so the cache is left alone")
+				return
+			}
+
+			var response struct {
+				Data ReconcileResponse `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, RatingSnapshot{AverageRating: 5, ReviewCount: 1}, response.Data.Before)
+			assert.Equal(t, RatingSnapshot{AverageRating: 4.5, ReviewCount: 2}, response.Data.After)
+			assert.True(t, response.Data.StatsDrifted)
+			assert.Equal(t, tt.wantCacheInvalidated, response.Data.CacheInvalidated)
+			assert.Equal(t, []uuid.UUID{productID}, tt.cache.invalidated)
+		})
+	}
+}
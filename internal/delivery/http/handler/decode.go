@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+)
+
+// decodeJSON decodes the request body and writes the error response on failure.
+// Returns false when the handler should stop.
+func decodeJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	return writeDecodeError(w, r, request.DecodeJSON(r, v))
+}
+
+// decodeJSONArray is decodeJSON for array bodies. More than maxItems elements is reported
+// as a validation error on field, before the rest of the array is read.
+func decodeJSONArray(w http.ResponseWriter, r *http.Request, v any, maxItems int, field string) bool {
+	err := request.DecodeJSONArray(r, v, maxItems)
+	if errors.Is(err, request.ErrTooManyItems) {
+		response.ValidationError(w, map[string]string{
+			field: fmt.Sprintf("must contain at most %d items", maxItems),
+		})
+		return false
+	}
+	return writeDecodeError(w, r, err)
+}
+
+// writeDecodeError maps a request decoding error to its response
+func writeDecodeError(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err == nil {
+		return true
+	}
+
+	if errors.Is(err, request.ErrBodyTooLarge) {
+		response.Error(w, http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("Request body exceeds the %d byte limit", request.MaxBodySize(r)))
+		return false
+	}
+
+	if errors.Is(err, request.ErrUnsupportedMediaType) {
+		response.Error(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+		return false
+	}
+
+	response.Error(w, http.StatusBadRequest, "Invalid request body")
+	return false
+}
@@ -0,0 +1,262 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/version"
+)
+
+// readinessCheckTimeout bounds each dependency probe so one hung dependency
+// can't stall the orchestrator's readiness probe past its own timeout
+const readinessCheckTimeout = 2 * time.Second
+
+// Overall statuses reported by the detailed health endpoint
+const (
+	HealthStatusOK       = "ok"
+	HealthStatusDegraded = "degraded"
+	HealthStatusDown     = "down"
+)
+
+// HealthCheck probes a single dependency; nil means it can serve traffic
+type HealthCheck func(ctx context.Context) error
+
+// OutboxInspector reports the backlog of events waiting in the transactional outbox
+type OutboxInspector interface {
+	Lag(ctx context.Context) (*domain.OutboxLag, error)
+}
+
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	checks             map[string]HealthCheck
+	streams            StreamInspector
+	lagThreshold       uint64
+	outbox             OutboxInspector
+	outboxLagThreshold time.Duration
+	logger             *logger.Logger
+}
+
+// NewHealthHandler creates a new health handler with named dependency checks.
+// streams may be nil when no event stream is available; lagThreshold is the
+// number of pending rating-worker events above which health reports degraded.
+// outbox may be nil when EVENT_OUTBOX is off; outboxLagThreshold is how long the oldest
+// unsent event may wait before health reports degraded.
+func NewHealthHandler(
+	checks map[string]HealthCheck,
+	streams StreamInspector,
+	lagThreshold uint64,
+	outbox OutboxInspector,
+	outboxLagThreshold time.Duration,
+	log *logger.Logger,
+) *HealthHandler {
+	return &HealthHandler{
+		checks:             checks,
+		streams:            streams,
+		lagThreshold:       lagThreshold,
+		outbox:             outbox,
+		outboxLagThreshold: outboxLagThreshold,
+		logger:             log,
+	}
+}
+
+// DependencyHealth is the result of probing a single dependency
+type DependencyHealth struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// EventLagHealth reports how far the rating worker is behind the review event stream
+type EventLagHealth struct {
+	Status     string `json:"status"`
+	Pending    uint64 `json:"pending"`
+	AckPending int    `json:"ack_pending"`
+	Threshold  uint64 `json:"threshold"`
+	Error      string `json:"error,omitempty"`
+}
+
+// OutboxLagHealth reports how far the outbox relay is behind committed review events
+type OutboxLagHealth struct {
+	Status           string  `json:"status"`
+	Pending          int64   `json:"pending"`
+	OldestAgeSeconds float64 `json:"oldest_age_seconds"`
+	ThresholdSeconds float64 `json:"threshold_seconds"`
+	Error            string  `json:"error,omitempty"`
+}
+
+// DetailedHealth is the response of the detailed health endpoint
+type DetailedHealth struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyHealth `json:"dependencies"`
+	EventLag     *EventLagHealth             `json:"event_lag,omitempty"`
+	OutboxLag    *OutboxLagHealth            `json:"outbox_lag,omitempty"`
+	// Build identifies the running binary, so a health report also says which build it is about
+	Build version.Info `json:"build"`
+}
+
+// Ready handles GET /readyz
+// Returns 503 when any dependency check fails so load balancers stop routing to this instance
+func (h *HealthHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	status := http.StatusOK
+	results := make(map[string]string, len(h.checks))
+	for name, result := range h.runChecks(r.Context()) {
+		if result.Error != "" {
+			results[name] = result.Error
+			status = http.StatusServiceUnavailable
+			continue
+		}
+		results[name] = "ok"
+	}
+
+	readiness := "ready"
+	if status != http.StatusOK {
+		readiness = "not_ready"
+	}
+
+	response.JSON(w, status, map[string]any{
+		"status": readiness,
+		"checks": results,
+	})
+}
+
+// Version handles GET /version
+// Build metadata is public: it names the deployed build for support tickets and rollouts,
+// and carries nothing an attacker couldn't learn from the image
+func (h *HealthHandler) Version(w http.ResponseWriter, r *http.Request) {
+	response.JSON(w, http.StatusOK, version.Get(time.Now()))
+}
+
+// Detailed handles GET /api/v1/admin/health/detailed
+// @Summary Get detailed dependency health
+// @Description Per-dependency status and probe latency, plus rating-worker lag (pending JetStream events), outbox relay lag (unsent events and the oldest one's age, with EVENT_OUTBOX) and the running build's version, commit, build time and uptime. Reports "degraded" when either lag exceeds its configured threshold and "down" (503) when a dependency is unreachable. Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Success 200 {object} DetailedHealth "All dependencies reachable (status ok or degraded)"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 503 {object} DetailedHealth "At least one dependency is down"
+// @Router /admin/health/detailed [get]
+func (h *HealthHandler) Detailed(w http.ResponseWriter, r *http.Request) {
+	health := DetailedHealth{
+		Status:       HealthStatusOK,
+		Dependencies: h.runChecks(r.Context()),
+		Build:        version.Get(time.Now()),
+	}
+
+	for _, dep := range health.Dependencies {
+		if dep.Status == HealthStatusDown {
+			health.Status = HealthStatusDown
+		}
+	}
+
+	if h.streams != nil {
+		health.EventLag = h.eventLag()
+		// Lag alone never marks the service down: the API keeps serving, only ratings go stale
+		if health.EventLag.Status != HealthStatusOK && health.Status == HealthStatusOK {
+			health.Status = HealthStatusDegraded
+		}
+	}
+
+	if h.outbox != nil {
+		health.OutboxLag = h.outboxLag(r.Context())
+		if health.OutboxLag.Status != HealthStatusOK && health.Status == HealthStatusOK {
+			health.Status = HealthStatusDegraded
+		}
+	}
+
+	status := http.StatusOK
+	if health.Status == HealthStatusDown {
+		status = http.StatusServiceUnavailable
+	}
+
+	response.JSON(w, status, health)
+}
+
+// runChecks probes every dependency in name order, timing each probe
+func (h *HealthHandler) runChecks(ctx context.Context) map[string]DependencyHealth {
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := make(map[string]DependencyHealth, len(names))
+	for _, name := range names {
+		checkCtx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+		start := time.Now()
+		err := h.checks[name](checkCtx)
+		latency := time.Since(start)
+		cancel()
+
+		result := DependencyHealth{
+			Status:    HealthStatusOK,
+			LatencyMs: float64(latency.Microseconds()) / 1000,
+		}
+		if err != nil {
+			h.logger.With("dependency", name).Warnf("Readiness check failed: %v", err)
+			result.Status = HealthStatusDown
+			result.Error = err.Error()
+		}
+		results[name] = result
+	}
+
+	return results
+}
+
+// eventLag reads the rating-worker consumer backlog from JetStream
+func (h *HealthHandler) eventLag() *EventLagHealth {
+	lag := &EventLagHealth{
+		Status:    HealthStatusOK,
+		Threshold: h.lagThreshold,
+	}
+
+	stats, err := h.streams.Stats()
+	if err != nil {
+		h.logger.Error("Failed to get JetStream consumer info for health", err)
+		lag.Status = HealthStatusDegraded
+		lag.Error = err.Error()
+		return lag
+	}
+
+	lag.Pending = stats.Consumer.NumPending
+	lag.AckPending = stats.Consumer.NumAckPending
+	if lag.Pending > h.lagThreshold {
+		lag.Status = HealthStatusDegraded
+	}
+
+	return lag
+}
+
+// outboxLag reads the unsent event backlog from the outbox table. The age of the oldest
+// event, not the count, decides the status: a burst of writes is fine if the relay keeps up.
+func (h *HealthHandler) outboxLag(ctx context.Context) *OutboxLagHealth {
+	lag := &OutboxLagHealth{
+		Status:           HealthStatusOK,
+		ThresholdSeconds: h.outboxLagThreshold.Seconds(),
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	backlog, err := h.outbox.Lag(checkCtx)
+	if err != nil {
+		h.logger.Error("Failed to read the outbox backlog for health", err)
+		lag.Status = HealthStatusDegraded
+		lag.Error = err.Error()
+		return lag
+	}
+
+	lag.Pending = backlog.Pending
+	lag.OldestAgeSeconds = backlog.OldestAge.Seconds()
+	if backlog.OldestAge > h.outboxLagThreshold {
+		lag.Status = HealthStatusDegraded
+	}
+
+	return lag
+}
@@ -0,0 +1,216 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/version"
+)
+
+func TestHealthHandler_Ready(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name       string
+		checks     map[string]HealthCheck
+		wantStatus int
+		wantState  string
+	}{
+		{
+			name:       "all dependencies healthy",
+			checks:     map[string]HealthCheck{"postgres": healthy, "redis": healthy},
+			wantStatus: http.StatusOK,
+			wantState:  "ready",
+		},
+		{
+			name:       "one dependency down",
+			checks:     map[string]HealthCheck{"postgres": healthy, "redis": failing},
+			wantStatus: http.StatusServiceUnavailable,
+			wantState:  "not_ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(tt.checks, nil, 0, nil, 0, logger.New("test"))
+
+			req := httptest.NewRequest(http.MethodGet, "/readyz", nil)
+			w := httptest.NewRecorder()
+
+			handler.Ready(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var body struct {
+				Status string            `json:"status"`
+				Checks map[string]string `json:"checks"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantState, body.Status)
+			assert.Equal(t, "ok", body.Checks["postgres"])
+		})
+	}
+}
+
+func TestHealthHandler_Detailed(t *testing.T) {
+	healthy := func(ctx context.Context) error { return nil }
+	failing := func(ctx context.Context) error { return errors.New("connection refused") }
+	withPending := func(pending uint64) *fakeStreamInspector {
+		return &fakeStreamInspector{stats: &events.StreamStats{
+			Consumer: events.ConsumerState{Name: events.ConsumerName, NumPending: pending},
+		}}
+	}
+
+	tests := []struct {
+		name       string
+		checks     map[string]HealthCheck
+		streams    StreamInspector
+		wantStatus int
+		wantHealth string
+		wantLag    string
+	}{
+		{
+			name:       "healthy with small backlog",
+			checks:     map[string]HealthCheck{"postgres": healthy},
+			streams:    withPending(10),
+			wantStatus: http.StatusOK,
+			wantHealth: HealthStatusOK,
+			wantLag:    HealthStatusOK,
+		},
+		{
+			name:       "worker far behind",
+			checks:     map[string]HealthCheck{"postgres": healthy},
+			streams:    withPending(500),
+			wantStatus: http.StatusOK,
+			wantHealth: HealthStatusDegraded,
+			wantLag:    HealthStatusDegraded,
+		},
+		{
+			name:       "consumer info unavailable",
+			checks:     map[string]HealthCheck{"postgres": healthy},
+			streams:    &fakeStreamInspector{err: assert.AnError},
+			wantStatus: http.StatusOK,
+			wantHealth: HealthStatusDegraded,
+			wantLag:    HealthStatusDegraded,
+		},
+		{
+			name:       "dependency down",
+			checks:     map[string]HealthCheck{"postgres": failing},
+			streams:    withPending(500),
+			wantStatus: http.StatusServiceUnavailable,
+			wantHealth: HealthStatusDown,
+			wantLag:    HealthStatusDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(tt.checks, tt.streams, 100, nil, 0, logger.New("test"))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/health/detailed", nil)
+			w := httptest.NewRecorder()
+
+			handler.Detailed(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+
+			var body DetailedHealth
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantHealth, body.Status)
+			if assert.NotNil(t, body.EventLag) {
+				assert.Equal(t, tt.wantLag, body.EventLag.Status)
+				assert.Equal(t, uint64(100), body.EventLag.Threshold)
+			}
+			assert.Contains(t, body.Dependencies, "postgres")
+			assert.Equal(t, version.Version, body.Build.Version)
+			assert.Nil(t, body.OutboxLag, "reported only with the outbox enabled")
+		})
+	}
+}
+
+// fakeOutboxInspector returns a fixed backlog or error
+type fakeOutboxInspector struct {
+	lag *domain.OutboxLag
+	err error
+}
+
+func (f *fakeOutboxInspector) Lag(ctx context.Context) (*domain.OutboxLag, error) {
+	return f.lag, f.err
+}
+
+func TestHealthHandler_Detailed_OutboxLag(t *testing.T) {
+	healthy := map[string]HealthCheck{"postgres": func(ctx context.Context) error { return nil }}
+
+	tests := []struct {
+		name       string
+		outbox     *fakeOutboxInspector
+		wantHealth string
+	}{
+		{
+			name:       "relay keeping up",
+			outbox:     &fakeOutboxInspector{lag: &domain.OutboxLag{Pending: 500, OldestAge: 2 * time.Second}},
+			wantHealth: HealthStatusOK,
+		},
+		{
+			name:       "oldest event waiting too long",
+			outbox:     &fakeOutboxInspector{lag: &domain.OutboxLag{Pending: 3, OldestAge: 5 * time.Minute}},
+			wantHealth: HealthStatusDegraded,
+		},
+		{
+			name:       "backlog unreadable",
+			outbox:     &fakeOutboxInspector{err: assert.AnError},
+			wantHealth: HealthStatusDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewHealthHandler(healthy, nil, 0, tt.outbox, time.Minute, logger.New("test"))
+			w := httptest.NewRecorder()
+
+			handler.Detailed(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/health/detailed", nil))
+
+			assert.Equal(t, http.StatusOK, w.Code, "outbox lag never marks the service down")
+			var body DetailedHealth
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			assert.Equal(t, tt.wantHealth, body.Status)
+			require.NotNil(t, body.OutboxLag)
+			assert.Equal(t, tt.wantHealth, body.OutboxLag.Status)
+			assert.Equal(t, float64(60), body.OutboxLag.ThresholdSeconds)
+			if tt.outbox.lag != nil {
+				assert.Equal(t, tt.outbox.lag.Pending, body.OutboxLag.Pending)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_Version(t *testing.T) {
+	original := version.Commit
+	version.Commit = "3f2c1a9"
+	t.Cleanup(func() { version.Commit = original })
+
+	handler := NewHealthHandler(nil, nil, 0, nil, 0, logger.New("test"))
+	w := httptest.NewRecorder()
+
+	handler.Version(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	var body version.Info
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "dev", body.Version)
+	assert.Equal(t, "3f2c1a9", body.Commit)
+	assert.False(t, body.StartedAt.IsZero())
+	assert.GreaterOrEqual(t, body.UptimeSeconds, int64(0))
+}
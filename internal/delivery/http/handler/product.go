@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
@@ -13,14 +14,21 @@ import (
 )
 
 type ProductHandler struct {
-	service *product.Service
-	logger  *logger.Logger
+	service       *product.Service
+	pagination    request.PaginationConfig
+	compareMaxIDs int
+	// minReviewsForRating is the review count below which average_rating is returned as null
+	minReviewsForRating int
+	logger              *logger.Logger
 }
 
-func NewProductHandler(service *product.Service, log *logger.Logger) *ProductHandler {
+func NewProductHandler(service *product.Service, pagination request.PaginationConfig, compareMaxIDs, minReviewsForRating int, log *logger.Logger) *ProductHandler {
 	return &ProductHandler{
-		service: service,
-		logger:  log,
+		service:             service,
+		pagination:          pagination,
+		compareMaxIDs:       compareMaxIDs,
+		minReviewsForRating: minReviewsForRating,
+		logger:              log,
 	}
 }
 
@@ -28,45 +36,58 @@ type CreateProductRequest struct {
 	Name        string  `json:"name" validate:"required,min=1,max=255"`
 	Description *string `json:"description,omitempty"`
 	Price       float64 `json:"price" validate:"required,gte=0"`
+	// ReviewsEnabled false rejects new reviews for the product; omitted means true
+	ReviewsEnabled *bool `json:"reviews_enabled,omitempty" example:"true"`
 }
 
 type UpdateProductRequest struct {
 	Name        string  `json:"name" validate:"required,min=1,max=255"`
 	Description *string `json:"description,omitempty"`
 	Price       float64 `json:"price" validate:"required,gte=0"`
-	Version     int     `json:"version" validate:"required,gte=1"`
+	// ReviewsEnabled freezes or reopens reviews; omitted keeps the stored value
+	ReviewsEnabled *bool `json:"reviews_enabled,omitempty" example:"true"`
+	Version        int   `json:"version" validate:"required,gte=1"`
+}
+
+// reviewsEnabled applies the default for a reviews_enabled omitted on create
+func reviewsEnabled(requested *bool) bool {
+	return requested == nil || *requested
 }
 
 // Create handles POST /api/v1/products
 // @Summary Create a new product
-// @Description Create a new product with name, description, and price
+// @Description Create a new product with name, description, and price. Set reviews_enabled to false to open it with reviews frozen.
 // @Tags Products
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param product body CreateProductRequest true "Product details"
-// @Success 201 {object} map[string]any "Product created successfully"
+// @Param Accept-Language header string false "Language for validation messages (en, de)" default(en)
+// @Success 201 {object} ProductResponse "Product created successfully"
 // @Failure 400 {object} map[string]string "Invalid request body"
+// @Failure 409 {object} map[string]string "Name already used by another product (when ENFORCE_UNIQUE_PRODUCT_NAME is on)"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /products [post]
 func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateProductRequest
-	if err := request.DecodeJSON(r, &req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	product := &domain.Product{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
+		Name:           req.Name,
+		Description:    req.Description,
+		Price:          req.Price,
+		ReviewsEnabled: reviewsEnabled(req.ReviewsEnabled),
 	}
 
 	if err := h.service.Create(r.Context(), product); err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	response.Created(w, product)
+	response.Created(w, toProductResponse(product, h.minReviewsForRating))
 }
 
 // GetByID handles GET /api/v1/products/:id
@@ -74,9 +95,9 @@ func (h *ProductHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Description Get detailed information about a product including average rating
 // @Tags Products
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param id path string true "Product ID (UUID)"
-// @Success 200 {object} map[string]any "Product details"
+// @Success 200 {object} ProductResponse "Product details"
 // @Failure 400 {object} map[string]string "Invalid product ID"
 // @Failure 404 {object} map[string]string "Product not found"
 // @Failure 500 {object} map[string]string "Internal server error"
@@ -90,11 +111,11 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 
 	product, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	response.Success(w, product)
+	response.Success(w, toProductResponse(product, h.minReviewsForRating))
 }
 
 // List handles GET /api/v1/products
@@ -102,35 +123,100 @@ func (h *ProductHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 // @Description Get a paginated list of products
 // @Tags Products
 // @Accept json
-// @Produce json
-// @Param limit query int false "Number of items per page (max 100)" default(20)
+// @Produce json,application/vnd.productreviews.v1+json
+// @Param limit query int false "Number of items per page (default PRODUCTS_PAGE_SIZE_DEFAULT, max PRODUCTS_PAGE_SIZE_MAX)" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
 // @Success 200 {object} map[string]any "Paginated list of products"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /products [get]
 func (h *ProductHandler) List(w http.ResponseWriter, r *http.Request) {
-	limit, offset := request.GetPaginationParams(r)
+	limit, offset := request.GetPaginationParamsWithConfig(r, h.pagination)
 
 	products, total, err := h.service.List(r.Context(), limit, offset)
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.Paginated(w, toProductResponses(products, h.minReviewsForRating), total, limit, offset)
+}
+
+// Unreviewed handles GET /api/v1/admin/products/unreviewed
+// @Summary List products without reviews
+// @Description Paginated list of products with a review_count of 0, newest first, so merchandising can solicit reviews for them. Uses the review count kept by the rating worker, so a product reviewed moments ago may still be listed. Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param limit query int false "Number of items per page (default PRODUCTS_PAGE_SIZE_DEFAULT, max PRODUCTS_PAGE_SIZE_MAX)" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} map[string]any "Paginated list of products"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/products/unreviewed [get]
+func (h *ProductHandler) Unreviewed(w http.ResponseWriter, r *http.Request) {
+	limit, offset := request.GetPaginationParamsWithConfig(r, h.pagination)
+
+	products, total, err := h.service.ListUnreviewed(r.Context(), limit, offset)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.Paginated(w, toProductResponses(products, h.minReviewsForRating), total, limit, offset)
+}
+
+// Compare handles GET /api/v1/products/compare
+// @Summary Compare products
+// @Description Get several products with their average rating, review count and rating distribution (count per star, 1 to MAX_RATING) in one call, in the order requested. Duplicate IDs are ignored.
+// @Tags Products
+// @Accept json
+// @Produce json,application/vnd.productreviews.v1+json
+// @Param ids query string true "Comma-separated product IDs (UUIDs), at most PRODUCTS_COMPARE_MAX_IDS"
+// @Success 200 {array} ProductComparisonResponse "Products with their rating distributions"
+// @Failure 400 {object} map[string]string "Missing, invalid or too many product IDs"
+// @Failure 404 {object} map[string]string "One of the products was not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/compare [get]
+func (h *ProductHandler) Compare(w http.ResponseWriter, r *http.Request) {
+	maxIDs := min(h.compareMaxIDs, request.MaxBatchItems(r))
+	ids, err := request.GetUUIDListQuery(r, "ids", maxIDs)
+	if errors.Is(err, request.ErrTooManyItems) {
+		response.Error(w, http.StatusBadRequest, fmt.Sprintf("At most %d products can be compared", maxIDs))
+		return
+	}
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID in ids")
+		return
+	}
+	if len(ids) == 0 {
+		response.Error(w, http.StatusBadRequest, "ids is required")
+		return
+	}
+
+	comparisons, err := h.service.Compare(r.Context(), ids)
+	if err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
-	response.Paginated(w, products, total, limit, offset)
+	response.Success(w, toProductComparisonResponses(comparisons, h.minReviewsForRating))
 }
 
 // Update handles PUT /api/v1/products/:id
 // @Summary Update a product
-// @Description Update product details (name, description, price). Requires version field for optimistic locking. If another client modifies the product between GET and PUT, you'll receive 409 Conflict. Fetch latest version and retry.
+// @Description Update product details (name, description, price, reviews_enabled). Omitted reviews_enabled keeps the current value. Requires version field for optimistic locking. If another client modifies the product between GET and PUT, you'll receive 409 Conflict. Fetch latest version and retry.
 // @Tags Products
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param id path string true "Product ID (UUID)"
 // @Param product body UpdateProductRequest true "Updated product details"
-// @Success 200 {object} map[string]any "Product updated successfully"
+// @Param Accept-Language header string false "Language for validation messages (en, de)" default(en)
+// @Success 200 {object} ProductResponse "Product updated successfully"
 // @Failure 400 {object} map[string]string "Invalid request"
-// @Failure 409 {object} map[string]string "Version conflict - product was modified. Fetch latest version and retry."
+// @Failure 409 {object} map[string]string "Version conflict (CONFLICT) or name already used by another product (ALREADY_EXISTS)"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /products/{id} [put]
 func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -141,13 +227,12 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req UpdateProductRequest
-	if err := request.DecodeJSON(r, &req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
 	if err := pkgValidator.Get().Struct(&req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid input")
+		response.ValidationError(w, pkgValidator.TranslateErrors(err, r.Header.Get("Accept-Language")))
 		return
 	}
 
@@ -159,12 +244,12 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 		Version:     req.Version,
 	}
 
-	if err := h.service.Update(r.Context(), product); err != nil {
-		h.handleError(w, err)
+	if err := h.service.Update(r.Context(), product, req.ReviewsEnabled); err != nil {
+		h.handleError(w, r, err)
 		return
 	}
 
-	response.Success(w, product)
+	response.Success(w, toProductResponse(product, h.minReviewsForRating))
 }
 
 // Delete handles DELETE /api/v1/products/:id
@@ -172,7 +257,7 @@ func (h *ProductHandler) Update(w http.ResponseWriter, r *http.Request) {
 // @Description Soft delete a product and all its reviews
 // @Tags Products
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param id path string true "Product ID (UUID)"
 // @Success 204 "Product deleted successfully"
 // @Failure 400 {object} map[string]string "Invalid product ID"
@@ -187,23 +272,25 @@ func (h *ProductHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Delete(r.Context(), id); err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
 	response.NoContent(w)
 }
 
-func (h *ProductHandler) handleError(w http.ResponseWriter, err error) {
+func (h *ProductHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
-		response.Error(w, http.StatusNotFound, "Product not found")
+		response.ErrorWithCode(w, http.StatusNotFound, response.CodeNotFound, "Product not found")
 	case errors.Is(err, domain.ErrInvalidInput):
-		response.Error(w, http.StatusBadRequest, "Invalid input")
+		response.ValidationError(w, pkgValidator.TranslateErrors(err, r.Header.Get("Accept-Language")))
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.ErrorWithCode(w, http.StatusConflict, response.CodeAlreadyExists, "A product with this name already exists")
 	case errors.Is(err, domain.ErrConflict):
-		response.Error(w, http.StatusConflict, "Version conflict - product was modified. Fetch latest version and retry.")
+		response.ErrorWithCode(w, http.StatusConflict, response.CodeConflict, "Version conflict - product was modified. Fetch latest version and retry.")
 	default:
 		h.logger.Error("Internal error in product handler", err)
-		response.Error(w, http.StatusInternalServerError, "Internal server error")
+		response.ErrorWithCode(w, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
 	}
 }
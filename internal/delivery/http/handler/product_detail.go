@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/product"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+// defaultDetailReviewsLimit matches the first page size storefront product pages render
+const defaultDetailReviewsLimit = 10
+
+// ProductDetailHandler serves product pages in one round trip by combining product and review data
+type ProductDetailHandler struct {
+	productService *product.Service
+	reviewService  *review.Service
+	// minReviewsForRating is the review count below which average_rating is returned as null
+	minReviewsForRating int
+	logger              *logger.Logger
+}
+
+// NewProductDetailHandler creates a new product detail handler
+func NewProductDetailHandler(productService *product.Service, reviewService *review.Service, minReviewsForRating int, log *logger.Logger) *ProductDetailHandler {
+	return &ProductDetailHandler{
+		productService:      productService,
+		reviewService:       reviewService,
+		minReviewsForRating: minReviewsForRating,
+		logger:              log,
+	}
+}
+
+// ProductDetailResponse is a product with its first page of reviews and rating distribution
+type ProductDetailResponse struct {
+	Product            ProductResponse  `json:"product"`
+	Reviews            []ReviewResponse `json:"reviews"`
+	TotalReviews       int              `json:"total_reviews"`
+	RatingDistribution map[int]int      `json:"rating_distribution"`
+}
+
+// Get handles GET /api/v1/products/:id/detail
+// @Summary Get a product with its reviews
+// @Description Get a product, its first page of reviews (newest first), total review count and rating distribution (count per star, 1 to MAX_RATING) in one call. The review portion is cached; the product itself is always read fresh.
+// @Tags Products
+// @Produce json,application/vnd.productreviews.v1+json
+// @Param id path string true "Product ID (UUID)"
+// @Param reviews_limit query int false "Number of reviews to include (max 100)" default(10)
+// @Success 200 {object} ProductDetailResponse "Product with reviews"
+// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/{id}/detail [get]
+func (h *ProductDetailHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := request.GetUUIDParam(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	limit := request.GetIntQuery(r, "reviews_limit", defaultDetailReviewsLimit)
+	if limit <= 0 || limit > 100 {
+		limit = defaultDetailReviewsLimit
+	}
+
+	// Product is read fresh so edits and deletions show immediately; only reviews come from cache
+	prod, err := h.productService.GetByID(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	overview, err := h.reviewService.GetOverview(r.Context(), id, limit)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, ProductDetailResponse{
+		Product:            toProductResponse(prod, h.minReviewsForRating),
+		Reviews:            toReviewResponses(overview.Reviews),
+		TotalReviews:       overview.Total,
+		RatingDistribution: overview.RatingDistribution,
+	})
+}
+
+// ReviewSummaryResponse is the compact rating summary for a product card
+type ReviewSummaryResponse struct {
+	// AverageRating is null until the product has MIN_REVIEWS_FOR_RATING reviews
+	AverageRating       *float64    `json:"average_rating"`
+	ReviewCount         int         `json:"review_count"`
+	Distribution        map[int]int `json:"distribution"`
+	LatestReviewExcerpt *string     `json:"latest_review_excerpt"`
+}
+
+// Summary handles GET /api/v1/products/:id/reviews/summary
+// @Summary Get a product's review summary
+// @Description Get a product's average rating, review count, rating distribution (count per star, 1 to MAX_RATING) and an excerpt of its newest review (null when there are none). A lighter, fully cached alternative to /detail for product cards in listing grids.
+// @Tags Products
+// @Produce json,application/vnd.productreviews.v1+json
+// @Param id path string true "Product ID (UUID)"
+// @Success 200 {object} ReviewSummaryResponse "Review summary"
+// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/{id}/reviews/summary [get]
+func (h *ProductDetailHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	id, err := request.GetUUIDParam(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
+		return
+	}
+
+	summary, err := h.reviewService.GetSummary(r.Context(), id)
+	if err != nil {
+		h.handleError(w, err)
+		return
+	}
+
+	response.Success(w, ReviewSummaryResponse{
+		AverageRating:       displayedRating(summary.AverageRating, summary.ReviewCount, h.minReviewsForRating),
+		ReviewCount:         summary.ReviewCount,
+		Distribution:        summary.RatingDistribution,
+		LatestReviewExcerpt: summary.LatestReviewExcerpt,
+	})
+}
+
+func (h *ProductDetailHandler) handleError(w http.ResponseWriter, err error) {
+	if errors.Is(err, domain.ErrNotFound) {
+		response.ErrorWithCode(w, http.StatusNotFound, response.CodeNotFound, "Product not found")
+		return
+	}
+
+	h.logger.Error("Internal error in product detail handler", err)
+	response.ErrorWithCode(w, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/product"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+func newProductDetailRequest(productID, query string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID+"/detail"+query, nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestProductDetailHandler_Get_Success(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	productService := product.NewService(mockProductRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	reviewService := review.NewService(mockReviewRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewProductDetailHandler(productService, reviewService, 0, log)
+
+	productID := uuid.New()
+	reviews := []*domain.Review{{ID: uuid.New(), ProductID: productID, Rating: 4}}
+
+	mockProductRepo.On("GetByID", mock.Anything, productID).Return(&domain.Product{ID: productID, Name: "Test Product"}, nil)
+	mockCache.On("GetReviewOverview", mock.Anything, productID, 5).Return(nil, domain.ErrNotFound)
+	mockReviewRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 5, 0).Return(reviews, nil)
+	mockReviewRepo.On("CountByProductID", mock.Anything, productID).Return(1, nil)
+	mockReviewRepo.On("GetRatingDistribution", mock.Anything, productID).Return(map[int]int{4: 1}, nil)
+	mockCache.On("SetReviewOverview", mock.Anything, productID, 5, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler.Get(w, newProductDetailRequest(productID.String(), "?reviews_limit=5"))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data ProductDetailResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, productID, body.Data.Product.ID)
+	assert.Len(t, body.Data.Reviews, 1)
+	assert.Equal(t, 1, body.Data.TotalReviews)
+	assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 0, 4: 1, 5: 0}, body.Data.RatingDistribution)
+	mockCache.AssertExpectations(t)
+}
+
+func TestProductDetailHandler_Get_ProductNotFound(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	productService := product.NewService(mockProductRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	reviewService := review.NewService(mockReviewRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewProductDetailHandler(productService, reviewService, 0, log)
+
+	productID := uuid.New()
+	mockProductRepo.On("GetByID", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	handler.Get(w, newProductDetailRequest(productID.String(), ""))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockCache.AssertNotCalled(t, "GetReviewOverview")
+}
+
+func newReviewSummaryRequest(productID string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID+"/reviews/summary", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestProductDetailHandler_Summary_Success(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	productService := product.NewService(mockProductRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	reviewService := review.NewService(mockReviewRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{Products: mockProductRepo}, log)
+	handler := NewProductDetailHandler(productService, reviewService, 0, log)
+
+	productID := uuid.New()
+	latest := []*domain.Review{{ID: uuid.New(), ProductID: productID, ReviewText: "Solid", Rating: 4}}
+
+	mockCache.On("GetReviewSummary", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+	mockProductRepo.On("GetByID", mock.Anything, productID).Return(&domain.Product{ID: productID, AverageRating: 4.0, ReviewCount: 1}, nil)
+	mockReviewRepo.On("GetRatingDistribution", mock.Anything, productID).Return(map[int]int{4: 1}, nil)
+	mockReviewRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 1, 0).Return(latest, nil)
+	mockCache.On("SetReviewSummary", mock.Anything, productID, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	handler.Summary(w, newReviewSummaryRequest(productID.String()))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 4.0, body.Data["average_rating"])
+	assert.Equal(t, 1.0, body.Data["review_count"])
+	assert.Equal(t, map[string]any{"1": 0.0, "2": 0.0, "3": 0.0, "4": 1.0, "5": 0.0}, body.Data["distribution"])
+	assert.Equal(t, "Solid", body.Data["latest_review_excerpt"])
+	mockCache.AssertExpectations(t)
+}
+
+func TestProductDetailHandler_Summary_BelowMinReviewsForRating(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	productService := product.NewService(mockProductRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	reviewService := review.NewService(mockReviewRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{Products: mockProductRepo}, log)
+	handler := NewProductDetailHandler(productService, reviewService, 5, log)
+
+	productID := uuid.New()
+	mockCache.On("GetReviewSummary", mock.Anything, productID).Return(&domain.ReviewSummary{
+		AverageRating:      5.0,
+		ReviewCount:        1,
+		RatingDistribution: map[int]int{5: 1},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	handler.Summary(w, newReviewSummaryRequest(productID.String()))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Contains(t, body.Data, "average_rating")
+	assert.Nil(t, body.Data["average_rating"], "a single review shouldn't show as a 5.0 average")
+	assert.Equal(t, 1.0, body.Data["review_count"])
+}
+
+func TestProductDetailHandler_Summary_ProductNotFound(t *testing.T) {
+	mockProductRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	productService := product.NewService(mockProductRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	reviewService := review.NewService(mockReviewRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{Products: mockProductRepo}, log)
+	handler := NewProductDetailHandler(productService, reviewService, 0, log)
+
+	productID := uuid.New()
+	mockCache.On("GetReviewSummary", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+	mockProductRepo.On("GetByID", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+
+	w := httptest.NewRecorder()
+	handler.Summary(w, newReviewSummaryRequest(productID.String()))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockCache.AssertNotCalled(t, "SetReviewSummary")
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// ProductResponse is a product as the API returns it. deleted_at is left out: deleted
+// products are never served, so it would always be empty.
+type ProductResponse struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description *string   `json:"description,omitempty"`
+	Price       float64   `json:"price"`
+	// AverageRating is null until the product has MIN_REVIEWS_FOR_RATING reviews
+	AverageRating *float64 `json:"average_rating"`
+	ReviewCount   int      `json:"review_count"`
+	// ReviewsEnabled false means new reviews are rejected with 403
+	ReviewsEnabled bool `json:"reviews_enabled"`
+	// Version must be sent back on update (optimistic locking)
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductComparisonResponse is one product on a comparison page with its rating distribution
+type ProductComparisonResponse struct {
+	Product            ProductResponse `json:"product"`
+	RatingDistribution map[int]int     `json:"rating_distribution"`
+}
+
+// displayedRating hides an average built on fewer than minReviews reviews, so a product
+// with a single 5-star review doesn't look perfect next to one with hundreds
+func displayedRating(average float64, reviewCount, minReviews int) *float64 {
+	if reviewCount < minReviews {
+		return nil
+	}
+	return &average
+}
+
+func toProductResponse(product *domain.Product, minReviews int) ProductResponse {
+	return ProductResponse{
+		ID:             product.ID,
+		Name:           product.Name,
+		Description:    product.Description,
+		Price:          product.Price,
+		AverageRating:  displayedRating(product.AverageRating, product.ReviewCount, minReviews),
+		ReviewCount:    product.ReviewCount,
+		ReviewsEnabled: product.ReviewsEnabled,
+		Version:        product.Version,
+		CreatedAt:      product.CreatedAt,
+		UpdatedAt:      product.UpdatedAt,
+	}
+}
+
+func toProductResponses(products []*domain.Product, minReviews int) []ProductResponse {
+	responses := make([]ProductResponse, len(products))
+	for i, product := range products {
+		responses[i] = toProductResponse(product, minReviews)
+	}
+	return responses
+}
+
+func toProductComparisonResponses(comparisons []*domain.ProductComparison, minReviews int) []ProductComparisonResponse {
+	responses := make([]ProductComparisonResponse, len(comparisons))
+	for i, comparison := range comparisons {
+		responses[i] = ProductComparisonResponse{
+			Product:            toProductResponse(comparison.Product, minReviews),
+			RatingDistribution: comparison.RatingDistribution,
+		}
+	}
+	return responses
+}
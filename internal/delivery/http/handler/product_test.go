@@ -7,15 +7,20 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
 	"github.com/Pesokrava/product_reviewer/internal/domain"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
 	"github.com/Pesokrava/product_reviewer/internal/usecase/product"
 )
 
@@ -37,6 +42,14 @@ func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Product, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]*domain.Product), args.Error(1)
+}
+
 func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
 	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
@@ -45,6 +58,22 @@ func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]
 	return args.Get(0).([]*domain.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) ListWithTotal(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductRepository) ListUnreviewed(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Product), args.Int(1), args.Error(2)
+}
+
 func (m *MockProductRepository) Update(ctx context.Context, prod *domain.Product) error {
 	args := m.Called(ctx, prod)
 	return args.Error(0)
@@ -65,6 +94,11 @@ func (m *MockProductRepository) Count(ctx context.Context) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockProductRepository) NameTaken(ctx context.Context, name string, excludeID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, name, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
 // MockReviewRepository is a mock implementation of domain.ReviewRepository
 type MockReviewRepository struct {
 	mock.Mock
@@ -83,8 +117,16 @@ func (m *MockReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*doma
 	return args.Get(0).(*domain.Review), args.Error(1)
 }
 
-func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, error) {
-	args := m.Called(ctx, productID, limit, offset)
+func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, sort string, limit, offset int) ([]*domain.Review, error) {
+	args := m.Called(ctx, productID, sort, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language, sort string, limit, offset int) ([]*domain.Review, error) {
+	args := m.Called(ctx, productID, language, sort, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -101,6 +143,14 @@ func (m *MockReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockReviewRepository) DeleteBatch(ctx context.Context, ids []uuid.UUID) ([]*domain.Review, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
 func (m *MockReviewRepository) DeleteByProductID(ctx context.Context, productID uuid.UUID) error {
 	args := m.Called(ctx, productID)
 	return args.Error(0)
@@ -111,11 +161,80 @@ func (m *MockReviewRepository) CountByProductID(ctx context.Context, productID u
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockReviewRepository) CountByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language string) (int, error) {
+	args := m.Called(ctx, productID, language)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingDistribution(ctx context.Context, productID uuid.UUID) (map[int]int, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingDistributions(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]map[int]int, error) {
+	args := m.Called(ctx, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]map[int]int), args.Error(1)
+}
+
+func (m *MockReviewRepository) ChangesSince(ctx context.Context, since time.Time, afterID uuid.UUID, limit int) ([]*domain.Review, error) {
+	args := m.Called(ctx, since, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) Recent(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RecentReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) Anonymize(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) Flag(ctx context.Context, flag *domain.ReviewFlag) (*domain.FlaggedReview, error) {
+	args := m.Called(ctx, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FlaggedReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListFlagged(ctx context.Context, threshold, limit, offset int) ([]*domain.FlaggedReview, error) {
+	args := m.Called(ctx, threshold, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FlaggedReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) CountFlagged(ctx context.Context, threshold int) (int, error) {
+	args := m.Called(ctx, threshold)
+	return args.Int(0), args.Error(1)
+}
+
+// defaultCompareMaxIDs matches the PRODUCTS_COMPARE_MAX_IDS default
+const defaultCompareMaxIDs = 10
+
 func TestProductHandler_Create_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	requestBody := CreateProductRequest{
 		Name:  "Test Product",
@@ -128,7 +247,7 @@ func TestProductHandler_Create_Success(t *testing.T) {
 	w := httptest.NewRecorder()
 
 	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
-		return p.Name == "Test Product" && p.Price == 99.99
+		return p.Name == "Test Product" && p.Price == 99.99 && p.ReviewsEnabled
 	})).Return(nil)
 
 	handler.Create(w, req)
@@ -142,11 +261,37 @@ func TestProductHandler_Create_Success(t *testing.T) {
 	assert.Contains(t, response, "data")
 }
 
+func TestProductHandler_Create_ReviewsDisabled(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	log := logger.New("test")
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products",
+		strings.NewReader(`{"name":"Old Model","price":10,"reviews_enabled":false}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+		return !p.ReviewsEnabled
+	})).Return(nil)
+
+	handler.Create(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp struct {
+		Data ProductResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.False(t, resp.Data.ReviewsEnabled)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestProductHandler_Create_InvalidJSON(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader([]byte("invalid json")))
 	req.Header.Set("Content-Type", "application/json")
@@ -159,13 +304,36 @@ func TestProductHandler_Create_InvalidJSON(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Contains(t, response["error"], "Invalid request body")
+	assert.Equal(t, "INVALID_REQUEST", response["code"])
+}
+
+func TestProductHandler_Create_BodyTooLarge(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	log := logger.New("test")
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+	body := []byte(`{"name":"Test Product","description":"` + strings.Repeat("a", 256) + `","price":10}`)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(request.WithMaxBodySize(req.Context(), 64))
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var response map[string]string
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "PAYLOAD_TOO_LARGE", response["code"])
+	mockRepo.AssertNotCalled(t, "Create")
 }
 
 func TestProductHandler_Create_ValidationError(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	requestBody := CreateProductRequest{
 		Name:  "", // Invalid: empty name
@@ -180,13 +348,45 @@ func TestProductHandler_Create_ValidationError(t *testing.T) {
 	handler.Create(w, req)
 
 	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Code   string            `json:"code"`
+		Fields map[string]string `json:"fields"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "VALIDATION_FAILED", response.Code)
+	assert.Equal(t, "name is a required field", response.Fields["name"])
+}
+
+func TestProductHandler_Create_ValidationError_Localized(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	log := logger.New("test")
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+	bodyBytes, _ := json.Marshal(CreateProductRequest{Name: "", Price: 99.99})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "fr;q=0.9, de-AT, en;q=0.5")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Fields map[string]string `json:"fields"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "name ist ein Pflichtfeld", response.Fields["name"])
 }
 
 func TestProductHandler_Create_RepositoryError(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	requestBody := CreateProductRequest{
 		Name:  "Test Product",
@@ -209,8 +409,8 @@ func TestProductHandler_Create_RepositoryError(t *testing.T) {
 func TestProductHandler_GetByID_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 	expectedProduct := &domain.Product{
@@ -240,13 +440,60 @@ func TestProductHandler_GetByID_Success(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Contains(t, response, "data")
+
+	data := response["data"].(map[string]any)
+	assert.Equal(t, float64(1), data["version"])
+	assert.NotContains(t, data, "deleted_at")
+}
+
+func TestProductHandler_GetByID_MinReviewsForRating(t *testing.T) {
+	for name, tc := range map[string]struct {
+		reviewCount int
+		want        any
+	}{
+		"below threshold hides the average": {reviewCount: 2, want: nil},
+		"at threshold shows the average":    {reviewCount: 3, want: 4.5},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			log := logger.New("test")
+			service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+			handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 3, log)
+
+			productID := uuid.New()
+			mockRepo.On("GetByID", mock.Anything, productID).Return(&domain.Product{
+				ID:            productID,
+				Name:          "Test Product",
+				AverageRating: 4.5,
+				ReviewCount:   tc.reviewCount,
+				Version:       1,
+			}, nil)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID.String(), nil)
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", productID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			handler.GetByID(w, req)
+
+			require.Equal(t, http.StatusOK, w.Code)
+			var response struct {
+				Data map[string]any `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Contains(t, response.Data, "average_rating", "average_rating should be present even when null")
+			assert.Equal(t, tc.want, response.Data["average_rating"])
+			assert.Equal(t, float64(tc.reviewCount), response.Data["review_count"])
+		})
+	}
 }
 
 func TestProductHandler_GetByID_InvalidUUID(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/invalid-uuid", nil)
 	w := httptest.NewRecorder()
@@ -268,8 +515,8 @@ func TestProductHandler_GetByID_InvalidUUID(t *testing.T) {
 func TestProductHandler_GetByID_NotFound(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -286,13 +533,18 @@ func TestProductHandler_GetByID_NotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 	mockRepo.AssertExpectations(t)
+
+	var response map[string]string
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "NOT_FOUND", response["code"])
 }
 
 func TestProductHandler_List_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	products := []*domain.Product{
 		{
@@ -312,8 +564,7 @@ func TestProductHandler_List_Success(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/products?limit=20&offset=0", nil)
 	w := httptest.NewRecorder()
 
-	mockRepo.On("List", mock.Anything, 20, 0).Return(products, nil)
-	mockRepo.On("Count", mock.Anything).Return(2, nil)
+	mockRepo.On("ListWithTotal", mock.Anything, 20, 0).Return(products, 2, nil)
 
 	handler.List(w, req)
 
@@ -327,19 +578,44 @@ func TestProductHandler_List_Success(t *testing.T) {
 	assert.Contains(t, response, "pagination")
 }
 
+func TestProductHandler_Unreviewed(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	log := logger.New("test")
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+	products := []*domain.Product{{ID: uuid.New(), Name: "Lonely", Price: 10, ReviewsEnabled: true}}
+	mockRepo.On("ListUnreviewed", mock.Anything, 10, 10).Return(products, 11, nil)
+
+	w := httptest.NewRecorder()
+	handler.Unreviewed(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/products/unreviewed?limit=10&offset=10", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data       []ProductResponse `json:"data"`
+		Pagination struct {
+			Total int `json:"total"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "Lonely", body.Data[0].Name)
+	assert.Equal(t, 11, body.Pagination.Total)
+	mockRepo.AssertExpectations(t)
+}
+
 func TestProductHandler_List_WithPagination(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	products := []*domain.Product{}
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/products?limit=10&offset=20", nil)
 	w := httptest.NewRecorder()
 
-	mockRepo.On("List", mock.Anything, 10, 20).Return(products, nil)
-	mockRepo.On("Count", mock.Anything).Return(100, nil)
+	mockRepo.On("ListWithTotal", mock.Anything, 10, 20).Return(products, 100, nil)
 
 	handler.List(w, req)
 
@@ -355,16 +631,49 @@ func TestProductHandler_List_WithPagination(t *testing.T) {
 	assert.Equal(t, float64(100), pagination["total"])
 }
 
+func TestProductHandler_List_CustomPagination(t *testing.T) {
+	pagination := request.PaginationConfig{DefaultLimit: 50, MaxLimit: 250}
+
+	tests := []struct {
+		name      string
+		query     string
+		wantLimit int
+	}{
+		{name: "default", query: "", wantLimit: 50},
+		{name: "above shared max", query: "?limit=200", wantLimit: 200},
+		{name: "above configured max", query: "?limit=300", wantLimit: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			log := logger.New("test")
+			service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+			handler := NewProductHandler(service, pagination, defaultCompareMaxIDs, 0, log)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products"+tt.query, nil)
+			w := httptest.NewRecorder()
+
+			mockRepo.On("ListWithTotal", mock.Anything, tt.wantLimit, 0).Return([]*domain.Product{}, 0, nil)
+
+			handler.List(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
 func TestProductHandler_List_RepositoryError(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/products", nil)
 	w := httptest.NewRecorder()
 
-	mockRepo.On("List", mock.Anything, 20, 0).Return(nil, fmt.Errorf("database error"))
+	mockRepo.On("ListWithTotal", mock.Anything, 20, 0).Return(nil, 0, fmt.Errorf("database error"))
 
 	handler.List(w, req)
 
@@ -372,11 +681,116 @@ func TestProductHandler_List_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestProductHandler_Compare_Success(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	log := logger.New("test")
+	service := product.NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+	first, second := uuid.New(), uuid.New()
+	ids := []uuid.UUID{first, second}
+
+	// Duplicates and blank entries are dropped before the lookup
+	req := httptest.NewRequest(http.MethodGet,
+		"/api/v1/products/compare?ids="+first.String()+","+second.String()+",,"+first.String(), nil)
+	w := httptest.NewRecorder()
+
+	mockRepo.On("GetByIDs", mock.Anything, ids).Return(map[uuid.UUID]*domain.Product{
+		first:  {ID: first, Name: "First", AverageRating: 4.5, ReviewCount: 2},
+		second: {ID: second, Name: "Second"},
+	}, nil)
+	mockReviewRepo.On("GetRatingDistributions", mock.Anything, ids).Return(map[uuid.UUID]map[int]int{
+		first: {4: 1, 5: 1},
+	}, nil)
+
+	handler.Compare(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data []*domain.ProductComparison `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data, 2)
+	assert.Equal(t, first, body.Data[0].Product.ID)
+	assert.Equal(t, 1, body.Data[0].RatingDistribution[5])
+	assert.Equal(t, second, body.Data[1].Product.ID)
+	mockRepo.AssertExpectations(t)
+	mockReviewRepo.AssertExpectations(t)
+}
+
+func TestProductHandler_Compare_InvalidIDs(t *testing.T) {
+	tooMany := make([]string, 0, 3)
+	for range 3 {
+		tooMany = append(tooMany, uuid.NewString())
+	}
+
+	tests := map[string]string{
+		"missing":  "",
+		"blank":    "?ids=,",
+		"not uuid": "?ids=" + uuid.NewString() + ",abc",
+		"too many": "?ids=" + strings.Join(tooMany, ","),
+	}
+
+	for name, query := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			log := logger.New("test")
+			service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+			handler := NewProductHandler(service, request.DefaultPagination, 2, 0, log)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/products/compare"+query, nil)
+			w := httptest.NewRecorder()
+
+			handler.Compare(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestProductHandler_Compare_MaxBatchItems(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	log := logger.New("test")
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+	// The router-wide batch limit wins when it is below PRODUCTS_COMPARE_MAX_IDS
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/compare?ids="+uuid.NewString()+","+uuid.NewString(), nil)
+	req = req.WithContext(request.WithMaxBatchItems(req.Context(), 1))
+	w := httptest.NewRecorder()
+
+	handler.Compare(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "At most 1 products can be compared")
+	mockRepo.AssertNotCalled(t, "GetByIDs", mock.Anything, mock.Anything)
+}
+
+func TestProductHandler_Compare_NotFound(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	log := logger.New("test")
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+	id := uuid.New()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/compare?ids="+id.String(), nil)
+	w := httptest.NewRecorder()
+
+	mockRepo.On("GetByIDs", mock.Anything, []uuid.UUID{id}).Return(map[uuid.UUID]*domain.Product{}, nil)
+
+	handler.Compare(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
 func TestProductHandler_Update_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
+	audits := new(fakes.AuditRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, audits, nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -395,6 +809,8 @@ func TestProductHandler_Update_Success(t *testing.T) {
 	rctx.URLParams.Add("id", productID.String())
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
+	existing := &domain.Product{ID: productID, Name: "Old Name", Price: 99.99, Version: 1}
+	mockRepo.On("GetByID", mock.Anything, productID).Return(existing, nil)
 	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
 		return p.ID == productID && p.Name == "Updated Name" && p.Price == 149.99 && p.Version == 1
 	})).Return(nil)
@@ -403,13 +819,71 @@ func TestProductHandler_Update_Success(t *testing.T) {
 
 	assert.Equal(t, http.StatusOK, w.Code)
 	mockRepo.AssertExpectations(t)
+
+	// The audit entry keeps both sides of the change
+	if assert.Len(t, audits.Entries, 1) {
+		entry := audits.Entries[0]
+		assert.Equal(t, domain.AuditActionUpdate, entry.Action)
+		assert.Equal(t, domain.AuditEntityProduct, entry.EntityType)
+		assert.Equal(t, productID, entry.EntityID)
+		assert.Contains(t, string(*entry.Before), `"name":"Old Name"`)
+		assert.Contains(t, string(*entry.After), `"name":"Updated Name"`)
+	}
+}
+
+func TestProductHandler_Update_ReviewsEnabled(t *testing.T) {
+	enabled := true
+	tests := []struct {
+		name           string
+		reviewsEnabled *bool
+		want           bool
+	}{
+		{name: "omitted keeps reviews frozen", want: false},
+		{name: "true reopens reviews", reviewsEnabled: &enabled, want: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			log := logger.New("test")
+			service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+			handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
+
+			productID := uuid.New()
+			bodyBytes, _ := json.Marshal(UpdateProductRequest{
+				Name:           "Updated Name",
+				Price:          149.99,
+				ReviewsEnabled: tc.reviewsEnabled,
+				Version:        1,
+			})
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/products/"+productID.String(), bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", productID.String())
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+			w := httptest.NewRecorder()
+
+			frozen := &domain.Product{ID: productID, Name: "Old Name", Price: 99.99, ReviewsEnabled: false, Version: 1}
+			mockRepo.On("GetByID", mock.Anything, productID).Return(frozen, nil)
+			mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(p *domain.Product) bool {
+				return p.ID == productID && p.ReviewsEnabled == tc.want
+			})).Return(nil)
+
+			handler.Update(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), fmt.Sprintf(`"reviews_enabled":%t`, tc.want))
+			mockRepo.AssertExpectations(t)
+		})
+	}
 }
 
 func TestProductHandler_Update_InvalidUUID(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	requestBody := UpdateProductRequest{
 		Name:  "Updated Name",
@@ -433,8 +907,8 @@ func TestProductHandler_Update_InvalidUUID(t *testing.T) {
 func TestProductHandler_Update_InvalidJSON(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -454,8 +928,8 @@ func TestProductHandler_Update_InvalidJSON(t *testing.T) {
 func TestProductHandler_Update_Conflict(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -474,19 +948,25 @@ func TestProductHandler_Update_Conflict(t *testing.T) {
 	rctx.URLParams.Add("id", productID.String())
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
+	mockRepo.On("GetByID", mock.Anything, productID).Return(&domain.Product{ID: productID, Version: 2}, nil)
 	mockRepo.On("Update", mock.Anything, mock.Anything).Return(domain.ErrConflict)
 
 	handler.Update(w, req)
 
 	assert.Equal(t, http.StatusConflict, w.Code)
 	mockRepo.AssertExpectations(t)
+
+	var response map[string]string
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, "CONFLICT", response["code"])
 }
 
 func TestProductHandler_Update_MissingVersion(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -513,8 +993,8 @@ func TestProductHandler_Update_MissingVersion(t *testing.T) {
 func TestProductHandler_Update_InvalidVersion(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -541,9 +1021,10 @@ func TestProductHandler_Update_InvalidVersion(t *testing.T) {
 func TestProductHandler_Delete_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	mockReviewRepo := new(MockReviewRepository)
+	audits := new(fakes.AuditRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, mockReviewRepo, log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, audits, nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -554,6 +1035,7 @@ func TestProductHandler_Delete_Success(t *testing.T) {
 	rctx.URLParams.Add("id", productID.String())
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
+	mockRepo.On("GetByID", mock.Anything, productID).Return(&domain.Product{ID: productID, Name: "Doomed"}, nil)
 	mockRepo.On("DeleteWithReviews", mock.Anything, productID).Return(nil)
 
 	handler.Delete(w, req)
@@ -561,13 +1043,19 @@ func TestProductHandler_Delete_Success(t *testing.T) {
 	assert.Equal(t, http.StatusNoContent, w.Code)
 	mockRepo.AssertExpectations(t)
 	mockReviewRepo.AssertExpectations(t)
+
+	if assert.Len(t, audits.Entries, 1) {
+		assert.Equal(t, domain.AuditActionDelete, audits.Entries[0].Action)
+		assert.Contains(t, string(*audits.Entries[0].Before), `"name":"Doomed"`)
+		assert.Nil(t, audits.Entries[0].After)
+	}
 }
 
 func TestProductHandler_Delete_InvalidUUID(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, new(MockReviewRepository), log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/products/invalid-uuid", nil)
 	w := httptest.NewRecorder()
@@ -585,8 +1073,8 @@ func TestProductHandler_Delete_NotFound(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	mockReviewRepo := new(MockReviewRepository)
 	log := logger.New("test")
-	service := product.NewService(mockRepo, mockReviewRepo, log)
-	handler := NewProductHandler(service, log)
+	service := product.NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
+	handler := NewProductHandler(service, request.DefaultPagination, defaultCompareMaxIDs, 0, log)
 
 	productID := uuid.New()
 
@@ -597,7 +1085,7 @@ func TestProductHandler_Delete_NotFound(t *testing.T) {
 	rctx.URLParams.Add("id", productID.String())
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
-	mockRepo.On("DeleteWithReviews", mock.Anything, productID).Return(domain.ErrNotFound)
+	mockRepo.On("GetByID", mock.Anything, productID).Return(nil, domain.ErrNotFound)
 
 	handler.Delete(w, req)
 
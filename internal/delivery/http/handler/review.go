@@ -2,7 +2,9 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 
@@ -10,56 +12,108 @@ import (
 	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
 	"github.com/Pesokrava/product_reviewer/internal/domain"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	pkgValidator "github.com/Pesokrava/product_reviewer/internal/pkg/validator"
 	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
 )
 
+// ReviewSourceHeader lets clients tag where a review came from without changing the body
+const ReviewSourceHeader = "X-Review-Source"
+
+// reviewListFields are the review fields selectable with ?fields= on the reviews list
+var reviewListFields = map[string]bool{
+	"id":           true,
+	"product_id":   true,
+	"display_name": true,
+	"avatar_url":   true,
+	"title":        true,
+	"language":     true,
+	"review_text":  true,
+	"rating":       true,
+	"source":       true,
+	"created_at":   true,
+	"updated_at":   true,
+}
+
 // ReviewHandler handles HTTP requests for reviews
 type ReviewHandler struct {
-	service *review.Service
-	logger  *logger.Logger
+	service       *review.Service
+	defaultSource string
+	pagination    request.PaginationConfig
+	logger        *logger.Logger
 }
 
 // NewReviewHandler creates a new review handler
-func NewReviewHandler(service *review.Service, log *logger.Logger) *ReviewHandler {
+func NewReviewHandler(service *review.Service, defaultSource string, pagination request.PaginationConfig, log *logger.Logger) *ReviewHandler {
 	return &ReviewHandler{
-		service: service,
-		logger:  log,
+		service:       service,
+		defaultSource: defaultSource,
+		pagination:    pagination,
+		logger:        log,
 	}
 }
 
 // CreateReviewRequest represents the request body for creating a review
 type CreateReviewRequest struct {
-	ProductID  string `json:"product_id" validate:"required"`
-	FirstName  string `json:"first_name" validate:"required,min=1,max=100"`
-	LastName   string `json:"last_name" validate:"required,min=1,max=100"`
-	ReviewText string `json:"review_text" validate:"required,min=1"`
-	Rating     int    `json:"rating" validate:"required,min=1,max=5"`
+	ProductID  string  `json:"product_id" validate:"required"`
+	FirstName  string  `json:"first_name" validate:"required,min=1,max=100"`
+	LastName   string  `json:"last_name" validate:"required,min=1,max=100"`
+	Email      string  `json:"email,omitempty" validate:"omitempty,email,max=254" example:"jane@example.com"` // Optional; only its Gravatar hash is stored, for the avatar
+	Title      *string `json:"title,omitempty" validate:"omitempty,max=200" example:"Solid and quiet"`        // Optional headline
+	Language   *string `json:"language,omitempty" validate:"omitempty,len=2,alpha" example:"en"`              // Optional ISO 639-1 code; detected from review_text when omitted and DETECT_REVIEW_LANGUAGE is on
+	ReviewText string  `json:"review_text" validate:"required,min=1"`
+	Rating     float64 `json:"rating" validate:"required,rating" minimum:"1" example:"5"` // 1 to MAX_RATING (default 5), in steps of 0.5 with HALF_STAR_RATINGS, or one of ALLOWED_RATINGS when set
+	Source     string  `json:"source,omitempty" validate:"omitempty,oneof=web mobile import api"`
+}
+
+// ImportReviewRequest is one review in the body of a bulk import
+type ImportReviewRequest struct {
+	FirstName  string  `json:"first_name" validate:"required,min=1,max=100"`
+	LastName   string  `json:"last_name" validate:"required,min=1,max=100"`
+	Email      string  `json:"email,omitempty" validate:"omitempty,email,max=254" example:"jane@example.com"` // Optional; only its Gravatar hash is stored, for the avatar
+	Title      *string `json:"title,omitempty" validate:"omitempty,max=200" example:"Solid and quiet"`        // Optional headline
+	Language   *string `json:"language,omitempty" validate:"omitempty,len=2,alpha" example:"en"`              // Optional ISO 639-1 code; detected from review_text when omitted and DETECT_REVIEW_LANGUAGE is on
+	ReviewText string  `json:"review_text" validate:"required,min=1"`
+	Rating     float64 `json:"rating" validate:"required,rating" minimum:"1" example:"5"` // 1 to MAX_RATING (default 5), in steps of 0.5 with HALF_STAR_RATINGS, or one of ALLOWED_RATINGS when set
+	Source     string  `json:"source,omitempty" validate:"omitempty,oneof=web mobile import api"`
+}
+
+// ImportReviewsResponse reports the outcome of a bulk import
+type ImportReviewsResponse struct {
+	Imported int `json:"imported"`
 }
 
 // UpdateReviewRequest represents the request body for updating a review
 type UpdateReviewRequest struct {
-	FirstName  string `json:"first_name" validate:"required,min=1,max=100"`
-	LastName   string `json:"last_name" validate:"required,min=1,max=100"`
-	ReviewText string `json:"review_text" validate:"required,min=1"`
-	Rating     int    `json:"rating" validate:"required,min=1,max=5"`
+	FirstName  string  `json:"first_name" validate:"required,min=1,max=100"`
+	LastName   string  `json:"last_name" validate:"required,min=1,max=100"`
+	Email      string  `json:"email,omitempty" validate:"omitempty,email,max=254" example:"jane@example.com"` // Optional; only its Gravatar hash is stored, for the avatar
+	Title      *string `json:"title,omitempty" validate:"omitempty,max=200" example:"Solid and quiet"`        // Optional headline
+	Language   *string `json:"language,omitempty" validate:"omitempty,len=2,alpha" example:"en"`              // Optional ISO 639-1 code; detected from review_text when omitted and DETECT_REVIEW_LANGUAGE is on
+	ReviewText string  `json:"review_text" validate:"required,min=1"`
+	Rating     float64 `json:"rating" validate:"required,rating" minimum:"1" example:"5"` // 1 to MAX_RATING (default 5), in steps of 0.5 with HALF_STAR_RATINGS, or one of ALLOWED_RATINGS when set
 }
 
 // Create handles POST /api/v1/reviews
 // @Summary Create a new review
-// @Description Create a new review for a product. Automatically updates product's average rating and publishes event.
+// @Description Create a new review for a product. Automatically updates product's average rating and publishes event. Source (web, mobile, import, api) is taken from the body, then the X-Review-Source header, then the server default.
 // @Tags Reviews
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param review body CreateReviewRequest true "Review details"
-// @Success 201 {object} map[string]any "Review created successfully"
+// @Param X-Review-Source header string false "Review source when not set in the body (web, mobile, import, api)"
+// @Param Accept-Language header string false "Language for validation messages (en, de)" default(en)
+// @Success 201 {object} ReviewResponse "Review created successfully"
 // @Failure 400 {object} map[string]string "Invalid request body or product not found"
+// @Failure 403 {object} map[string]string "Reviews are disabled for this product"
 // @Failure 404 {object} map[string]string "Product not found"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)"
+// @Failure 429 {object} map[string]string "Too many reviews for this product from the client IP (when REVIEW_THROTTLE_LIMIT is set)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /reviews [post]
 func (h *ReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateReviewRequest
-	if err := request.DecodeJSON(r, &req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -73,16 +127,89 @@ func (h *ReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
 		ProductID:  productID,
 		FirstName:  req.FirstName,
 		LastName:   req.LastName,
+		Email:      req.Email,
+		Title:      req.Title,
+		Language:   req.Language,
 		ReviewText: req.ReviewText,
 		Rating:     req.Rating,
+		Source:     h.resolveSource(r, req.Source),
 	}
 
 	if err := h.service.Create(r.Context(), review); err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.Created(w, toReviewResponse(review))
+}
+
+// Import handles POST /api/v1/products/:id/reviews/import
+// @Summary Bulk import reviews for a product
+// @Description Create up to 1000 reviews (or MAX_BATCH_ITEMS, if lower) for one product in a single transaction; either all are created or none. Source defaults to "import". Publishes one product.rating.recalc event instead of a review.created event per review. Requires the admin API key.
+// @Tags Reviews
+// @Accept json
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param id path string true "Product ID (UUID)"
+// @Param reviews body []ImportReviewRequest true "Reviews to import"
+// @Param Accept-Language header string false "Language for validation messages (en, de)" default(en)
+// @Success 201 {object} ImportReviewsResponse "Number of reviews imported"
+// @Failure 400 {object} map[string]string "Invalid product ID, invalid review or batch size out of range"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled, or reviews are disabled for this product"
+// @Failure 404 {object} map[string]string "Product not found"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /products/{id}/reviews/import [post]
+func (h *ReviewHandler) Import(w http.ResponseWriter, r *http.Request) {
+	productID, err := request.GetUUIDParam(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid product ID")
 		return
 	}
 
-	response.Created(w, review)
+	var req []ImportReviewRequest
+	if !decodeJSONArray(w, r, &req, min(request.MaxBatchItems(r), review.MaxImportBatchSize), "reviews") {
+		return
+	}
+
+	if len(req) == 0 {
+		response.ValidationError(w, map[string]string{
+			"reviews": fmt.Sprintf("must contain between 1 and %d reviews", review.MaxImportBatchSize),
+		})
+		return
+	}
+
+	// Validate here rather than leaving it to the service so errors can say which review failed
+	validate := pkgValidator.Get()
+	for i, item := range req {
+		if err := validate.Struct(item); err != nil {
+			response.ValidationError(w, pkgValidator.TranslateErrors(pkgValidator.AtIndex(i, err), r.Header.Get("Accept-Language")))
+			return
+		}
+	}
+
+	reviews := make([]*domain.Review, len(req))
+	for i, item := range req {
+		reviews[i] = &domain.Review{
+			FirstName:  item.FirstName,
+			LastName:   item.LastName,
+			Email:      item.Email,
+			Title:      item.Title,
+			Language:   item.Language,
+			ReviewText: item.ReviewText,
+			Rating:     item.Rating,
+			Source:     item.Source,
+		}
+	}
+
+	if err := h.service.Import(r.Context(), productID, reviews); err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.Created(w, ImportReviewsResponse{Imported: len(reviews)})
 }
 
 // Update handles PUT /api/v1/reviews/:id
@@ -90,12 +217,15 @@ func (h *ReviewHandler) Create(w http.ResponseWriter, r *http.Request) {
 // @Description Update review details. Automatically recalculates product's average rating and publishes event.
 // @Tags Reviews
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param id path string true "Review ID (UUID)"
 // @Param review body UpdateReviewRequest true "Updated review details"
-// @Success 200 {object} map[string]any "Review updated successfully"
+// @Param Accept-Language header string false "Language for validation messages (en, de)" default(en)
+// @Success 200 {object} ReviewResponse "Review updated successfully"
 // @Failure 400 {object} map[string]string "Invalid request"
 // @Failure 404 {object} map[string]string "Review not found"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /reviews/{id} [put]
 func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
@@ -106,8 +236,7 @@ func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req UpdateReviewRequest
-	if err := request.DecodeJSON(r, &req); err != nil {
-		response.Error(w, http.StatusBadRequest, "Invalid request body")
+	if !decodeJSON(w, r, &req) {
 		return
 	}
 
@@ -115,16 +244,19 @@ func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
 		ID:         id,
 		FirstName:  req.FirstName,
 		LastName:   req.LastName,
+		Email:      req.Email,
+		Title:      req.Title,
+		Language:   req.Language,
 		ReviewText: req.ReviewText,
 		Rating:     req.Rating,
 	}
 
 	if err := h.service.Update(r.Context(), review); err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	response.Success(w, review)
+	response.Success(w, toReviewResponse(review))
 }
 
 // Delete handles DELETE /api/v1/reviews/:id
@@ -132,7 +264,7 @@ func (h *ReviewHandler) Update(w http.ResponseWriter, r *http.Request) {
 // @Description Soft delete a review. Automatically recalculates product's average rating and publishes event.
 // @Tags Reviews
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param id path string true "Review ID (UUID)"
 // @Success 204 "Review deleted successfully"
 // @Failure 400 {object} map[string]string "Invalid review ID"
@@ -147,24 +279,54 @@ func (h *ReviewHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if err := h.service.Delete(r.Context(), id); err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
 	response.NoContent(w)
 }
 
+// Anonymize handles POST /api/v1/reviews/:id/anonymize
+// @Summary Anonymize a review
+// @Description Replace the reviewer's first and last name with "Anonymous" for right-to-be-forgotten requests. Rating and text are kept, so unlike delete the review still counts toward the product rating. Publishes a review.anonymized event.
+// @Tags Reviews
+// @Produce json,application/vnd.productreviews.v1+json
+// @Param id path string true "Review ID (UUID)"
+// @Success 200 {object} ReviewResponse "Anonymized review"
+// @Failure 400 {object} map[string]string "Invalid review ID"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /reviews/{id}/anonymize [post]
+func (h *ReviewHandler) Anonymize(w http.ResponseWriter, r *http.Request) {
+	id, err := request.GetUUIDParam(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid review ID")
+		return
+	}
+
+	review, err := h.service.Anonymize(r.Context(), id)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.Success(w, toReviewResponse(review))
+}
+
 // GetByProductID handles GET /api/v1/products/:id/reviews
 // @Summary Get reviews for a product
-// @Description Get a paginated list of reviews for a specific product. Results are cached.
+// @Description Get a paginated list of reviews for a specific product, ordered by DEFAULT_REVIEW_SORT (newest first unless configured). Reviewers are shown by display name (first name and last initial). Results are cached. A product without reviews returns an empty list; an unknown or deleted product returns 404.
 // @Tags Reviews
 // @Accept json
-// @Produce json
+// @Produce json,application/vnd.productreviews.v1+json
 // @Param id path string true "Product ID (UUID)"
-// @Param limit query int false "Number of items per page (max 100)" default(20)
+// @Param limit query int false "Number of items per page (default REVIEWS_PAGE_SIZE_DEFAULT, max REVIEWS_PAGE_SIZE_MAX)" default(20)
 // @Param offset query int false "Number of items to skip" default(0)
+// @Param fields query string false "Comma-separated review fields to return, e.g. id,rating,review_text (default: all)"
+// @Param language query string false "Only reviews in this language (ISO 639-1 code, e.g. en); reviews without a language are left out"
 // @Success 200 {object} map[string]any "Paginated list of reviews"
-// @Failure 400 {object} map[string]string "Invalid product ID"
+// @Failure 400 {object} map[string]string "Invalid product ID, unknown field or invalid language"
+// @Failure 404 {object} map[string]string "Product not found"
 // @Failure 500 {object} map[string]string "Internal server error"
 // @Router /products/{id}/reviews [get]
 func (h *ReviewHandler) GetByProductID(w http.ResponseWriter, r *http.Request) {
@@ -174,26 +336,99 @@ func (h *ReviewHandler) GetByProductID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit, offset := request.GetPaginationParams(r)
+	fields := request.GetListQuery(r, "fields")
+	var unknown []string
+	for _, field := range fields {
+		if !reviewListFields[field] {
+			unknown = append(unknown, field)
+		}
+	}
+	if len(unknown) > 0 {
+		response.ValidationError(w, map[string]string{"fields": "unknown fields: " + strings.Join(unknown, ", ")})
+		return
+	}
+
+	language := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("language")))
+	if language != "" && !domain.IsValidLanguage(language) {
+		response.ValidationError(w, map[string]string{"language": "must be a two-letter ISO 639-1 code, e.g. en"})
+		return
+	}
+
+	limit, offset := request.GetPaginationParamsWithConfig(r, h.pagination)
 
-	reviews, total, err := h.service.GetByProductID(r.Context(), productID, limit, offset)
+	reviews, total, err := h.service.GetByProductIDAndLanguage(r.Context(), productID, language, limit, offset)
+	if errors.Is(err, domain.ErrNotFound) {
+		response.ErrorWithCode(w, http.StatusNotFound, response.CodeNotFound, "Product not found")
+		return
+	}
 	if err != nil {
-		h.handleError(w, err)
+		h.handleError(w, r, err)
 		return
 	}
 
-	response.Paginated(w, reviews, total, limit, offset)
+	public := toReviewResponses(reviews)
+	if len(fields) == 0 {
+		response.Paginated(w, public, total, limit, offset)
+		return
+	}
+
+	projected, err := response.Project(public, fields)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+	response.Paginated(w, projected, total, limit, offset)
+}
+
+// Recent handles GET /api/v1/reviews/recent
+// @Summary List the newest reviews across all products
+// @Description The most recent reviews on any product, newest first, each with its product name, for a "recently reviewed" feed. Cached for CACHE_TTL_RECENT_REVIEWS, so new reviews can take that long to appear.
+// @Tags Reviews
+// @Produce json,application/vnd.productreviews.v1+json
+// @Param limit query int false "Number of reviews (max 100)" default(20)
+// @Success 200 {array} RecentReviewResponse "Recent reviews"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /reviews/recent [get]
+func (h *ReviewHandler) Recent(w http.ResponseWriter, r *http.Request) {
+	limit := request.GetIntQuery(r, "limit", review.DefaultRecentLimit)
+
+	reviews, err := h.service.Recent(r.Context(), limit)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.Success(w, toRecentReviewResponses(reviews))
+}
+
+// resolveSource picks the review source by precedence: body, header, configured default
+func (h *ReviewHandler) resolveSource(r *http.Request, bodySource string) string {
+	if bodySource != "" {
+		return bodySource
+	}
+	if headerSource := r.Header.Get(ReviewSourceHeader); headerSource != "" {
+		return headerSource
+	}
+	return h.defaultSource
 }
 
 // handleError handles service layer errors and returns appropriate HTTP responses
-func (h *ReviewHandler) handleError(w http.ResponseWriter, err error) {
+func (h *ReviewHandler) handleError(w http.ResponseWriter, r *http.Request, err error) {
 	switch {
 	case errors.Is(err, domain.ErrNotFound):
-		response.Error(w, http.StatusNotFound, "Review or product not found")
+		response.ErrorWithCode(w, http.StatusNotFound, response.CodeNotFound, "Review or product not found")
 	case errors.Is(err, domain.ErrInvalidInput):
-		response.Error(w, http.StatusBadRequest, "Invalid input")
+		response.ValidationError(w, pkgValidator.TranslateErrors(err, r.Header.Get("Accept-Language")))
+	case errors.Is(err, domain.ErrAlreadyExists):
+		response.ErrorWithCode(w, http.StatusConflict, response.CodeAlreadyExists, "Review already exists")
+	case errors.Is(err, domain.ErrConflict):
+		response.ErrorWithCode(w, http.StatusConflict, response.CodeConflict, "Review was modified concurrently. Retry the request.")
+	case errors.Is(err, domain.ErrReviewsDisabled):
+		response.ErrorWithCode(w, http.StatusForbidden, response.CodeForbidden, "Reviews are disabled for this product")
+	case errors.Is(err, domain.ErrRateLimited):
+		response.ErrorWithCode(w, http.StatusTooManyRequests, response.CodeRateLimited, "Too many reviews for this product from your address. Try again later.")
 	default:
 		h.logger.Error("Internal error in review handler", err)
-		response.Error(w, http.StatusInternalServerError, "Internal server error")
+		response.ErrorWithCode(w, http.StatusInternalServerError, response.CodeInternal, "Internal server error")
 	}
 }
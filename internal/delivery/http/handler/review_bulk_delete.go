@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+// BulkDeleteReviewsResponse reports the outcome of a bulk delete
+type BulkDeleteReviewsResponse struct {
+	Deleted int `json:"deleted"`
+	// NotFound lists requested IDs that don't exist or were already deleted
+	NotFound []uuid.UUID `json:"not_found"`
+}
+
+// BulkDelete handles POST /api/v1/admin/reviews/bulk-delete
+// @Summary Delete reviews in bulk
+// @Description Soft delete up to 1000 reviews in one transaction, e.g. to clear a spam wave. Caches are invalidated and one product.rating.recalc event is published per affected product instead of a review.deleted event per review. IDs that don't exist or are already deleted are reported in not_found rather than failing the request. Requires the admin API key.
+// @Tags Admin
+// @Accept json
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param ids body []string true "Review IDs (UUIDs) to delete"
+// @Success 200 {object} BulkDeleteReviewsResponse "Number of reviews deleted and IDs that were skipped"
+// @Failure 400 {object} map[string]string "Invalid body, invalid review ID or batch size out of range"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 413 {object} map[string]string "Request body too large"
+// @Failure 415 {object} map[string]string "Content-Type is not application/json (when STRICT_CONTENT_TYPE is on)"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/reviews/bulk-delete [post]
+func (h *ReviewHandler) BulkDelete(w http.ResponseWriter, r *http.Request) {
+	var ids []uuid.UUID
+	if !decodeJSONArray(w, r, &ids, min(request.MaxBatchItems(r), review.MaxDeleteBatchSize), "ids") {
+		return
+	}
+
+	if len(ids) == 0 {
+		response.ValidationError(w, map[string]string{
+			"ids": fmt.Sprintf("must contain between 1 and %d review IDs", review.MaxDeleteBatchSize),
+		})
+		return
+	}
+
+	deleted, err := h.service.DeleteBatch(r.Context(), ids)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	deletedIDs := make(map[uuid.UUID]bool, len(deleted))
+	for _, review := range deleted {
+		deletedIDs[review.ID] = true
+	}
+	notFound := []uuid.UUID{}
+	for _, id := range ids {
+		if !deletedIDs[id] {
+			// Mark as seen so a duplicated ID is reported once
+			deletedIDs[id] = true
+			notFound = append(notFound, id)
+		}
+	}
+
+	response.Success(w, BulkDeleteReviewsResponse{Deleted: len(deleted), NotFound: notFound})
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+func newBulkDeleteRequest(body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reviews/bulk-delete", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestReviewHandler_BulkDelete(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{FlagThreshold: 3}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	productID := uuid.New()
+	deletedID, missingID := uuid.New(), uuid.New()
+	mockRepo.On("DeleteBatch", mock.Anything, []uuid.UUID{deletedID, missingID, missingID}).
+		Return([]*domain.Review{{ID: deletedID, ProductID: productID, Rating: 1}}, nil)
+	mockCache.On("InvalidateProducts", mock.Anything, []uuid.UUID{productID}).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+	body := `["` + deletedID.String() + `","` + missingID.String() + `","` + missingID.String() + `"]`
+	w := httptest.NewRecorder()
+	handler.BulkDelete(w, newBulkDeleteRequest(body))
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		Data BulkDeleteReviewsResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Deleted)
+	assert.Equal(t, []uuid.UUID{missingID}, resp.Data.NotFound, "a repeated ID is reported once")
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+}
+
+func TestReviewHandler_BulkDelete_InvalidBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+	}{
+		{name: "empty list", body: `[]`},
+		{name: "invalid UUID", body: `["not-a-uuid"]`},
+		{name: "not an array", body: `{"ids":[]}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockRepo := newTestFlagsHandler(3)
+
+			w := httptest.NewRecorder()
+			handler.BulkDelete(w, newBulkDeleteRequest(tc.body))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+			mockRepo.AssertNotCalled(t, "DeleteBatch", mock.Anything, mock.Anything)
+		})
+	}
+}
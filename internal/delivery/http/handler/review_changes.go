@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+// defaultChangesLimit is the page size of the changes feed when no limit is given
+const defaultChangesLimit = 100
+
+// ReviewChange is a review as seen by the changes feed; Deleted marks soft-deleted reviews.
+// The feed is admin-only, so unlike ReviewResponse it carries the reviewer's full name.
+type ReviewChange struct {
+	ID         uuid.UUID  `json:"id"`
+	ProductID  uuid.UUID  `json:"product_id"`
+	FirstName  string     `json:"first_name"`
+	LastName   string     `json:"last_name"`
+	AvatarURL  string     `json:"avatar_url,omitempty"`
+	Title      *string    `json:"title,omitempty"`
+	Language   *string    `json:"language,omitempty"`
+	ReviewText string     `json:"review_text"`
+	Rating     float64    `json:"rating"`
+	Source     string     `json:"source"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"`
+	Deleted    bool       `json:"deleted"`
+}
+
+// ReviewChangesResponse is one page of the review changes feed
+type ReviewChangesResponse struct {
+	Changes []ReviewChange `json:"changes"`
+	// NextCursor resumes after the last change; empty once the feed is caught up
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Changes handles GET /api/v1/reviews/changes
+// @Summary List review changes for incremental sync
+// @Description Reviews created, updated or soft-deleted (deleted=true) after the given time, oldest change first. Pass next_cursor back as cursor to fetch the next page; an empty next_cursor means the feed is caught up, so store the last cursor (or the newest updated_at) for the next sync. Requires the admin API key.
+// @Tags Reviews
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param since query string false "Return changes after this RFC 3339 time (required unless cursor is set)"
+// @Param cursor query string false "Opaque cursor from a previous page's next_cursor"
+// @Param limit query int false "Number of changes per page (max 500)" default(100)
+// @Success 200 {object} ReviewChangesResponse "Page of review changes"
+// @Failure 400 {object} map[string]string "Missing or invalid since/cursor"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /reviews/changes [get]
+func (h *ReviewHandler) Changes(w http.ResponseWriter, r *http.Request) {
+	var (
+		since   time.Time
+		afterID uuid.UUID
+		err     error
+	)
+
+	switch {
+	case r.URL.Query().Get("cursor") != "":
+		since, afterID, err = decodeChangesCursor(r.URL.Query().Get("cursor"))
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid cursor")
+			return
+		}
+	case r.URL.Query().Get("since") != "":
+		since, err = time.Parse(time.RFC3339Nano, r.URL.Query().Get("since"))
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "Invalid since: expected an RFC 3339 timestamp")
+			return
+		}
+	default:
+		response.Error(w, http.StatusBadRequest, "Either since or cursor is required")
+		return
+	}
+
+	limit := request.GetIntQuery(r, "limit", defaultChangesLimit)
+	if limit <= 0 || limit > review.MaxChangesLimit {
+		limit = defaultChangesLimit
+	}
+
+	reviews, err := h.service.ChangesSince(r.Context(), since, afterID, limit)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	changes := make([]ReviewChange, len(reviews))
+	for i, rev := range reviews {
+		changes[i] = toReviewChange(rev)
+	}
+
+	resp := ReviewChangesResponse{Changes: changes}
+	// A short page means there is nothing left to fetch right now
+	if len(reviews) == limit {
+		last := reviews[len(reviews)-1]
+		resp.NextCursor = encodeChangesCursor(last.UpdatedAt, last.ID)
+	}
+
+	response.Success(w, resp)
+}
+
+// encodeChangesCursor packs the keyset position so clients treat it as opaque
+func encodeChangesCursor(updatedAt time.Time, id uuid.UUID) string {
+	raw := updatedAt.UTC().Format(time.RFC3339Nano) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeChangesCursor(cursor string) (time.Time, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor encoding: %w", err)
+	}
+
+	timestamp, idPart, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return time.Time{}, uuid.Nil, fmt.Errorf("malformed cursor")
+	}
+
+	updatedAt, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor time: %w", err)
+	}
+
+	id, err := uuid.Parse(idPart)
+	if err != nil {
+		return time.Time{}, uuid.Nil, fmt.Errorf("invalid cursor id: %w", err)
+	}
+
+	return updatedAt, id, nil
+}
+
+func toReviewChange(review *domain.Review) ReviewChange {
+	return ReviewChange{
+		ID:         review.ID,
+		ProductID:  review.ProductID,
+		FirstName:  review.FirstName,
+		LastName:   review.LastName,
+		AvatarURL:  review.AvatarURL(),
+		Title:      review.Title,
+		Language:   review.Language,
+		ReviewText: review.ReviewText,
+		Rating:     review.Rating,
+		Source:     review.Source,
+		CreatedAt:  review.CreatedAt,
+		UpdatedAt:  review.UpdatedAt,
+		DeletedAt:  review.DeletedAt,
+		Deleted:    review.DeletedAt != nil,
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+func newTestChangesHandler() (*ReviewHandler, *MockReviewRepository) {
+	mockRepo := new(MockReviewRepository)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, new(MockReviewCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	return NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log), mockRepo
+}
+
+func TestReviewHandler_Changes_PagesWithCursor(t *testing.T) {
+	handler, mockRepo := newTestChangesHandler()
+
+	since := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	deletedAt := since.Add(2 * time.Minute)
+	live := &domain.Review{ID: uuid.New(), Rating: 5, UpdatedAt: since.Add(time.Minute)}
+	deleted := &domain.Review{ID: uuid.New(), Rating: 1, UpdatedAt: deletedAt, DeletedAt: &deletedAt}
+
+	mockRepo.On("ChangesSince", mock.Anything, since, uuid.Nil, 2).Return([]*domain.Review{live, deleted}, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews/changes?limit=2&since="+url.QueryEscape(since.Format(time.RFC3339)), nil)
+	w := httptest.NewRecorder()
+	handler.Changes(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var first struct {
+		Data ReviewChangesResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	require.Len(t, first.Data.Changes, 2)
+	assert.False(t, first.Data.Changes[0].Deleted)
+	assert.True(t, first.Data.Changes[1].Deleted)
+	require.NotEmpty(t, first.Data.NextCursor, "a full page should return a cursor")
+
+	// The cursor resumes strictly after the last change of the previous page
+	mockRepo.On("ChangesSince", mock.Anything, deletedAt, deleted.ID, 2).Return([]*domain.Review{}, nil)
+
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/reviews/changes?limit=2&cursor="+first.Data.NextCursor, nil)
+	w = httptest.NewRecorder()
+	handler.Changes(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var second struct {
+		Data ReviewChangesResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &second))
+	assert.Empty(t, second.Data.Changes)
+	assert.Empty(t, second.Data.NextCursor)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestReviewHandler_Changes_InvalidParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "neither since nor cursor", query: ""},
+		{name: "since not RFC 3339", query: "?since=yesterday"},
+		{name: "garbage cursor", query: "?cursor=not-a-cursor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mockRepo := newTestChangesHandler()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews/changes"+tt.query, nil)
+			w := httptest.NewRecorder()
+			handler.Changes(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockRepo.AssertNotCalled(t, "ChangesSince")
+		})
+	}
+}
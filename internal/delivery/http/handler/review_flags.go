@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// FlagReviewRequest represents the request body for flagging a review
+type FlagReviewRequest struct {
+	Reason string `json:"reason" validate:"required,max=500" example:"Spam"`
+}
+
+// FlaggedReviewResponse is a review in the moderation queue.
+// The queue is admin-only, so unlike ReviewResponse it carries the reviewer's full name.
+type FlaggedReviewResponse struct {
+	ID         uuid.UUID `json:"id"`
+	ProductID  uuid.UUID `json:"product_id"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	AvatarURL  string    `json:"avatar_url,omitempty"`
+	Title      *string   `json:"title,omitempty"`
+	Language   *string   `json:"language,omitempty"`
+	ReviewText string    `json:"review_text"`
+	Rating     float64   `json:"rating"`
+	Source     string    `json:"source"`
+	FlagCount  int       `json:"flag_count"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func toFlaggedReviewResponses(reviews []*domain.FlaggedReview) []FlaggedReviewResponse {
+	responses := make([]FlaggedReviewResponse, len(reviews))
+	for i, review := range reviews {
+		responses[i] = FlaggedReviewResponse{
+			ID:         review.ID,
+			ProductID:  review.ProductID,
+			FirstName:  review.FirstName,
+			LastName:   review.LastName,
+			AvatarURL:  review.AvatarURL(),
+			Title:      review.Title,
+			Language:   review.Language,
+			ReviewText: review.ReviewText,
+			Rating:     review.Rating,
+			Source:     review.Source,
+			FlagCount:  review.FlagCount,
+			CreatedAt:  review.CreatedAt,
+			UpdatedAt:  review.UpdatedAt,
+		}
+	}
+	return responses
+}
+
+// Flag handles POST /api/v1/reviews/:id/flag
+// @Summary Flag a review
+// @Description Report a review as breaking the rules. Each client IP may flag a review once. A review flagged more than REVIEW_FLAG_THRESHOLD times (default 3) enters the moderation queue, and the flag that puts it there publishes a review.flagged event.
+// @Tags Reviews
+// @Accept json
+// @Produce json,application/vnd.productreviews.v1+json
+// @Param id path string true "Review ID (UUID)"
+// @Param flag body FlagReviewRequest true "Why the review is flagged"
+// @Param Accept-Language header string false "Language for validation messages (en, de)" default(en)
+// @Success 204 "Flag recorded"
+// @Failure 400 {object} map[string]string "Invalid review ID or request body, or the client IP couldn't be resolved"
+// @Failure 404 {object} map[string]string "Review not found"
+// @Failure 409 {object} map[string]string "Review already flagged from this address"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /reviews/{id}/flag [post]
+func (h *ReviewHandler) Flag(w http.ResponseWriter, r *http.Request) {
+	id, err := request.GetUUIDParam(r, "id")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid review ID")
+		return
+	}
+
+	var req FlagReviewRequest
+	if !decodeJSON(w, r, &req) {
+		return
+	}
+
+	_, err = h.service.Flag(r.Context(), &domain.ReviewFlag{ReviewID: id, Reason: req.Reason})
+	if errors.Is(err, domain.ErrAlreadyExists) {
+		response.ErrorWithCode(w, http.StatusConflict, response.CodeAlreadyExists, "You have already flagged this review")
+		return
+	}
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Flagged handles GET /api/v1/admin/reviews/flagged
+// @Summary List the review moderation queue
+// @Description Reviews flagged more than REVIEW_FLAG_THRESHOLD times, most flagged first, with the reviewer's full name. Requires the admin API key.
+// @Tags Admin
+// @Produce json,application/vnd.productreviews.v1+json
+// @Security AdminKey
+// @Param limit query int false "Number of items per page (default REVIEWS_PAGE_SIZE_DEFAULT, max REVIEWS_PAGE_SIZE_MAX)" default(20)
+// @Param offset query int false "Number of items to skip" default(0)
+// @Success 200 {object} map[string]any "Paginated list of flagged reviews"
+// @Failure 401 {object} map[string]string "Missing or invalid admin key"
+// @Failure 403 {object} map[string]string "Admin API is disabled"
+// @Failure 500 {object} map[string]string "Internal server error"
+// @Router /admin/reviews/flagged [get]
+func (h *ReviewHandler) Flagged(w http.ResponseWriter, r *http.Request) {
+	limit, offset := request.GetPaginationParamsWithConfig(r, h.pagination)
+
+	reviews, total, err := h.service.ListFlagged(r.Context(), limit, offset)
+	if err != nil {
+		h.handleError(w, r, err)
+		return
+	}
+
+	response.Paginated(w, toFlaggedReviewResponses(reviews), total, limit, offset)
+}
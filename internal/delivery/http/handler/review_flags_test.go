@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clientip"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+func newTestFlagsHandler(flagThreshold int) (*ReviewHandler, *MockReviewRepository) {
+	mockRepo := new(MockReviewRepository)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, new(MockReviewCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{FlagThreshold: flagThreshold}, log)
+	return NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log), mockRepo
+}
+
+func newFlagRequest(reviewID uuid.UUID, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/"+reviewID.String()+"/flag", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", reviewID.String())
+	ctx := clientip.WithIP(context.WithValue(req.Context(), chi.RouteCtxKey, rctx), "203.0.113.7")
+	return req.WithContext(ctx)
+}
+
+func TestReviewHandler_Flag(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		repoErr  error
+		wantCode int
+	}{
+		{name: "recorded", body: `{"reason":"Spam"}`, wantCode: http.StatusNoContent},
+		{name: "missing reason", body: `{}`, wantCode: http.StatusBadRequest},
+		{name: "already flagged", body: `{"reason":"Spam"}`, repoErr: domain.ErrAlreadyExists, wantCode: http.StatusConflict},
+		{name: "review not found", body: `{"reason":"Spam"}`, repoErr: domain.ErrNotFound, wantCode: http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			handler, mockRepo := newTestFlagsHandler(3)
+			reviewID := uuid.New()
+
+			if tc.repoErr != nil {
+				mockRepo.On("Flag", mock.Anything, mock.Anything).Return(nil, tc.repoErr)
+			} else {
+				flagged := &domain.FlaggedReview{Review: domain.Review{ID: reviewID, ProductID: uuid.New()}, FlagCount: 1}
+				mockRepo.On("Flag", mock.Anything, mock.Anything).Return(flagged, nil)
+			}
+
+			w := httptest.NewRecorder()
+			handler.Flag(w, newFlagRequest(reviewID, tc.body))
+
+			assert.Equal(t, tc.wantCode, w.Code, w.Body.String())
+		})
+	}
+}
+
+func TestReviewHandler_Flagged(t *testing.T) {
+	handler, mockRepo := newTestFlagsHandler(3)
+
+	queued := []*domain.FlaggedReview{{
+		Review:    domain.Review{ID: uuid.New(), ProductID: uuid.New(), FirstName: "John", LastName: "Doe", Rating: 1},
+		FlagCount: 5,
+	}}
+	mockRepo.On("ListFlagged", mock.Anything, 3, 20, 0).Return(queued, nil)
+	mockRepo.On("CountFlagged", mock.Anything, 3).Return(1, nil)
+
+	w := httptest.NewRecorder()
+	handler.Flagged(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/reviews/flagged", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body struct {
+		Data       []FlaggedReviewResponse `json:"data"`
+		Pagination struct {
+			Total int `json:"total"`
+		} `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, 1, body.Pagination.Total)
+	assert.Equal(t, 5, body.Data[0].FlagCount)
+	assert.Equal(t, "Doe", body.Data[0].LastName, "moderators see the full name")
+	mockRepo.AssertExpectations(t)
+}
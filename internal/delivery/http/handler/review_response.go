@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// ReviewResponse is a review as the API returns it. The reviewer appears only by display
+// name ("John D."); full names are kept for admin responses such as the changes feed.
+// Internal fields like deleted_at are left out, and the email hash only appears as the
+// Gravatar avatar_url, omitted when the reviewer gave no email.
+type ReviewResponse struct {
+	ID          uuid.UUID `json:"id"`
+	ProductID   uuid.UUID `json:"product_id"`
+	DisplayName string    `json:"display_name"`
+	AvatarURL   string    `json:"avatar_url,omitempty"`
+	Title       *string   `json:"title,omitempty"`
+	Language    *string   `json:"language,omitempty"`
+	ReviewText  string    `json:"review_text"`
+	Rating      float64   `json:"rating"`
+	Source      string    `json:"source"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RecentReviewResponse is a ReviewResponse in the recently reviewed feed
+type RecentReviewResponse struct {
+	ReviewResponse
+	ProductName string `json:"product_name"`
+}
+
+func toReviewResponse(review *domain.Review) ReviewResponse {
+	return ReviewResponse{
+		ID:          review.ID,
+		ProductID:   review.ProductID,
+		DisplayName: review.DisplayName(),
+		AvatarURL:   review.AvatarURL(),
+		Title:       review.Title,
+		Language:    review.Language,
+		ReviewText:  review.ReviewText,
+		Rating:      review.Rating,
+		Source:      review.Source,
+		CreatedAt:   review.CreatedAt,
+		UpdatedAt:   review.UpdatedAt,
+	}
+}
+
+func toReviewResponses(reviews []*domain.Review) []ReviewResponse {
+	responses := make([]ReviewResponse, len(reviews))
+	for i, review := range reviews {
+		responses[i] = toReviewResponse(review)
+	}
+	return responses
+}
+
+func toRecentReviewResponses(reviews []*domain.RecentReview) []RecentReviewResponse {
+	responses := make([]RecentReviewResponse, len(reviews))
+	for i, review := range reviews {
+		responses[i] = RecentReviewResponse{
+			ReviewResponse: toReviewResponse(&review.Review),
+			ProductName:    review.ProductName,
+		}
+	}
+	return responses
+}
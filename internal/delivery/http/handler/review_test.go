@@ -8,14 +8,19 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clientip"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
 	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
 )
 
@@ -24,16 +29,55 @@ type MockReviewCache struct {
 	mock.Mock
 }
 
-func (m *MockReviewCache) GetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, int, error) {
-	args := m.Called(ctx, productID, limit, offset)
+func (m *MockReviewCache) GetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int) ([]*domain.Review, int, error) {
+	args := m.Called(ctx, productID, language, limit, offset)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
 	return args.Get(0).([]*domain.Review), args.Int(1), args.Error(2)
 }
 
-func (m *MockReviewCache) SetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int, reviews []*domain.Review, total int) error {
-	args := m.Called(ctx, productID, limit, offset, reviews, total)
+func (m *MockReviewCache) SetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int, reviews []*domain.Review, total int) error {
+	args := m.Called(ctx, productID, language, limit, offset, reviews, total)
+	return args.Error(0)
+}
+
+func (m *MockReviewCache) GetReviewOverview(ctx context.Context, productID uuid.UUID, limit int) (*domain.ReviewOverview, error) {
+	args := m.Called(ctx, productID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewOverview), args.Error(1)
+}
+
+func (m *MockReviewCache) SetReviewOverview(ctx context.Context, productID uuid.UUID, limit int, overview *domain.ReviewOverview) error {
+	args := m.Called(ctx, productID, limit, overview)
+	return args.Error(0)
+}
+
+func (m *MockReviewCache) GetReviewSummary(ctx context.Context, productID uuid.UUID) (*domain.ReviewSummary, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewSummary), args.Error(1)
+}
+
+func (m *MockReviewCache) SetReviewSummary(ctx context.Context, productID uuid.UUID, summary *domain.ReviewSummary) error {
+	args := m.Called(ctx, productID, summary)
+	return args.Error(0)
+}
+
+func (m *MockReviewCache) GetRecentReviews(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RecentReview), args.Error(1)
+}
+
+func (m *MockReviewCache) SetRecentReviews(ctx context.Context, limit int, reviews []*domain.RecentReview) error {
+	args := m.Called(ctx, limit, reviews)
 	return args.Error(0)
 }
 
@@ -42,6 +86,16 @@ func (m *MockReviewCache) InvalidateAllProductCache(ctx context.Context, product
 	return args.Error(0)
 }
 
+func (m *MockReviewCache) InvalidateProducts(ctx context.Context, productIDs []uuid.UUID) error {
+	args := m.Called(ctx, productIDs)
+	return args.Error(0)
+}
+
+func (m *MockReviewCache) IncrReviewAttempts(ctx context.Context, ip string, productID uuid.UUID, window time.Duration) (int64, error) {
+	args := m.Called(ctx, ip, productID, window)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockEventPublisher is a mock implementation of review.EventPublisher
 type MockEventPublisher struct {
 	mock.Mock
@@ -57,8 +111,8 @@ func TestReviewHandler_Create_Success(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 	requestBody := CreateReviewRequest{
@@ -91,13 +145,81 @@ func TestReviewHandler_Create_Success(t *testing.T) {
 	assert.Contains(t, response, "data")
 }
 
+func TestReviewHandler_Create_EmailBecomesAvatar(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	productID := uuid.New()
+	bodyBytes, _ := json.Marshal(CreateReviewRequest{
+		ProductID:  productID.String(),
+		FirstName:  "Jane",
+		LastName:   "Doe",
+		Email:      "jane@example.com",
+		ReviewText: "Great product!",
+		Rating:     5,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, productID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+	handler.Create(w, req)
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var response struct {
+		Data map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "https://www.gravatar.com/avatar/"+domain.HashEmail("jane@example.com")+"?d=identicon", response.Data["avatar_url"])
+	assert.NotContains(t, w.Body.String(), "jane@example.com", "the address is never echoed back")
+}
+
+func TestReviewHandler_Create_Throttled(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	throttle := review.Throttle{Limit: 1, Window: time.Hour}
+	service := review.NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{Throttle: throttle}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	productID := uuid.New()
+	bodyBytes, _ := json.Marshal(CreateReviewRequest{
+		ProductID:  productID.String(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Great product!",
+		Rating:     5,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(clientip.WithIP(req.Context(), "203.0.113.7"))
+	w := httptest.NewRecorder()
+
+	mockCache.On("IncrReviewAttempts", mock.Anything, "203.0.113.7", productID, time.Hour).Return(int64(2), nil)
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "RATE_LIMITED")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestReviewHandler_Create_InvalidJSON(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader([]byte("invalid json")))
 	req.Header.Set("Content-Type", "application/json")
@@ -112,13 +234,75 @@ func TestReviewHandler_Create_InvalidJSON(t *testing.T) {
 	assert.Contains(t, response["error"], "Invalid request body")
 }
 
+func TestReviewHandler_Create_UnsupportedMediaType(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	tests := []struct {
+		name        string
+		contentType string
+		wantStatus  int
+	}{
+		{name: "missing", contentType: "", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "form encoded", contentType: "application/x-www-form-urlencoded", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "text plain", contentType: "text/plain", wantStatus: http.StatusUnsupportedMediaType},
+		{name: "json with charset", contentType: "application/json; charset=utf-8", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Body is deliberately invalid so an accepted Content-Type surfaces as 400, not a service call
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader([]byte("{}x")))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+		})
+	}
+
+	mockRepo.AssertNotCalled(t, "Create")
+}
+
+func TestReviewHandler_Create_LenientContentType(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+		t.Run(contentType, func(t *testing.T) {
+			// Invalid JSON shows the body reached the decoder instead of being rejected with 415
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader([]byte("{}x")))
+			req = req.WithContext(request.WithStrictContentType(req.Context(), false))
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
+
 func TestReviewHandler_Create_InvalidProductID(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	requestBody := CreateReviewRequest{
 		ProductID:  "invalid-uuid",
@@ -142,13 +326,97 @@ func TestReviewHandler_Create_InvalidProductID(t *testing.T) {
 	assert.Contains(t, response["error"], "Invalid product ID")
 }
 
+func TestReviewHandler_Create_SourcePrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		bodySource string
+		header     string
+		wantSource string
+	}{
+		{name: "configured default", wantSource: domain.ReviewSourceAPI},
+		{name: "header", header: domain.ReviewSourceMobile, wantSource: domain.ReviewSourceMobile},
+		{name: "body overrides header", bodySource: domain.ReviewSourceImport, header: domain.ReviewSourceMobile, wantSource: domain.ReviewSourceImport},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockCache := new(MockReviewCache)
+			mockPublisher := new(MockEventPublisher)
+			log := logger.New("test")
+			service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+			handler := NewReviewHandler(service, domain.ReviewSourceAPI, request.DefaultPagination, log)
+
+			productID := uuid.New()
+			bodyBytes, _ := json.Marshal(CreateReviewRequest{
+				ProductID:  productID.String(),
+				FirstName:  "John",
+				LastName:   "Doe",
+				ReviewText: "Great product!",
+				Rating:     5,
+				Source:     tt.bodySource,
+			})
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader(bodyBytes))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.header != "" {
+				req.Header.Set(ReviewSourceHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+
+			mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.Review) bool {
+				return r.Source == tt.wantSource
+			})).Return(nil)
+			mockCache.On("InvalidateAllProductCache", mock.Anything, productID).Return(nil)
+			mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+			handler.Create(w, req)
+
+			assert.Equal(t, http.StatusCreated, w.Code)
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestReviewHandler_Create_InvalidSourceHeader(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	bodyBytes, _ := json.Marshal(CreateReviewRequest{
+		ProductID:  uuid.New().String(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Great product!",
+		Rating:     5,
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(ReviewSourceHeader, "fax")
+	w := httptest.NewRecorder()
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	var response struct {
+		Fields map[string]string `json:"fields"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Contains(t, response.Fields, "source")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
 func TestReviewHandler_Create_ValidationError(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 	requestBody := CreateReviewRequest{
@@ -174,8 +442,8 @@ func TestReviewHandler_Create_InvalidRating(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 	requestBody := CreateReviewRequest{
@@ -201,8 +469,8 @@ func TestReviewHandler_Create_RepositoryError(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 	requestBody := CreateReviewRequest{
@@ -226,13 +494,33 @@ func TestReviewHandler_Create_RepositoryError(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestReviewHandler_Create_ReviewsDisabled(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, new(MockReviewCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	body := `{"product_id":"` + uuid.New().String() + `","first_name":"John","last_name":"Doe","review_text":"Great","rating":5}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(domain.ErrReviewsDisabled)
+
+	handler.Create(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Contains(t, w.Body.String(), "FORBIDDEN")
+	mockRepo.AssertExpectations(t)
+}
+
 func TestReviewHandler_Update_Success(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	reviewID := uuid.New()
 	productID := uuid.New()
@@ -279,8 +567,8 @@ func TestReviewHandler_Update_InvalidUUID(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	requestBody := UpdateReviewRequest{
 		FirstName:  "Jane",
@@ -308,8 +596,8 @@ func TestReviewHandler_Update_InvalidJSON(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	reviewID := uuid.New()
 
@@ -331,8 +619,8 @@ func TestReviewHandler_Update_NotFound(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	reviewID := uuid.New()
 
@@ -365,8 +653,8 @@ func TestReviewHandler_Delete_Success(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	reviewID := uuid.New()
 	productID := uuid.New()
@@ -398,8 +686,8 @@ func TestReviewHandler_Delete_InvalidUUID(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v1/reviews/invalid-uuid", nil)
 	w := httptest.NewRecorder()
@@ -418,8 +706,8 @@ func TestReviewHandler_Delete_NotFound(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	reviewID := uuid.New()
 
@@ -438,13 +726,84 @@ func TestReviewHandler_Delete_NotFound(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestReviewHandler_Anonymize_Success(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	reviewID := uuid.New()
+	productID := uuid.New()
+	anonymized := &domain.Review{
+		ID:         reviewID,
+		ProductID:  productID,
+		FirstName:  domain.AnonymousName,
+		LastName:   domain.AnonymousName,
+		ReviewText: "Great product!",
+		Rating:     5,
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/"+reviewID.String()+"/anonymize", nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", reviewID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	mockRepo.On("Anonymize", mock.Anything, reviewID).Return(anonymized, nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, productID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+	handler.Anonymize(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "Delete")
+
+	var response struct {
+		Data ReviewResponse `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, domain.AnonymousName, response.Data.DisplayName)
+	assert.Equal(t, 5.0, response.Data.Rating)
+}
+
+func TestReviewHandler_Anonymize_NotFound(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	reviewID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/"+reviewID.String()+"/anonymize", nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", reviewID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	mockRepo.On("Anonymize", mock.Anything, reviewID).Return(nil, domain.ErrNotFound)
+
+	handler.Anonymize(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	mockCache.AssertNotCalled(t, "InvalidateAllProductCache")
+}
+
 func TestReviewHandler_GetByProductID_Success(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 	reviews := []*domain.Review{
@@ -474,10 +833,10 @@ func TestReviewHandler_GetByProductID_Success(t *testing.T) {
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
 	// Cache miss scenario
-	mockCache.On("GetReviewsList", mock.Anything, productID, 20, 0).Return(nil, 0, fmt.Errorf("cache miss"))
-	mockRepo.On("GetByProductID", mock.Anything, productID, 20, 0).Return(reviews, nil)
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(nil, 0, fmt.Errorf("cache miss"))
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 20, 0).Return(reviews, nil)
 	mockRepo.On("CountByProductID", mock.Anything, productID).Return(2, nil)
-	mockCache.On("SetReviewsList", mock.Anything, productID, 20, 0, reviews, 2).Return(nil)
+	mockCache.On("SetReviewsList", mock.Anything, productID, "", 20, 0, reviews, 2).Return(nil)
 
 	handler.GetByProductID(w, req)
 
@@ -490,6 +849,12 @@ func TestReviewHandler_GetByProductID_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Contains(t, response, "data")
 	assert.Contains(t, response, "pagination")
+
+	// Other shoppers see a display name, never the full last name
+	first := response["data"].([]any)[0].(map[string]any)
+	assert.Equal(t, "John D.", first["display_name"])
+	assert.NotContains(t, first, "last_name")
+	assert.NotContains(t, first, "first_name")
 }
 
 func TestReviewHandler_GetByProductID_CacheHit(t *testing.T) {
@@ -497,8 +862,8 @@ func TestReviewHandler_GetByProductID_CacheHit(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 	reviews := []*domain.Review{
@@ -520,7 +885,7 @@ func TestReviewHandler_GetByProductID_CacheHit(t *testing.T) {
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
 	// Cache hit scenario - count is included in cache
-	mockCache.On("GetReviewsList", mock.Anything, productID, 20, 0).Return(reviews, 1, nil)
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(reviews, 1, nil)
 
 	handler.GetByProductID(w, req)
 
@@ -541,8 +906,8 @@ func TestReviewHandler_GetByProductID_InvalidUUID(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/invalid-uuid/reviews", nil)
 	w := httptest.NewRecorder()
@@ -565,8 +930,8 @@ func TestReviewHandler_GetByProductID_WithPagination(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 	reviews := []*domain.Review{}
@@ -578,10 +943,10 @@ func TestReviewHandler_GetByProductID_WithPagination(t *testing.T) {
 	rctx.URLParams.Add("id", productID.String())
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
-	mockCache.On("GetReviewsList", mock.Anything, productID, 10, 20).Return(nil, 0, fmt.Errorf("cache miss"))
-	mockRepo.On("GetByProductID", mock.Anything, productID, 10, 20).Return(reviews, nil)
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 10, 20).Return(nil, 0, fmt.Errorf("cache miss"))
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 10, 20).Return(reviews, nil)
 	mockRepo.On("CountByProductID", mock.Anything, productID).Return(100, nil)
-	mockCache.On("SetReviewsList", mock.Anything, productID, 10, 20, reviews, 100).Return(nil)
+	mockCache.On("SetReviewsList", mock.Anything, productID, "", 10, 20, reviews, 100).Return(nil)
 
 	handler.GetByProductID(w, req)
 
@@ -602,8 +967,8 @@ func TestReviewHandler_GetByProductID_RepositoryError(t *testing.T) {
 	mockCache := new(MockReviewCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := review.NewService(mockRepo, mockCache, mockPublisher, log)
-	handler := NewReviewHandler(service, log)
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
 
 	productID := uuid.New()
 
@@ -614,11 +979,313 @@ func TestReviewHandler_GetByProductID_RepositoryError(t *testing.T) {
 	rctx.URLParams.Add("id", productID.String())
 	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
 
-	mockCache.On("GetReviewsList", mock.Anything, productID, 20, 0).Return(nil, 0, fmt.Errorf("cache miss"))
-	mockRepo.On("GetByProductID", mock.Anything, productID, 20, 0).Return(nil, fmt.Errorf("database error"))
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(nil, 0, fmt.Errorf("cache miss"))
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 20, 0).Return(nil, fmt.Errorf("database error"))
 
 	handler.GetByProductID(w, req)
 
 	assert.Equal(t, http.StatusInternalServerError, w.Code)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestReviewHandler_GetByProductID_FieldSelection(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	productID := uuid.New()
+	reviewID := uuid.New()
+	reviews := []*domain.Review{
+		{
+			ID:         reviewID,
+			ProductID:  productID,
+			FirstName:  "John",
+			LastName:   "Doe",
+			ReviewText: "Great product!",
+			Rating:     5,
+		},
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID.String()+"/reviews?fields=id,rating,review_text", nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(reviews, 1, nil)
+
+	handler.GetByProductID(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		Data []map[string]any `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]any{{
+		"id":          reviewID.String(),
+		"rating":      float64(5),
+		"review_text": "Great product!",
+	}}, response.Data)
+}
+
+func TestReviewHandler_GetByProductID_UnknownField(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	productID := uuid.New()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID.String()+"/reviews?fields=id,deleted_at,password", nil)
+	w := httptest.NewRecorder()
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+	handler.GetByProductID(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown fields: deleted_at, password")
+	mockCache.AssertNotCalled(t, "GetReviewsList")
+}
+
+func TestReviewHandler_GetByProductID_UnknownProduct(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockProductRepo := new(MockProductRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{Products: mockProductRepo}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	productID := uuid.New()
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(nil, 0, fmt.Errorf("cache miss"))
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 20, 0).Return([]*domain.Review{}, nil)
+	mockRepo.On("CountByProductID", mock.Anything, productID).Return(0, nil)
+	mockProductRepo.On("GetByID", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID.String()+"/reviews", nil)
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID.String())
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	w := httptest.NewRecorder()
+
+	handler.GetByProductID(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Product not found")
+	mockCache.AssertNotCalled(t, "SetReviewsList")
+}
+
+func TestReviewHandler_GetByProductID_Language(t *testing.T) {
+	newRequest := func(productID uuid.UUID, language string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/products/"+productID.String()+"/reviews?language="+language, nil)
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", productID.String())
+		return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	}
+
+	t.Run("filters by the lowercased language", func(t *testing.T) {
+		mockRepo := new(MockReviewRepository)
+		mockCache := new(MockReviewCache)
+		log := logger.New("test")
+		service := review.NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+		handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+		productID := uuid.New()
+		german := "de"
+		reviews := []*domain.Review{{ID: uuid.New(), ProductID: productID, FirstName: "Jana", LastName: "Novak", Language: &german, ReviewText: "Sehr gut", Rating: 5}}
+		mockCache.On("GetReviewsList", mock.Anything, productID, "de", 20, 0).Return(nil, 0, fmt.Errorf("cache miss"))
+		mockRepo.On("GetByProductIDAndLanguage", mock.Anything, productID, "de", domain.ReviewSortNewest, 20, 0).Return(reviews, nil)
+		mockRepo.On("CountByProductIDAndLanguage", mock.Anything, productID, "de").Return(1, nil)
+		mockCache.On("SetReviewsList", mock.Anything, productID, "de", 20, 0, reviews, 1).Return(nil)
+
+		w := httptest.NewRecorder()
+		handler.GetByProductID(w, newRequest(productID, "DE"))
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockRepo.AssertExpectations(t)
+
+		var response map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		first := response["data"].([]any)[0].(map[string]any)
+		assert.Equal(t, "de", first["language"])
+	})
+
+	t.Run("invalid language", func(t *testing.T) {
+		mockCache := new(MockReviewCache)
+		log := logger.New("test")
+		service := review.NewService(new(MockReviewRepository), mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+		handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+		w := httptest.NewRecorder()
+		handler.GetByProductID(w, newRequest(uuid.New(), "german"))
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "ISO 639-1")
+		mockCache.AssertNotCalled(t, "GetReviewsList")
+	})
+}
+
+func newImportRequest(t *testing.T, productID string, body any) *http.Request {
+	t.Helper()
+
+	bodyBytes, err := json.Marshal(body)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/products/"+productID+"/reviews/import", bytes.NewReader(bodyBytes))
+	req.Header.Set("Content-Type", "application/json")
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("id", productID)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+}
+
+func TestReviewHandler_Import_Success(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	productID := uuid.New()
+	req := newImportRequest(t, productID.String(), []ImportReviewRequest{
+		{FirstName: "Ann", LastName: "Lee", ReviewText: "Good", Rating: 4},
+		{FirstName: "Bo", LastName: "Kim", ReviewText: "Bad", Rating: 1},
+	})
+	w := httptest.NewRecorder()
+
+	mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.Review) bool {
+		return r.ProductID == productID && r.Source == domain.ReviewSourceImport
+	})).Return(nil).Twice()
+	mockCache.On("InvalidateProducts", mock.Anything, []uuid.UUID{productID}).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil).Once()
+
+	handler.Import(w, req)
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+
+	var response struct {
+		Data ImportReviewsResponse `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, 2, response.Data.Imported)
+}
+
+func TestReviewHandler_Import_ValidationErrors(t *testing.T) {
+	tooMany := make([]ImportReviewRequest, review.MaxImportBatchSize+1)
+
+	tests := []struct {
+		name      string
+		body      []ImportReviewRequest
+		wantField string
+	}{
+		{name: "empty batch", body: []ImportReviewRequest{}, wantField: "reviews"},
+		{name: "batch too large", body: tooMany, wantField: "reviews"},
+		{
+			name: "invalid review names its index",
+			body: []ImportReviewRequest{
+				{FirstName: "Ann", LastName: "Lee", ReviewText: "Good", Rating: 4},
+				{FirstName: "Bo", LastName: "Kim", ReviewText: "Bad", Rating: 9},
+			},
+			wantField: "[1].rating",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			log := logger.New("test")
+			service := review.NewService(mockRepo, new(MockReviewCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+			handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+			w := httptest.NewRecorder()
+			handler.Import(w, newImportRequest(t, uuid.New().String(), tt.body))
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), `"`+tt.wantField+`"`)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestReviewHandler_Import_MaxBatchItems(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, new(MockReviewCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	item := ImportReviewRequest{FirstName: "Ann", LastName: "Lee", ReviewText: "Good", Rating: 4}
+	req := newImportRequest(t, uuid.New().String(), []ImportReviewRequest{item, item, item})
+	req = req.WithContext(request.WithMaxBatchItems(req.Context(), 2))
+	w := httptest.NewRecorder()
+
+	handler.Import(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "must contain at most 2 items")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReviewHandler_Import_NotAnArray(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, new(MockReviewCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	w := httptest.NewRecorder()
+	handler.Import(w, newImportRequest(t, uuid.New().String(), map[string]any{"first_name": "Ann"}))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "Invalid request body")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestReviewHandler_Recent(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockReviewCache)
+	log := logger.New("test")
+	service := review.NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), review.Options{}, log)
+	handler := NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+
+	recent := []*domain.RecentReview{
+		{
+			Review:      domain.Review{ID: uuid.New(), ProductID: uuid.New(), FirstName: "John", LastName: "Doe", ReviewText: "Great", Rating: 5},
+			ProductName: "Widget",
+		},
+	}
+	mockCache.On("GetRecentReviews", mock.Anything, 5).Return(nil, domain.ErrNotFound)
+	mockRepo.On("Recent", mock.Anything, 5).Return(recent, nil)
+	mockCache.On("SetRecentReviews", mock.Anything, 5, recent).Return(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews/recent?limit=5", nil)
+	w := httptest.NewRecorder()
+
+	handler.Recent(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+
+	var body struct {
+		Data []map[string]any `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Data, 1)
+	// The embedded review is flattened next to the product name
+	assert.Equal(t, "Widget", body.Data[0]["product_name"])
+	assert.Equal(t, "Great", body.Data[0]["review_text"])
+	assert.Equal(t, "John D.", body.Data[0]["display_name"])
+	assert.NotContains(t, body.Data[0], "last_name")
+}
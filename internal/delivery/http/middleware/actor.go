@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/audit"
+)
+
+// AuditActor records who is making the request for the audit trail.
+// The public API has no user accounts, so requests presenting the admin API key are
+// attributed to the admin and everything else to an anonymous caller. Unlike AdminAuth
+// it never rejects a request.
+func AuditActor(adminAPIKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actor := audit.ActorAnonymous
+			provided := r.Header.Get(AdminKeyHeader)
+			if adminAPIKey != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(adminAPIKey)) == 1 {
+				actor = audit.ActorAdmin
+			}
+
+			next.ServeHTTP(w, r.WithContext(audit.WithActor(r.Context(), actor)))
+		})
+	}
+}
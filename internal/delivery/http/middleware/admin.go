@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+)
+
+// AdminKeyHeader carries the shared admin API key
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminAuth returns a middleware that restricts access to requests presenting the admin API key
+// An empty key disables admin endpoints entirely rather than leaving them open
+func AdminAuth(apiKey string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if apiKey == "" {
+				response.Error(w, http.StatusForbidden, "Admin API is disabled")
+				return
+			}
+
+			// Constant-time comparison to avoid leaking the key through response timing
+			provided := r.Header.Get(AdminKeyHeader)
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+				response.Error(w, http.StatusUnauthorized, "Unauthorized")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+)
+
+// MaxBodySize sets the request body limit enforced by request.DecodeJSON.
+// Applied router-wide with the configured default; wrap individual routes
+// with a larger value (e.g. bulk import) to override it.
+func MaxBodySize(limit int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(request.WithMaxBodySize(r.Context(), limit)))
+		})
+	}
+}
+
+// MaxBatchItems sets the item limit handlers apply to JSON arrays and ID lists
+// (via request.MaxBatchItems), so oversized batches are rejected before any work is done
+func MaxBatchItems(limit int) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(request.WithMaxBatchItems(r.Context(), limit)))
+		})
+	}
+}
+
+// StrictContentType sets whether request.DecodeJSON rejects bodies whose Content-Type isn't
+// application/json (415). Lenient deployments turn it off for clients that send JSON as
+// text/plain or without a Content-Type.
+func StrictContentType(strict bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(request.WithStrictContentType(r.Context(), strict)))
+		})
+	}
+}
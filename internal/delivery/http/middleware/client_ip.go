@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clientip"
+)
+
+// ClientIP makes trustedProxies available to request.ClientIP and records the resolved
+// client IP for services (see clientip.FromContext)
+func ClientIP(trustedProxies []*net.IPNet) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.WithContext(request.WithTrustedProxies(r.Context(), trustedProxies))
+			next.ServeHTTP(w, r.WithContext(clientip.WithIP(r.Context(), request.ClientIP(r))))
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clientip"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
 )
 
@@ -38,6 +39,7 @@ func Logger(log *logger.Logger) func(http.Handler) http.Handler {
 				"status":      rw.statusCode,
 				"duration_ms": duration.Milliseconds(),
 				"remote_addr": r.RemoteAddr,
+				"client_ip":   clientip.FromContext(r.Context()),
 			}).Info("HTTP request")
 		})
 	}
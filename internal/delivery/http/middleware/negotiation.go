@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
+)
+
+// ContentNegotiation resolves the response media type from the Accept header.
+// Clients pinning an envelope version this server can't produce get 406 instead
+// of a body shape they don't expect.
+func ContentNegotiation() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mediaType, ok := response.NegotiateMediaType(r.Header.Get("Accept"))
+			if !ok {
+				response.Error(w, http.StatusNotAcceptable,
+					"Supported media types: "+response.MediaTypeJSON+", "+response.MediaTypeV1)
+				return
+			}
+
+			// response.JSON keeps this instead of overwriting it with plain JSON
+			w.Header().Set("Content-Type", mediaType)
+			w.Header().Add("Vary", "Accept")
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/sanitize"
+)
+
+// reviewTextField is the JSON key of review text in every response that carries a review
+const reviewTextField = "review_text"
+
+// reviewTitleField is the JSON key of the optional review headline, sanitized like the text
+const reviewTitleField = "title"
+
+// SanitizeReviewText strips HTML tags from review_text and title in JSON responses
+// (SANITIZE_REVIEW_TEXT=output). Stored text is left untouched, so turning the mode
+// off restores what reviewers wrote; the cost is buffering each response once.
+func SanitizeReviewText() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			buf := &bufferedWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(buf, r)
+
+			body := buf.body.Bytes()
+			// Only JSON carries review_text; a body we can't parse goes out unchanged
+			if strings.Contains(w.Header().Get("Content-Type"), "json") && bytes.Contains(body, []byte(reviewTextField)) {
+				if sanitized, err := sanitize.JSONField(body, reviewTextField, reviewTitleField); err == nil {
+					body = sanitized
+				}
+			}
+
+			if w.Header().Get("Content-Length") != "" {
+				w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			}
+			w.WriteHeader(buf.status)
+			_, _ = w.Write(body)
+		})
+	}
+}
+
+// bufferedWriter holds the status and body until the handler returns; headers go
+// straight to the underlying writer since nothing is sent before WriteHeader
+type bufferedWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (w *bufferedWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.status = status
+	w.wroteHeader = true
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.body.Write(b)
+}
@@ -0,0 +1,67 @@
+package request
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+type trustedProxiesKey struct{}
+
+// WithTrustedProxies returns a context carrying the proxies whose forwarding headers ClientIP believes
+func WithTrustedProxies(ctx context.Context, proxies []*net.IPNet) context.Context {
+	return context.WithValue(ctx, trustedProxiesKey{}, proxies)
+}
+
+// ClientIP returns the address of the client that made the request.
+// Forwarding headers are only read when the direct peer is a trusted proxy (TRUSTED_PROXIES),
+// since anyone can send them. X-Forwarded-For is walked from the right, skipping trusted
+// proxies, so entries a client prepended itself are never reached; X-Real-IP is the fallback
+// for proxies that only set that. Without a trusted peer the peer address is returned.
+func ClientIP(r *http.Request) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+
+	proxies, _ := r.Context().Value(trustedProxiesKey{}).([]*net.IPNet)
+	if !isTrustedProxy(peer, proxies) {
+		return peer
+	}
+
+	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+		hops := strings.Split(forwarded, ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if net.ParseIP(hop) == nil {
+				// Garbage from beyond our proxies; the last address they vouched for is all we know
+				break
+			}
+			if !isTrustedProxy(hop, proxies) {
+				return hop
+			}
+			peer = hop
+		}
+		return peer
+	}
+
+	if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+		return realIP
+	}
+
+	return peer
+}
+
+func isTrustedProxy(ip string, proxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range proxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package request
+
+import (
+	"net"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIP(t *testing.T) {
+	_, proxies, _ := net.ParseCIDR("10.0.0.0/8")
+	trusted := []*net.IPNet{proxies}
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		headers    map[string]string
+		trusted    []*net.IPNet
+		want       string
+	}{
+		{
+			name:       "no proxies configured ignores headers",
+			remoteAddr: "198.51.100.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "untrusted peer cannot spoof",
+			remoteAddr: "198.51.100.1:5000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7", "X-Real-IP": "203.0.113.8"},
+			trusted:    trusted,
+			want:       "198.51.100.1",
+		},
+		{
+			name:       "trusted peer forwards client",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Forwarded-For": "203.0.113.7"},
+			trusted:    trusted,
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "client-supplied entries left of the real client are skipped",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Forwarded-For": "1.2.3.4, 203.0.113.7, 10.0.0.9"},
+			trusted:    trusted,
+			want:       "203.0.113.7",
+		},
+		{
+			name:       "garbage stops the walk at the last trusted hop",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Forwarded-For": "not-an-ip, 10.0.0.9"},
+			trusted:    trusted,
+			want:       "10.0.0.9",
+		},
+		{
+			name:       "X-Real-IP when X-Forwarded-For is absent",
+			remoteAddr: "10.0.0.5:5000",
+			headers:    map[string]string{"X-Real-IP": "203.0.113.8"},
+			trusted:    trusted,
+			want:       "203.0.113.8",
+		},
+		{
+			name:       "trusted peer without headers",
+			remoteAddr: "10.0.0.5:5000",
+			trusted:    trusted,
+			want:       "10.0.0.5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				r.Header.Set(k, v)
+			}
+			r = r.WithContext(WithTrustedProxies(r.Context(), tt.trusted))
+
+			assert.Equal(t, tt.want, ClientIP(r))
+		})
+	}
+}
@@ -1,17 +1,82 @@
 package request
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
+	"mime"
 	"net/http"
+	"reflect"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 )
 
-const maxRequestBodySize = 1 << 20 // 1MB
+// DefaultMaxBodySize applies when no route-specific limit was set on the request context
+const DefaultMaxBodySize int64 = 1 << 20 // 1MB
+
+// DefaultMaxBatchItems applies when no batch item limit was set on the request context
+const DefaultMaxBatchItems = 1000
+
+var (
+	// ErrBodyTooLarge is returned by DecodeJSON when the body exceeds the request's size limit
+	ErrBodyTooLarge = errors.New("request body too large")
+	// ErrUnsupportedMediaType is returned by DecodeJSON when Content-Type isn't application/json
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	// ErrTooManyItems is returned when a JSON array or ID list exceeds its item limit
+	ErrTooManyItems = errors.New("too many items")
+)
+
+type maxBodySizeKey struct{}
+
+type maxBatchItemsKey struct{}
+
+type strictContentTypeKey struct{}
+
+// WithMaxBodySize returns a context carrying the body size limit DecodeJSON enforces
+func WithMaxBodySize(ctx context.Context, limit int64) context.Context {
+	return context.WithValue(ctx, maxBodySizeKey{}, limit)
+}
+
+// MaxBodySize returns the body size limit for the request, falling back to DefaultMaxBodySize
+func MaxBodySize(r *http.Request) int64 {
+	if limit, ok := r.Context().Value(maxBodySizeKey{}).(int64); ok && limit > 0 {
+		return limit
+	}
+	return DefaultMaxBodySize
+}
+
+// WithMaxBatchItems returns a context carrying the batch item limit handlers read via MaxBatchItems
+func WithMaxBatchItems(ctx context.Context, limit int) context.Context {
+	return context.WithValue(ctx, maxBatchItemsKey{}, limit)
+}
+
+// MaxBatchItems returns the largest number of items a batch in this request may hold,
+// falling back to DefaultMaxBatchItems
+func MaxBatchItems(r *http.Request) int {
+	if limit, ok := r.Context().Value(maxBatchItemsKey{}).(int); ok && limit > 0 {
+		return limit
+	}
+	return DefaultMaxBatchItems
+}
+
+// WithStrictContentType returns a context telling DecodeJSON whether to reject bodies
+// not declared as application/json
+func WithStrictContentType(ctx context.Context, strict bool) context.Context {
+	return context.WithValue(ctx, strictContentTypeKey{}, strict)
+}
+
+// StrictContentType reports whether the request's body must be declared as application/json.
+// Strict unless a lenient deployment turned it off.
+func StrictContentType(r *http.Request) bool {
+	if strict, ok := r.Context().Value(strictContentTypeKey{}).(bool); ok {
+		return strict
+	}
+	return true
+}
 
 // DecodeJSON decodes JSON request body into the provided struct with size limit
 func DecodeJSON(r *http.Request, v any) error {
@@ -19,12 +84,102 @@ func DecodeJSON(r *http.Request, v any) error {
 		_ = r.Body.Close()
 	}()
 
-	// Limit request body size to prevent DoS attacks
-	limitedReader := io.LimitReader(r.Body, maxRequestBodySize)
+	if err := requireJSONContentType(r); err != nil {
+		return err
+	}
+
+	// Limit request body size to prevent DoS attacks.
+	// MaxBytesReader (unlike io.LimitReader) reports overflow instead of silently
+	// truncating, so oversized bodies surface as 413 rather than a JSON syntax error.
+	limit := MaxBodySize(r)
+	limitedReader := http.MaxBytesReader(nil, r.Body, limit)
 
 	if err := json.NewDecoder(limitedReader).Decode(v); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, limit)
+		}
+		return fmt.Errorf("failed to decode JSON: %w", err)
+	}
+	return nil
+}
+
+// DecodeJSONArray decodes a JSON array body into v, which must point to a slice.
+// Elements are decoded one at a time and decoding stops with ErrTooManyItems as soon as
+// the array grows past maxItems, so an oversized batch is rejected before it is held in memory.
+// The body size limit and Content-Type check of DecodeJSON apply as well.
+func DecodeJSONArray(r *http.Request, v any, maxItems int) error {
+	defer func() {
+		_ = r.Body.Close()
+	}()
+
+	slice := reflect.ValueOf(v)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("DecodeJSONArray: expected pointer to slice, got %T", v)
+	}
+	slice = slice.Elem()
+
+	if err := requireJSONContentType(r); err != nil {
+		return err
+	}
+
+	limit := MaxBodySize(r)
+	dec := json.NewDecoder(http.MaxBytesReader(nil, r.Body, limit))
+
+	err := decodeArray(dec, slice, maxItems)
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &maxBytesErr):
+		return fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, limit)
+	case errors.Is(err, ErrTooManyItems):
+		return err
+	default:
 		return fmt.Errorf("failed to decode JSON: %w", err)
 	}
+}
+
+// decodeArray reads '[', then each element into a new slice entry, then ']'
+func decodeArray(dec *json.Decoder, slice reflect.Value, maxItems int) error {
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected JSON array, got %v", token)
+	}
+
+	// Non-nil even when empty, matching json.Unmarshal of []
+	slice.Set(reflect.MakeSlice(slice.Type(), 0, 0))
+	for dec.More() {
+		if slice.Len() == maxItems {
+			return fmt.Errorf("%w: at most %d allowed", ErrTooManyItems, maxItems)
+		}
+		elem := reflect.New(slice.Type().Elem())
+		if err := dec.Decode(elem.Interface()); err != nil {
+			return err
+		}
+		slice.Set(reflect.Append(slice, elem.Elem()))
+	}
+
+	_, err = dec.Token()
+	return err
+}
+
+// requireJSONContentType rejects bodies not declared as application/json.
+// Parameters such as charset are allowed. With strict checking off any Content-Type,
+// or none, is accepted and a non-JSON body fails as a decode error instead.
+func requireJSONContentType(r *http.Request) error {
+	if !StrictContentType(r) {
+		return nil
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "application/json" {
+		return fmt.Errorf("%w: %q", ErrUnsupportedMediaType, contentType)
+	}
 	return nil
 }
 
@@ -58,14 +213,70 @@ func GetIntQuery(r *http.Request, key string, defaultValue int) int {
 	return intValue
 }
 
-// GetPaginationParams extracts and validates pagination parameters
+// GetListQuery splits a comma-separated query parameter, ignoring blank entries
+func GetListQuery(r *http.Request, key string) []string {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return nil
+	}
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// GetUUIDListQuery parses a comma-separated list of UUIDs, dropping duplicates but keeping
+// first-seen order. Returns ErrTooManyItems when more than maxItems distinct IDs remain.
+func GetUUIDListQuery(r *http.Request, key string, maxItems int) ([]uuid.UUID, error) {
+	items := GetListQuery(r, key)
+
+	ids := make([]uuid.UUID, 0, len(items))
+	seen := make(map[uuid.UUID]bool, len(items))
+	for _, item := range items {
+		id, err := uuid.Parse(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid UUID %q in %s: %w", item, key, err)
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+
+	if len(ids) > maxItems {
+		return nil, fmt.Errorf("%w: at most %d allowed", ErrTooManyItems, maxItems)
+	}
+	return ids, nil
+}
+
+// PaginationConfig holds the page size rules for one list endpoint
+type PaginationConfig struct {
+	DefaultLimit int
+	MaxLimit     int
+}
+
+// DefaultPagination is used by list endpoints without their own page size configuration
+var DefaultPagination = PaginationConfig{DefaultLimit: 20, MaxLimit: 100}
+
+// GetPaginationParams extracts and validates pagination parameters using DefaultPagination
 func GetPaginationParams(r *http.Request) (limit, offset int) {
-	limit = GetIntQuery(r, "limit", 20)
+	return GetPaginationParamsWithConfig(r, DefaultPagination)
+}
+
+// GetPaginationParamsWithConfig extracts and validates pagination parameters.
+// A missing, non-positive or too large limit falls back to the configured default.
+func GetPaginationParamsWithConfig(r *http.Request, cfg PaginationConfig) (limit, offset int) {
+	limit = GetIntQuery(r, "limit", cfg.DefaultLimit)
 	offset = GetIntQuery(r, "offset", 0)
 
 	// Validate and clamp values
-	if limit <= 0 || limit > 100 {
-		limit = 20
+	if limit <= 0 || limit > cfg.MaxLimit {
+		limit = cfg.DefaultLimit
 	}
 	if offset < 0 {
 		offset = 0
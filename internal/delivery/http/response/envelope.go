@@ -0,0 +1,65 @@
+package response
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// APIVersion is the response envelope version reported in every enveloped body
+const APIVersion = "1.0"
+
+// Media types the API can respond with
+const (
+	MediaTypeJSON = "application/json"
+	// MediaTypeV1 lets clients pin the v1 envelope explicitly via Accept
+	MediaTypeV1 = "application/vnd.productreviews.v1+json"
+
+	vendorMediaTypePrefix = "application/vnd.productreviews."
+)
+
+// NegotiateMediaType picks the response media type from an Accept header.
+// An explicit v1 vendor type is echoed back; anything else gets plain JSON, as
+// before versioning existed. Returns false only when the client accepts nothing
+// but envelope versions this server can't produce (e.g. a future v2).
+func NegotiateMediaType(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return MediaTypeJSON, true
+	}
+
+	acceptable := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+
+		switch {
+		case mediaType == MediaTypeV1:
+			return MediaTypeV1, true
+		case strings.HasPrefix(mediaType, vendorMediaTypePrefix):
+			// Versioned envelope we don't serve; keep looking for an alternative
+		default:
+			acceptable = true
+		}
+	}
+
+	if !acceptable {
+		return "", false
+	}
+	return MediaTypeJSON, true
+}
+
+// envelope adds the fields every enveloped response carries.
+// All enveloped responses go through here so the shape changes in one place.
+func envelope(body map[string]any) map[string]any {
+	body["api_version"] = APIVersion
+	return body
+}
+
+// setContentType keeps a media type negotiated earlier in the chain and defaults to JSON
+func setContentType(w http.ResponseWriter) {
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", MediaTypeJSON)
+	}
+}
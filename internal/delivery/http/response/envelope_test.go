@@ -0,0 +1,52 @@
+package response
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiateMediaType(t *testing.T) {
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+		wantOK bool
+	}{
+		{name: "no accept header", accept: "", want: MediaTypeJSON, wantOK: true},
+		{name: "wildcard", accept: "*/*", want: MediaTypeJSON, wantOK: true},
+		{name: "plain json", accept: "application/json", want: MediaTypeJSON, wantOK: true},
+		{name: "pinned v1", accept: MediaTypeV1, want: MediaTypeV1, wantOK: true},
+		{name: "v1 among others", accept: "text/html, " + MediaTypeV1 + ";q=0.9", want: MediaTypeV1, wantOK: true},
+		{name: "unsupported version with json fallback", accept: "application/vnd.productreviews.v2+json, application/json;q=0.5", want: MediaTypeJSON, wantOK: true},
+		{name: "only unsupported version", accept: "application/vnd.productreviews.v2+json", wantOK: false},
+		{name: "non-json types stay lenient", accept: "text/html", want: MediaTypeJSON, wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := NegotiateMediaType(tt.accept)
+
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEnvelope_CarriesAPIVersion(t *testing.T) {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", MediaTypeV1)
+
+	Success(w, map[string]string{"id": "1"})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	// Negotiated media type is kept
+	assert.Equal(t, MediaTypeV1, w.Header().Get("Content-Type"))
+
+	var body map[string]any
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, APIVersion, body["api_version"])
+}
@@ -0,0 +1,33 @@
+package response
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Project re-encodes a slice of items as objects holding only the given JSON fields.
+// Projection happens after the data is loaded (and cached) in full, so one cache
+// entry serves every field selection.
+func Project(items any, fields []string) ([]map[string]json.RawMessage, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode items for projection: %w", err)
+	}
+
+	var full []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, fmt.Errorf("items are not a list of objects: %w", err)
+	}
+
+	projected := make([]map[string]json.RawMessage, len(full))
+	for i, item := range full {
+		projected[i] = make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := item[field]; ok {
+				projected[i][field] = value
+			}
+		}
+	}
+
+	return projected, nil
+}
@@ -19,33 +19,96 @@ func JSON(w http.ResponseWriter, statusCode int, data any) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	setContentType(w)
 	w.WriteHeader(statusCode)
 	// If writing to response fails, connection is broken and no recovery possible
 	_, _ = buf.WriteTo(w)
 }
 
-// Error writes an error response
+// Machine-readable error codes so clients can branch without parsing the English message
+const (
+	CodeInvalidRequest     = "INVALID_REQUEST"
+	CodeValidationFailed   = "VALIDATION_FAILED"
+	CodeNotFound           = "NOT_FOUND"
+	CodeAlreadyExists      = "ALREADY_EXISTS"
+	CodeConflict           = "CONFLICT"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMedia   = "UNSUPPORTED_MEDIA_TYPE"
+	CodeNotAcceptable      = "NOT_ACCEPTABLE"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	CodeInternal           = "INTERNAL_ERROR"
+)
+
+// codeForStatus picks a default error code for callers that only know the HTTP status
+func codeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return CodeInvalidRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusNotAcceptable:
+		return CodeNotAcceptable
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusUnsupportedMediaType:
+		return CodeUnsupportedMedia
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	default:
+		return CodeInternal
+	}
+}
+
+// Error writes an error response with a code derived from the status
 func Error(w http.ResponseWriter, statusCode int, message string) {
-	JSON(w, statusCode, map[string]string{
+	ErrorWithCode(w, statusCode, codeForStatus(statusCode), message)
+}
+
+// ErrorWithCode writes an error response with an explicit machine-readable code
+func ErrorWithCode(w http.ResponseWriter, statusCode int, code, message string) {
+	JSON(w, statusCode, envelope(map[string]any{
 		"error": message,
-	})
+		"code":  code,
+	}))
+}
+
+// ValidationError writes a 400 response listing per-field validation messages
+func ValidationError(w http.ResponseWriter, fields map[string]string) {
+	body := map[string]any{
+		"error": "Invalid input",
+		"code":  CodeValidationFailed,
+	}
+	if len(fields) > 0 {
+		body["fields"] = fields
+	}
+	JSON(w, http.StatusBadRequest, envelope(body))
 }
 
 // Success writes a success response with data
 func Success(w http.ResponseWriter, data any) {
-	JSON(w, http.StatusOK, map[string]any{
+	JSON(w, http.StatusOK, envelope(map[string]any{
 		"success": true,
 		"data":    data,
-	})
+	}))
 }
 
 // Created writes a created response
 func Created(w http.ResponseWriter, data any) {
-	JSON(w, http.StatusCreated, map[string]any{
+	JSON(w, http.StatusCreated, envelope(map[string]any{
 		"success": true,
 		"data":    data,
-	})
+	}))
 }
 
 // NoContent writes a no content response
@@ -55,7 +118,7 @@ func NoContent(w http.ResponseWriter) {
 
 // Paginated writes a paginated response
 func Paginated(w http.ResponseWriter, data any, total, limit, offset int) {
-	JSON(w, http.StatusOK, map[string]any{
+	JSON(w, http.StatusOK, envelope(map[string]any{
 		"success": true,
 		"data":    data,
 		"pagination": map[string]int{
@@ -63,5 +126,5 @@ func Paginated(w http.ResponseWriter, data any, total, limit, offset int) {
 			"limit":  limit,
 			"offset": offset,
 		},
-	})
+	}))
 }
@@ -2,6 +2,7 @@ package http
 
 import (
 	"net/http"
+	"net/http/pprof"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -12,64 +13,198 @@ import (
 	"github.com/Pesokrava/product_reviewer/internal/delivery/http/middleware"
 	"github.com/Pesokrava/product_reviewer/internal/delivery/http/response"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/sanitize"
 )
 
+// importMaxBodySize fits a full review import batch (1000 reviews of up to 5000 characters)
+const importMaxBodySize = 8 << 20 // 8MB
+
 // Router holds HTTP handlers and router configuration
 type Router struct {
 	productHandler *handler.ProductHandler
 	reviewHandler  *handler.ReviewHandler
+	detailHandler  *handler.ProductDetailHandler
+	adminHandler   *handler.AdminHandler
+	healthHandler  *handler.HealthHandler
+	metrics        *metrics.Registry
 	logger         *logger.Logger
 	cfg            *config.Config
 }
 
-// NewRouter creates a new HTTP router
+// NewRouter creates a new HTTP router.
+// reg is served on /metrics next to the other operational routes; nil leaves it out.
 func NewRouter(
 	productHandler *handler.ProductHandler,
 	reviewHandler *handler.ReviewHandler,
+	detailHandler *handler.ProductDetailHandler,
+	adminHandler *handler.AdminHandler,
+	healthHandler *handler.HealthHandler,
+	reg *metrics.Registry,
 	cfg *config.Config,
 	log *logger.Logger,
 ) *Router {
 	return &Router{
 		productHandler: productHandler,
 		reviewHandler:  reviewHandler,
+		detailHandler:  detailHandler,
+		adminHandler:   adminHandler,
+		healthHandler:  healthHandler,
+		metrics:        reg,
 		logger:         log,
 		cfg:            cfg,
 	}
 }
 
-// Setup configures and returns the HTTP router
+// Setup configures and returns the public HTTP router.
+// Operational routes (readiness, metrics, pprof, admin API) are only included when no
+// separate admin listener is configured; otherwise SetupAdmin serves them.
 func (rt *Router) Setup() http.Handler {
 	r := chi.NewRouter()
 
 	r.Use(middleware.Recovery(rt.logger))
+	r.Use(middleware.ClientIP(rt.cfg.Server.TrustedProxies))
 	r.Use(middleware.Logger(rt.logger))
 	r.Use(middleware.Timeout(30 * time.Second))
+	r.Use(middleware.MaxBodySize(rt.cfg.Server.MaxRequestBodySize))
+	r.Use(middleware.MaxBatchItems(rt.cfg.Server.MaxBatchItems))
+	r.Use(middleware.StrictContentType(rt.cfg.Server.StrictContentType))
 
 	r.Get("/health", rt.healthCheck)
+	r.Get("/version", rt.healthHandler.Version)
 	// Redirect /docs to /docs/index.html to ensure the Swagger UI is served correctly
 	r.Get("/docs", http.RedirectHandler("/docs/index.html", http.StatusMovedPermanently).ServeHTTP)
 	r.Get("/docs/*", httpSwagger.WrapHandler)
 
+	servesOps := rt.cfg.Server.AdminPort == ""
+	if servesOps {
+		rt.mountOps(r)
+	}
+
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(middleware.ContentNegotiation())
+		r.Use(middleware.AuditActor(rt.cfg.Admin.APIKey))
+		if rt.cfg.Review.SanitizeText == sanitize.ModeOutput {
+			r.Use(middleware.SanitizeReviewText())
+		}
+
 		r.Route("/products", func(r chi.Router) {
 			r.Post("/", rt.productHandler.Create)
 			r.Get("/", rt.productHandler.List)
+			r.Get("/compare", rt.productHandler.Compare)
 			r.Get("/{id}", rt.productHandler.GetByID)
 			r.Put("/{id}", rt.productHandler.Update)
 			r.Delete("/{id}", rt.productHandler.Delete)
 			r.Get("/{id}/reviews", rt.reviewHandler.GetByProductID)
+			r.Get("/{id}/reviews/summary", rt.detailHandler.Summary)
+			r.Get("/{id}/detail", rt.detailHandler.Get)
+			if servesOps {
+				rt.mountProductsAdmin(r)
+			}
 		})
 
 		r.Route("/reviews", func(r chi.Router) {
 			r.Post("/", rt.reviewHandler.Create)
+			r.Get("/recent", rt.reviewHandler.Recent)
+			if servesOps {
+				rt.mountReviewsAdmin(r)
+			}
 			r.Put("/{id}", rt.reviewHandler.Update)
 			r.Delete("/{id}", rt.reviewHandler.Delete)
+			r.Post("/{id}/anonymize", rt.reviewHandler.Anonymize)
+			r.Post("/{id}/flag", rt.reviewHandler.Flag)
 		})
+
+		if servesOps {
+			r.Route("/admin", rt.mountAdminAPI)
+		}
 	})
 
 	return r
 }
 
+// SetupAdmin configures and returns the router for the separate admin listener (ADMIN_PORT).
+// Shares handlers with the public router so both listeners see the same state.
+func (rt *Router) SetupAdmin() http.Handler {
+	r := chi.NewRouter()
+
+	r.Use(middleware.Recovery(rt.logger))
+	r.Use(middleware.ClientIP(rt.cfg.Server.TrustedProxies))
+	r.Use(middleware.Logger(rt.logger))
+	r.Use(middleware.MaxBodySize(rt.cfg.Server.MaxRequestBodySize))
+	r.Use(middleware.MaxBatchItems(rt.cfg.Server.MaxBatchItems))
+	r.Use(middleware.StrictContentType(rt.cfg.Server.StrictContentType))
+
+	r.Get("/health", rt.healthCheck)
+	r.Get("/version", rt.healthHandler.Version)
+	rt.mountOps(r)
+	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(middleware.ContentNegotiation())
+		r.Use(middleware.AuditActor(rt.cfg.Admin.APIKey))
+		if rt.cfg.Review.SanitizeText == sanitize.ModeOutput {
+			r.Use(middleware.SanitizeReviewText())
+		}
+
+		r.Route("/products", rt.mountProductsAdmin)
+		r.Route("/reviews", rt.mountReviewsAdmin)
+		r.Route("/admin", rt.mountAdminAPI)
+	})
+
+	return r
+}
+
+// mountOps registers operational routes that shouldn't be exposed next to the public API when avoidable
+func (rt *Router) mountOps(r chi.Router) {
+	r.Get("/readyz", rt.healthHandler.Ready)
+	if rt.metrics != nil {
+		r.Method(http.MethodGet, "/metrics", rt.metrics)
+	}
+
+	if rt.cfg.Server.EnablePprof {
+		r.Route("/debug/pprof", mountPprof)
+	}
+}
+
+// mountAdminAPI registers the key-guarded admin endpoints
+func (rt *Router) mountAdminAPI(r chi.Router) {
+	r.Use(middleware.AdminAuth(rt.cfg.Admin.APIKey))
+	r.Get("/stream-info", rt.adminHandler.StreamInfo)
+	r.Get("/health/detailed", rt.healthHandler.Detailed)
+	r.Post("/cache/flush", rt.adminHandler.FlushCache)
+	r.Delete("/cache/products/{id}", rt.adminHandler.InvalidateProductCache)
+	r.Get("/audit", rt.adminHandler.Audit)
+	r.Get("/products/unreviewed", rt.productHandler.Unreviewed)
+	r.Post("/products/{id}/reconcile", rt.adminHandler.ReconcileProduct)
+	r.Get("/reviews/flagged", rt.reviewHandler.Flagged)
+	r.Post("/reviews/bulk-delete", rt.reviewHandler.BulkDelete)
+}
+
+// mountProductsAdmin registers the key-guarded routes under /products. They keep their
+// public paths but follow /api/v1/admin onto the admin listener.
+func (rt *Router) mountProductsAdmin(r chi.Router) {
+	r.With(
+		middleware.AdminAuth(rt.cfg.Admin.APIKey),
+		middleware.MaxBodySize(importMaxBodySize),
+	).Post("/{id}/reviews/import", rt.reviewHandler.Import)
+}
+
+// mountReviewsAdmin registers the key-guarded routes under /reviews; see mountProductsAdmin
+func (rt *Router) mountReviewsAdmin(r chi.Router) {
+	r.With(middleware.AdminAuth(rt.cfg.Admin.APIKey)).Get("/changes", rt.reviewHandler.Changes)
+}
+
+// mountPprof registers the net/http/pprof handlers.
+// Registered explicitly because importing net/http/pprof only wires them into
+// http.DefaultServeMux, which this server doesn't use.
+func mountPprof(r chi.Router) {
+	r.HandleFunc("/cmdline", pprof.Cmdline)
+	r.HandleFunc("/profile", pprof.Profile)
+	r.HandleFunc("/symbol", pprof.Symbol)
+	r.HandleFunc("/trace", pprof.Trace)
+	// Index serves the listing and every named profile (heap, goroutine, ...)
+	r.HandleFunc("/*", pprof.Index)
+}
+
 // healthCheck handles health check requests
 func (rt *Router) healthCheck(w http.ResponseWriter, r *http.Request) {
 	response.JSON(w, http.StatusOK, map[string]string{
@@ -0,0 +1,285 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/handler"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/middleware"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/audit"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
+	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+)
+
+// routerReviewRepository stores created reviews; calls outside the tested routes panic
+type routerReviewRepository struct {
+	domain.ReviewRepository
+	mu      sync.Mutex
+	created int
+	flags   map[domain.ReviewFlag]bool
+}
+
+// Flag counts one flag per review and client IP, like the review_flags unique constraint
+func (r *routerReviewRepository) Flag(_ context.Context, flag *domain.ReviewFlag) (*domain.FlaggedReview, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.flags == nil {
+		r.flags = make(map[domain.ReviewFlag]bool)
+	}
+	key := domain.ReviewFlag{ReviewID: flag.ReviewID, ClientIP: flag.ClientIP}
+	if r.flags[key] {
+		return nil, domain.ErrAlreadyExists
+	}
+	r.flags[key] = true
+	return &domain.FlaggedReview{Review: domain.Review{ID: flag.ReviewID}, FlagCount: len(r.flags)}, nil
+}
+
+func (r *routerReviewRepository) DeleteBatch(_ context.Context, ids []uuid.UUID) ([]*domain.Review, error) {
+	deleted := make([]*domain.Review, len(ids))
+	for i, id := range ids {
+		deleted[i] = &domain.Review{ID: id, ProductID: uuid.New()}
+	}
+	return deleted, nil
+}
+
+func (r *routerReviewRepository) Create(_ context.Context, review *domain.Review) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.created++
+	review.ID = uuid.New()
+	return nil
+}
+
+// routerReviewCache counts review attempts per client IP and product like the Redis cache does
+type routerReviewCache struct {
+	review.ReviewCache
+	mu       sync.Mutex
+	attempts map[string]int64
+}
+
+func (c *routerReviewCache) IncrReviewAttempts(_ context.Context, ip string, productID uuid.UUID, _ time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.attempts == nil {
+		c.attempts = make(map[string]int64)
+	}
+	c.attempts[ip+"|"+productID.String()]++
+	return c.attempts[ip+"|"+productID.String()], nil
+}
+
+func (c *routerReviewCache) InvalidateAllProductCache(context.Context, uuid.UUID) error {
+	return nil
+}
+
+func (c *routerReviewCache) InvalidateProducts(context.Context, []uuid.UUID) error {
+	return nil
+}
+
+type routerPublisher struct{}
+
+func (routerPublisher) Publish(context.Context, string, []byte) error {
+	return nil
+}
+
+// newTestRouter wires the public router around a review service backed by repo and cache
+func newTestRouter(t *testing.T, cfg *config.Config, repo domain.ReviewRepository, cache review.ReviewCache) http.Handler {
+	t.Helper()
+	return newReviewRouter(t, cfg, repo, cache, &fakes.AuditRepository{}).Setup()
+}
+
+// newReviewRouter builds a Router whose review handler writes audit entries to audits
+func newReviewRouter(t *testing.T, cfg *config.Config, repo domain.ReviewRepository, cache review.ReviewCache, audits *fakes.AuditRepository) *Router {
+	t.Helper()
+
+	log := logger.New("test")
+	throttle := review.Throttle{Limit: cfg.Review.ThrottleLimit, Window: cfg.Review.ThrottleWindow}
+	service := review.NewService(repo, cache, routerPublisher{}, fakes.PassthroughTx{}, audits, review.Options{Throttle: throttle, FlagThreshold: cfg.Review.FlagThreshold}, log)
+	t.Cleanup(func() { service.Shutdown(context.Background()) })
+
+	reviewHandler := handler.NewReviewHandler(service, domain.ReviewSourceWeb, request.DefaultPagination, log)
+	return NewRouter(nil, reviewHandler, nil, nil, nil, nil, cfg, log)
+}
+
+func postReview(t *testing.T, router http.Handler, productID uuid.UUID, remoteAddr string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	body, err := json.Marshal(handler.CreateReviewRequest{
+		ProductID:  productID.String(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Great product!",
+		Rating:     5,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.RemoteAddr = remoteAddr
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRouter_Setup_ThrottlesReviewsPerClientIP(t *testing.T) {
+	cfg := &config.Config{Review: config.ReviewConfig{ThrottleLimit: 2, ThrottleWindow: time.Hour}}
+	repo := &routerReviewRepository{}
+	router := newTestRouter(t, cfg, repo, &routerReviewCache{})
+	productID := uuid.New()
+
+	for i := 0; i < cfg.Review.ThrottleLimit; i++ {
+		w := postReview(t, router, productID, "203.0.113.7:4000", nil)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	w := postReview(t, router, productID, "203.0.113.7:4001", nil)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Contains(t, w.Body.String(), "RATE_LIMITED")
+
+	// Another client is counted separately
+	w = postReview(t, router, productID, "198.51.100.9:4000", nil)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Equal(t, cfg.Review.ThrottleLimit+1, repo.created)
+}
+
+func TestRouter_Setup_ResolvesClientIPBehindTrustedProxy(t *testing.T) {
+	_, proxies, err := net.ParseCIDR("10.0.0.0/8")
+	require.NoError(t, err)
+	cfg := &config.Config{
+		Server: config.ServerConfig{TrustedProxies: []*net.IPNet{proxies}},
+		Review: config.ReviewConfig{ThrottleLimit: 1, ThrottleWindow: time.Hour},
+	}
+	cache := &routerReviewCache{}
+	router := newTestRouter(t, cfg, &routerReviewRepository{}, cache)
+	productID := uuid.New()
+
+	forwardedFor := func(ip string) http.Header {
+		return http.Header{"X-Forwarded-For": []string{ip}}
+	}
+
+	// Clients behind the proxy are counted by their forwarded address, not the proxy's
+	w := postReview(t, router, productID, "10.0.0.2:4000", forwardedFor("203.0.113.7"))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = postReview(t, router, productID, "10.0.0.2:4000", forwardedFor("198.51.100.9"))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = postReview(t, router, productID, "10.0.0.3:4000", forwardedFor("203.0.113.7"))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	// An untrusted peer can't dodge the limit by forging the header
+	w = postReview(t, router, productID, "192.0.2.50:4000", forwardedFor("192.0.2.51"))
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	w = postReview(t, router, productID, "192.0.2.50:4000", forwardedFor("192.0.2.52"))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	assert.Equal(t, int64(2), cache.attempts["203.0.113.7|"+productID.String()])
+	assert.Equal(t, int64(2), cache.attempts["192.0.2.50|"+productID.String()])
+}
+
+func TestRouter_Setup_FlagsOncePerClientIP(t *testing.T) {
+	cfg := &config.Config{Review: config.ReviewConfig{FlagThreshold: 3}}
+	router := newTestRouter(t, cfg, &routerReviewRepository{}, &routerReviewCache{})
+	reviewID := uuid.New()
+
+	flag := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/reviews/"+reviewID.String()+"/flag", strings.NewReader(`{"reason":"Spam"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusNoContent, flag("203.0.113.7:4000").Code)
+	w := flag("203.0.113.7:4001")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Contains(t, w.Body.String(), "already flagged")
+	assert.Equal(t, http.StatusNoContent, flag("198.51.100.9:4000").Code)
+}
+
+func TestRouter_SetupAdmin_ServesMetrics(t *testing.T) {
+	reg := metrics.NewRegistry()
+	reg.GaugeFunc("test_gauge", "A gauge.", func() float64 { return 7 })
+	cfg := &config.Config{Server: config.ServerConfig{AdminPort: "8081"}}
+	router := NewRouter(nil, nil, nil, nil, nil, reg, cfg, logger.New("test"))
+
+	w := httptest.NewRecorder()
+	router.SetupAdmin().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, metrics.ContentType, w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Body.String(), "test_gauge 7\n")
+
+	// With an admin listener the public port doesn't expose it
+	w = httptest.NewRecorder()
+	router.Setup().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRouter_SetupAdmin_AttributesChangesToTheAdmin(t *testing.T) {
+	cfg := &config.Config{
+		Server: config.ServerConfig{AdminPort: "8081"},
+		Admin:  config.AdminConfig{APIKey: "secret"},
+	}
+	audits := &fakes.AuditRepository{}
+	router := newReviewRouter(t, cfg, &routerReviewRepository{}, &routerReviewCache{}, audits).SetupAdmin()
+
+	body := `["` + uuid.New().String() + `","` + uuid.New().String() + `"]`
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/reviews/bulk-delete", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.AdminKeyHeader, "secret")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, audits.Entries, 2)
+	for _, entry := range audits.Entries {
+		assert.Equal(t, audit.ActorAdmin, entry.Actor)
+	}
+}
+
+func TestRouter_AdminKeyRoutesFollowTheAdminListener(t *testing.T) {
+	adminOnly := []struct{ method, path string }{
+		{http.MethodPost, "/api/v1/products/" + uuid.New().String() + "/reviews/import"},
+		{http.MethodGet, "/api/v1/reviews/changes"},
+		{http.MethodGet, "/api/v1/admin/reviews/flagged"},
+	}
+	serve := func(router http.Handler, method, path string) int {
+		req := httptest.NewRequest(method, path, strings.NewReader(`[]`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Without ADMIN_PORT the public listener guards them with the key
+	cfg := &config.Config{Admin: config.AdminConfig{APIKey: "secret"}}
+	router := newReviewRouter(t, cfg, &routerReviewRepository{}, &routerReviewCache{}, &fakes.AuditRepository{})
+	for _, route := range adminOnly {
+		assert.Equal(t, http.StatusUnauthorized, serve(router.Setup(), route.method, route.path), route.path)
+	}
+
+	cfg.Server.AdminPort = "8081"
+	for _, route := range adminOnly {
+		// 405 where the path matches a public route for another method
+		assert.Contains(t, []int{http.StatusNotFound, http.StatusMethodNotAllowed}, serve(router.Setup(), route.method, route.path), route.path)
+		assert.Equal(t, http.StatusUnauthorized, serve(router.SetupAdmin(), route.method, route.path), route.path)
+	}
+}
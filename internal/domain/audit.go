@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit actions recorded for mutations
+const (
+	AuditActionCreate    = "create"
+	AuditActionUpdate    = "update"
+	AuditActionDelete    = "delete"
+	AuditActionAnonymize = "anonymize"
+)
+
+// Audited entity types
+const (
+	AuditEntityProduct = "product"
+	AuditEntityReview  = "review"
+)
+
+// AuditEntry is one row of the audit trail: who did what to which entity,
+// with the entity as JSON before and after the change (nil for create and delete respectively)
+type AuditEntry struct {
+	ID         uuid.UUID        `json:"id" db:"id"`
+	Actor      string           `json:"actor" db:"actor"`
+	Action     string           `json:"action" db:"action"`
+	EntityType string           `json:"entity_type" db:"entity_type"`
+	EntityID   uuid.UUID        `json:"entity_id" db:"entity_id"`
+	Before     *json.RawMessage `json:"before" db:"before_snapshot"`
+	After      *json.RawMessage `json:"after" db:"after_snapshot"`
+	CreatedAt  time.Time        `json:"created_at" db:"created_at"`
+}
+
+// AuditRepository defines the interface for audit trail data access
+type AuditRepository interface {
+	// Record appends an entry to the audit trail
+	Record(ctx context.Context, entry *AuditEntry) error
+
+	// ListByEntityID returns an entity's audit entries, newest first
+	ListByEntityID(ctx context.Context, entityID uuid.UUID, limit, offset int) ([]*AuditEntry, error)
+
+	// CountByEntityID returns the number of audit entries for an entity
+	CountByEntityID(ctx context.Context, entityID uuid.UUID) (int, error)
+
+	// Redact overwrites the given top-level fields in every before/after snapshot of an
+	// entity, so erasing personal data also erases it from the entity's history
+	Redact(ctx context.Context, entityID uuid.UUID, fields map[string]any) error
+}
+
+// Transactor runs a function in a single database transaction.
+// Repository calls made with the context passed to fn take part in the transaction.
+type Transactor interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -15,6 +15,12 @@ var (
 	// ErrConflict is returned when there's a conflict (e.g., optimistic locking)
 	ErrConflict = errors.New("conflict occurred")
 
+	// ErrReviewsDisabled is returned when a review is submitted for a product whose reviews are turned off
+	ErrReviewsDisabled = errors.New("reviews are disabled for this product")
+
+	// ErrRateLimited is returned when a client has made too many attempts of an action
+	ErrRateLimited = errors.New("rate limited")
+
 	// ErrInternal is returned when an internal error occurs
 	ErrInternal = errors.New("internal error")
 )
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// OutboxEvent is an event written in the same transaction as the change it describes,
+// waiting for the relay to publish it
+type OutboxEvent struct {
+	ID      int64  `db:"id"`
+	Subject string `db:"subject"`
+	// Payload is the event as published, JSON
+	Payload   []byte     `db:"payload"`
+	CreatedAt time.Time  `db:"created_at"`
+	SentAt    *time.Time `db:"sent_at"`
+}
+
+// OutboxRepository defines the interface for the transactional event outbox
+type OutboxRepository interface {
+	// Add stores an event, as part of the caller's transaction when ctx carries one
+	Add(ctx context.Context, subject string, payload []byte) error
+
+	// Claim leases up to limit unsent events, oldest first, to the caller for lease.
+	// Events under another caller's unexpired lease are skipped rather than waited for.
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]*OutboxEvent, error)
+
+	// MarkSent stamps events as published
+	MarkSent(ctx context.Context, ids []int64) error
+
+	// Release ends the lease on claimed events that weren't published, so the next claim
+	// picks them up without waiting for the lease to run out
+	Release(ctx context.Context, ids []int64) error
+
+	// DeleteSentBefore removes events published before the cutoff and returns how many
+	DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error)
+
+	// Lag reports how far publishing is behind
+	Lag(ctx context.Context) (*OutboxLag, error)
+}
+
+// OutboxLag is the backlog of unsent outbox events
+type OutboxLag struct {
+	Pending int64
+	// OldestAge is how long the oldest unsent event has waited; zero when none are pending
+	OldestAge time.Duration
+}
@@ -0,0 +1,17 @@
+package domain
+
+import "fmt"
+
+// MaxPageSize is the hard ceiling on any list page. Per-endpoint page size limits are
+// configurable, but must stay within it so a misconfiguration can't load unbounded pages.
+const MaxPageSize = 1000
+
+// CheckPageSize rejects a page size outside 1 to MaxPageSize. Handlers apply each
+// endpoint's configured default and maximum; services only refuse unbounded pages rather
+// than substituting a size of their own.
+func CheckPageSize(limit int) error {
+	if limit <= 0 || limit > MaxPageSize {
+		return fmt.Errorf("%w: page size must be between 1 and %d, got %d", ErrInvalidInput, MaxPageSize, limit)
+	}
+	return nil
+}
@@ -7,33 +7,60 @@ import (
 	"github.com/google/uuid"
 )
 
-// Product represents a product in the system
+// Product represents a product in the system.
+// AverageRating and ReviewCount are derived by the rating worker; they are never
+// written by user edits and don't change Version, which guards user-editable fields only.
 type Product struct {
-	ID            uuid.UUID  `json:"id" db:"id"`
-	Name          string     `json:"name" db:"name" validate:"required,min=1,max=255"`
-	Description   *string    `json:"description,omitempty" db:"description" validate:"omitempty,max=2000"`
-	Price         float64    `json:"price" db:"price" validate:"required,gte=0"`
-	AverageRating float64    `json:"average_rating" db:"average_rating"`
-	Version       int        `json:"version" db:"version"`
-	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
-	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
-	DeletedAt     *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+	ID            uuid.UUID `json:"id" db:"id"`
+	Name          string    `json:"name" db:"name" validate:"required,min=1,max=255"`
+	Description   *string   `json:"description,omitempty" db:"description" validate:"omitempty,max=2000"`
+	Price         float64   `json:"price" db:"price" validate:"required,gte=0"`
+	AverageRating float64   `json:"average_rating" db:"average_rating"`
+	ReviewCount   int       `json:"review_count" db:"review_count"`
+	// ReviewsEnabled false rejects new reviews; existing ones are still listed and rated
+	ReviewsEnabled bool       `json:"reviews_enabled" db:"reviews_enabled"`
+	Version        int        `json:"version" db:"version"`
+	CreatedAt      time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at" db:"updated_at"`
+	DeletedAt      *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// ProductComparison is one product on a comparison page: the product with its average
+// rating and review count, plus how its ratings are spread across 1 to MaxRating stars
+type ProductComparison struct {
+	Product            *Product    `json:"product"`
+	RatingDistribution map[int]int `json:"rating_distribution"`
 }
 
 // ProductRepository defines the interface for product data access
 type ProductRepository interface {
-	// Create creates a new product
+	// Create creates a new product. A non-nil ID is kept, so callers can pick it up front
+	// (an import, say); otherwise one is generated. A taken ID fails with ErrAlreadyExists.
 	Create(ctx context.Context, product *Product) error
 
 	// GetByID retrieves a product by ID (excludes soft-deleted)
 	GetByID(ctx context.Context, id uuid.UUID) (*Product, error)
 
+	// GetByIDs retrieves several products in one query, keyed by ID.
+	// Missing and soft-deleted products are absent from the map.
+	GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*Product, error)
+
 	// List retrieves a paginated list of products (excludes soft-deleted)
 	List(ctx context.Context, limit, offset int) ([]*Product, error)
 
+	// ListWithTotal is List plus the total number of products, in a single query
+	ListWithTotal(ctx context.Context, limit, offset int) ([]*Product, int, error)
+
+	// ListUnreviewed is ListWithTotal restricted to products with no live reviews, judged by
+	// the denormalized review_count, newest first
+	ListUnreviewed(ctx context.Context, limit, offset int) ([]*Product, int, error)
+
 	// Update updates an existing product
 	Update(ctx context.Context, product *Product) error
 
+	// NameTaken reports whether a live product other than excludeID is named name
+	NameTaken(ctx context.Context, name string, excludeID uuid.UUID) (bool, error)
+
 	// Delete soft-deletes a product
 	Delete(ctx context.Context, id uuid.UUID) error
 
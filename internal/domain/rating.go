@@ -0,0 +1,138 @@
+package domain
+
+import (
+	"math"
+	"slices"
+	"sync/atomic"
+)
+
+const (
+	// DefaultMaxRating is the top of the rating scale unless MAX_RATING changes it
+	DefaultMaxRating = 5
+	// MaxRatingCeiling bounds MAX_RATING; the database constraints allow ratings up to it
+	MaxRatingCeiling = 10
+)
+
+// maxRating is process-wide because struct tag validation has no way to receive it;
+// it is set once at startup, before any request is served
+var maxRating atomic.Int64
+
+func init() {
+	maxRating.Store(DefaultMaxRating)
+}
+
+// halfStarRatings is process-wide for the same reason as maxRating
+var halfStarRatings atomic.Bool
+
+// allowedRatings is process-wide for the same reason as maxRating; nil means any rating on
+// the 1 to MaxRating scale
+var allowedRatings atomic.Pointer[[]float64]
+
+// MaxRating returns the top of the rating scale; ratings run from 1 to MaxRating
+func MaxRating() int {
+	return int(maxRating.Load())
+}
+
+// SetMaxRating changes the rating scale. Call it at startup, after loading config.
+func SetMaxRating(n int) {
+	maxRating.Store(int64(n))
+}
+
+// HalfStarRatings reports whether ratings may be given in steps of 0.5 rather than whole stars
+func HalfStarRatings() bool {
+	return halfStarRatings.Load()
+}
+
+// SetHalfStarRatings switches between half-star and whole-star ratings. Call it at startup,
+// after loading config. Reviews stored under either mode stay valid averages either way.
+func SetHalfStarRatings(enabled bool) {
+	halfStarRatings.Store(enabled)
+}
+
+// AllowedRatings returns the discrete ratings accepted instead of the 1 to MaxRating range,
+// in ascending order, or nil when any rating on the scale is accepted
+func AllowedRatings() []float64 {
+	if allowed := allowedRatings.Load(); allowed != nil {
+		return *allowed
+	}
+	return nil
+}
+
+// SetAllowedRatings restricts ratings to the given values (e.g. 1, 3, 5 for thumbs
+// down/neutral/up); empty accepts the whole scale again. Call it at startup, after loading config.
+func SetAllowedRatings(ratings []float64) {
+	if len(ratings) == 0 {
+		allowedRatings.Store(nil)
+		return
+	}
+	sorted := slices.Sorted(slices.Values(ratings))
+	sorted = slices.Compact(sorted)
+	allowedRatings.Store(&sorted)
+}
+
+// IsValidRating reports whether rating is on the configured scale: one of AllowedRatings
+// when set, otherwise between 1 and MaxRating, in whole stars, or in steps of 0.5 with
+// HalfStarRatings
+func IsValidRating(rating float64) bool {
+	if allowed := AllowedRatings(); allowed != nil {
+		return slices.Contains(allowed, rating)
+	}
+	if rating < 1 || rating > float64(MaxRating()) {
+		return false
+	}
+	if HalfStarRatings() {
+		return rating*2 == math.Trunc(rating*2)
+	}
+	return rating == math.Trunc(rating)
+}
+
+// Rounding modes for RATING_ROUNDING_MODE, applied when an average rating is stored with one decimal
+const (
+	// RatingRoundingHalfUp rounds halves up (4.25 -> 4.3), as Postgres ROUND does for positive numerics
+	RatingRoundingHalfUp = "half_up"
+	// RatingRoundingHalfEven rounds halves to the even digit (4.25 -> 4.2, 4.35 -> 4.4),
+	// so ties don't bias averages upward
+	RatingRoundingHalfEven = "half_even"
+	// RatingRoundingFloor truncates (4.29 -> 4.2), so a product never shows more than it earned
+	RatingRoundingFloor = "floor"
+)
+
+// IsValidRatingRounding reports whether mode is one of the RATING_ROUNDING_MODE modes
+func IsValidRatingRounding(mode string) bool {
+	switch mode {
+	case RatingRoundingHalfUp, RatingRoundingHalfEven, RatingRoundingFloor:
+		return true
+	default:
+		return false
+	}
+}
+
+// RoundRating returns sum/count rounded to one decimal place with mode; 0 when count is 0.
+// sum is a total of whole or half-star ratings. It is worked on as an integer count of half
+// points rather than a float average, so a tie like 4.35 is seen as exactly half instead of
+// 4.3499999... and every mode rounds it as documented.
+func RoundRating(sum float64, count int, mode string) float64 {
+	if count <= 0 {
+		return 0
+	}
+
+	// Tenths: quotient and remainder of sum*10 / count, where sum*10 = halfPoints*5.
+	// Ratings are positive, so no sign handling
+	halfPoints := int64(math.Round(sum * 2))
+	n, d := halfPoints*5, int64(count)
+	tenths, rem := n/d, n%d
+
+	switch mode {
+	case RatingRoundingFloor:
+	case RatingRoundingHalfEven:
+		if 2*rem > d || (2*rem == d && tenths%2 == 1) {
+			tenths++
+		}
+	default:
+		if 2*rem >= d {
+			tenths++
+		}
+	}
+
+	return float64(tenths) / 10
+}
@@ -0,0 +1,84 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundRating(t *testing.T) {
+	tests := []struct {
+		name  string
+		sum   float64
+		count int
+		mode  string
+		want  float64
+	}{
+		{name: "no reviews", sum: 0, count: 0, mode: RatingRoundingHalfUp, want: 0},
+		{name: "exact", sum: 9, count: 2, mode: RatingRoundingHalfUp, want: 4.5},
+		{name: "half up on tie", sum: 17, count: 4, mode: RatingRoundingHalfUp, want: 4.3},
+		{name: "half up below tie", sum: 11, count: 3, mode: RatingRoundingHalfUp, want: 3.7},
+		{name: "half even rounds tie down to even", sum: 17, count: 4, mode: RatingRoundingHalfEven, want: 4.2},
+		{name: "half even rounds tie up to even", sum: 87, count: 20, mode: RatingRoundingHalfEven, want: 4.4},
+		{name: "half even above tie", sum: 11, count: 3, mode: RatingRoundingHalfEven, want: 3.7},
+		{name: "floor", sum: 11, count: 3, mode: RatingRoundingFloor, want: 3.6},
+		{name: "floor keeps exact", sum: 9, count: 2, mode: RatingRoundingFloor, want: 4.5},
+		{name: "top of scale", sum: 50, count: 10, mode: RatingRoundingHalfEven, want: 5},
+		{name: "unknown mode rounds half up", sum: 17, count: 4, mode: "", want: 4.3},
+		{name: "half stars", sum: 9.5, count: 2, mode: RatingRoundingHalfUp, want: 4.8},
+		{name: "half stars half even", sum: 9.5, count: 2, mode: RatingRoundingHalfEven, want: 4.8},
+		{name: "half stars floor", sum: 9.5, count: 2, mode: RatingRoundingFloor, want: 4.7},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, RoundRating(tc.sum, tc.count, tc.mode))
+		})
+	}
+}
+
+func TestIsValidRating(t *testing.T) {
+	t.Cleanup(func() { SetHalfStarRatings(false) })
+
+	tests := []struct {
+		rating    float64
+		whole     bool
+		halfStars bool
+	}{
+		{rating: 1, whole: true, halfStars: true},
+		{rating: 5, whole: true, halfStars: true},
+		{rating: 4.5, whole: false, halfStars: true},
+		{rating: 1.5, whole: false, halfStars: true},
+		{rating: 4.2, whole: false, halfStars: false},
+		{rating: 0.5, whole: false, halfStars: false},
+		{rating: 5.5, whole: false, halfStars: false},
+		{rating: 0, whole: false, halfStars: false},
+	}
+
+	for _, tc := range tests {
+		SetHalfStarRatings(false)
+		assert.Equal(t, tc.whole, IsValidRating(tc.rating), "whole stars: %v", tc.rating)
+		SetHalfStarRatings(true)
+		assert.Equal(t, tc.halfStars, IsValidRating(tc.rating), "half stars: %v", tc.rating)
+	}
+}
+
+func TestIsValidRating_AllowedRatings(t *testing.T) {
+	t.Cleanup(func() {
+		SetAllowedRatings(nil)
+		SetHalfStarRatings(false)
+	})
+
+	SetAllowedRatings([]float64{5, 1, 3, 3})
+	assert.Equal(t, []float64{1, 3, 5}, AllowedRatings(), "sorted without duplicates")
+
+	// The list replaces the range check, half-star mode included
+	SetHalfStarRatings(true)
+	for rating, want := range map[float64]bool{1: true, 3: true, 5: true, 2: false, 4: false, 4.5: false, 0: false} {
+		assert.Equal(t, want, IsValidRating(rating), "rating %v", rating)
+	}
+
+	SetAllowedRatings(nil)
+	assert.Nil(t, AllowedRatings())
+	assert.True(t, IsValidRating(4.5), "an empty list accepts the whole scale again")
+}
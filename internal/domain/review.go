@@ -2,34 +2,205 @@ package domain
 
 import (
 	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"strings"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 )
 
+// Review sources identify where a review was submitted from
+const (
+	ReviewSourceWeb    = "web"
+	ReviewSourceMobile = "mobile"
+	ReviewSourceImport = "import"
+	ReviewSourceAPI    = "api"
+)
+
+// AnonymousName replaces the reviewer's first and last name when a review is anonymized
+const AnonymousName = "Anonymous"
+
+// gravatarURL is the avatar URL prefix. d=identicon gives reviewers without a Gravatar
+// account a generated pattern instead of the Gravatar logo.
+const gravatarURL = "https://www.gravatar.com/avatar/%s?d=identicon"
+
+// IsValidReviewSource reports whether source is one of the known review sources
+func IsValidReviewSource(source string) bool {
+	switch source {
+	case ReviewSourceWeb, ReviewSourceMobile, ReviewSourceImport, ReviewSourceAPI:
+		return true
+	default:
+		return false
+	}
+}
+
+// Review list orderings, selectable with DEFAULT_REVIEW_SORT. Ties are broken by newest first.
+const (
+	ReviewSortNewest        = "newest"
+	ReviewSortOldest        = "oldest"
+	ReviewSortHighestRating = "highest_rating"
+	ReviewSortLowestRating  = "lowest_rating"
+)
+
+// DefaultReviewSort is the reviews list order when none is configured
+const DefaultReviewSort = ReviewSortNewest
+
+// IsValidReviewSort reports whether sort is one of the review list orderings
+func IsValidReviewSort(sort string) bool {
+	switch sort {
+	case ReviewSortNewest, ReviewSortOldest, ReviewSortHighestRating, ReviewSortLowestRating:
+		return true
+	default:
+		return false
+	}
+}
+
 // Review represents a product review in the system
 type Review struct {
-	ID         uuid.UUID  `json:"id" db:"id"`
-	ProductID  uuid.UUID  `json:"product_id" db:"product_id" validate:"required"`
-	FirstName  string     `json:"first_name" db:"first_name" validate:"required,min=1,max=100"`
-	LastName   string     `json:"last_name" db:"last_name" validate:"required,min=1,max=100"`
+	ID        uuid.UUID `json:"id" db:"id"`
+	ProductID uuid.UUID `json:"product_id" db:"product_id" validate:"required"`
+	FirstName string    `json:"first_name" db:"first_name" validate:"required,min=1,max=100"`
+	LastName  string    `json:"last_name" db:"last_name" validate:"required,min=1,max=100"`
+	// Email is input only: the review service validates it and replaces it with EmailHash
+	// before the review is stored, so the address never reaches the database, events or
+	// the audit log
+	Email string `json:"email,omitempty" db:"-" validate:"omitempty,email,max=254"`
+	// EmailHash is the Gravatar hash of the reviewer's email; nil when they gave none
+	EmailHash *string `json:"email_hash,omitempty" db:"email_hash"`
+	// Title is an optional headline; nil when the reviewer gave none
+	Title *string `json:"title,omitempty" db:"title" validate:"omitempty,max=200"`
+	// Language is the ISO 639-1 code of the review text, given by the client or detected;
+	// nil when neither happened
+	Language   *string    `json:"language,omitempty" db:"language" validate:"omitempty,len=2,lowercase,alpha"`
 	ReviewText string     `json:"review_text" db:"review_text" validate:"required,min=1,max=5000"`
-	Rating     int        `json:"rating" db:"rating" validate:"required,min=1,max=5"`
+	Rating     float64    `json:"rating" db:"rating" validate:"required,rating"`
+	Source     string     `json:"source" db:"source" validate:"omitempty,oneof=web mobile import api"`
 	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt  time.Time  `json:"updated_at" db:"updated_at"`
 	DeletedAt  *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
+// IsValidLanguage reports whether code looks like an ISO 639-1 language code: two lowercase
+// letters. The code list isn't checked, so a client can tag a language detection doesn't know.
+func IsValidLanguage(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// HashEmail returns the Gravatar hash of email: the hex MD5 of the trimmed, lowercased
+// address, as Gravatar specifies. MD5 only keeps the address out of storage; a known
+// address can still be matched against it.
+func HashEmail(email string) string {
+	sum := md5.Sum([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// AvatarURL is the reviewer's Gravatar image, or "" when they gave no email
+func (r *Review) AvatarURL() string {
+	if r.EmailHash == nil {
+		return ""
+	}
+	return fmt.Sprintf(gravatarURL, *r.EmailHash)
+}
+
+// DisplayName is how the reviewer is shown publicly: first name and last initial ("John D."),
+// so full last names never reach other shoppers. Falls back to the first name alone when
+// there's no last name, and to AnonymousName when there's no first name or the review
+// was anonymized.
+func (r *Review) DisplayName() string {
+	first := strings.TrimSpace(r.FirstName)
+	last := strings.TrimSpace(r.LastName)
+
+	if first == "" || (first == AnonymousName && last == AnonymousName) {
+		return AnonymousName
+	}
+	if last == "" {
+		return first
+	}
+	return first + " " + initial(last) + "."
+}
+
+// initial returns the upper-cased first character of name, keeping any combining marks
+// that follow it so a decomposed "É" (E + U+0301) isn't cut to a bare "E"
+func initial(name string) string {
+	first, size := utf8.DecodeRuneInString(name)
+	end := size
+	for end < len(name) {
+		next, n := utf8.DecodeRuneInString(name[end:])
+		if !unicode.Is(unicode.Mn, next) {
+			break
+		}
+		end += n
+	}
+	return string(unicode.ToUpper(first)) + name[size:end]
+}
+
+// ReviewOverview is the review portion of a product detail page:
+// the first page of reviews plus how ratings are spread across 1 to MaxRating stars
+type ReviewOverview struct {
+	Reviews            []*Review   `json:"reviews"`
+	Total              int         `json:"total"`
+	RatingDistribution map[int]int `json:"rating_distribution"`
+}
+
+// ReviewSummary is the compact rating summary shown on product cards in listing grids.
+// AverageRating and ReviewCount come from the product, so they match the product listing.
+type ReviewSummary struct {
+	AverageRating      float64     `json:"average_rating"`
+	ReviewCount        int         `json:"review_count"`
+	RatingDistribution map[int]int `json:"rating_distribution"`
+	// LatestReviewExcerpt is the start of the newest review's text; nil when there are no reviews
+	LatestReviewExcerpt *string `json:"latest_review_excerpt"`
+}
+
+// RecentReview is a review in the cross-product "recently reviewed" feed,
+// carrying the product name so the feed renders without a lookup per review
+type RecentReview struct {
+	Review
+	ProductName string `json:"product_name" db:"product_name"`
+}
+
+// ReviewFlag is a shopper's report that a review breaks the rules
+type ReviewFlag struct {
+	ReviewID uuid.UUID `json:"review_id"`
+	Reason   string    `json:"reason" validate:"required,max=500"`
+	// ClientIP lets each address flag a review once; review.Service rejects flags without one
+	ClientIP string `json:"-"`
+}
+
+// FlaggedReview is a review with the number of times it has been flagged,
+// as listed in the moderation queue
+type FlaggedReview struct {
+	Review
+	FlagCount int `json:"flag_count" db:"flag_count"`
+}
+
 // ReviewRepository defines the interface for review data access
 type ReviewRepository interface {
-	// Create creates a new review
+	// Create creates a new review. A non-nil ID is kept, so callers can pick it up front
+	// (an import, say); otherwise one is generated. A taken ID fails with ErrAlreadyExists.
 	Create(ctx context.Context, review *Review) error
 
 	// GetByID retrieves a review by ID (excludes soft-deleted)
 	GetByID(ctx context.Context, id uuid.UUID) (*Review, error)
 
-	// GetByProductID retrieves reviews for a product with pagination (excludes soft-deleted)
-	GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*Review, error)
+	// GetByProductID retrieves reviews for a product with pagination (excludes soft-deleted),
+	// ordered by sort (one of the ReviewSort values)
+	GetByProductID(ctx context.Context, productID uuid.UUID, sort string, limit, offset int) ([]*Review, error)
+
+	// GetByProductIDAndLanguage is GetByProductID for reviews in one language only
+	GetByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language, sort string, limit, offset int) ([]*Review, error)
 
 	// Update updates an existing review
 	Update(ctx context.Context, review *Review) error
@@ -37,9 +208,47 @@ type ReviewRepository interface {
 	// Delete soft-deletes a review
 	Delete(ctx context.Context, id uuid.UUID) error
 
+	// Anonymize strips reviewer PII from a review but keeps its rating and text,
+	// so it still counts toward the product rating (excludes soft-deleted)
+	Anonymize(ctx context.Context, id uuid.UUID) (*Review, error)
+
+	// Flag records a flag against a review and returns the review with its new flag count
+	// (excludes soft-deleted). Returns ErrAlreadyExists when the client IP already flagged it.
+	Flag(ctx context.Context, flag *ReviewFlag) (*FlaggedReview, error)
+
+	// ListFlagged returns reviews flagged more than threshold times, most flagged first
+	// (excludes soft-deleted)
+	ListFlagged(ctx context.Context, threshold, limit, offset int) ([]*FlaggedReview, error)
+
+	// CountFlagged returns the number of reviews flagged more than threshold times (excludes soft-deleted)
+	CountFlagged(ctx context.Context, threshold int) (int, error)
+
+	// DeleteBatch soft-deletes the given reviews and returns them as they were before the
+	// delete. IDs that don't exist or are already deleted are skipped.
+	DeleteBatch(ctx context.Context, ids []uuid.UUID) ([]*Review, error)
+
 	// DeleteByProductID soft-deletes all reviews for a product (cascade delete)
 	DeleteByProductID(ctx context.Context, productID uuid.UUID) error
 
 	// CountByProductID returns the total number of reviews for a product (excludes soft-deleted)
 	CountByProductID(ctx context.Context, productID uuid.UUID) (int, error)
+
+	// CountByProductIDAndLanguage returns the number of a product's reviews in one language (excludes soft-deleted)
+	CountByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language string) (int, error)
+
+	// GetRatingDistribution returns review counts keyed by whole-star rating (excludes soft-deleted);
+	// half-star ratings count toward the star below. Ratings without reviews are absent from the map
+	GetRatingDistribution(ctx context.Context, productID uuid.UUID) (map[int]int, error)
+
+	// GetRatingDistributions is GetRatingDistribution for several products in one query.
+	// Products without reviews are absent from the outer map.
+	GetRatingDistributions(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]map[int]int, error)
+
+	// ChangesSince returns reviews (including soft-deleted) ordered by (updated_at, id),
+	// starting after the given position
+	ChangesSince(ctx context.Context, since time.Time, afterID uuid.UUID, limit int) ([]*Review, error)
+
+	// Recent returns the newest reviews across all products, newest first
+	// (excludes soft-deleted reviews and reviews of soft-deleted products)
+	Recent(ctx context.Context, limit int) ([]*RecentReview, error)
 }
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReview_DisplayName(t *testing.T) {
+	tests := []struct {
+		name      string
+		firstName string
+		lastName  string
+		want      string
+	}{
+		{name: "first name and last initial", firstName: "John", lastName: "Doe", want: "John D."},
+		{name: "lowercase last name", firstName: "john", lastName: "doe", want: "john D."},
+		{name: "multi-word last name", firstName: "Ana", lastName: "de la Cruz", want: "Ana D."},
+		{name: "surrounding whitespace", firstName: "  John ", lastName: " Doe ", want: "John D."},
+		{name: "empty last name", firstName: "Cher", lastName: "", want: "Cher"},
+		{name: "blank last name", firstName: "Cher", lastName: "   ", want: "Cher"},
+		{name: "empty first name", firstName: "", lastName: "Doe", want: AnonymousName},
+		{name: "anonymized", firstName: AnonymousName, lastName: AnonymousName, want: AnonymousName},
+		{name: "unicode last name", firstName: "Jürgen", lastName: "Özdemir", want: "Jürgen Ö."},
+		{name: "lowercase unicode initial", firstName: "Léa", lastName: "élise", want: "Léa É."},
+		{name: "combining mark kept", firstName: "Zoe", lastName: "e\u0301clair", want: "Zoe E\u0301."},
+		{name: "non-latin script", firstName: "Анна", lastName: "Иванова", want: "Анна И."},
+		{name: "no case", firstName: "太郎", lastName: "山田", want: "太郎 山."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			review := &Review{FirstName: tt.firstName, LastName: tt.lastName}
+			assert.Equal(t, tt.want, review.DisplayName())
+		})
+	}
+}
+
+func TestHashEmail(t *testing.T) {
+	// The example from Gravatar's documentation; case and surrounding space don't matter
+	assert.Equal(t, "0bc83cb571cd1c50ba6f3e8a78ef1346", HashEmail(" MyEmailAddress@example.com "))
+}
+
+func TestReview_AvatarURL(t *testing.T) {
+	hash := HashEmail("jane@example.com")
+
+	assert.Equal(t, "https://www.gravatar.com/avatar/"+hash+"?d=identicon", (&Review{EmailHash: &hash}).AvatarURL())
+	assert.Empty(t, (&Review{}).AvatarURL(), "no email, no avatar")
+}
+
+func TestIsValidLanguage(t *testing.T) {
+	for _, code := range []string{"en", "de", "xx"} {
+		assert.True(t, IsValidLanguage(code), code)
+	}
+	for _, code := range []string{"", "e", "EN", "eng", "en-US", "é1"} {
+		assert.False(t, IsValidLanguage(code), code)
+	}
+}
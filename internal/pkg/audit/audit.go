@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// Actors recorded when the request doesn't identify anyone more specific
+const (
+	// ActorSystem covers changes made outside an HTTP request (workers, scripts, tests)
+	ActorSystem = "system"
+	// ActorAnonymous covers public API requests, which carry no credentials
+	ActorAnonymous = "anonymous"
+	// ActorAdmin covers requests presenting the admin API key
+	ActorAdmin = "admin"
+)
+
+type actorKey struct{}
+
+// WithActor returns a context recording who is making the change
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set by WithActor, falling back to ActorSystem
+func ActorFrom(ctx context.Context) string {
+	if actor, ok := ctx.Value(actorKey{}).(string); ok && actor != "" {
+		return actor
+	}
+	return ActorSystem
+}
+
+// Record writes an audit entry for a mutation of entityID by the actor on ctx.
+// before and after are stored as JSON snapshots; pass nil for the side that doesn't exist.
+// Call it with the context of the mutation's transaction so both commit or neither does.
+func Record(
+	ctx context.Context,
+	repo domain.AuditRepository,
+	action, entityType string,
+	entityID uuid.UUID,
+	before, after any,
+) error {
+	beforeSnapshot, err := snapshot(before)
+	if err != nil {
+		return fmt.Errorf("audit %s %s before snapshot: %w", action, entityType, err)
+	}
+	afterSnapshot, err := snapshot(after)
+	if err != nil {
+		return fmt.Errorf("audit %s %s after snapshot: %w", action, entityType, err)
+	}
+
+	return repo.Record(ctx, &domain.AuditEntry{
+		Actor:      ActorFrom(ctx),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeSnapshot,
+		After:      afterSnapshot,
+	})
+}
+
+// snapshot marshals v, returning nil for a nil value so the column stays NULL
+func snapshot(v any) (*json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := json.RawMessage(data)
+	return &raw, nil
+}
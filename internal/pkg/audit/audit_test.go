@@ -0,0 +1,42 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// recordingRepository keeps the last recorded entry
+type recordingRepository struct {
+	domain.AuditRepository
+	entry *domain.AuditEntry
+}
+
+func (r *recordingRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	r.entry = entry
+	return nil
+}
+
+func TestActorFrom(t *testing.T) {
+	assert.Equal(t, ActorSystem, ActorFrom(context.Background()))
+	assert.Equal(t, ActorAdmin, ActorFrom(WithActor(context.Background(), ActorAdmin)))
+}
+
+func TestRecord_Snapshots(t *testing.T) {
+	repo := &recordingRepository{}
+	entityID := uuid.New()
+	ctx := WithActor(context.Background(), ActorAnonymous)
+
+	err := Record(ctx, repo, domain.AuditActionCreate, domain.AuditEntityReview, entityID, nil, &domain.Review{Rating: 4})
+
+	require.NoError(t, err)
+	assert.Equal(t, ActorAnonymous, repo.entry.Actor)
+	assert.Equal(t, entityID, repo.entry.EntityID)
+	assert.Nil(t, repo.entry.Before, "a missing side is stored as NULL, not as JSON null")
+	assert.Contains(t, string(*repo.entry.After), `"rating":4`)
+}
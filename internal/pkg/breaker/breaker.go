@@ -0,0 +1,123 @@
+// Package breaker stops calls to a failing dependency for a while, so an outage costs
+// callers an immediate error rather than a timeout each.
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// ErrOpen is returned instead of calling the dependency while the breaker is open
+var ErrOpen = errors.New("circuit breaker is open")
+
+type state int
+
+const (
+	closed state = iota
+	open
+	// halfOpen lets a single probe through to find out whether the dependency is back
+	halfOpen
+)
+
+// Breaker opens after threshold consecutive failures. After cooldown one call probes the
+// dependency: success closes the breaker, failure keeps it open for another cooldown.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	logger    *logger.Logger
+	// now is replaced in tests
+	now func() time.Time
+
+	mu       sync.Mutex
+	state    state
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+// New returns a breaker for the dependency called name, used in its log lines.
+// A threshold of 0 disables it: every call is allowed.
+func New(name string, threshold int, cooldown time.Duration, log *logger.Logger) *Breaker {
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		logger:    log,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may go to the dependency. Every allowed call must be
+// followed by Record, or a half-open breaker stays waiting for its probe.
+func (b *Breaker) Allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case open:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = halfOpen
+		b.probing = true
+		return true
+	case halfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+// Record counts the outcome of an allowed call; nil is a success. Callers map errors that
+// say nothing about the dependency's health (a cache miss, say) to nil first.
+func (b *Breaker) Record(err error) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// A caller that gave up says nothing about the dependency; let the next call probe instead
+	if errors.Is(err, context.Canceled) {
+		b.probing = false
+		return
+	}
+
+	if err == nil {
+		if b.state != closed {
+			b.logger.Infof("%s recovered, closing circuit breaker", b.name)
+		}
+		b.state = closed
+		b.failures = 0
+		b.probing = false
+		return
+	}
+
+	b.failures++
+	if b.state == halfOpen || b.failures >= b.threshold {
+		if b.state == closed {
+			b.logger.WithFields(map[string]any{
+				"failures": b.failures,
+				"cooldown": b.cooldown.String(),
+				"error":    err.Error(),
+			}).Warnf("%s failing, opening circuit breaker", b.name)
+		}
+		b.state = open
+		b.openedAt = b.now()
+		b.probing = false
+	}
+}
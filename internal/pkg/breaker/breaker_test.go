@@ -0,0 +1,86 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+func newTestBreaker(threshold int, cooldown time.Duration) (*Breaker, *time.Time) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New("test", threshold, cooldown, logger.New("test"))
+	b.now = func() time.Time { return now }
+	return b, &now
+}
+
+func TestBreaker_OpensAfterConsecutiveFailures(t *testing.T) {
+	b, _ := newTestBreaker(3, time.Second)
+	timeout := errors.New("i/o timeout")
+
+	b.Record(timeout)
+	b.Record(timeout)
+	b.Record(nil)
+	b.Record(timeout)
+	b.Record(timeout)
+	assert.True(t, b.Allow(), "a success in between resets the count")
+
+	b.Record(timeout)
+	assert.False(t, b.Allow())
+}
+
+func TestBreaker_CancellationsAreNotFailures(t *testing.T) {
+	b, _ := newTestBreaker(1, time.Second)
+
+	b.Record(context.Canceled)
+
+	assert.True(t, b.Allow())
+}
+
+func TestBreaker_ProbesAfterCooldown(t *testing.T) {
+	b, now := newTestBreaker(1, 10*time.Second)
+	timeout := errors.New("i/o timeout")
+
+	b.Record(timeout)
+	require.False(t, b.Allow())
+
+	*now = now.Add(10 * time.Second)
+	assert.True(t, b.Allow(), "the first call after the cooldown probes the dependency")
+	assert.False(t, b.Allow(), "only one probe at a time")
+
+	b.Record(timeout)
+	assert.False(t, b.Allow(), "a failed probe reopens the breaker for another cooldown")
+
+	*now = now.Add(10 * time.Second)
+	require.True(t, b.Allow())
+	b.Record(nil)
+	assert.True(t, b.Allow())
+	assert.True(t, b.Allow(), "a successful probe closes the breaker")
+}
+
+func TestBreaker_CancelledProbeLetsTheNextCallProbe(t *testing.T) {
+	b, now := newTestBreaker(1, time.Second)
+
+	b.Record(errors.New("i/o timeout"))
+	*now = now.Add(time.Second)
+	require.True(t, b.Allow())
+
+	b.Record(context.Canceled)
+
+	assert.True(t, b.Allow())
+}
+
+func TestBreaker_ZeroThresholdDisables(t *testing.T) {
+	b, _ := newTestBreaker(0, time.Second)
+
+	for range 10 {
+		b.Record(errors.New("i/o timeout"))
+	}
+
+	assert.True(t, b.Allow())
+}
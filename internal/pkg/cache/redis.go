@@ -29,21 +29,21 @@ func NewRedisClient(cfg *config.Config) (*redis.Client, error) {
 	return client, nil
 }
 
-// WaitForRedis waits for Redis to become available with retries
-func WaitForRedis(cfg *config.Config, maxRetries int, retryDelay time.Duration) (*redis.Client, error) {
+// WaitForRedis waits for Redis to become available, retrying as retry describes
+func WaitForRedis(cfg *config.Config, retry config.ConnectRetryConfig) (*redis.Client, error) {
 	var client *redis.Client
 	var err error
 
-	for i := range maxRetries {
+	for i := range retry.MaxRetries {
 		client, err = NewRedisClient(cfg)
 		if err == nil {
 			return client, nil
 		}
 
-		if i < maxRetries-1 {
-			time.Sleep(retryDelay)
+		if i < retry.MaxRetries-1 {
+			time.Sleep(retry.Wait(i))
 		}
 	}
 
-	return nil, fmt.Errorf("failed to connect to Redis after %d retries: %w", maxRetries, err)
+	return nil, fmt.Errorf("failed to connect to Redis after %d retries: %w", retry.MaxRetries, err)
 }
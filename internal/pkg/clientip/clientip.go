@@ -0,0 +1,19 @@
+// Package clientip carries the caller's IP address from the HTTP layer to services
+// that apply per-client rules, without services depending on net/http
+package clientip
+
+import "context"
+
+type ipKey struct{}
+
+// WithIP returns a context recording the client IP the request came from
+func WithIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, ipKey{}, ip)
+}
+
+// FromContext returns the IP set by WithIP, or "" outside an HTTP request
+// (workers, scripts, tests), where per-client rules don't apply
+func FromContext(ctx context.Context) string {
+	ip, _ := ctx.Value(ipKey{}).(string)
+	return ip
+}
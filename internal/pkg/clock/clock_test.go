@@ -0,0 +1,38 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFake_AdvanceRunsDueTimersInOrder(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFake(start)
+
+	var fired []string
+	c.AfterFunc(2*time.Second, func() { fired = append(fired, "second") })
+	c.AfterFunc(time.Second, func() { fired = append(fired, "first") })
+	c.AfterFunc(5*time.Second, func() { fired = append(fired, "later") })
+
+	c.Advance(2 * time.Second)
+
+	assert.Equal(t, []string{"first", "second"}, fired)
+	assert.Equal(t, start.Add(2*time.Second), c.Now())
+	assert.Equal(t, 1, c.PendingTimers())
+}
+
+func TestFake_StoppedTimerDoesNotFire(t *testing.T) {
+	c := NewFake(time.Now())
+
+	fired := false
+	timer := c.AfterFunc(time.Second, func() { fired = true })
+
+	assert.True(t, timer.Stop())
+	assert.False(t, timer.Stop())
+
+	c.Advance(time.Hour)
+	assert.False(t, fired)
+	assert.Zero(t, c.PendingTimers())
+}
@@ -30,21 +30,21 @@ func NewPostgresDB(cfg *config.Config) (*sqlx.DB, error) {
 	return db, nil
 }
 
-// WaitForDB waits for the database to become available with retries
-func WaitForDB(cfg *config.Config, maxRetries int, retryDelay time.Duration) (*sqlx.DB, error) {
+// WaitForDB waits for the database to become available, retrying as retry describes
+func WaitForDB(cfg *config.Config, retry config.ConnectRetryConfig) (*sqlx.DB, error) {
 	var db *sqlx.DB
 	var err error
 
-	for i := 0; i < maxRetries; i++ {
+	for i := 0; i < retry.MaxRetries; i++ {
 		db, err = NewPostgresDB(cfg)
 		if err == nil {
 			return db, nil
 		}
 
-		if i < maxRetries-1 {
-			time.Sleep(retryDelay)
+		if i < retry.MaxRetries-1 {
+			time.Sleep(retry.Wait(i))
 		}
 	}
 
-	return nil, fmt.Errorf("failed to connect to database after %d retries: %w", maxRetries, err)
+	return nil, fmt.Errorf("failed to connect to database after %d retries: %w", retry.MaxRetries, err)
 }
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pgSerializationFailure is the SQLSTATE of a transaction Postgres couldn't serialize
+const pgSerializationFailure = "40001"
+
+// RetryableTx is the isolation level for transactions that settle conflicts by rerunning
+// under RetrySerializable. At REPEATABLE READ, writing or locking a row another transaction
+// changed after this one's snapshot fails with a serialization failure, which is rerun from
+// a fresh snapshot; at the default READ COMMITTED the statement would wait and act on the
+// newer row instead. Only use it where a rerun is cheaper than waiting: every conflict
+// becomes a failed attempt.
+var RetryableTx = sql.TxOptions{Isolation: sql.LevelRepeatableRead}
+
+// serializationRetryBaseDelay is the first retry's upper bound; it doubles per attempt
+// until maxSerializationRetryDoublings
+const (
+	serializationRetryBaseDelay    = 10 * time.Millisecond
+	maxSerializationRetryDoublings = 6
+)
+
+// IsSerializationFailure reports whether err is, or wraps, a Postgres serialization failure
+func IsSerializationFailure(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == pgSerializationFailure
+}
+
+// RetrySerializable runs fn and reruns it up to retries more times while it fails with a
+// serialization failure. Postgres aborts the whole transaction on one, so fn must be a
+// complete transaction rather than a statement inside one. Waits are random up to an
+// exponentially growing bound, so transactions that collided don't collide again in lockstep.
+func RetrySerializable(ctx context.Context, retries int, fn func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= retries || !IsSerializationFailure(err) {
+			return err
+		}
+
+		wait := rand.N(serializationRetryBaseDelay << min(attempt, maxSerializationRetryDoublings))
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
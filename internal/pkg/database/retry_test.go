@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var errSerialization = fmt.Errorf("commit: %w", &pq.Error{Code: "40001"})
+
+func TestRetrySerializable_RetriesUntilSuccess(t *testing.T) {
+	calls := 0
+	err := RetrySerializable(context.Background(), 3, func() error {
+		calls++
+		if calls < 3 {
+			return errSerialization
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestRetrySerializable_GivesUpAfterRetries(t *testing.T) {
+	calls := 0
+	err := RetrySerializable(context.Background(), 2, func() error {
+		calls++
+		return errSerialization
+	})
+
+	assert.ErrorIs(t, err, errSerialization)
+	assert.Equal(t, 3, calls, "the first attempt plus two retries")
+}
+
+func TestRetrySerializable_OtherErrorsAreNotRetried(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{name: "unique violation", err: &pq.Error{Code: "23505"}},
+		{name: "plain error", err: errors.New("connection refused")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			calls := 0
+			err := RetrySerializable(context.Background(), 3, func() error {
+				calls++
+				return tc.err
+			})
+
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestRetrySerializable_StopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := RetrySerializable(ctx, 3, func() error {
+		calls++
+		return errSerialization
+	})
+
+	assert.ErrorIs(t, err, errSerialization)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,125 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// maxBackoff caps the exponential backoff between retries
+const maxBackoff = 30 * time.Second
+
+// maxDrainBytes bounds how much of a failed response body is read before retrying;
+// a larger body costs more than a fresh connection would
+const maxDrainBytes = 64 << 10
+
+// Client is the shared outbound HTTP client for webhook-style calls.
+// Retries network errors and 5xx responses with exponential backoff so each
+// consumer doesn't reimplement retries with slightly different rules.
+type Client struct {
+	httpClient     *http.Client
+	maxRetries     int
+	initialBackoff time.Duration
+	logger         *logger.Logger
+}
+
+// New creates an outbound HTTP client with pooled connections
+func New(cfg config.HTTPClientConfig, log *logger.Logger) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = cfg.MaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConns
+
+	return &Client{
+		httpClient: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: transport,
+		},
+		maxRetries:     cfg.MaxRetries,
+		initialBackoff: cfg.RetryBackoff,
+		logger:         log,
+	}
+}
+
+// Do sends the request, retrying on network errors and 5xx responses.
+// Requests with a body must be replayable (http.NewRequest sets GetBody for
+// bytes/strings readers). The caller closes the returned response body.
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	backoff := c.initialBackoff
+
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 {
+			if err := rewindBody(req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if !shouldRetry(resp, err) || attempt >= c.maxRetries {
+			return resp, err
+		}
+
+		fields := map[string]any{
+			"url":     req.URL.Redacted(),
+			"attempt": attempt + 1,
+			"backoff": backoff.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			// Drain so the connection goes back to the pool
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+			_ = resp.Body.Close()
+		}
+		c.logger.WithFields(fields).Warn("Outbound HTTP request failed, retrying")
+
+		if err := sleep(req.Context(), backoff); err != nil {
+			return nil, err
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// shouldRetry retries transport errors and server errors; client errors won't succeed on retry
+func shouldRetry(resp *http.Response, err error) bool {
+	if err != nil {
+		// A cancelled or expired caller context is final
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
+
+func rewindBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	if req.GetBody == nil {
+		return fmt.Errorf("cannot retry %s %s: request body is not replayable", req.Method, req.URL.Redacted())
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return fmt.Errorf("failed to rewind request body: %w", err)
+	}
+	req.Body = body
+	return nil
+}
+
+// sleep waits for d or until ctx is done, whichever comes first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
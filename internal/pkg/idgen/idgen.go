@@ -0,0 +1,90 @@
+// Package idgen generates primary keys for new products and reviews.
+//
+// Random (v4) UUIDs land anywhere in a B-tree index, so every insert touches a different
+// page. Version 7 UUIDs start with a millisecond Unix timestamp and are inserted in
+// roughly ascending order, which keeps index writes on the rightmost pages and lets IDs
+// be ordered by creation time. The google/uuid version in go.mod predates v7, hence NewV7.
+package idgen
+
+import (
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Strategies for DB_ID_STRATEGY
+const (
+	// StrategyV4 generates random UUIDs, the same kind Postgres' gen_random_uuid() returns
+	StrategyV4 = "v4"
+	// StrategyV7 generates time-ordered UUIDs (RFC 9562)
+	StrategyV7 = "v7"
+)
+
+// Generator returns a new primary key
+type Generator func() uuid.UUID
+
+// IsValidStrategy reports whether strategy is one of the DB_ID_STRATEGY values
+func IsValidStrategy(strategy string) bool {
+	return strategy == StrategyV4 || strategy == StrategyV7
+}
+
+// New returns the generator for strategy; anything but StrategyV7 gets random v4 IDs
+func New(strategy string) Generator {
+	if strategy == StrategyV7 {
+		return NewV7
+	}
+	return uuid.New
+}
+
+var (
+	v7Mu sync.Mutex
+	// lastV7 is the last timestamp handed out, in the 48+12 bit layout of NewV7
+	lastV7 int64
+)
+
+// NewV7 returns a version 7 UUID. The 12 bits after the millisecond timestamp hold the
+// sub-millisecond fraction (RFC 9562 method 3) and are bumped when the clock hasn't
+// moved, so IDs from one process are strictly increasing. Like uuid.New it panics if
+// the system's random source fails.
+func NewV7() uuid.UUID {
+	id := uuid.New() // random bits with the RFC 4122 variant already set
+
+	ms, frac := nextV7Time(time.Now())
+
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(ms))
+	copy(id[0:6], ts[2:8])
+	id[6] = 0x70 | byte(frac>>8)&0x0f
+	id[7] = byte(frac)
+
+	return id
+}
+
+// nextV7Time splits now into milliseconds and a 12-bit fraction of a millisecond, moving
+// past the last value handed out if the clock stood still or stepped back
+func nextV7Time(now time.Time) (ms, frac int64) {
+	nanos := now.UnixNano()
+	ms = nanos / int64(time.Millisecond)
+	// 1e6 nanoseconds >> 8 is at most 3906, which fits in 12 bits
+	frac = (nanos - ms*int64(time.Millisecond)) >> 8
+
+	v7Mu.Lock()
+	defer v7Mu.Unlock()
+
+	t := ms<<12 | frac
+	if t <= lastV7 {
+		t = lastV7 + 1
+	}
+	lastV7 = t
+
+	return t >> 12, t & 0xfff
+}
+
+// Time returns the creation time encoded in a version 7 UUID, to millisecond precision
+func Time(id uuid.UUID) time.Time {
+	var ts [8]byte
+	copy(ts[2:8], id[0:6])
+	return time.UnixMilli(int64(binary.BigEndian.Uint64(ts[:])))
+}
@@ -0,0 +1,60 @@
+package idgen
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewV7(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	id := NewV7()
+	after := time.Now()
+
+	assert.Equal(t, uuid.Version(7), id.Version())
+	assert.Equal(t, uuid.RFC4122, id.Variant())
+
+	created := Time(id)
+	assert.False(t, created.Before(before), "timestamp %s before %s", created, before)
+	assert.False(t, created.After(after), "timestamp %s after %s", created, after)
+}
+
+func TestNewV7_StrictlyIncreasing(t *testing.T) {
+	prev := NewV7()
+	// Far more IDs than fit in one 12-bit fraction, so some share a millisecond
+	for range 10000 {
+		next := NewV7()
+		require.Positive(t, bytes.Compare(next[:], prev[:]), "%s after %s", next, prev)
+		prev = next
+	}
+}
+
+func TestNextV7Time_ClockStepsBack(t *testing.T) {
+	// Runs an hour ahead, so put the state back for the tests that compare with time.Now
+	v7Mu.Lock()
+	saved := lastV7
+	v7Mu.Unlock()
+	t.Cleanup(func() {
+		v7Mu.Lock()
+		lastV7 = saved
+		v7Mu.Unlock()
+	})
+
+	now := time.Now().Add(time.Hour)
+	ms, frac := nextV7Time(now)
+
+	// An earlier clock reading still moves forward from the last value
+	ms2, frac2 := nextV7Time(now.Add(-time.Second))
+	assert.Equal(t, ms<<12|frac+1, ms2<<12|frac2)
+}
+
+func TestNew(t *testing.T) {
+	assert.Equal(t, uuid.Version(7), New(StrategyV7)().Version())
+	assert.Equal(t, uuid.Version(4), New(StrategyV4)().Version())
+	assert.True(t, IsValidStrategy(StrategyV4))
+	assert.False(t, IsValidStrategy("v1"))
+}
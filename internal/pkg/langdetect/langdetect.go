@@ -0,0 +1,73 @@
+// Package langdetect guesses the language of review text from its most common words.
+// It knows a handful of European languages and is meant for tagging reviews, not for
+// short or mixed-language text: when the evidence is thin it gives no answer rather
+// than a wrong one.
+package langdetect
+
+import (
+	"strings"
+	"unicode"
+)
+
+// minHits is how many stopwords the winning language needs. One or two words match in
+// several languages ("a", "de", "in"), so a single hit says little.
+const minHits = 3
+
+// minLead is how many times more hits the winner needs than the runner-up, so text that
+// mixes languages, or is written in one they're both close to, gets no answer
+const minLead = 1.5
+
+// stopwords are frequent function words per ISO 639-1 code. Words shared between languages
+// count for each of them; the other words decide.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "it", "this", "was", "for", "with", "not", "but", "very", "are", "have", "my", "you", "of", "to", "that", "they", "would", "at", "be", "its", "great", "good"},
+	"de": {"der", "die", "das", "und", "ist", "nicht", "ich", "es", "mit", "sehr", "ein", "eine", "auf", "für", "auch", "den", "dem", "zu", "war", "aber", "sich", "wir", "gut", "noch", "nach"},
+	"fr": {"le", "la", "les", "et", "est", "un", "une", "des", "pas", "je", "pour", "du", "que", "qui", "très", "dans", "ce", "sur", "avec", "mais", "il", "au", "produit", "bien", "ne"},
+	"es": {"el", "la", "los", "las", "y", "es", "un", "una", "que", "muy", "por", "para", "con", "pero", "lo", "del", "se", "no", "mi", "su", "está", "producto", "bien", "al", "como"},
+	"it": {"il", "lo", "gli", "e", "è", "un", "una", "che", "non", "per", "molto", "con", "ma", "del", "della", "sono", "ho", "mi", "questo", "prodotto", "bene", "come", "di", "anche", "più"},
+	"nl": {"de", "het", "een", "en", "is", "niet", "ik", "van", "dat", "met", "voor", "zijn", "op", "maar", "ook", "heel", "erg", "goed", "wel", "dit", "te", "er", "naar", "nog", "geen"},
+	"pt": {"o", "os", "as", "e", "é", "um", "uma", "que", "não", "muito", "para", "com", "mas", "do", "da", "em", "no", "na", "eu", "produto", "bem", "como", "mais", "foi", "são"},
+}
+
+// index maps each stopword to the languages it belongs to
+var index = func() map[string][]string {
+	index := make(map[string][]string)
+	for lang, words := range stopwords {
+		for _, word := range words {
+			index[word] = append(index[word], lang)
+		}
+	}
+	return index
+}()
+
+// Detect returns the ISO 639-1 code of the language text is most likely written in,
+// or "" when it can't tell
+func Detect(text string) string {
+	hits := make(map[string]int, len(stopwords))
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	for _, word := range words {
+		for _, lang := range index[word] {
+			hits[lang]++
+		}
+	}
+
+	best, bestHits, runnerUp := "", 0, 0
+	for lang, n := range hits {
+		switch {
+		case n > bestHits:
+			best, bestHits, runnerUp = lang, n, bestHits
+		case n == bestHits:
+			// A tie has no winner, whatever order the map is walked in
+			best, runnerUp = "", n
+		case n > runnerUp:
+			runnerUp = n
+		}
+	}
+
+	if best == "" || bestHits < minHits || float64(bestHits) < minLead*float64(runnerUp) {
+		return ""
+	}
+	return best
+}
@@ -0,0 +1,33 @@
+package langdetect
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	tests := map[string]struct {
+		text string
+		want string
+	}{
+		"english":              {text: "This is a great product and it was very easy to set up. The battery lasts for days.", want: "en"},
+		"german":               {text: "Das Produkt ist sehr gut und die Lieferung war auch schnell. Ich bin zufrieden.", want: "de"},
+		"french":               {text: "Le produit est très bien, je le recommande pour la cuisine et pour le jardin.", want: "fr"},
+		"spanish":              {text: "El producto es muy bueno y llegó rápido, pero la caja estaba rota.", want: "es"},
+		"italian":              {text: "Il prodotto è molto bello e funziona bene, ma non è economico.", want: "it"},
+		"dutch":                {text: "Het is een heel goed product, maar de levering was niet snel.", want: "nl"},
+		"portuguese":           {text: "O produto é muito bom e chegou rápido, mas a caixa não veio com manual.", want: "pt"},
+		"case and punctuation": {text: "THE BEST! It's the one I wanted, and it WORKS.", want: "en"},
+		"too short":            {text: "Great!", want: ""},
+		"no words":             {text: "5/5 !!!", want: ""},
+		"unknown":              {text: "Tämä tuote on erittäin hyvä ja toimii hienosti.", want: ""},
+		"empty":                {text: "", want: ""},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, Detect(tc.text))
+		})
+	}
+}
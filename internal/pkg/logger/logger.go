@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"fmt"
 	"os"
 	"time"
 
@@ -37,6 +38,17 @@ func New(env string) *Logger {
 	return &Logger{logger: logger}
 }
 
+// SetLevel changes the minimum level of every logger, e.g. to debug during an incident.
+// level is debug, info, warn or error; New sets debug in development and info otherwise.
+func SetLevel(level string) error {
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil || parsed < zerolog.DebugLevel || parsed > zerolog.ErrorLevel {
+		return fmt.Errorf("unknown log level %q (debug, info, warn or error)", level)
+	}
+	zerolog.SetGlobalLevel(parsed)
+	return nil
+}
+
 // Debug logs a debug message
 func (l *Logger) Debug(msg string) {
 	l.logger.Debug().Msg(msg)
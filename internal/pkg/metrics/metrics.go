@@ -0,0 +1,123 @@
+// Package metrics exposes process metrics in the Prometheus text exposition format.
+//
+// Metrics are read from callbacks at scrape time instead of being pushed into the
+// registry, so components keep their own counters (usually atomics) and don't depend
+// on this package. Only unlabelled counters and gauges are supported; that covers
+// what the binaries report today without pulling in the Prometheus client library.
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the Prometheus text format version that WriteTo produces
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type metric struct {
+	name  string
+	help  string
+	kind  string
+	value func() float64
+}
+
+// Registry holds the metrics a binary exposes on /metrics
+type Registry struct {
+	mu      sync.Mutex
+	metrics []metric
+	names   map[string]struct{}
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]struct{})}
+}
+
+// CounterFunc registers a counter read from fn on every scrape.
+// fn must only ever increase; Prometheus treats a drop as a process restart.
+func (r *Registry) CounterFunc(name, help string, fn func() float64) {
+	r.register(metric{name: name, help: help, kind: "counter", value: fn})
+}
+
+// GaugeFunc registers a gauge read from fn on every scrape
+func (r *Registry) GaugeFunc(name, help string, fn func() float64) {
+	r.register(metric{name: name, help: help, kind: "gauge", value: fn})
+}
+
+// RegisterRuntime exposes the goroutine count and live heap size under the names the
+// Prometheus Go client uses, so existing dashboards pick them up
+func RegisterRuntime(r *Registry) {
+	r.GaugeFunc("go_goroutines", "Number of goroutines that currently exist.",
+		func() float64 { return float64(runtime.NumGoroutine()) })
+	r.GaugeFunc("go_memstats_heap_alloc_bytes", "Number of heap bytes allocated and still in use.",
+		func() float64 {
+			var stats runtime.MemStats
+			runtime.ReadMemStats(&stats)
+			return float64(stats.HeapAlloc)
+		})
+}
+
+// register panics on a duplicate name: two series with one name is a wiring bug that
+// Prometheus would reject at scrape time, long after startup
+func (r *Registry) register(m metric) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, dup := r.names[m.name]; dup {
+		panic(fmt.Sprintf("metrics: %s registered twice", m.name))
+	}
+	r.names[m.name] = struct{}{}
+	r.metrics = append(r.metrics, m)
+}
+
+// WriteTo writes every metric in registration order in the Prometheus text format
+func (r *Registry) WriteTo(w io.Writer) (int64, error) {
+	r.mu.Lock()
+	metrics := append([]metric(nil), r.metrics...)
+	r.mu.Unlock()
+
+	cw := &countingWriter{w: bufio.NewWriter(w)}
+	for _, m := range metrics {
+		fmt.Fprintf(cw, "# HELP %s %s\n", m.name, escapeHelp(m.help))
+		fmt.Fprintf(cw, "# TYPE %s %s\n", m.name, m.kind)
+		fmt.Fprintf(cw, "%s %s\n", m.name, strconv.FormatFloat(m.value(), 'g', -1, 64))
+	}
+	if cw.err != nil {
+		return cw.n, cw.err
+	}
+	return cw.n, cw.w.Flush()
+}
+
+// ServeHTTP serves the registry as a Prometheus scrape target
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = r.WriteTo(w)
+}
+
+// escapeHelp applies the escaping the text format requires in HELP lines
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
+
+// countingWriter keeps the first write error so WriteTo can check once at the end
+type countingWriter struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	c.err = err
+	return n, err
+}
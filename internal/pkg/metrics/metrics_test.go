@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry_ServeHTTP(t *testing.T) {
+	r := NewRegistry()
+	events := 0.0
+	r.CounterFunc("events_total", "Events received.", func() float64 { return events })
+	r.GaugeFunc("pending", "Pending\nwork with a \\ backslash.", func() float64 { return 2.5 })
+
+	events = 12
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t,
+		"# HELP events_total Events received.\n"+
+			"# TYPE events_total counter\n"+
+			"events_total 12\n"+
+			"# HELP pending Pending\\nwork with a \\\\ backslash.\n"+
+			"# TYPE pending gauge\n"+
+			"pending 2.5\n",
+		rec.Body.String(), "values are read at scrape time, in registration order")
+}
+
+func TestRegistry_DuplicateNamePanics(t *testing.T) {
+	r := NewRegistry()
+	r.GaugeFunc("pending", "", func() float64 { return 0 })
+
+	assert.Panics(t, func() {
+		r.CounterFunc("pending", "", func() float64 { return 0 })
+	})
+}
+
+func TestRegisterRuntime(t *testing.T) {
+	r := NewRegistry()
+	RegisterRuntime(r)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Contains(t, rec.Body.String(), "# TYPE go_goroutines gauge\n")
+	assert.Contains(t, rec.Body.String(), "# TYPE go_memstats_heap_alloc_bytes gauge\n")
+}
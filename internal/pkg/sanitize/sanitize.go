@@ -0,0 +1,120 @@
+// Package sanitize strips HTML from user-supplied text so frontends that render it
+// as HTML can't be made to run injected markup
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Modes for SANITIZE_REVIEW_TEXT
+const (
+	// ModeOff returns review text exactly as submitted
+	ModeOff = "off"
+	// ModeStore strips tags before reviews are validated and saved, so stored text is clean
+	ModeStore = "store"
+	// ModeOutput keeps stored text verbatim and strips tags from review_text in API responses
+	ModeOutput = "output"
+)
+
+// IsValidMode reports whether mode is one of the SANITIZE_REVIEW_TEXT modes
+func IsValidMode(mode string) bool {
+	switch mode {
+	case ModeOff, ModeStore, ModeOutput:
+		return true
+	default:
+		return false
+	}
+}
+
+// StripTags removes HTML tags and comments, keeping the text between them.
+// Text is kept as written, entities included: "&lt;b&gt;" stays literal rather than being
+// decoded into a tag. Script and style contents are dropped entirely since they were
+// never meant as visible text.
+func StripTags(s string) string {
+	// Fast path: without '<' there is no markup to remove
+	if !strings.Contains(s, "<") {
+		return s
+	}
+
+	var (
+		out       strings.Builder
+		skipDepth int
+	)
+	z := html.NewTokenizer(strings.NewReader(s))
+
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			// io.EOF is the only error a strings.Reader can produce
+			return out.String()
+		case html.TextToken:
+			if skipDepth == 0 {
+				out.Write(z.Raw())
+			}
+		case html.StartTagToken:
+			if isRawTextElement(z) {
+				skipDepth++
+			}
+		case html.EndTagToken:
+			if skipDepth > 0 && isRawTextElement(z) {
+				skipDepth--
+			}
+		}
+	}
+}
+
+// isRawTextElement reports whether the current tag is script or style
+func isRawTextElement(z *html.Tokenizer) bool {
+	name, _ := z.TagName()
+	switch atom.Lookup(name) {
+	case atom.Script, atom.Style:
+		return true
+	default:
+		return false
+	}
+}
+
+// JSONField strips tags from every string value stored under one of keys, at any depth of
+// a JSON document, and returns the re-encoded document. Numbers are decoded as json.Number
+// so they round-trip exactly.
+func JSONField(body []byte, keys ...string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	// Encoder rather than json.Marshal to keep the trailing newline response.JSON writes
+	if err := json.NewEncoder(&out).Encode(stripField(doc, keys)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// stripField walks v in place; a matching key holding a non-string (e.g. an object) is descended into
+func stripField(v any, keys []string) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if s, ok := child.(string); ok && slices.Contains(keys, k) {
+				val[k] = StripTags(s)
+				continue
+			}
+			val[k] = stripField(child, keys)
+		}
+	case []any:
+		for i, child := range val {
+			val[i] = stripField(child, keys)
+		}
+	}
+	return v
+}
@@ -0,0 +1,58 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStripTags(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want string
+	}{
+		"plain text unchanged":     {in: "Great product, 5/5 & would buy again", want: "Great product, 5/5 & would buy again"},
+		"comparison kept":          {in: "price < value", want: "price < value"},
+		"formatting tags removed":  {in: "<b>Great</b> <i>product</i>", want: "Great product"},
+		"script body dropped":      {in: "Nice<script>alert(1)</script>!", want: "Nice!"},
+		"style body dropped":       {in: "<style>body{display:none}</style>Fine", want: "Fine"},
+		"event handler attributes": {in: `<img src=x onerror="alert(1)">Broken`, want: "Broken"},
+		"comments removed":         {in: "Good<!-- hidden -->.", want: "Good."},
+		"entities stay literal":    {in: "&lt;script&gt;", want: "&lt;script&gt;"},
+		"unclosed tag":             {in: "Fine <a href='x", want: "Fine "},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, StripTags(tc.in))
+		})
+	}
+}
+
+func TestJSONField(t *testing.T) {
+	body := []byte(`{"success":true,"data":{"reviews":[` +
+		`{"rating":5,"review_text":"<b>Great</b>","title":"<b>kept</b>"},` +
+		`{"rating":4,"review_text":"plain"}],"total":12345678901234567890}}`)
+
+	got, err := JSONField(body, "review_text")
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"success":true,"data":{"reviews":[`+
+		`{"rating":5,"review_text":"Great","title":"<b>kept</b>"},`+
+		`{"rating":4,"review_text":"plain"}],"total":12345678901234567890}}`, string(got))
+}
+
+func TestJSONField_SeveralKeys(t *testing.T) {
+	body := []byte(`{"data":{"title":"<i>Wow</i>","review_text":"<b>Great</b>","name":"<b>kept</b>"}}`)
+
+	got, err := JSONField(body, "review_text", "title")
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"data":{"title":"Wow","review_text":"Great","name":"<b>kept</b>"}}`, string(got))
+}
+
+func TestJSONField_InvalidJSON(t *testing.T) {
+	_, err := JSONField([]byte("not json"), "review_text")
+	assert.Error(t, err)
+}
@@ -1,17 +1,217 @@
 package validator
 
 import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-playground/locales/de"
+	"github.com/go-playground/locales/en"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
+	deTranslations "github.com/go-playground/validator/v10/translations/de"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
 )
 
 // Shared validator instance to avoid creating multiple instances
 var validate *validator.Validate
 
+// Translators for validation messages; English is the fallback for unsupported languages
+var translators *ut.UniversalTranslator
+
 func init() {
 	validate = validator.New()
+
+	// Report JSON field names so messages refer to what the client actually sent
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+
+	english := en.New()
+	translators = ut.New(english, english, de.New())
+
+	enTrans, _ := translators.GetTranslator("en")
+	if err := enTranslations.RegisterDefaultTranslations(validate, enTrans); err != nil {
+		panic("failed to register English validation translations: " + err.Error())
+	}
+
+	deTrans, _ := translators.GetTranslator("de")
+	if err := deTranslations.RegisterDefaultTranslations(validate, deTrans); err != nil {
+		panic("failed to register German validation translations: " + err.Error())
+	}
+
+	registerRating(enTrans, deTrans)
+}
+
+// registerRating adds the "rating" tag: a rating on the configured 1 to domain.MaxRating()
+// scale, in whole stars or, with domain.HalfStarRatings(), steps of 0.5, or one of
+// domain.AllowedRatings() when those are set. A tag like max=5 would freeze the scale at
+// compile time.
+func registerRating(enTrans, deTrans ut.Translator) {
+	if err := validate.RegisterValidation("rating", func(fl validator.FieldLevel) bool {
+		field := fl.Field()
+		switch {
+		case field.CanFloat():
+			return domain.IsValidRating(field.Float())
+		case field.CanInt():
+			return domain.IsValidRating(float64(field.Int()))
+		default:
+			return false
+		}
+	}); err != nil {
+		panic("failed to register rating validation: " + err.Error())
+	}
+
+	messages := map[ut.Translator][3]string{
+		enTrans: {"{0} must be between 1 and {1}", "{0} must be between 1 and {1} in steps of 0.5", "{0} must be one of {1}"},
+		deTrans: {"{0} muss zwischen 1 und {1} liegen", "{0} muss zwischen 1 und {1} in Schritten von 0,5 liegen", "{0} muss einer der Werte {1} sein"},
+	}
+	for trans, message := range messages {
+		err := validate.RegisterTranslation("rating", trans,
+			func(ut ut.Translator) error {
+				if err := ut.Add("rating", message[0], true); err != nil {
+					return err
+				}
+				if err := ut.Add("rating_half", message[1], true); err != nil {
+					return err
+				}
+				return ut.Add("rating_allowed", message[2], true)
+			},
+			func(ut ut.Translator, fe validator.FieldError) string {
+				if allowed := domain.AllowedRatings(); allowed != nil {
+					values := make([]string, len(allowed))
+					for i, rating := range allowed {
+						values[i] = strconv.FormatFloat(rating, 'f', -1, 64)
+					}
+					msg, _ := ut.T("rating_allowed", fe.Field(), strings.Join(values, ", "))
+					return msg
+				}
+
+				key := "rating"
+				if domain.HalfStarRatings() {
+					key = "rating_half"
+				}
+				msg, _ := ut.T(key, fe.Field(), strconv.Itoa(domain.MaxRating()))
+				return msg
+			},
+		)
+		if err != nil {
+			panic("failed to register rating translation: " + err.Error())
+		}
+	}
 }
 
 // Get returns the shared validator instance
 func Get() *validator.Validate {
 	return validate
 }
+
+// InvalidInput marks a failed validation as domain.ErrInvalidInput, keeping the validator
+// errors attached so handlers can report per-field messages
+func InvalidInput(err error) error {
+	return fmt.Errorf("%w: %w", domain.ErrInvalidInput, err)
+}
+
+// AtIndex ties validation errors to the item at index in a batch; TranslateErrors keys
+// their messages "[index].field", so a client can tell which item failed
+func AtIndex(index int, err error) error {
+	return &itemError{index: index, err: err}
+}
+
+// itemError is the validation error of one item in a batch
+type itemError struct {
+	index int
+	err   error
+}
+
+func (e *itemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.index, e.err)
+}
+
+func (e *itemError) Unwrap() error {
+	return e.err
+}
+
+// Translator returns the translator best matching an Accept-Language header, falling back to English
+func Translator(acceptLanguage string) ut.Translator {
+	trans, _ := translators.FindTranslator(parseAcceptLanguage(acceptLanguage)...)
+	return trans
+}
+
+// TranslateErrors returns translated validation messages keyed by JSON field name,
+// prefixed with "[index]." for errors wrapped by AtIndex
+// Returns nil when err carries no validation errors
+func TranslateErrors(err error, acceptLanguage string) map[string]string {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	prefix := ""
+	var item *itemError
+	if errors.As(err, &item) {
+		prefix = fmt.Sprintf("[%d].", item.index)
+	}
+
+	trans := Translator(acceptLanguage)
+	fields := make(map[string]string, len(validationErrs))
+	for _, fieldErr := range validationErrs {
+		fields[prefix+fieldErr.Field()] = fieldErr.Translate(trans)
+	}
+	return fields
+}
+
+// parseAcceptLanguage orders locales from an Accept-Language header by quality
+// Region-specific tags (de-AT) are followed by their base language (de) so partial matches still work
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag     string
+		quality float64
+	}
+
+	var tags []weighted
+	for part := range strings.SplitSeq(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(q, 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+
+		tags = append(tags, weighted{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].quality > tags[j].quality
+	})
+
+	locales := make([]string, 0, len(tags)*2)
+	for _, t := range tags {
+		base, region, found := strings.Cut(t.tag, "-")
+		if found {
+			locales = append(locales, base+"_"+strings.ToUpper(region))
+		}
+		locales = append(locales, base)
+	}
+	return locales
+}
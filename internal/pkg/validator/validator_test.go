@@ -0,0 +1,127 @@
+package validator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+type ratedItem struct {
+	Rating int `json:"rating" validate:"required,rating"`
+}
+
+func TestRating_FollowsConfiguredScale(t *testing.T) {
+	t.Cleanup(func() { domain.SetMaxRating(domain.DefaultMaxRating) })
+
+	tests := []struct {
+		name      string
+		maxRating int
+		rating    int
+		valid     bool
+	}{
+		{name: "top of default scale", maxRating: 5, rating: 5, valid: true},
+		{name: "above default scale", maxRating: 5, rating: 6, valid: false},
+		{name: "zero", maxRating: 5, rating: 0, valid: false},
+		{name: "negative", maxRating: 10, rating: -1, valid: false},
+		{name: "ten point scale", maxRating: 10, rating: 8, valid: true},
+		{name: "above ten point scale", maxRating: 10, rating: 11, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain.SetMaxRating(tt.maxRating)
+
+			err := Get().Struct(ratedItem{Rating: tt.rating})
+
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRating_TranslatedMessage(t *testing.T) {
+	t.Cleanup(func() { domain.SetMaxRating(domain.DefaultMaxRating) })
+	domain.SetMaxRating(10)
+
+	err := Get().Struct(ratedItem{Rating: 11})
+
+	assert.Equal(t, map[string]string{"rating": "rating must be between 1 and 10"}, TranslateErrors(err, "en"))
+	assert.Equal(t, map[string]string{"rating": "rating muss zwischen 1 und 10 liegen"}, TranslateErrors(err, "de"))
+}
+
+type halfRatedItem struct {
+	Rating float64 `json:"rating" validate:"required,rating"`
+}
+
+func TestRating_HalfStars(t *testing.T) {
+	t.Cleanup(func() { domain.SetHalfStarRatings(false) })
+
+	tests := []struct {
+		name      string
+		halfStars bool
+		rating    float64
+		valid     bool
+	}{
+		{name: "whole star", halfStars: false, rating: 4, valid: true},
+		{name: "half star without the mode", halfStars: false, rating: 4.5, valid: false},
+		{name: "half star", halfStars: true, rating: 4.5, valid: true},
+		{name: "whole star in half-star mode", halfStars: true, rating: 5, valid: true},
+		{name: "not a half step", halfStars: true, rating: 4.3, valid: false},
+		{name: "half step below the scale", halfStars: true, rating: 0.5, valid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			domain.SetHalfStarRatings(tt.halfStars)
+
+			err := Get().Struct(halfRatedItem{Rating: tt.rating})
+
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRating_HalfStarTranslatedMessage(t *testing.T) {
+	t.Cleanup(func() { domain.SetHalfStarRatings(false) })
+	domain.SetHalfStarRatings(true)
+
+	err := Get().Struct(halfRatedItem{Rating: 4.3})
+
+	assert.Equal(t, map[string]string{"rating": "rating must be between 1 and 5 in steps of 0.5"}, TranslateErrors(err, "en"))
+	assert.Equal(t, map[string]string{"rating": "rating muss zwischen 1 und 5 in Schritten von 0,5 liegen"}, TranslateErrors(err, "de"))
+}
+
+func TestRating_AllowedRatings(t *testing.T) {
+	t.Cleanup(func() { domain.SetAllowedRatings(nil) })
+	domain.SetAllowedRatings([]float64{1, 3, 5})
+
+	assert.NoError(t, Get().Struct(halfRatedItem{Rating: 3}))
+
+	err := Get().Struct(halfRatedItem{Rating: 4})
+
+	assert.Equal(t, map[string]string{"rating": "rating must be one of 1, 3, 5"}, TranslateErrors(err, "en"))
+	assert.Equal(t, map[string]string{"rating": "rating muss einer der Werte 1, 3, 5 sein"}, TranslateErrors(err, "de"))
+}
+
+func TestInvalidInput_KeepsFieldErrors(t *testing.T) {
+	err := InvalidInput(Get().Struct(ratedItem{}))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Equal(t, map[string]string{"rating": "rating is a required field"}, TranslateErrors(err, "en"))
+}
+
+func TestInvalidInput_AtIndexKeysFieldsByItem(t *testing.T) {
+	err := InvalidInput(AtIndex(3, Get().Struct(ratedItem{})))
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Equal(t, map[string]string{"[3].rating": "rating is a required field"}, TranslateErrors(err, "en"))
+}
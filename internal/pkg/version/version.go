@@ -0,0 +1,44 @@
+// Package version holds build metadata injected at link time, for example:
+//
+//	go build -ldflags "-X github.com/Pesokrava/product_reviewer/internal/pkg/version.Version=v1.2.0 \
+//		-X github.com/Pesokrava/product_reviewer/internal/pkg/version.Commit=$(git rev-parse HEAD) \
+//		-X github.com/Pesokrava/product_reviewer/internal/pkg/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// The Makefile and Dockerfile do this; a plain go build or go run reports the defaults.
+package version
+
+import "time"
+
+// Set with -ldflags -X, which only works on string variables, so these must not become constants
+var (
+	// Version is the release, e.g. a git tag
+	Version = "dev"
+	// Commit is the git commit the binary was built from
+	Commit = "unknown"
+	// BuildTime is when the binary was built, in RFC 3339
+	BuildTime = "unknown"
+)
+
+// startedAt approximates process start; package variables are initialized before main runs
+var startedAt = time.Now()
+
+// Info describes the running build
+type Info struct {
+	Version   string    `json:"version" example:"v1.2.0"`
+	Commit    string    `json:"commit" example:"3f2c1a9"`
+	BuildTime string    `json:"build_time" example:"2026-01-15T10:00:00Z"`
+	StartedAt time.Time `json:"started_at"`
+	// UptimeSeconds is whole seconds since the process started
+	UptimeSeconds int64 `json:"uptime_seconds" example:"3600"`
+}
+
+// Get returns the build metadata and how long the process has been running at now
+func Get(now time.Time) Info {
+	return Info{
+		Version:       Version,
+		Commit:        Commit,
+		BuildTime:     BuildTime,
+		StartedAt:     startedAt,
+		UptimeSeconds: int64(now.Sub(startedAt) / time.Second),
+	}
+}
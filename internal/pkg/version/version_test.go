@@ -0,0 +1,16 @@
+package version
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet_Uptime(t *testing.T) {
+	info := Get(startedAt.Add(90*time.Second + 500*time.Millisecond))
+
+	assert.Equal(t, int64(90), info.UptimeSeconds, "whole seconds")
+	assert.Equal(t, startedAt, info.StartedAt)
+	assert.Equal(t, Version, info.Version)
+}
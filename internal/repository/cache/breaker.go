@@ -0,0 +1,27 @@
+package cache
+
+import (
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/breaker"
+)
+
+// guard runs fn unless b is open, in which case it returns breaker.ErrOpen without touching
+// Redis. Callers already treat cache errors as misses, so requests go straight to the database.
+// A miss (redis.Nil) is a healthy answer and counts as a success.
+func guard[T any](b *breaker.Breaker, fn func() (T, error)) (T, error) {
+	if !b.Allow() {
+		var zero T
+		return zero, breaker.ErrOpen
+	}
+
+	val, err := fn()
+	if errors.Is(err, redis.Nil) {
+		b.Record(nil)
+	} else {
+		b.Record(err)
+	}
+	return val, err
+}
@@ -0,0 +1,74 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/breaker"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// failingHook fails every command as an unreachable Redis would, counting the attempts
+type failingHook struct {
+	calls int
+}
+
+func (h *failingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in tests")
+	}
+}
+
+func (h *failingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.calls++
+		cmd.SetErr(context.DeadlineExceeded)
+		return cmd.Err()
+	}
+}
+
+func (h *failingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRedisCache_BreakerSkipsRedisWhileOpen(t *testing.T) {
+	hook := &failingHook{}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 2, time.Minute, nil, 0, logger.New("test"))
+	ctx := context.Background()
+	productID := uuid.New()
+
+	_, err := c.GetProductRating(ctx, productID)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Error(t, c.SetProductRating(ctx, productID, 4))
+	require.Equal(t, 2, hook.calls)
+
+	_, err = c.GetProductRating(ctx, productID)
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.NotErrorIs(t, err, domain.ErrNotFound)
+	_, _, err = c.GetReviewsList(ctx, productID, "", 10, 0)
+	assert.ErrorIs(t, err, breaker.ErrOpen)
+	assert.ErrorIs(t, c.InvalidateAllProductCache(ctx, productID), breaker.ErrOpen)
+	assert.Equal(t, 2, hook.calls, "no command should reach Redis while the breaker is open")
+}
+
+func TestGuard_MissIsNotAFailure(t *testing.T) {
+	b := breaker.New("test", 1, time.Minute, logger.New("test"))
+
+	_, err := guard(b, func() (string, error) { return "", redis.Nil })
+
+	assert.ErrorIs(t, err, redis.Nil)
+	assert.True(t, b.Allow(), "a miss means Redis answered")
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+)
+
+// Cache payload codecs for CACHE_CODEC
+const (
+	CodecJSON = "json"
+	CodecGob  = "gob"
+)
+
+// Codec serializes the structured values the cache stores: review pages, overviews,
+// summaries and the recent reviews feed. Ratings are plain numbers and bypass it.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// NewCodec returns the codec called name.
+//
+// JSON, the default, can be read with redis-cli while debugging. Gob is smaller and
+// faster for large review pages, at the cost of opaque values. Every binary sharing a
+// Redis must use the same codec: an entry in the other format fails to decode, which
+// callers treat as a miss, so mixed codecs keep overwriting each other's entries.
+func NewCodec(name string) (Codec, error) {
+	switch name {
+	case CodecJSON:
+		return jsonCodec{}, nil
+	case CodecGob:
+		return gobCodec{}, nil
+	default:
+		return nil, fmt.Errorf("unknown cache codec %q (json or gob)", name)
+	}
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// gobCodec writes each value as a self-contained gob stream, type description included,
+// so any process can decode an entry without having seen an earlier one
+type gobCodec struct{}
+
+func (gobCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// largeReviewsPage is a full page of reviews with every optional field set
+func largeReviewsPage() CachedReviewsList {
+	title, language, hash := "Solid", "en", domain.HashEmail("ann@example.com")
+	createdAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	reviews := make([]*domain.Review, 100)
+	for i := range reviews {
+		reviews[i] = &domain.Review{
+			ID:         uuid.New(),
+			ProductID:  uuid.New(),
+			FirstName:  "Ann",
+			LastName:   "Lee",
+			EmailHash:  &hash,
+			Title:      &title,
+			Language:   &language,
+			ReviewText: "Works as described, would buy again.",
+			Rating:     4.5,
+			Source:     domain.ReviewSourceWeb,
+			CreatedAt:  createdAt,
+			UpdatedAt:  createdAt.Add(time.Hour),
+		}
+	}
+	return CachedReviewsList{Reviews: reviews, Total: 1234}
+}
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	page := largeReviewsPage()
+
+	for _, name := range []string{CodecJSON, CodecGob} {
+		t.Run(name, func(t *testing.T) {
+			codec, err := NewCodec(name)
+			require.NoError(t, err)
+
+			data, err := codec.Marshal(page)
+			require.NoError(t, err)
+
+			var decoded CachedReviewsList
+			require.NoError(t, codec.Unmarshal(data, &decoded))
+			assert.Equal(t, page.Total, decoded.Total)
+			require.Len(t, decoded.Reviews, len(page.Reviews))
+			assert.Equal(t, *page.Reviews[0], *decoded.Reviews[0])
+		})
+	}
+}
+
+func TestCodecs_GobIsSmaller(t *testing.T) {
+	page := largeReviewsPage()
+
+	jsonData, err := jsonCodec{}.Marshal(page)
+	require.NoError(t, err)
+	gobData, err := gobCodec{}.Marshal(page)
+	require.NoError(t, err)
+
+	assert.Less(t, len(gobData), len(jsonData))
+}
+
+func TestCodecs_OtherFormatFailsToDecode(t *testing.T) {
+	data, err := jsonCodec{}.Marshal(largeReviewsPage())
+	require.NoError(t, err)
+
+	// Callers treat the error as a cache miss, so switching codecs only costs misses
+	var decoded CachedReviewsList
+	assert.Error(t, gobCodec{}.Unmarshal(data, &decoded))
+}
+
+func TestNewCodec_Unknown(t *testing.T) {
+	_, err := NewCodec("msgpack")
+	assert.Error(t, err)
+}
+
+func BenchmarkCodecs_ReviewsPage(b *testing.B) {
+	page := largeReviewsPage()
+
+	for _, name := range []string{CodecJSON, CodecGob} {
+		codec, err := NewCodec(name)
+		require.NoError(b, err)
+
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				data, err := codec.Marshal(page)
+				if err != nil {
+					b.Fatal(err)
+				}
+				var decoded CachedReviewsList
+				if err := codec.Unmarshal(data, &decoded); err != nil {
+					b.Fatal(err)
+				}
+				b.ReportMetric(float64(len(data)), "bytes/page")
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// Every cached reviews list starts with one of these header bytes so a reader knows
+// whether to decompress, whatever threshold the writer ran with
+const (
+	payloadPlain byte = 0
+	payloadGzip  byte = 1
+)
+
+// compressPayload prefixes data with its header byte, gzipping it first when it is at
+// least threshold bytes. A threshold of 0 or less never compresses. BestSpeed keeps the
+// CPU cost low; review text compresses well even at that level.
+func compressPayload(data []byte, threshold int) ([]byte, error) {
+	if threshold <= 0 || len(data) < threshold {
+		return append([]byte{payloadPlain}, data...), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte(payloadGzip)
+	zw, err := gzip.NewWriterLevel(&buf, gzip.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := zw.Write(data); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decompressPayload strips the header byte written by compressPayload and undoes the
+// compression it names. Entries without a known header (written before the header was
+// introduced) return an error, which callers treat as a miss.
+func decompressPayload(payload []byte) ([]byte, error) {
+	if len(payload) == 0 {
+		return nil, fmt.Errorf("empty cache payload")
+	}
+
+	switch payload[0] {
+	case payloadPlain:
+		return payload[1:], nil
+	case payloadGzip:
+		zr, err := gzip.NewReader(bytes.NewReader(payload[1:]))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		return io.ReadAll(zr)
+	default:
+		return nil, fmt.Errorf("unknown cache payload header %#x", payload[0])
+	}
+}
@@ -0,0 +1,46 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressPayload(t *testing.T) {
+	data := bytes.Repeat([]byte(`{"review_text":"Works as described"}`), 50)
+
+	tests := []struct {
+		name       string
+		threshold  int
+		wantHeader byte
+	}{
+		{"disabled", 0, payloadPlain},
+		{"below threshold", len(data) + 1, payloadPlain},
+		{"at threshold", len(data), payloadGzip},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := compressPayload(data, tt.threshold)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantHeader, payload[0])
+			if tt.wantHeader == payloadGzip {
+				assert.Less(t, len(payload), len(data)/4, "repetitive review JSON should shrink a lot")
+			}
+
+			got, err := decompressPayload(payload)
+			require.NoError(t, err)
+			assert.Equal(t, data, got)
+		})
+	}
+}
+
+func TestDecompressPayload_UnknownHeader(t *testing.T) {
+	// A JSON entry from before the header byte existed
+	_, err := decompressPayload([]byte(`{"reviews":[],"total":0}`))
+	assert.Error(t, err)
+
+	_, err = decompressPayload(nil)
+	assert.Error(t, err)
+}
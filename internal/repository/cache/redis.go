@@ -2,17 +2,30 @@ package cache
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/breaker"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
 )
 
+// keyNamespace prefixes every key this cache writes; FlushAll only removes keys under it
+// so other data sharing the Redis database survives
+const keyNamespace = "product:"
+
+// flushScanBatch is the SCAN COUNT hint for FlushAll; small batches keep each call short
+// so Redis keeps serving other clients during a flush
+const flushScanBatch = 500
+
 // CachedReviewsList contains reviews and total count for caching
 type CachedReviewsList struct {
 	Reviews []*domain.Review `json:"reviews"`
@@ -21,30 +34,95 @@ type CachedReviewsList struct {
 
 // RedisCache implements caching for products and reviews
 type RedisCache struct {
-	client           *redis.Client
-	productRatingTTL time.Duration
-	reviewsListTTL   time.Duration
+	client *redis.Client
+	// ttls is swapped as a whole by SetTTLs on config reload
+	ttls      atomic.Pointer[cacheTTLs]
+	ttlJitter float64
+	codec     Codec
+	// compressThreshold is the encoded size from which reviews lists are gzipped; 0 disables
+	compressThreshold int
+	breaker           *breaker.Breaker
+	logger            *logger.Logger
 }
 
-// NewRedisCache creates a new Redis cache instance
-func NewRedisCache(client *redis.Client, productRatingTTL, reviewsListTTL time.Duration) *RedisCache {
-	return &RedisCache{
-		client:           client,
-		productRatingTTL: productRatingTTL,
-		reviewsListTTL:   reviewsListTTL,
+// NewRedisCache creates a new Redis cache instance.
+// ttlJitter spreads each entry's TTL by up to ±ttlJitter (a fraction, e.g. 0.1 for ±10%)
+// so entries written together don't all expire and hit the database at the same moment.
+// After breakerThreshold consecutive Redis failures, calls fail fast with breaker.ErrOpen for
+// breakerCooldown before one is let through to probe; a threshold of 0 disables the breaker.
+// codec serializes review pages and the like (CACHE_CODEC); nil means JSON.
+// Reviews lists whose encoding reaches compressThreshold bytes are stored gzipped; 0 never
+// compresses. Either way they are readable by caches with any threshold.
+func NewRedisCache(
+	client *redis.Client,
+	productRatingTTL, reviewsListTTL, recentReviewsTTL time.Duration,
+	ttlJitter float64,
+	breakerThreshold int,
+	breakerCooldown time.Duration,
+	codec Codec,
+	compressThreshold int,
+	log *logger.Logger,
+) *RedisCache {
+	if codec == nil {
+		codec = jsonCodec{}
 	}
+	c := &RedisCache{
+		client:            client,
+		ttlJitter:         ttlJitter,
+		codec:             codec,
+		compressThreshold: compressThreshold,
+		breaker:           breaker.New("Redis cache", breakerThreshold, breakerCooldown, log),
+		logger:            log,
+	}
+	c.SetTTLs(productRatingTTL, reviewsListTTL, recentReviewsTTL)
+
+	return c
+}
+
+type cacheTTLs struct {
+	productRating time.Duration
+	reviewsList   time.Duration
+	recentReviews time.Duration
+}
+
+// SetTTLs changes the TTLs of entries written from now on; existing entries keep theirs
+func (c *RedisCache) SetTTLs(productRatingTTL, reviewsListTTL, recentReviewsTTL time.Duration) {
+	c.ttls.Store(&cacheTTLs{
+		productRating: productRatingTTL,
+		reviewsList:   reviewsListTTL,
+		recentReviews: recentReviewsTTL,
+	})
+}
+
+// jitteredTTL returns ttl randomly scaled within ±ttlJitter
+func (c *RedisCache) jitteredTTL(ttl time.Duration) time.Duration {
+	if c.ttlJitter <= 0 {
+		return ttl
+	}
+	factor := 1 + c.ttlJitter*(2*rand.Float64()-1)
+	return time.Duration(float64(ttl) * factor)
+}
+
+// do runs a Redis call that only returns an error through the circuit breaker
+func (c *RedisCache) do(fn func() error) error {
+	_, err := guard(c.breaker, func() (struct{}, error) {
+		return struct{}{}, fn()
+	})
+	return err
 }
 
 // Product rating cache keys and methods
 
 func (c *RedisCache) productRatingKey(productID uuid.UUID) string {
-	return fmt.Sprintf("product:%s:rating", productID.String())
+	return fmt.Sprintf(keyNamespace+"%s:rating", productID.String())
 }
 
 // GetProductRating retrieves cached product rating
 func (c *RedisCache) GetProductRating(ctx context.Context, productID uuid.UUID) (float64, error) {
 	key := c.productRatingKey(productID)
-	val, err := c.client.Get(ctx, key).Float64()
+	val, err := guard(c.breaker, func() (float64, error) {
+		return c.client.Get(ctx, key).Float64()
+	})
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
 			return 0, domain.ErrNotFound
@@ -57,82 +135,250 @@ func (c *RedisCache) GetProductRating(ctx context.Context, productID uuid.UUID)
 // SetProductRating stores product rating in cache
 func (c *RedisCache) SetProductRating(ctx context.Context, productID uuid.UUID, rating float64) error {
 	key := c.productRatingKey(productID)
-	return c.client.Set(ctx, key, rating, c.productRatingTTL).Err()
+	return c.do(func() error {
+		return c.client.Set(ctx, key, rating, c.jitteredTTL(c.ttls.Load().productRating)).Err()
+	})
 }
 
 // InvalidateProductRating removes product rating from cache
 func (c *RedisCache) InvalidateProductRating(ctx context.Context, productID uuid.UUID) error {
 	key := c.productRatingKey(productID)
-	return c.client.Del(ctx, key).Err()
+	return c.do(func() error {
+		return c.client.Del(ctx, key).Err()
+	})
 }
 
-// Product reviews list cache keys and methods
+// Product review cache versioning
 
-func (c *RedisCache) reviewsListKey(productID uuid.UUID, limit, offset int) string {
-	return fmt.Sprintf("product:%s:reviews:limit:%d:offset:%d", productID.String(), limit, offset)
+// reviewsVersionSuffix ends every review cache version key
+const reviewsVersionSuffix = ":reviews_version"
+
+// reviewsVersionKey holds the product's review cache version. Review pages, the overview and
+// the summary embed it in their keys, so one INCR makes every entry written under the old
+// version unreachable; the orphans expire on their own TTL. The key has no TTL and FlushAll
+// keeps it: if the counter ever restarted, a later version could match a stale orphan again.
+func (c *RedisCache) reviewsVersionKey(productID uuid.UUID) string {
+	return keyNamespace + productID.String() + reviewsVersionSuffix
 }
 
-func (c *RedisCache) productCacheKeysSet(productID uuid.UUID) string {
-	return fmt.Sprintf("product:%s:cache_keys", productID.String())
+// reviewsVersion returns the product's review cache version; 0 until it is first invalidated
+func (c *RedisCache) reviewsVersion(ctx context.Context, productID uuid.UUID) (int64, error) {
+	version, err := guard(c.breaker, func() (int64, error) {
+		return c.client.Get(ctx, c.reviewsVersionKey(productID)).Int64()
+	})
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	return version, err
 }
 
-// GetReviewsList retrieves cached reviews list and total count for a product
-func (c *RedisCache) GetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, int, error) {
-	key := c.reviewsListKey(productID, limit, offset)
-	val, err := c.client.Get(ctx, key).Result()
+// getVersioned reads the entry key builds for the product's current review cache version
+func (c *RedisCache) getVersioned(ctx context.Context, productID uuid.UUID, key func(version int64) string) ([]byte, error) {
+	version, err := c.reviewsVersion(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	val, err := guard(c.breaker, func() ([]byte, error) {
+		return c.client.Get(ctx, key(version)).Bytes()
+	})
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
-			return nil, 0, domain.ErrNotFound
+			return nil, domain.ErrNotFound
 		}
+		return nil, err
+	}
+	return val, nil
+}
+
+// setVersioned stores an entry under the product's current review cache version. Like the
+// rest of this look-aside cache, data read from the database before an invalidation but
+// stored after it stays stale until its TTL.
+func (c *RedisCache) setVersioned(ctx context.Context, productID uuid.UUID, key func(version int64) string, data []byte) error {
+	version, err := c.reviewsVersion(ctx, productID)
+	if err != nil {
+		return err
+	}
+
+	return c.do(func() error {
+		return c.client.Set(ctx, key(version), data, c.jitteredTTL(c.ttls.Load().reviewsList)).Err()
+	})
+}
+
+// Product reviews list cache keys and methods
+
+// reviewsListKey keys a reviews page; a language-filtered page adds the language, so the
+// unfiltered keys are the same as before the filter existed
+func (c *RedisCache) reviewsListKey(productID uuid.UUID, version int64, language string, limit, offset int) string {
+	if language != "" {
+		return fmt.Sprintf(keyNamespace+"%s:v%d:reviews:language:%s:limit:%d:offset:%d", productID.String(), version, language, limit, offset)
+	}
+	return fmt.Sprintf(keyNamespace+"%s:v%d:reviews:limit:%d:offset:%d", productID.String(), version, limit, offset)
+}
+
+// GetReviewsList retrieves cached reviews list and total count for a product, limited to
+// one language unless language is ""
+func (c *RedisCache) GetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int) ([]*domain.Review, int, error) {
+	val, err := c.getVersioned(ctx, productID, func(version int64) string {
+		return c.reviewsListKey(productID, version, language, limit, offset)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	data, err := decompressPayload(val)
+	if err != nil {
 		return nil, 0, err
 	}
 
 	var cached CachedReviewsList
-	if err := json.Unmarshal([]byte(val), &cached); err != nil {
+	if err := c.codec.Unmarshal(data, &cached); err != nil {
 		return nil, 0, err
 	}
 
 	return cached.Reviews, cached.Total, nil
 }
 
-// SetReviewsList stores reviews list and total count in cache and tracks the key in a SET
-func (c *RedisCache) SetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int, reviews []*domain.Review, total int) error {
-	key := c.reviewsListKey(productID, limit, offset)
-	trackingKey := c.productCacheKeysSet(productID)
-
+// SetReviewsList stores reviews list and total count in cache, gzipped when the encoded
+// list reaches the compression threshold
+func (c *RedisCache) SetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int, reviews []*domain.Review, total int) error {
 	cached := CachedReviewsList{
 		Reviews: reviews,
 		Total:   total,
 	}
 
-	data, err := json.Marshal(cached)
+	data, err := c.codec.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	payload, err := compressPayload(data, c.compressThreshold)
 	if err != nil {
 		return err
 	}
 
-	pipe := c.client.Pipeline()
-	pipe.Set(ctx, key, data, c.reviewsListTTL)
-	pipe.SAdd(ctx, trackingKey, key)
-	pipe.Expire(ctx, trackingKey, c.reviewsListTTL)
-	_, err = pipe.Exec(ctx)
-	return err
+	return c.setVersioned(ctx, productID, func(version int64) string {
+		return c.reviewsListKey(productID, version, language, limit, offset)
+	}, payload)
 }
 
-// InvalidateReviewsList removes all cached review pages for a product using SET-based tracking
-func (c *RedisCache) InvalidateReviewsList(ctx context.Context, productID uuid.UUID) error {
-	trackingKey := c.productCacheKeysSet(productID)
+// Product review overview (detail page) cache keys and methods
+
+func (c *RedisCache) reviewOverviewKey(productID uuid.UUID, version int64, limit int) string {
+	return fmt.Sprintf(keyNamespace+"%s:v%d:overview:limit:%d", productID.String(), version, limit)
+}
+
+// GetReviewOverview retrieves the cached review overview for a product detail page
+func (c *RedisCache) GetReviewOverview(ctx context.Context, productID uuid.UUID, limit int) (*domain.ReviewOverview, error) {
+	val, err := c.getVersioned(ctx, productID, func(version int64) string {
+		return c.reviewOverviewKey(productID, version, limit)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var overview domain.ReviewOverview
+	if err := c.codec.Unmarshal(val, &overview); err != nil {
+		return nil, err
+	}
+
+	return &overview, nil
+}
 
-	keys, err := c.client.SMembers(ctx, trackingKey).Result()
-	if err != nil && !errors.Is(err, redis.Nil) {
+// SetReviewOverview stores a product's review overview
+// Versioned like review pages so the same review writes invalidate it
+func (c *RedisCache) SetReviewOverview(ctx context.Context, productID uuid.UUID, limit int, overview *domain.ReviewOverview) error {
+	data, err := c.codec.Marshal(overview)
+	if err != nil {
 		return err
 	}
 
-	if len(keys) > 0 {
-		keys = append(keys, trackingKey)
-		return c.client.Unlink(ctx, keys...).Err()
+	return c.setVersioned(ctx, productID, func(version int64) string {
+		return c.reviewOverviewKey(productID, version, limit)
+	}, data)
+}
+
+// Product review summary (product card) cache keys and methods
+
+func (c *RedisCache) reviewSummaryKey(productID uuid.UUID, version int64) string {
+	return fmt.Sprintf(keyNamespace+"%s:v%d:summary", productID.String(), version)
+}
+
+// GetReviewSummary retrieves the cached review summary for a product card
+func (c *RedisCache) GetReviewSummary(ctx context.Context, productID uuid.UUID) (*domain.ReviewSummary, error) {
+	val, err := c.getVersioned(ctx, productID, func(version int64) string {
+		return c.reviewSummaryKey(productID, version)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	var summary domain.ReviewSummary
+	if err := c.codec.Unmarshal(val, &summary); err != nil {
+		return nil, err
+	}
+
+	return &summary, nil
+}
+
+// SetReviewSummary stores a product's review summary.
+// Versioned like review pages so review writes and rating recalculations invalidate it.
+func (c *RedisCache) SetReviewSummary(ctx context.Context, productID uuid.UUID, summary *domain.ReviewSummary) error {
+	data, err := c.codec.Marshal(summary)
+	if err != nil {
+		return err
+	}
+
+	return c.setVersioned(ctx, productID, func(version int64) string {
+		return c.reviewSummaryKey(productID, version)
+	}, data)
+}
+
+// Recent reviews feed cache keys and methods
+
+func (c *RedisCache) recentReviewsKey(limit int) string {
+	return fmt.Sprintf(keyNamespace+"recent_reviews:limit:%d", limit)
+}
+
+// GetRecentReviews retrieves the cached cross-product recent reviews feed
+func (c *RedisCache) GetRecentReviews(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	val, err := guard(c.breaker, func() (string, error) {
+		return c.client.Get(ctx, c.recentReviewsKey(limit)).Result()
+	})
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, err
+	}
+
+	var reviews []*domain.RecentReview
+	if err := c.codec.Unmarshal([]byte(val), &reviews); err != nil {
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
+// SetRecentReviews stores the recent reviews feed.
+// Any review write anywhere changes the feed, so instead of tracking it for invalidation
+// it lives for the short recentReviewsTTL and is allowed to lag writes by that much.
+func (c *RedisCache) SetRecentReviews(ctx context.Context, limit int, reviews []*domain.RecentReview) error {
+	data, err := c.codec.Marshal(reviews)
+	if err != nil {
+		return err
+	}
+
+	return c.do(func() error {
+		return c.client.Set(ctx, c.recentReviewsKey(limit), data, c.jitteredTTL(c.ttls.Load().recentReviews)).Err()
+	})
+}
+
+// InvalidateReviewsList makes a product's cached review pages, overview and summary
+// unreachable by bumping its review cache version
+func (c *RedisCache) InvalidateReviewsList(ctx context.Context, productID uuid.UUID) error {
+	return c.do(func() error {
+		return c.client.Incr(ctx, c.reviewsVersionKey(productID)).Err()
+	})
 }
 
 // InvalidateAllProductCache invalidates all cache entries for a product
@@ -147,3 +393,96 @@ func (c *RedisCache) InvalidateAllProductCache(ctx context.Context, productID uu
 
 	return nil
 }
+
+// InvalidateProducts invalidates all cache entries for several products at once, as
+// InvalidateAllProductCache does for one. Bulk operations touch many products, so every
+// product's commands go out in a single pipeline: one round trip instead of two per product.
+// The pipeline isn't a transaction; a failure leaves the rest invalidated and stale entries
+// on the others only until their TTL.
+func (c *RedisCache) InvalidateProducts(ctx context.Context, productIDs []uuid.UUID) error {
+	if len(productIDs) == 0 {
+		return nil
+	}
+
+	return c.do(func() error {
+		pipe := c.client.Pipeline()
+		for _, productID := range productIDs {
+			pipe.Del(ctx, c.productRatingKey(productID))
+			pipe.Incr(ctx, c.reviewsVersionKey(productID))
+		}
+		_, err := pipe.Exec(ctx)
+		return err
+	})
+}
+
+// Review throttle counters
+
+// reviewThrottleKey sits outside keyNamespace: the counters are abuse protection, not
+// cached data, so FlushAll must not reset them
+func (c *RedisCache) reviewThrottleKey(ip string, productID uuid.UUID) string {
+	return fmt.Sprintf("review_throttle:%s:%s", productID, ip)
+}
+
+// IncrReviewAttempts counts a review submission from ip for productID and returns the
+// number of attempts in the current window. The window starts at the first attempt and
+// the counter expires with it.
+func (c *RedisCache) IncrReviewAttempts(ctx context.Context, ip string, productID uuid.UUID, window time.Duration) (int64, error) {
+	key := c.reviewThrottleKey(ip, productID)
+
+	pipe := c.client.TxPipeline()
+	attempts := pipe.Incr(ctx, key)
+	// NX keeps later attempts from sliding the window forward
+	pipe.ExpireNX(ctx, key, window)
+	if err := c.do(func() error {
+		_, err := pipe.Exec(ctx)
+		return err
+	}); err != nil {
+		return 0, fmt.Errorf("failed to count review attempt: %w", err)
+	}
+
+	return attempts.Val(), nil
+}
+
+// FlushAll removes every product and review cache entry and returns how many keys were removed.
+// Uses SCAN over the cache's key namespace instead of FLUSHDB so unrelated keys survive,
+// deleting batch by batch with UNLINK so Redis is never blocked for long. Review cache
+// versions are kept (see reviewsVersionKey); they are counters, not cached data.
+func (c *RedisCache) FlushAll(ctx context.Context) (int64, error) {
+	var (
+		cursor  uint64
+		removed int64
+	)
+
+	for {
+		var next uint64
+		keys, err := guard(c.breaker, func() ([]string, error) {
+			page, pageNext, err := c.client.Scan(ctx, cursor, keyNamespace+"*", flushScanBatch).Result()
+			next = pageNext
+			return page, err
+		})
+		if err != nil {
+			return removed, fmt.Errorf("failed to scan cache keys: %w", err)
+		}
+
+		keys = slices.DeleteFunc(keys, func(key string) bool {
+			return strings.HasSuffix(key, reviewsVersionSuffix)
+		})
+		if len(keys) > 0 {
+			n, err := guard(c.breaker, func() (int64, error) {
+				return c.client.Unlink(ctx, keys...).Result()
+			})
+			if err != nil {
+				return removed, fmt.Errorf("failed to remove cache keys: %w", err)
+			}
+			removed += n
+		}
+
+		cursor = next
+		if cursor == 0 {
+			break
+		}
+	}
+
+	c.logger.Infof("Flushed %d cache keys", removed)
+	return removed, nil
+}
@@ -0,0 +1,336 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// memoryHook serves GET, SET, INCR and DEL from a map instead of Redis and counts the
+// pipelines sent
+type memoryHook struct {
+	values    map[string]string
+	pipelines int
+}
+
+func (h *memoryHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in tests")
+	}
+}
+
+func (h *memoryHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.apply(cmd)
+		return cmd.Err()
+	}
+}
+
+func (h *memoryHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		h.pipelines++
+		for _, cmd := range cmds {
+			h.apply(cmd)
+		}
+		return nil
+	}
+}
+
+func (h *memoryHook) apply(cmd redis.Cmder) {
+	args := cmd.Args()
+	key := args[1].(string)
+	switch c := cmd.(type) {
+	case *redis.StringCmd:
+		if val, ok := h.values[key]; ok {
+			c.SetVal(val)
+		} else {
+			c.SetErr(redis.Nil)
+		}
+	case *redis.StatusCmd:
+		h.values[key] = string(args[2].([]byte))
+		c.SetVal("OK")
+	case *redis.IntCmd:
+		if cmd.Name() == "del" {
+			_, ok := h.values[key]
+			delete(h.values, key)
+			if ok {
+				c.SetVal(1)
+			}
+			return
+		}
+		n, _ := strconv.ParseInt(h.values[key], 10, 64)
+		h.values[key] = strconv.FormatInt(n+1, 10)
+		c.SetVal(n + 1)
+	}
+}
+
+func TestRedisCache_InvalidateReviewsList_BumpsVersion(t *testing.T) {
+	hook := &memoryHook{values: map[string]string{}}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, nil, 0, logger.New("test"))
+	ctx := context.Background()
+	productID := uuid.New()
+	reviews := []*domain.Review{{ID: uuid.New(), ProductID: productID}}
+
+	require.NoError(t, c.SetReviewsList(ctx, productID, "", 10, 0, reviews, 1))
+	require.NoError(t, c.SetReviewSummary(ctx, productID, &domain.ReviewSummary{ReviewCount: 1}))
+	assert.Contains(t, hook.values, "product:"+productID.String()+":v0:reviews:limit:10:offset:0")
+
+	cached, total, err := c.GetReviewsList(ctx, productID, "", 10, 0)
+	require.NoError(t, err)
+	assert.Len(t, cached, 1)
+	assert.Equal(t, 1, total)
+
+	require.NoError(t, c.InvalidateReviewsList(ctx, productID))
+	assert.Equal(t, "1", hook.values["product:"+productID.String()+":reviews_version"])
+
+	_, _, err = c.GetReviewsList(ctx, productID, "", 10, 0)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "pages from the old version should be unreachable")
+	_, err = c.GetReviewSummary(ctx, productID)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "the summary is versioned with the pages")
+
+	require.NoError(t, c.SetReviewsList(ctx, productID, "", 10, 0, reviews, 1))
+	assert.Contains(t, hook.values, "product:"+productID.String()+":v1:reviews:limit:10:offset:0")
+}
+
+func TestRedisCache_InvalidateProducts_OnePipeline(t *testing.T) {
+	hook := &memoryHook{values: map[string]string{}}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, nil, 0, logger.New("test"))
+	ctx := context.Background()
+	productIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, productID := range productIDs {
+		hook.values["product:"+productID.String()+":rating"] = "4.5"
+		require.NoError(t, c.SetReviewsList(ctx, productID, "", 10, 0, []*domain.Review{{ID: uuid.New(), ProductID: productID}}, 1))
+	}
+
+	require.NoError(t, c.InvalidateProducts(ctx, productIDs))
+
+	assert.Equal(t, 1, hook.pipelines, "every product in one round trip")
+	for _, productID := range productIDs {
+		_, err := c.GetProductRating(ctx, productID)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		_, _, err = c.GetReviewsList(ctx, productID, "", 10, 0)
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	}
+
+	require.NoError(t, c.InvalidateProducts(ctx, nil))
+	assert.Equal(t, 1, hook.pipelines, "nothing to send for no products")
+}
+
+func TestRedisCache_ReviewsList_KeyedByLanguage(t *testing.T) {
+	hook := &memoryHook{values: map[string]string{}}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, nil, 0, logger.New("test"))
+	ctx := context.Background()
+	productID := uuid.New()
+	reviews := []*domain.Review{{ID: uuid.New(), ProductID: productID}}
+
+	require.NoError(t, c.SetReviewsList(ctx, productID, "de", 10, 0, reviews, 1))
+	assert.Contains(t, hook.values, "product:"+productID.String()+":v0:reviews:language:de:limit:10:offset:0")
+
+	_, _, err := c.GetReviewsList(ctx, productID, "", 10, 0)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "a language page is not the unfiltered page")
+
+	require.NoError(t, c.InvalidateReviewsList(ctx, productID))
+	_, _, err = c.GetReviewsList(ctx, productID, "de", 10, 0)
+	assert.ErrorIs(t, err, domain.ErrNotFound, "language pages are versioned with the product")
+}
+
+func TestRedisCache_GobCodec(t *testing.T) {
+	hook := &memoryHook{values: map[string]string{}}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	codec, err := NewCodec(CodecGob)
+	require.NoError(t, err)
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, codec, 0, logger.New("test"))
+	ctx := context.Background()
+	productID := uuid.New()
+	reviews := []*domain.Review{{ID: uuid.New(), ProductID: productID, ReviewText: "Great", Rating: 5}}
+
+	require.NoError(t, c.SetReviewsList(ctx, productID, "", 10, 0, reviews, 7))
+	assert.NotContains(t, hook.values["product:"+productID.String()+":v0:reviews:limit:10:offset:0"], `"reviews"`, "stored as gob, not JSON")
+
+	cached, total, err := c.GetReviewsList(ctx, productID, "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, reviews, cached)
+	assert.Equal(t, 7, total)
+}
+
+func TestRedisCache_ReviewsList_CompressedAboveThreshold(t *testing.T) {
+	hook := &memoryHook{values: map[string]string{}}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, nil, 1024, logger.New("test"))
+	ctx := context.Background()
+	productID := uuid.New()
+	key := func(limit int) string {
+		return "product:" + productID.String() + ":v0:reviews:limit:" + strconv.Itoa(limit) + ":offset:0"
+	}
+
+	small := []*domain.Review{{ID: uuid.New(), ProductID: productID, ReviewText: "Great", Rating: 5}}
+	large := make([]*domain.Review, 50)
+	for i := range large {
+		large[i] = &domain.Review{ID: uuid.New(), ProductID: productID, ReviewText: "Works as described, would buy again.", Rating: 4}
+	}
+
+	require.NoError(t, c.SetReviewsList(ctx, productID, "", 1, 0, small, 1))
+	require.NoError(t, c.SetReviewsList(ctx, productID, "", 50, 0, large, 50))
+	assert.Equal(t, payloadPlain, hook.values[key(1)][0], "small pages stay uncompressed")
+	assert.Equal(t, payloadGzip, hook.values[key(50)][0])
+
+	cached, total, err := c.GetReviewsList(ctx, productID, "", 50, 0)
+	require.NoError(t, err)
+	assert.Equal(t, large, cached)
+	assert.Equal(t, 50, total)
+
+	// A cache with compression off still reads what a compressing one wrote
+	reader := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, nil, 0, logger.New("test"))
+	cached, _, err = reader.GetReviewsList(ctx, productID, "", 50, 0)
+	require.NoError(t, err)
+	assert.Len(t, cached, 50)
+}
+
+func TestRedisCache_JitteredTTL(t *testing.T) {
+	ttl := 100 * time.Second
+
+	noJitter := NewRedisCache(nil, ttl, ttl, ttl, 0, 0, 0, nil, 0, logger.New("test"))
+	assert.Equal(t, ttl, noJitter.jitteredTTL(ttl))
+
+	c := NewRedisCache(nil, ttl, ttl, ttl, 0.2, 0, 0, nil, 0, logger.New("test"))
+
+	seen := make(map[time.Duration]bool)
+	for range 100 {
+		got := c.jitteredTTL(ttl)
+		assert.GreaterOrEqual(t, got, 80*time.Second)
+		assert.LessOrEqual(t, got, 120*time.Second)
+		seen[got] = true
+	}
+	assert.Greater(t, len(seen), 1, "TTLs should be spread out")
+}
+
+// recordingHook records single commands' arguments without hitting Redis
+type recordingHook struct {
+	args [][]any
+}
+
+func (h *recordingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in tests")
+	}
+}
+
+func (h *recordingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		h.args = append(h.args, cmd.Args())
+		return nil
+	}
+}
+
+func (h *recordingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRedisCache_SetTTLs(t *testing.T) {
+	hook := &recordingHook{}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, nil, 0, logger.New("test"))
+	productID := uuid.New()
+
+	require.NoError(t, c.SetProductRating(context.Background(), productID, 4.5))
+	c.SetTTLs(10*time.Minute, time.Minute, time.Minute)
+	require.NoError(t, c.SetProductRating(context.Background(), productID, 4.5))
+
+	require.Len(t, hook.args, 2)
+	assert.Equal(t, []any{"ex", int64(60)}, hook.args[0][3:])
+	assert.Equal(t, []any{"ex", int64(600)}, hook.args[1][3:], "writes after SetTTLs should use the new TTL")
+}
+
+// scanPagesHook serves SCAN from canned pages and counts UNLINKed keys without hitting Redis
+type scanPagesHook struct {
+	pages    [][]string
+	patterns []string
+	unlinked []string
+}
+
+func (h *scanPagesHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("dial disabled in tests")
+	}
+}
+
+func (h *scanPagesHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		switch c := cmd.(type) {
+		case *redis.ScanCmd:
+			args := c.Args()
+			page := int(args[1].(uint64))
+			h.patterns = append(h.patterns, args[3].(string))
+
+			var next uint64
+			if page+1 < len(h.pages) {
+				next = uint64(page + 1)
+			}
+			c.SetVal(h.pages[page], next)
+		case *redis.IntCmd:
+			for _, arg := range c.Args()[1:] {
+				h.unlinked = append(h.unlinked, arg.(string))
+			}
+			c.SetVal(int64(len(c.Args()) - 1))
+		}
+		return nil
+	}
+}
+
+func (h *scanPagesHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestRedisCache_FlushAll_ScansInBatches(t *testing.T) {
+	hook := &scanPagesHook{pages: [][]string{
+		{"product:a:rating", "product:a:v2:summary", "product:a:reviews_version"},
+		{},
+		{"product:b:rating"},
+	}}
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	client.AddHook(hook)
+	defer client.Close()
+
+	c := NewRedisCache(client, time.Minute, time.Minute, time.Minute, 0, 0, 0, nil, 0, logger.New("test"))
+
+	removed, err := c.FlushAll(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), removed)
+	assert.Equal(t, []string{"product:a:rating", "product:a:v2:summary", "product:b:rating"}, hook.unlinked,
+		"review cache versions must survive so they are never reused")
+	// Every SCAN stays inside the cache namespace rather than touching the whole database
+	assert.Equal(t, []string{"product:*", "product:*", "product:*"}, hook.patterns)
+}
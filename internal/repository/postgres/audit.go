@@ -0,0 +1,111 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// AuditRepository implements domain.AuditRepository for PostgreSQL
+type AuditRepository struct {
+	db          *sqlx.DB
+	slowQueries *SlowQueryLogger
+}
+
+// NewAuditRepository creates a new PostgreSQL audit repository.
+// slowQueries may be nil to disable slow-query logging.
+func NewAuditRepository(db *sqlx.DB, slowQueries *SlowQueryLogger) *AuditRepository {
+	return &AuditRepository{db: db, slowQueries: slowQueries}
+}
+
+// Record inserts an audit entry, as part of the caller's transaction when ctx carries one
+func (r *AuditRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	defer r.slowQueries.track("audit.Record", map[string]any{"entity_id": entry.EntityID})()
+
+	query := `
+		INSERT INTO audit_log (actor, action, entity_type, entity_id, before_snapshot, after_snapshot)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at
+	`
+
+	err := conn(ctx, r.db).QueryRowxContext(
+		ctx,
+		query,
+		entry.Actor,
+		entry.Action,
+		entry.EntityType,
+		entry.EntityID,
+		snapshotArg(entry.Before),
+		snapshotArg(entry.After),
+	).Scan(&entry.ID, &entry.CreatedAt)
+	return classifyError(err)
+}
+
+// ListByEntityID returns an entity's audit entries, newest first
+func (r *AuditRepository) ListByEntityID(ctx context.Context, entityID uuid.UUID, limit, offset int) ([]*domain.AuditEntry, error) {
+	defer r.slowQueries.track("audit.ListByEntityID", map[string]any{"entity_id": entityID, "limit": limit, "offset": offset})()
+
+	query := `
+		SELECT id, actor, action, entity_type, entity_id, before_snapshot, after_snapshot, created_at
+		FROM audit_log
+		WHERE entity_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	var entries []*domain.AuditEntry
+	err := conn(ctx, r.db).SelectContext(ctx, &entries, query, entityID, limit, offset)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return entries, nil
+}
+
+// CountByEntityID returns the number of audit entries for an entity
+func (r *AuditRepository) CountByEntityID(ctx context.Context, entityID uuid.UUID) (int, error) {
+	defer r.slowQueries.track("audit.CountByEntityID", map[string]any{"entity_id": entityID})()
+
+	query := `SELECT COUNT(*) FROM audit_log WHERE entity_id = $1`
+
+	var count int
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, entityID)
+	if err != nil {
+		return 0, classifyError(err)
+	}
+
+	return count, nil
+}
+
+// Redact overwrites fields in every stored snapshot of an entity.
+// jsonb || replaces top-level keys, so only the listed fields change.
+func (r *AuditRepository) Redact(ctx context.Context, entityID uuid.UUID, fields map[string]any) error {
+	defer r.slowQueries.track("audit.Redact", map[string]any{"entity_id": entityID})()
+
+	patch, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		UPDATE audit_log
+		SET before_snapshot = before_snapshot || $1::jsonb,
+			after_snapshot = after_snapshot || $1::jsonb
+		WHERE entity_id = $2
+	`
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, query, string(patch), entityID)
+	return classifyError(err)
+}
+
+// snapshotArg converts a snapshot to a query argument, keeping a missing snapshot NULL
+func snapshotArg(snapshot *json.RawMessage) any {
+	if snapshot == nil {
+		return nil
+	}
+	return string(*snapshot)
+}
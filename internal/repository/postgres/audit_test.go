@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// newTestAuditSetup returns product and audit repositories plus a transactor sharing one mocked database
+func newTestAuditSetup(t *testing.T) (*ProductRepository, *AuditRepository, *Transactor, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	return NewProductRepository(sqlxDB, nil, nil), NewAuditRepository(sqlxDB, nil), NewTransactor(sqlxDB, 0), mock
+}
+
+func TestAuditRepository_Record_NullSnapshot(t *testing.T) {
+	_, repo, _, mock := newTestAuditSetup(t)
+	entityID := uuid.New()
+	after := json.RawMessage(`{"rating":5}`)
+	entryID := uuid.New()
+
+	mock.ExpectQuery("INSERT INTO audit_log").
+		WithArgs("anonymous", domain.AuditActionCreate, domain.AuditEntityReview, entityID, nil, `{"rating":5}`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(entryID, time.Now()))
+
+	entry := &domain.AuditEntry{
+		Actor:      "anonymous",
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityReview,
+		EntityID:   entityID,
+		After:      &after,
+	}
+	require.NoError(t, repo.Record(context.Background(), entry))
+
+	assert.Equal(t, entryID, entry.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_ListByEntityID(t *testing.T) {
+	_, repo, _, mock := newTestAuditSetup(t)
+	entityID := uuid.New()
+
+	mock.ExpectQuery("SELECT (.+) FROM audit_log").
+		WithArgs(entityID, 50, 0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "actor", "action", "entity_type", "entity_id", "before_snapshot", "after_snapshot", "created_at"}).
+			AddRow(uuid.New(), "admin", domain.AuditActionDelete, domain.AuditEntityProduct, entityID, []byte(`{"name":"Lamp"}`), nil, time.Now()))
+
+	entries, err := repo.ListByEntityID(context.Background(), entityID, 50, 0)
+
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.JSONEq(t, `{"name":"Lamp"}`, string(*entries[0].Before))
+	assert.Nil(t, entries[0].After)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAuditRepository_Redact(t *testing.T) {
+	_, repo, _, mock := newTestAuditSetup(t)
+	entityID := uuid.New()
+
+	mock.ExpectExec("UPDATE audit_log").
+		WithArgs(`{"first_name":"Anonymous"}`, entityID).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	err := repo.Redact(context.Background(), entityID, map[string]any{"first_name": domain.AnonymousName})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinTx_SharesTransaction(t *testing.T) {
+	productRepo, auditRepo, transactor, mock := newTestAuditSetup(t)
+	productID := uuid.New()
+
+	// DeleteWithReviews joins the outer transaction instead of opening its own,
+	// so the delete and its audit row commit together
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE reviews").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec("UPDATE products").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO audit_log").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(uuid.New(), time.Now()))
+	mock.ExpectCommit()
+
+	err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := productRepo.DeleteWithReviews(ctx, productID); err != nil {
+			return err
+		}
+		return auditRepo.Record(ctx, &domain.AuditEntry{Actor: "admin", Action: domain.AuditActionDelete, EntityType: domain.AuditEntityProduct, EntityID: productID})
+	})
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinTx_RollsBackOnError(t *testing.T) {
+	productRepo, auditRepo, transactor, mock := newTestAuditSetup(t)
+	productID := uuid.New()
+	auditErr := errors.New("audit insert failed")
+
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE reviews").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("UPDATE products").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery("INSERT INTO audit_log").WillReturnError(auditErr)
+	mock.ExpectRollback()
+
+	err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		if err := productRepo.DeleteWithReviews(ctx, productID); err != nil {
+			return err
+		}
+		return auditRepo.Record(ctx, &domain.AuditEntry{Actor: "admin", Action: domain.AuditActionDelete, EntityType: domain.AuditEntityProduct, EntityID: productID})
+	})
+
+	assert.ErrorIs(t, err, auditErr)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinTx_RetriesSerializationFailure(t *testing.T) {
+	_, auditRepo, transactor, mock := newTestAuditSetup(t)
+	transactor.serializationRetries = 1
+	entry := &domain.AuditEntry{Actor: "admin", Action: domain.AuditActionUpdate, EntityType: domain.AuditEntityProduct, EntityID: uuid.New()}
+
+	// The commit fails to serialize, so the whole transaction runs again
+	for _, commitErr := range []error{&pq.Error{Code: "40001"}, nil} {
+		mock.ExpectBegin()
+		mock.ExpectQuery("INSERT INTO audit_log").
+			WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(uuid.New(), time.Now()))
+		mock.ExpectCommit().WillReturnError(commitErr)
+	}
+
+	calls := 0
+	err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		calls++
+		return auditRepo.Record(ctx, entry)
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransactor_WithinTx_NestedCallLeavesRetryToOutermost(t *testing.T) {
+	_, _, transactor, mock := newTestAuditSetup(t)
+	transactor.serializationRetries = 1
+	serializationErr := &pq.Error{Code: "40001"}
+
+	// One rerun of the outer transaction, not a retry of the inner call inside an aborted one
+	for range 2 {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+	}
+
+	calls := 0
+	err := transactor.WithinTx(context.Background(), func(ctx context.Context) error {
+		return transactor.WithinTx(ctx, func(ctx context.Context) error {
+			calls++
+			return serializationErr
+		})
+	})
+
+	assert.ErrorIs(t, err, serializationErr)
+	assert.Equal(t, 2, calls)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,46 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// PostgreSQL error codes this package translates into domain errors
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgStringTooLong        = "22001"
+	pgForeignKeyViolation  = "23503"
+	pgUniqueViolation      = "23505"
+	pgCheckViolation       = "23514"
+	pgSerializationFailure = "40001"
+)
+
+// classifyError maps Postgres errors with a known cause to domain errors so the API can
+// answer 404, 409 or 400 instead of a generic 500. The driver error stays wrapped for
+// logging; anything unrecognized (including nil) is returned unchanged.
+func classifyError(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code {
+	case pgUniqueViolation:
+		return fmt.Errorf("%w: %w", domain.ErrAlreadyExists, err)
+	case pgForeignKeyViolation:
+		// The referenced row (e.g. a review's product) is gone
+		return fmt.Errorf("%w: %w", domain.ErrNotFound, err)
+	case pgCheckViolation, pgStringTooLong:
+		// A value too long for its VARCHAR column gets here when a length limit above the
+		// column's (a handler tag, a config change) let it through validation
+		return fmt.Errorf("%w: %w", domain.ErrInvalidInput, err)
+	case pgSerializationFailure:
+		return fmt.Errorf("%w: %w", domain.ErrConflict, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "unique violation", err: &pq.Error{Code: "23505"}, want: domain.ErrAlreadyExists},
+		{name: "foreign key violation", err: &pq.Error{Code: "23503"}, want: domain.ErrNotFound},
+		{name: "check violation", err: &pq.Error{Code: "23514"}, want: domain.ErrInvalidInput},
+		{name: "string data right truncation", err: &pq.Error{Code: "22001"}, want: domain.ErrInvalidInput},
+		{name: "serialization failure", err: &pq.Error{Code: "40001"}, want: domain.ErrConflict},
+		{name: "wrapped driver error", err: fmt.Errorf("query: %w", &pq.Error{Code: "23505"}), want: domain.ErrAlreadyExists},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := classifyError(tc.err)
+
+			assert.ErrorIs(t, err, tc.want)
+			assert.ErrorIs(t, err, tc.err, "the driver error stays wrapped")
+		})
+	}
+}
+
+func TestClassifyError_Unrecognized(t *testing.T) {
+	undefinedTable := &pq.Error{Code: "42P01"}
+	plain := errors.New("connection reset")
+
+	assert.Same(t, undefinedTable, classifyError(undefinedTable))
+	assert.Equal(t, plain, classifyError(plain))
+	assert.NoError(t, classifyError(nil))
+}
@@ -0,0 +1,125 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// OutboxRepository implements domain.OutboxRepository for PostgreSQL
+type OutboxRepository struct {
+	db          *sqlx.DB
+	slowQueries *SlowQueryLogger
+}
+
+// NewOutboxRepository creates a new PostgreSQL outbox repository.
+// slowQueries may be nil to disable slow-query logging.
+func NewOutboxRepository(db *sqlx.DB, slowQueries *SlowQueryLogger) *OutboxRepository {
+	return &OutboxRepository{db: db, slowQueries: slowQueries}
+}
+
+// Add inserts an event, as part of the caller's transaction when ctx carries one
+func (r *OutboxRepository) Add(ctx context.Context, subject string, payload []byte) error {
+	defer r.slowQueries.track("outbox.Add", map[string]any{"subject": subject})()
+
+	query := `INSERT INTO events_outbox (subject, payload) VALUES ($1, $2)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, subject, string(payload))
+	return classifyError(err)
+}
+
+// Claim leases up to limit unsent events, oldest first, by stamping claimed_until (migration
+// 000018). It is one statement, so no lock outlives it: SKIP LOCKED only keeps two relays
+// claiming at the same moment from taking the same rows, and the lease keeps the others off
+// them afterwards. Times are on the database clock, like created_at.
+func (r *OutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	defer r.slowQueries.track("outbox.Claim", map[string]any{"limit": limit})()
+
+	// RETURNING doesn't keep the subquery's order, hence the outer ORDER BY
+	query := `
+		WITH claimed AS (
+			UPDATE events_outbox
+			SET claimed_until = LOCALTIMESTAMP + make_interval(secs => $2)
+			WHERE id IN (
+				SELECT id
+				FROM events_outbox
+				WHERE sent_at IS NULL AND (claimed_until IS NULL OR claimed_until < LOCALTIMESTAMP)
+				ORDER BY id
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, subject, payload, created_at, sent_at
+		)
+		SELECT id, subject, payload, created_at, sent_at FROM claimed ORDER BY id
+	`
+
+	var events []*domain.OutboxEvent
+	if err := conn(ctx, r.db).SelectContext(ctx, &events, query, limit, lease.Seconds()); err != nil {
+		return nil, classifyError(err)
+	}
+	return events, nil
+}
+
+// MarkSent stamps events as published
+func (r *OutboxRepository) MarkSent(ctx context.Context, ids []int64) error {
+	defer r.slowQueries.track("outbox.MarkSent", map[string]any{"count": len(ids)})()
+
+	query := `UPDATE events_outbox SET sent_at = NOW() WHERE id = ANY($1)`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array(ids))
+	return classifyError(err)
+}
+
+// Release clears the lease on events that weren't published
+func (r *OutboxRepository) Release(ctx context.Context, ids []int64) error {
+	defer r.slowQueries.track("outbox.Release", map[string]any{"count": len(ids)})()
+
+	query := `UPDATE events_outbox SET claimed_until = NULL WHERE id = ANY($1) AND sent_at IS NULL`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, pq.Array(ids))
+	return classifyError(err)
+}
+
+// DeleteSentBefore removes events published before the cutoff and returns how many
+func (r *OutboxRepository) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	defer r.slowQueries.track("outbox.DeleteSentBefore", map[string]any{"cutoff": cutoff})()
+
+	query := `DELETE FROM events_outbox WHERE sent_at < $1`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, cutoff)
+	if err != nil {
+		return 0, classifyError(err)
+	}
+
+	return result.RowsAffected()
+}
+
+// Lag counts unsent events and measures the oldest one's wait on the database clock, so
+// the result doesn't depend on the caller's clock being in sync
+func (r *OutboxRepository) Lag(ctx context.Context) (*domain.OutboxLag, error) {
+	defer r.slowQueries.track("outbox.Lag", nil)()
+
+	query := `
+		SELECT COUNT(*) AS pending,
+		       COALESCE(EXTRACT(EPOCH FROM LOCALTIMESTAMP - MIN(created_at)), 0) AS oldest_age_seconds
+		FROM events_outbox
+		WHERE sent_at IS NULL
+	`
+
+	var row struct {
+		Pending          int64   `db:"pending"`
+		OldestAgeSeconds float64 `db:"oldest_age_seconds"`
+	}
+	if err := conn(ctx, r.db).GetContext(ctx, &row, query); err != nil {
+		return nil, classifyError(err)
+	}
+
+	return &domain.OutboxLag{
+		Pending:   row.Pending,
+		OldestAge: time.Duration(row.OldestAgeSeconds * float64(time.Second)),
+	}, nil
+}
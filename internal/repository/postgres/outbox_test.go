@@ -0,0 +1,93 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestOutboxSetup(t *testing.T) (*OutboxRepository, *Transactor, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	return NewOutboxRepository(sqlxDB, nil), NewTransactor(sqlxDB, 0), mock
+}
+
+func TestOutboxRepository_Add_JoinsTransaction(t *testing.T) {
+	repo, tx, mock := newTestOutboxSetup(t)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO events_outbox").
+		WithArgs("reviews.events", `{"event_type":"review.created"}`).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	err := tx.WithinTx(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, repo.Add(ctx, "reviews.events", []byte(`{"event_type":"review.created"}`)))
+		return assert.AnError
+	})
+
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NoError(t, mock.ExpectationsWereMet(), "the event must roll back with the change")
+}
+
+func TestOutboxRepository_ClaimAndMarkSent(t *testing.T) {
+	repo, _, mock := newTestOutboxSetup(t)
+	now := time.Now()
+
+	// The lease is stamped in the claiming statement; rows under a live lease are skipped
+	mock.ExpectQuery(`SET claimed_until = LOCALTIMESTAMP \+ make_interval\(secs => \$2\)[\s\S]+`+
+		`WHERE sent_at IS NULL AND \(claimed_until IS NULL OR claimed_until < LOCALTIMESTAMP\)[\s\S]+`+
+		`FOR UPDATE SKIP LOCKED[\s\S]+FROM claimed ORDER BY id`).
+		WithArgs(100, 60.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "subject", "payload", "created_at", "sent_at"}).
+			AddRow(int64(7), "reviews.events", []byte(`{"n":1}`), now, nil).
+			AddRow(int64(8), "reviews.events", []byte(`{"n":2}`), now, nil))
+	mock.ExpectExec("UPDATE events_outbox SET sent_at = NOW()").
+		WithArgs(pq.Array([]int64{7, 8})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	events, err := repo.Claim(context.Background(), 100, time.Minute)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, int64(7), events[0].ID)
+	assert.Equal(t, `{"n":1}`, string(events[0].Payload))
+
+	require.NoError(t, repo.MarkSent(context.Background(), []int64{events[0].ID, events[1].ID}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_Release(t *testing.T) {
+	repo, _, mock := newTestOutboxSetup(t)
+
+	mock.ExpectExec(`UPDATE events_outbox SET claimed_until = NULL WHERE id = ANY\(\$1\) AND sent_at IS NULL`).
+		WithArgs(pq.Array([]int64{9, 10})).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	require.NoError(t, repo.Release(context.Background(), []int64{9, 10}))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestOutboxRepository_Lag(t *testing.T) {
+	repo, _, mock := newTestOutboxSetup(t)
+
+	mock.ExpectQuery(`SELECT COUNT\(\*\) AS pending`).
+		WillReturnRows(sqlmock.NewRows([]string{"pending", "oldest_age_seconds"}).AddRow(int64(12), 90.5))
+
+	lag, err := repo.Lag(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), lag.Pending)
+	assert.Equal(t, 90500*time.Millisecond, lag.OldestAge)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -8,43 +8,67 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/idgen"
 )
 
 // ProductRepository implements domain.ProductRepository for PostgreSQL
 type ProductRepository struct {
-	db *sqlx.DB
+	db          *sqlx.DB
+	slowQueries *SlowQueryLogger
+	newID       idgen.Generator
 }
 
-// NewProductRepository creates a new PostgreSQL product repository
-func NewProductRepository(db *sqlx.DB) *ProductRepository {
-	return &ProductRepository{db: db}
+// NewProductRepository creates a new PostgreSQL product repository.
+// slowQueries may be nil to disable slow-query logging.
+// newID generates the IDs of new products that don't bring one (DB_ID_STRATEGY); nil means
+// random v4 UUIDs.
+func NewProductRepository(db *sqlx.DB, slowQueries *SlowQueryLogger, newID idgen.Generator) *ProductRepository {
+	if newID == nil {
+		newID = idgen.New(idgen.StrategyV4)
+	}
+	return &ProductRepository{db: db, slowQueries: slowQueries, newID: newID}
+}
+
+// idFor returns the ID to insert a new product with: the caller's if set, else a generated one
+func (r *ProductRepository) idFor(id uuid.UUID) uuid.UUID {
+	if id != uuid.Nil {
+		return id
+	}
+	return r.newID()
 }
 
-// Create creates a new product
+// Create creates a new product, keeping product.ID if the caller set one.
+// An ID that is already taken fails with domain.ErrAlreadyExists.
 func (r *ProductRepository) Create(ctx context.Context, product *domain.Product) error {
+	defer r.slowQueries.track("product.Create", nil)()
+
 	query := `
-		INSERT INTO products (name, description, price)
-		VALUES ($1, $2, $3)
-		RETURNING id, average_rating, version, created_at, updated_at
+		INSERT INTO products (id, name, description, price, reviews_enabled)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, average_rating, review_count, version, created_at, updated_at
 	`
 
-	err := r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
+		r.idFor(product.ID),
 		product.Name,
 		product.Description,
 		product.Price,
+		product.ReviewsEnabled,
 	).Scan(
 		&product.ID,
 		&product.AverageRating,
+		&product.ReviewCount,
 		&product.Version,
 		&product.CreatedAt,
 		&product.UpdatedAt,
 	)
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	return nil
@@ -52,70 +76,192 @@ func (r *ProductRepository) Create(ctx context.Context, product *domain.Product)
 
 // GetByID retrieves a product by ID
 func (r *ProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	defer r.slowQueries.track("product.GetByID", map[string]any{"product_id": id})()
+
 	query := `
-		SELECT id, name, description, price, average_rating, version, created_at, updated_at, deleted_at
+		SELECT id, name, description, price, average_rating, review_count, reviews_enabled, version, created_at, updated_at, deleted_at
 		FROM products
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var product domain.Product
-	err := r.db.GetContext(ctx, &product, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &product, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	return &product, nil
 }
 
+// GetByIDs retrieves products by ID in one query, keyed by ID.
+// Missing and soft-deleted products are absent from the map rather than an error.
+func (r *ProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Product, error) {
+	if len(ids) == 0 {
+		return map[uuid.UUID]*domain.Product{}, nil
+	}
+	defer r.slowQueries.track("product.GetByIDs", map[string]any{"count": len(ids)})()
+
+	query := `
+		SELECT id, name, description, price, average_rating, review_count, reviews_enabled, version, created_at, updated_at, deleted_at
+		FROM products
+		WHERE id = ANY($1) AND deleted_at IS NULL
+	`
+
+	var products []*domain.Product
+	if err := conn(ctx, r.db).SelectContext(ctx, &products, query, pq.Array(ids)); err != nil {
+		return nil, classifyError(err)
+	}
+
+	byID := make(map[uuid.UUID]*domain.Product, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+
+	return byID, nil
+}
+
 // List retrieves a paginated list of products
 func (r *ProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
+	defer r.slowQueries.track("product.List", map[string]any{"limit": limit, "offset": offset})()
+
 	query := `
-		SELECT id, name, description, price, average_rating, version, created_at, updated_at, deleted_at
+		SELECT id, name, description, price, average_rating, review_count, reviews_enabled, version, created_at, updated_at, deleted_at
 		FROM products
 		WHERE deleted_at IS NULL
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, id DESC
 		LIMIT $1 OFFSET $2
 	`
 
 	var products []*domain.Product
-	err := r.db.SelectContext(ctx, &products, query, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &products, query, limit, offset)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	return products, nil
 }
 
-// Update updates an existing product
+// productWithTotal is a product row carrying the COUNT(*) OVER () window total
+type productWithTotal struct {
+	domain.Product
+	Total int `db:"total_count"`
+}
+
+// ListWithTotal returns a page of products and the total count in one round trip.
+// The window count is evaluated before LIMIT, so every row carries the full total.
+// A page past the end has no rows to carry it, so that case falls back to Count.
+func (r *ProductRepository) ListWithTotal(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
+	defer r.slowQueries.track("product.ListWithTotal", map[string]any{"limit": limit, "offset": offset})()
+
+	query := `
+		SELECT id, name, description, price, average_rating, review_count, reviews_enabled, version, created_at, updated_at, deleted_at,
+			COUNT(*) OVER () AS total_count
+		FROM products
+		WHERE deleted_at IS NULL
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	var rows []productWithTotal
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, limit, offset); err != nil {
+		return nil, 0, classifyError(err)
+	}
+
+	if len(rows) == 0 {
+		if offset == 0 {
+			return []*domain.Product{}, 0, nil
+		}
+		total, err := r.Count(ctx)
+		if err != nil {
+			return nil, 0, classifyError(err)
+		}
+		return []*domain.Product{}, total, nil
+	}
+
+	products := make([]*domain.Product, len(rows))
+	for i := range rows {
+		products[i] = &rows[i].Product
+	}
+
+	return products, rows[0].Total, nil
+}
+
+// ListUnreviewed returns a page of products without reviews and their total, like
+// ListWithTotal. It trusts the review_count the rating worker maintains rather than
+// joining reviews, so a product whose recalculation is still pending may show up (or be
+// missed) for a moment.
+func (r *ProductRepository) ListUnreviewed(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
+	defer r.slowQueries.track("product.ListUnreviewed", map[string]any{"limit": limit, "offset": offset})()
+
+	query := `
+		SELECT id, name, description, price, average_rating, review_count, reviews_enabled, version, created_at, updated_at, deleted_at,
+			COUNT(*) OVER () AS total_count
+		FROM products
+		WHERE deleted_at IS NULL AND review_count = 0
+		ORDER BY created_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	var rows []productWithTotal
+	if err := conn(ctx, r.db).SelectContext(ctx, &rows, query, limit, offset); err != nil {
+		return nil, 0, classifyError(err)
+	}
+
+	if len(rows) == 0 {
+		if offset == 0 {
+			return []*domain.Product{}, 0, nil
+		}
+		var total int
+		countQuery := `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL AND review_count = 0`
+		if err := conn(ctx, r.db).GetContext(ctx, &total, countQuery); err != nil {
+			return nil, 0, classifyError(err)
+		}
+		return []*domain.Product{}, total, nil
+	}
+
+	products := make([]*domain.Product, len(rows))
+	for i := range rows {
+		products[i] = &rows[i].Product
+	}
+
+	return products, rows[0].Total, nil
+}
+
+// Update updates an existing product's user-editable fields.
+// Derived fields are read back rather than written, so the response reflects the
+// worker's latest rating instead of whatever the client sent.
 func (r *ProductRepository) Update(ctx context.Context, product *domain.Product) error {
+	defer r.slowQueries.track("product.Update", map[string]any{"product_id": product.ID})()
+
 	query := `
 		UPDATE products
-		SET name = $1, description = $2, price = $3, updated_at = $4, version = version + 1
-		WHERE id = $5 AND deleted_at IS NULL AND version = $6
-		RETURNING version, updated_at
+		SET name = $1, description = $2, price = $3, reviews_enabled = $4, updated_at = $5, version = version + 1
+		WHERE id = $6 AND deleted_at IS NULL AND version = $7
+		RETURNING version, updated_at, created_at, average_rating, review_count
 	`
 
 	product.UpdatedAt = time.Now()
 	oldVersion := product.Version
 
-	err := r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		product.Name,
 		product.Description,
 		product.Price,
+		product.ReviewsEnabled,
 		product.UpdatedAt,
 		product.ID,
 		oldVersion,
-	).Scan(&product.Version, &product.UpdatedAt)
+	).Scan(&product.Version, &product.UpdatedAt, &product.CreatedAt, &product.AverageRating, &product.ReviewCount)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.ErrConflict
 		}
-		return err
+		return classifyError(err)
 	}
 
 	return nil
@@ -123,20 +269,22 @@ func (r *ProductRepository) Update(ctx context.Context, product *domain.Product)
 
 // Delete soft-deletes a product
 func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer r.slowQueries.track("product.Delete", map[string]any{"product_id": id})()
+
 	query := `
 		UPDATE products
 		SET deleted_at = $1
 		WHERE id = $2 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	if rowsAffected == 0 {
@@ -148,58 +296,84 @@ func (r *ProductRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 // DeleteWithReviews soft-deletes a product and all its reviews in a single transaction
 // Uses the same timestamp for both operations to ensure consistency
+// Joins the caller's transaction when ctx carries one
 func (r *ProductRepository) DeleteWithReviews(ctx context.Context, id uuid.UUID) error {
-	tx, err := r.db.BeginTxx(ctx, nil)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = tx.Rollback() }()
-
-	deletedAt := time.Now()
-
-	// Delete all reviews for the product first
-	reviewQuery := `
-		UPDATE reviews
-		SET deleted_at = $1
-		WHERE product_id = $2 AND deleted_at IS NULL
-	`
-	_, err = tx.ExecContext(ctx, reviewQuery, deletedAt, id)
-	if err != nil {
-		return err
-	}
+	defer r.slowQueries.track("product.DeleteWithReviews", map[string]any{"product_id": id})()
+
+	return withinTx(ctx, r.db, func(ctx context.Context) error {
+		tx := conn(ctx, r.db)
+		deletedAt := time.Now()
+
+		// Delete all reviews for the product first
+		reviewQuery := `
+			UPDATE reviews
+			SET deleted_at = $1, updated_at = $1
+			WHERE product_id = $2 AND deleted_at IS NULL
+		`
+		_, err := tx.ExecContext(ctx, reviewQuery, deletedAt, id)
+		if err != nil {
+			return classifyError(err)
+		}
 
-	// Delete the product
-	productQuery := `
-		UPDATE products
-		SET deleted_at = $1
-		WHERE id = $2 AND deleted_at IS NULL
-	`
-	result, err := tx.ExecContext(ctx, productQuery, deletedAt, id)
-	if err != nil {
-		return err
-	}
+		// Delete the product
+		productQuery := `
+			UPDATE products
+			SET deleted_at = $1
+			WHERE id = $2 AND deleted_at IS NULL
+		`
+		result, err := tx.ExecContext(ctx, productQuery, deletedAt, id)
+		if err != nil {
+			return classifyError(err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return err
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return classifyError(err)
+		}
 
-	if rowsAffected == 0 {
-		return domain.ErrNotFound
-	}
+		if rowsAffected == 0 {
+			return domain.ErrNotFound
+		}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // Count returns the total number of products
 func (r *ProductRepository) Count(ctx context.Context) (int, error) {
+	defer r.slowQueries.track("product.Count", nil)()
+
 	query := `SELECT COUNT(*) FROM products WHERE deleted_at IS NULL`
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query)
 	if err != nil {
-		return 0, err
+		return 0, classifyError(err)
 	}
 
 	return count, nil
 }
+
+// NameTaken reports whether a live product other than excludeID is named name.
+// Names are only unique when ENFORCE_UNIQUE_PRODUCT_NAME is on, so there's no index to
+// back the check; instead it takes a transaction-scoped advisory lock on the name first,
+// and a concurrent writer of the same name waits for this transaction to commit. The lock
+// is a separate statement so the check's snapshot includes whatever that writer committed.
+// Outside a transaction the lock is released straight away.
+func (r *ProductRepository) NameTaken(ctx context.Context, name string, excludeID uuid.UUID) (bool, error) {
+	defer r.slowQueries.track("product.NameTaken", map[string]any{"product_id": excludeID})()
+
+	db := conn(ctx, r.db)
+	if _, err := db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('products.name:' || $1))`, name); err != nil {
+		return false, classifyError(err)
+	}
+
+	query := `SELECT EXISTS (SELECT 1 FROM products WHERE name = $1 AND id <> $2 AND deleted_at IS NULL)`
+
+	var taken bool
+	if err := db.GetContext(ctx, &taken, query, name, excludeID); err != nil {
+		return false, classifyError(err)
+	}
+
+	return taken, nil
+}
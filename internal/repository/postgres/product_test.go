@@ -0,0 +1,267 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/idgen"
+)
+
+func newTestProductRepository(t *testing.T) (*ProductRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewProductRepository(sqlx.NewDb(db, "sqlmock"), nil, nil), mock
+}
+
+func TestProductRepository_Create_UsesIDGenerator(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	id := idgen.NewV7()
+	repo := NewProductRepository(sqlx.NewDb(db, "sqlmock"), nil, func() uuid.UUID { return id })
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO products").
+		WithArgs(id, "Widget", nil, 10.0, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "average_rating", "review_count", "version", "created_at", "updated_at"}).
+			AddRow(id, 0, 0, 1, now, now))
+
+	product := &domain.Product{Name: "Widget", Price: 10}
+	require.NoError(t, repo.Create(context.Background(), product))
+
+	assert.Equal(t, id, product.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_Create_KeepsCallerID(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	id := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO products").
+		WithArgs(id, "Widget", nil, 10.0, false).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "average_rating", "review_count", "version", "created_at", "updated_at"}).
+			AddRow(id, 0, 0, 1, now, now))
+
+	product := &domain.Product{ID: id, Name: "Widget", Price: 10}
+	require.NoError(t, repo.Create(context.Background(), product))
+
+	assert.Equal(t, id, product.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_NameTaken(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	id := uuid.New()
+
+	// The name is locked before the check, so concurrent writers of it queue up
+	mock.ExpectExec(`SELECT pg_advisory_xact_lock\(hashtext\('products.name:' \|\| \$1\)\)`).
+		WithArgs("Widget").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT EXISTS \(SELECT 1 FROM products WHERE name = \$1 AND id <> \$2 AND deleted_at IS NULL\)`).
+		WithArgs("Widget", id).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	taken, err := repo.NameTaken(context.Background(), "Widget", id)
+
+	require.NoError(t, err)
+	assert.True(t, taken)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_Update_ReadsBackDerivedFields(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	product := &domain.Product{ID: uuid.New(), Name: "Widget", Price: 10, ReviewsEnabled: true, Version: 3}
+	now := time.Now()
+
+	// Only user-editable fields are written; rating and count come back from the row
+	mock.ExpectQuery(`SET name = \$1, description = \$2, price = \$3, reviews_enabled = \$4, updated_at = \$5, version = version \+ 1`).
+		WithArgs(product.Name, product.Description, product.Price, true, sqlmock.AnyArg(), product.ID, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "updated_at", "created_at", "average_rating", "review_count"}).
+			AddRow(4, now, now, 4.5, 12))
+
+	err := repo.Update(context.Background(), product)
+
+	require.NoError(t, err)
+	assert.Equal(t, 4, product.Version)
+	assert.Equal(t, 4.5, product.AverageRating)
+	assert.Equal(t, 12, product.ReviewCount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_GetByIDs_KeysByID(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	now := time.Now()
+	first, second := uuid.New(), uuid.New()
+
+	columns := []string{"id", "name", "description", "price", "average_rating", "review_count", "version", "created_at", "updated_at", "deleted_at"}
+	mock.ExpectQuery(`WHERE id = ANY\(\$1\) AND deleted_at IS NULL`).
+		WithArgs(pq.Array([]uuid.UUID{first, second})).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(second, "Second", nil, 5.0, 0.0, 0, 1, now, now, nil).
+			AddRow(first, "First", nil, 10.0, 4.5, 2, 1, now, now, nil))
+
+	products, err := repo.GetByIDs(context.Background(), []uuid.UUID{first, second})
+
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "First", products[first].Name)
+	assert.Equal(t, "Second", products[second].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_GetByIDs_OmitsMissing(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	now := time.Now()
+	found, missing := uuid.New(), uuid.New()
+
+	columns := []string{"id", "name", "description", "price", "average_rating", "review_count", "version", "created_at", "updated_at", "deleted_at"}
+	mock.ExpectQuery(`WHERE id = ANY\(\$1\) AND deleted_at IS NULL`).
+		WithArgs(pq.Array([]uuid.UUID{found, missing})).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(found, "Found", nil, 10.0, 0.0, 0, 1, now, now, nil))
+
+	products, err := repo.GetByIDs(context.Background(), []uuid.UUID{found, missing})
+
+	require.NoError(t, err)
+	assert.Len(t, products, 1)
+	assert.Contains(t, products, found)
+	assert.NotContains(t, products, missing)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_GetByIDs_EmptySkipsQuery(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+
+	products, err := repo.GetByIDs(context.Background(), nil)
+
+	require.NoError(t, err)
+	assert.NotNil(t, products)
+	assert.Empty(t, products)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_GetByIDs_QueryError(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	dbErr := errors.New("connection reset")
+
+	mock.ExpectQuery(`WHERE id = ANY`).WillReturnError(dbErr)
+
+	products, err := repo.GetByIDs(context.Background(), []uuid.UUID{uuid.New()})
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Nil(t, products)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_ListWithTotal(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	now := time.Now()
+	first, second := uuid.New(), uuid.New()
+
+	columns := []string{"id", "name", "description", "price", "average_rating", "review_count", "version", "created_at", "updated_at", "deleted_at", "total_count"}
+	mock.ExpectQuery(`COUNT\(\*\) OVER \(\) AS total_count`).
+		WithArgs(2, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(first, "First", nil, 10.0, 4.5, 2, 1, now, now, nil, 7).
+			AddRow(second, "Second", nil, 5.0, 0.0, 0, 1, now, now, nil, 7))
+
+	products, total, err := repo.ListWithTotal(context.Background(), 2, 0)
+
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, first, products[0].ID)
+	assert.Equal(t, "Second", products[1].Name)
+	assert.Equal(t, 7, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_ListWithTotal_EmptyCatalog(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+
+	columns := []string{"id", "name", "description", "price", "average_rating", "review_count", "version", "created_at", "updated_at", "deleted_at", "total_count"}
+	mock.ExpectQuery(`COUNT\(\*\) OVER \(\)`).
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows(columns))
+
+	products, total, err := repo.ListWithTotal(context.Background(), 20, 0)
+
+	require.NoError(t, err)
+	assert.NotNil(t, products)
+	assert.Empty(t, products)
+	assert.Equal(t, 0, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_ListWithTotal_PastLastPageCounts(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+
+	columns := []string{"id", "name", "description", "price", "average_rating", "review_count", "version", "created_at", "updated_at", "deleted_at", "total_count"}
+	mock.ExpectQuery(`COUNT\(\*\) OVER \(\)`).
+		WithArgs(20, 100).
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM products`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(42))
+
+	products, total, err := repo.ListWithTotal(context.Background(), 20, 100)
+
+	require.NoError(t, err)
+	assert.Empty(t, products)
+	assert.Equal(t, 42, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_ListUnreviewed(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+	now := time.Now()
+	id := uuid.New()
+
+	columns := []string{"id", "name", "description", "price", "average_rating", "review_count", "reviews_enabled", "version", "created_at", "updated_at", "deleted_at", "total_count"}
+	mock.ExpectQuery(`WHERE deleted_at IS NULL AND review_count = 0\s+ORDER BY created_at DESC, id DESC`).
+		WithArgs(20, 0).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(id, "Lonely", nil, 10.0, 0.0, 0, true, 1, now, now, nil, 3))
+
+	products, total, err := repo.ListUnreviewed(context.Background(), 20, 0)
+
+	require.NoError(t, err)
+	require.Len(t, products, 1)
+	assert.Equal(t, id, products[0].ID)
+	assert.Equal(t, 3, total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestProductRepository_ListUnreviewed_PastLastPageCounts(t *testing.T) {
+	repo, mock := newTestProductRepository(t)
+
+	columns := []string{"id", "name", "description", "price", "average_rating", "review_count", "reviews_enabled", "version", "created_at", "updated_at", "deleted_at", "total_count"}
+	mock.ExpectQuery(`COUNT\(\*\) OVER \(\)`).
+		WithArgs(20, 100).
+		WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM products WHERE deleted_at IS NULL AND review_count = 0`).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(5))
+
+	products, total, err := repo.ListUnreviewed(context.Background(), 20, 100)
+
+	require.NoError(t, err)
+	assert.Empty(t, products)
+	assert.Equal(t, 5, total, "the total counts unreviewed products only")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -4,97 +4,173 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/idgen"
 )
 
 // ReviewRepository implements domain.ReviewRepository for PostgreSQL
 type ReviewRepository struct {
-	db *sqlx.DB
+	db          *sqlx.DB
+	slowQueries *SlowQueryLogger
+	newID       idgen.Generator
 }
 
-// NewReviewRepository creates a new PostgreSQL review repository
-func NewReviewRepository(db *sqlx.DB) *ReviewRepository {
-	return &ReviewRepository{db: db}
+// NewReviewRepository creates a new PostgreSQL review repository.
+// slowQueries may be nil to disable slow-query logging.
+// newID generates the IDs of new reviews that don't bring one (DB_ID_STRATEGY); nil means
+// random v4 UUIDs.
+func NewReviewRepository(db *sqlx.DB, slowQueries *SlowQueryLogger, newID idgen.Generator) *ReviewRepository {
+	if newID == nil {
+		newID = idgen.New(idgen.StrategyV4)
+	}
+	return &ReviewRepository{db: db, slowQueries: slowQueries, newID: newID}
 }
 
-// Create creates a new review
-func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
-	// Return domain.ErrNotFound instead of cryptic foreign key constraint violation
-	var exists bool
-	checkQuery := `SELECT EXISTS(SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`
-	err := r.db.GetContext(ctx, &exists, checkQuery, review.ProductID)
-	if err != nil {
-		return err
-	}
-	if !exists {
-		return domain.ErrNotFound
+// idFor returns the ID to insert a new review with: the caller's if set, else a generated one
+func (r *ReviewRepository) idFor(id uuid.UUID) uuid.UUID {
+	if id != uuid.Nil {
+		return id
 	}
+	return r.newID()
+}
+
+// Create creates a new review, keeping review.ID if the caller set one.
+// An ID that is already taken fails with domain.ErrAlreadyExists.
+func (r *ReviewRepository) Create(ctx context.Context, review *domain.Review) error {
+	defer r.slowQueries.track("review.Create", map[string]any{"product_id": review.ProductID})()
 
+	// Insert only while the product is live and open for reviews, in one statement so a
+	// concurrent delete or reviews toggle can't slip between a check and the INSERT.
+	// FOR SHARE makes a concurrent soft-delete either wait for us or be seen, and the FK
+	// covers rows that are gone entirely.
 	query := `
-		INSERT INTO reviews (product_id, first_name, last_name, review_text, rating)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO reviews (id, product_id, first_name, last_name, email_hash, title, language, review_text, rating, source)
+		SELECT $1, p.id, $3, $4, $5, $6, $7, $8, $9::numeric, $10
+		FROM products p
+		WHERE p.id = $2 AND p.deleted_at IS NULL AND p.reviews_enabled
+		FOR SHARE
 		RETURNING id, created_at, updated_at
 	`
 
-	err = r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
+		r.idFor(review.ID),
 		review.ProductID,
 		review.FirstName,
 		review.LastName,
+		review.EmailHash,
+		review.Title,
+		review.Language,
 		review.ReviewText,
 		review.Rating,
+		review.Source,
 	).Scan(
 		&review.ID,
 		&review.CreatedAt,
 		&review.UpdatedAt,
 	)
 	if err != nil {
-		return err
+		// No row means the product doesn't exist, is soft-deleted or has reviews disabled
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.rejectionReason(ctx, review.ProductID)
+		}
+		return classifyError(err)
 	}
 
 	return nil
 }
 
+// rejectionReason tells why Create inserted nothing: a live product means its reviews were
+// disabled. It only picks the error to report; the INSERT already refused the review, so a
+// toggle racing this read can't let one in.
+func (r *ReviewRepository) rejectionReason(ctx context.Context, productID uuid.UUID) error {
+	var exists bool
+	query := `SELECT EXISTS (SELECT 1 FROM products WHERE id = $1 AND deleted_at IS NULL)`
+	if err := conn(ctx, r.db).GetContext(ctx, &exists, query, productID); err != nil {
+		return classifyError(err)
+	}
+	if !exists {
+		return domain.ErrNotFound
+	}
+	return domain.ErrReviewsDisabled
+}
+
 // GetByID retrieves a review by ID
 func (r *ReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	defer r.slowQueries.track("review.GetByID", map[string]any{"review_id": id})()
+
 	query := `
-		SELECT id, product_id, first_name, last_name, review_text, rating, created_at, updated_at, deleted_at
+		SELECT id, product_id, first_name, last_name, email_hash, title, language, review_text, rating, source, created_at, updated_at, deleted_at
 		FROM reviews
 		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var review domain.Review
-	err := r.db.GetContext(ctx, &review, query, id)
+	err := conn(ctx, r.db).GetContext(ctx, &review, query, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, domain.ErrNotFound
 		}
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	return &review, nil
 }
 
+// reviewSortOrders maps each review sort to its ORDER BY. Every order ends in created_at
+// and id so rows sharing a rating keep a stable order across pages; newest and oldest are
+// backed by idx_reviews_product_created_id, the rating orders sort the product's reviews.
+var reviewSortOrders = map[string]string{
+	domain.ReviewSortNewest:        "created_at DESC, id DESC",
+	domain.ReviewSortOldest:        "created_at ASC, id ASC",
+	domain.ReviewSortHighestRating: "rating DESC, created_at DESC, id DESC",
+	domain.ReviewSortLowestRating:  "rating ASC, created_at DESC, id DESC",
+}
+
 // GetByProductID retrieves reviews for a product with pagination
-func (r *ReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, error) {
-	query := `
-		SELECT id, product_id, first_name, last_name, review_text, rating, created_at, updated_at, deleted_at
+func (r *ReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, sort string, limit, offset int) ([]*domain.Review, error) {
+	defer r.slowQueries.track("review.GetByProductID", map[string]any{"product_id": productID, "sort": sort, "limit": limit, "offset": offset})()
+
+	return r.listByProduct(ctx, "product_id = $1", []any{productID}, sort, limit, offset)
+}
+
+// GetByProductIDAndLanguage is GetByProductID limited to reviews in one language.
+// Reviews with no language are never included.
+func (r *ReviewRepository) GetByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language, sort string, limit, offset int) ([]*domain.Review, error) {
+	defer r.slowQueries.track("review.GetByProductIDAndLanguage", map[string]any{"product_id": productID, "language": language, "sort": sort, "limit": limit, "offset": offset})()
+
+	return r.listByProduct(ctx, "product_id = $1 AND language = $2", []any{productID, language}, sort, limit, offset)
+}
+
+// listByProduct runs a reviews page query for the given filter, which must only use
+// placeholders for the leading args; limit and offset take the next two
+func (r *ReviewRepository) listByProduct(ctx context.Context, filter string, args []any, sort string, limit, offset int) ([]*domain.Review, error) {
+	// Only whitelisted orders are interpolated into the query
+	order, ok := reviewSortOrders[sort]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown review sort %q", domain.ErrInvalidInput, sort)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, product_id, first_name, last_name, email_hash, title, language, review_text, rating, source, created_at, updated_at, deleted_at
 		FROM reviews
-		WHERE product_id = $1 AND deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+		WHERE %s AND deleted_at IS NULL
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, filter, order, len(args)+1, len(args)+2)
 
 	var reviews []*domain.Review
-	err := r.db.SelectContext(ctx, &reviews, query, productID, limit, offset)
+	err := conn(ctx, r.db).SelectContext(ctx, &reviews, query, append(args, limit, offset)...)
 	if err != nil {
-		return nil, err
+		return nil, classifyError(err)
 	}
 
 	return reviews, nil
@@ -102,20 +178,25 @@ func (r *ReviewRepository) GetByProductID(ctx context.Context, productID uuid.UU
 
 // Update updates an existing review
 func (r *ReviewRepository) Update(ctx context.Context, review *domain.Review) error {
+	defer r.slowQueries.track("review.Update", map[string]any{"review_id": review.ID, "product_id": review.ProductID})()
+
 	query := `
 		UPDATE reviews
-		SET first_name = $1, last_name = $2, review_text = $3, rating = $4, updated_at = $5
-		WHERE id = $6 AND deleted_at IS NULL
+		SET first_name = $1, last_name = $2, email_hash = $3, title = $4, language = $5, review_text = $6, rating = $7, updated_at = $8
+		WHERE id = $9 AND deleted_at IS NULL
 		RETURNING updated_at
 	`
 
 	review.UpdatedAt = time.Now()
 
-	err := r.db.QueryRowxContext(
+	err := conn(ctx, r.db).QueryRowxContext(
 		ctx,
 		query,
 		review.FirstName,
 		review.LastName,
+		review.EmailHash,
+		review.Title,
+		review.Language,
 		review.ReviewText,
 		review.Rating,
 		review.UpdatedAt,
@@ -125,28 +206,138 @@ func (r *ReviewRepository) Update(ctx context.Context, review *domain.Review) er
 		if errors.Is(err, sql.ErrNoRows) {
 			return domain.ErrNotFound
 		}
-		return err
+		return classifyError(err)
 	}
 
 	return nil
 }
 
+// Anonymize replaces the reviewer's name, clears their email hash and returns the updated review
+func (r *ReviewRepository) Anonymize(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	defer r.slowQueries.track("review.Anonymize", map[string]any{"review_id": id})()
+
+	query := `
+		UPDATE reviews
+		SET first_name = $1, last_name = $1, email_hash = NULL, updated_at = $2
+		WHERE id = $3 AND deleted_at IS NULL
+		RETURNING id, product_id, first_name, last_name, email_hash, title, language, review_text, rating, source, created_at, updated_at, deleted_at
+	`
+
+	var review domain.Review
+	err := conn(ctx, r.db).GetContext(ctx, &review, query, domain.AnonymousName, time.Now(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, domain.ErrNotFound
+		}
+		return nil, classifyError(err)
+	}
+
+	return &review, nil
+}
+
+// Flag records a flag and increments the review's flag count in one statement, so the
+// count can't drift from the rows in review_flags. The flag is skipped when the client IP
+// already flagged the review; FOR SHARE keeps a concurrent soft-delete from slipping in.
+func (r *ReviewRepository) Flag(ctx context.Context, flag *domain.ReviewFlag) (*domain.FlaggedReview, error) {
+	defer r.slowQueries.track("review.Flag", map[string]any{"review_id": flag.ReviewID})()
+
+	query := `
+		WITH flag AS (
+			INSERT INTO review_flags (review_id, reason, client_ip)
+			SELECT id, $2, $3 FROM reviews
+			WHERE id = $1 AND deleted_at IS NULL
+			FOR SHARE
+			ON CONFLICT (review_id, client_ip) DO NOTHING
+			RETURNING review_id
+		)
+		UPDATE reviews r
+		SET flag_count = r.flag_count + 1
+		FROM flag
+		WHERE r.id = flag.review_id
+		RETURNING r.id, r.product_id, r.first_name, r.last_name, r.email_hash, r.title, r.language, r.review_text, r.rating, r.source,
+			r.created_at, r.updated_at, r.deleted_at, r.flag_count
+	`
+
+	clientIP := sql.NullString{String: flag.ClientIP, Valid: flag.ClientIP != ""}
+
+	var review domain.FlaggedReview
+	err := conn(ctx, r.db).GetContext(ctx, &review, query, flag.ReviewID, flag.Reason, clientIP)
+	if err == nil {
+		return &review, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, classifyError(err)
+	}
+
+	// Nothing was flagged: either the review isn't live or this IP flagged it before
+	var exists bool
+	query = `SELECT EXISTS (SELECT 1 FROM reviews WHERE id = $1 AND deleted_at IS NULL)`
+	if err := conn(ctx, r.db).GetContext(ctx, &exists, query, flag.ReviewID); err != nil {
+		return nil, classifyError(err)
+	}
+	if exists {
+		return nil, domain.ErrAlreadyExists
+	}
+	return nil, domain.ErrNotFound
+}
+
+// ListFlagged returns live reviews flagged more than threshold times, most flagged first.
+// The flag_count > 0 condition lets the planner use the partial idx_reviews_flag_count
+// whatever threshold is bound.
+func (r *ReviewRepository) ListFlagged(ctx context.Context, threshold, limit, offset int) ([]*domain.FlaggedReview, error) {
+	defer r.slowQueries.track("review.ListFlagged", map[string]any{"threshold": threshold, "limit": limit, "offset": offset})()
+
+	query := `
+		SELECT id, product_id, first_name, last_name, email_hash, title, language, review_text, rating, source, created_at, updated_at, deleted_at, flag_count
+		FROM reviews
+		WHERE deleted_at IS NULL AND flag_count > 0 AND flag_count > $1
+		ORDER BY flag_count DESC, id
+		LIMIT $2 OFFSET $3
+	`
+
+	var reviews []*domain.FlaggedReview
+	err := conn(ctx, r.db).SelectContext(ctx, &reviews, query, threshold, limit, offset)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return reviews, nil
+}
+
+// CountFlagged returns the number of live reviews flagged more than threshold times
+func (r *ReviewRepository) CountFlagged(ctx context.Context, threshold int) (int, error) {
+	defer r.slowQueries.track("review.CountFlagged", map[string]any{"threshold": threshold})()
+
+	query := `SELECT COUNT(*) FROM reviews WHERE deleted_at IS NULL AND flag_count > 0 AND flag_count > $1`
+
+	var count int
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, threshold)
+	if err != nil {
+		return 0, classifyError(err)
+	}
+
+	return count, nil
+}
+
 // Delete soft-deletes a review
+// updated_at is bumped too so ChangesSince picks up the deletion
 func (r *ReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	defer r.slowQueries.track("review.Delete", map[string]any{"review_id": id})()
+
 	query := `
 		UPDATE reviews
-		SET deleted_at = $1
+		SET deleted_at = $1, updated_at = $1
 		WHERE id = $2 AND deleted_at IS NULL
 	`
 
-	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), id)
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	if rowsAffected == 0 {
@@ -156,17 +347,46 @@ func (r *ReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+// DeleteBatch soft-deletes several reviews in one statement and returns them as they were
+// before the delete. Joining the table to itself lets RETURNING read the pre-update row, so
+// callers get the audit "before" state without a separate SELECT that could race the UPDATE.
+func (r *ReviewRepository) DeleteBatch(ctx context.Context, ids []uuid.UUID) ([]*domain.Review, error) {
+	if len(ids) == 0 {
+		return []*domain.Review{}, nil
+	}
+	defer r.slowQueries.track("review.DeleteBatch", map[string]any{"count": len(ids)})()
+
+	query := `
+		UPDATE reviews r
+		SET deleted_at = $1, updated_at = $1
+		FROM reviews old
+		WHERE r.id = old.id AND r.id = ANY($2) AND r.deleted_at IS NULL
+		RETURNING old.id, old.product_id, old.first_name, old.last_name, old.email_hash, old.title, old.language, old.review_text, old.rating,
+			old.source, old.created_at, old.updated_at, old.deleted_at
+	`
+
+	reviews := []*domain.Review{}
+	err := conn(ctx, r.db).SelectContext(ctx, &reviews, query, time.Now(), pq.Array(ids))
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return reviews, nil
+}
+
 // DeleteByProductID soft-deletes all reviews for a product (cascade delete)
 func (r *ReviewRepository) DeleteByProductID(ctx context.Context, productID uuid.UUID) error {
+	defer r.slowQueries.track("review.DeleteByProductID", map[string]any{"product_id": productID})()
+
 	query := `
 		UPDATE reviews
-		SET deleted_at = $1
+		SET deleted_at = $1, updated_at = $1
 		WHERE product_id = $2 AND deleted_at IS NULL
 	`
 
-	_, err := r.db.ExecContext(ctx, query, time.Now(), productID)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, time.Now(), productID)
 	if err != nil {
-		return err
+		return classifyError(err)
 	}
 
 	return nil
@@ -174,13 +394,143 @@ func (r *ReviewRepository) DeleteByProductID(ctx context.Context, productID uuid
 
 // CountByProductID returns the total number of reviews for a product
 func (r *ReviewRepository) CountByProductID(ctx context.Context, productID uuid.UUID) (int, error) {
+	defer r.slowQueries.track("review.CountByProductID", map[string]any{"product_id": productID})()
+
 	query := `SELECT COUNT(*) FROM reviews WHERE product_id = $1 AND deleted_at IS NULL`
 
 	var count int
-	err := r.db.GetContext(ctx, &count, query, productID)
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, productID)
+	if err != nil {
+		return 0, classifyError(err)
+	}
+
+	return count, nil
+}
+
+// CountByProductIDAndLanguage returns the number of a product's reviews in one language (excludes soft-deleted)
+func (r *ReviewRepository) CountByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language string) (int, error) {
+	defer r.slowQueries.track("review.CountByProductIDAndLanguage", map[string]any{"product_id": productID, "language": language})()
+
+	query := `SELECT COUNT(*) FROM reviews WHERE product_id = $1 AND language = $2 AND deleted_at IS NULL`
+
+	var count int
+	err := conn(ctx, r.db).GetContext(ctx, &count, query, productID, language)
 	if err != nil {
-		return 0, err
+		return 0, classifyError(err)
 	}
 
 	return count, nil
 }
+
+// GetRatingDistribution returns review counts per rating for a product
+// Buckets are whole stars; a half-star rating counts toward the star below (4.5 under 4)
+func (r *ReviewRepository) GetRatingDistribution(ctx context.Context, productID uuid.UUID) (map[int]int, error) {
+	defer r.slowQueries.track("review.GetRatingDistribution", map[string]any{"product_id": productID})()
+
+	query := `
+		SELECT FLOOR(rating)::int AS rating, COUNT(*) AS count
+		FROM reviews
+		WHERE product_id = $1 AND deleted_at IS NULL
+		GROUP BY 1
+	`
+
+	var rows []struct {
+		Rating int `db:"rating"`
+		Count  int `db:"count"`
+	}
+	err := conn(ctx, r.db).SelectContext(ctx, &rows, query, productID)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	distribution := make(map[int]int, len(rows))
+	for _, row := range rows {
+		distribution[row.Rating] = row.Count
+	}
+
+	return distribution, nil
+}
+
+// GetRatingDistributions returns review counts per rating for several products in one query.
+// Products without reviews are absent from the map.
+func (r *ReviewRepository) GetRatingDistributions(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]map[int]int, error) {
+	if len(productIDs) == 0 {
+		return map[uuid.UUID]map[int]int{}, nil
+	}
+	defer r.slowQueries.track("review.GetRatingDistributions", map[string]any{"count": len(productIDs)})()
+
+	query := `
+		SELECT product_id, FLOOR(rating)::int AS rating, COUNT(*) AS count
+		FROM reviews
+		WHERE product_id = ANY($1) AND deleted_at IS NULL
+		GROUP BY 1, 2
+	`
+
+	var rows []struct {
+		ProductID uuid.UUID `db:"product_id"`
+		Rating    int       `db:"rating"`
+		Count     int       `db:"count"`
+	}
+	err := conn(ctx, r.db).SelectContext(ctx, &rows, query, pq.Array(productIDs))
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	distributions := make(map[uuid.UUID]map[int]int)
+	for _, row := range rows {
+		if distributions[row.ProductID] == nil {
+			distributions[row.ProductID] = make(map[int]int, 5)
+		}
+		distributions[row.ProductID][row.Rating] = row.Count
+	}
+
+	return distributions, nil
+}
+
+// ChangesSince returns reviews changed after (since, afterID), including soft-deleted ones.
+// Keyset pagination on (updated_at, id) keeps pages stable while rows keep changing:
+// a row updated mid-sync moves to the end instead of shifting every later page.
+func (r *ReviewRepository) ChangesSince(ctx context.Context, since time.Time, afterID uuid.UUID, limit int) ([]*domain.Review, error) {
+	defer r.slowQueries.track("review.ChangesSince", map[string]any{"since": since, "after_id": afterID, "limit": limit})()
+
+	query := `
+		SELECT id, product_id, first_name, last_name, email_hash, title, language, review_text, rating, source, created_at, updated_at, deleted_at
+		FROM reviews
+		WHERE (updated_at, id) > ($1, $2)
+		ORDER BY updated_at, id
+		LIMIT $3
+	`
+
+	var reviews []*domain.Review
+	err := conn(ctx, r.db).SelectContext(ctx, &reviews, query, since, afterID, limit)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return reviews, nil
+}
+
+// Recent returns the newest reviews across all products with their product names.
+// Ordered by (created_at, id) like the per-product list, so it is backed by
+// idx_reviews_created_id and stops after limit rows instead of sorting the table.
+func (r *ReviewRepository) Recent(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	defer r.slowQueries.track("review.Recent", map[string]any{"limit": limit})()
+
+	query := `
+		SELECT r.id, r.product_id, r.first_name, r.last_name, r.email_hash, r.title, r.language, r.review_text, r.rating, r.source,
+			r.created_at, r.updated_at, r.deleted_at, p.name AS product_name
+		FROM reviews r
+		JOIN products p ON p.id = r.product_id AND p.deleted_at IS NULL
+		WHERE r.deleted_at IS NULL
+		ORDER BY r.created_at DESC, r.id DESC
+		LIMIT $1
+	`
+
+	var reviews []*domain.RecentReview
+	err := conn(ctx, r.db).SelectContext(ctx, &reviews, query, limit)
+	if err != nil {
+		return nil, classifyError(err)
+	}
+
+	return reviews, nil
+}
@@ -0,0 +1,396 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+func newTestReviewRepository(t *testing.T) (*ReviewRepository, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	return NewReviewRepository(sqlx.NewDb(db, "sqlmock"), nil, nil), mock
+}
+
+func newTestReview() *domain.Review {
+	return &domain.Review{
+		ProductID:  uuid.New(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Great product",
+		Rating:     5,
+		Source:     domain.ReviewSourceWeb,
+	}
+}
+
+func TestReviewRepository_Create_Success(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	review := newTestReview()
+	reviewID := uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO reviews").
+		WithArgs(sqlmock.AnyArg(), review.ProductID, review.FirstName, review.LastName, review.EmailHash, review.Title, review.Language, review.ReviewText, review.Rating, review.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(reviewID, now, now))
+
+	err := repo.Create(context.Background(), review)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reviewID, review.ID)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Create_KeepsCallerID(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	review := newTestReview()
+	review.ID = uuid.New()
+	now := time.Now()
+
+	mock.ExpectQuery("INSERT INTO reviews").
+		WithArgs(review.ID, review.ProductID, review.FirstName, review.LastName, review.EmailHash, review.Title, review.Language, review.ReviewText, review.Rating, review.Source).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).AddRow(review.ID, now, now))
+
+	require.NoError(t, repo.Create(context.Background(), review))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Create_TakenID(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	review := newTestReview()
+	review.ID = uuid.New()
+
+	mock.ExpectQuery("INSERT INTO reviews").
+		WillReturnError(&pq.Error{Code: "23505", Constraint: "reviews_pkey"})
+
+	err := repo.Create(context.Background(), review)
+
+	assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Create_ProductNotFound(t *testing.T) {
+	t.Run("no live product row", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+
+		// Product soft-deleted or never existed: the conditional INSERT selects no row,
+		// and the follow-up read only decides which error to report
+		mock.ExpectQuery("INSERT INTO reviews").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT EXISTS`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		err := repo.Create(context.Background(), newTestReview())
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("foreign key violation", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+
+		// Product row removed entirely between request and INSERT
+		mock.ExpectQuery("INSERT INTO reviews").
+			WillReturnError(&pq.Error{Code: "23503", Constraint: "reviews_product_id_fkey"})
+
+		err := repo.Create(context.Background(), newTestReview())
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepository_Create_ReviewsDisabled(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+
+	mock.ExpectQuery(`WHERE p.id = \$2 AND p.deleted_at IS NULL AND p.reviews_enabled`).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery(`SELECT EXISTS`).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	err := repo.Create(context.Background(), newTestReview())
+
+	assert.ErrorIs(t, err, domain.ErrReviewsDisabled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Create_CheckViolation(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	checkViolation := &pq.Error{Code: "23514", Constraint: "reviews_rating_check"}
+
+	mock.ExpectQuery("INSERT INTO reviews").WillReturnError(checkViolation)
+
+	err := repo.Create(context.Background(), newTestReview())
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.ErrorIs(t, err, checkViolation, "the driver error stays wrapped for logging")
+	assert.NotErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestReviewRepository_Update_ValueTooLong(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	tooLong := &pq.Error{Code: "22001", Message: "value too long for type character varying(200)"}
+
+	mock.ExpectQuery("UPDATE reviews").WillReturnError(tooLong)
+
+	err := repo.Update(context.Background(), newTestReview())
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.ErrorIs(t, err, tooLong)
+}
+
+func TestReviewRepository_ChangesSince_IncludesDeleted(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	since := time.Now().Add(-time.Hour)
+	afterID := uuid.New()
+	deletedAt := time.Now()
+
+	columns := []string{"id", "product_id", "first_name", "last_name", "review_text", "rating", "source", "created_at", "updated_at", "deleted_at"}
+	mock.ExpectQuery(`WHERE \(updated_at, id\) > \(\$1, \$2\)\s+ORDER BY updated_at, id`).
+		WithArgs(since, afterID, 50).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), uuid.New(), "John", "Doe", "Great", 5, "web", since, since, nil).
+			AddRow(uuid.New(), uuid.New(), "Jane", "Doe", "Meh", 2, "web", since, deletedAt, deletedAt))
+
+	reviews, err := repo.ChangesSince(context.Background(), since, afterID, 50)
+
+	require.NoError(t, err)
+	require.Len(t, reviews, 2)
+	assert.Nil(t, reviews[0].DeletedAt)
+	assert.NotNil(t, reviews[1].DeletedAt)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Recent_JoinsProductName(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	now := time.Now()
+	productID := uuid.New()
+
+	columns := []string{"id", "product_id", "first_name", "last_name", "review_text", "rating", "source", "created_at", "updated_at", "deleted_at", "product_name"}
+	mock.ExpectQuery(`JOIN products p ON p.id = r.product_id AND p.deleted_at IS NULL\s+WHERE r.deleted_at IS NULL\s+ORDER BY r.created_at DESC, r.id DESC\s+LIMIT \$1`).
+		WithArgs(20).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(uuid.New(), productID, "John", "Doe", "Great", 5, "web", now, now, nil, "Widget"))
+
+	reviews, err := repo.Recent(context.Background(), 20)
+
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, productID, reviews[0].ProductID)
+	assert.Equal(t, "Widget", reviews[0].ProductName)
+	assert.Equal(t, 5.0, reviews[0].Rating)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetRatingDistributions_GroupsByProduct(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	first, second, unreviewed := uuid.New(), uuid.New(), uuid.New()
+	ids := []uuid.UUID{first, second, unreviewed}
+
+	mock.ExpectQuery(`WHERE product_id = ANY\(\$1\) AND deleted_at IS NULL\s+GROUP BY 1, 2`).
+		WithArgs(pq.Array(ids)).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "rating", "count"}).
+			AddRow(first, 5, 3).
+			AddRow(first, 1, 1).
+			AddRow(second, 4, 2))
+
+	distributions, err := repo.GetRatingDistributions(context.Background(), ids)
+
+	require.NoError(t, err)
+	assert.Equal(t, map[int]int{5: 3, 1: 1}, distributions[first])
+	assert.Equal(t, map[int]int{4: 2}, distributions[second])
+	assert.NotContains(t, distributions, unreviewed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_GetByProductID_Sort(t *testing.T) {
+	tests := []struct {
+		sort  string
+		order string
+	}{
+		{sort: domain.ReviewSortNewest, order: `ORDER BY created_at DESC, id DESC`},
+		{sort: domain.ReviewSortOldest, order: `ORDER BY created_at ASC, id ASC`},
+		{sort: domain.ReviewSortHighestRating, order: `ORDER BY rating DESC, created_at DESC, id DESC`},
+		{sort: domain.ReviewSortLowestRating, order: `ORDER BY rating ASC, created_at DESC, id DESC`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.sort, func(t *testing.T) {
+			repo, mock := newTestReviewRepository(t)
+			productID := uuid.New()
+
+			mock.ExpectQuery(tc.order).
+				WithArgs(productID, 20, 0).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+			_, err := repo.GetByProductID(context.Background(), productID, tc.sort, 20, 0)
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+
+	t.Run("unknown sort is rejected before querying", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+
+		_, err := repo.GetByProductID(context.Background(), uuid.New(), "rating; DROP TABLE reviews", 20, 0)
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestReviewRepository_GetByProductIDAndLanguage(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	productID := uuid.New()
+
+	mock.ExpectQuery(`WHERE product_id = \$1 AND language = \$2 AND deleted_at IS NULL\s+ORDER BY created_at DESC, id DESC\s+LIMIT \$3 OFFSET \$4`).
+		WithArgs(productID, "de", 20, 40).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "language"}).AddRow(uuid.New(), "de"))
+	mock.ExpectQuery(`SELECT COUNT\(\*\) FROM reviews WHERE product_id = \$1 AND language = \$2`).
+		WithArgs(productID, "de").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(41))
+
+	reviews, err := repo.GetByProductIDAndLanguage(context.Background(), productID, "de", domain.ReviewSortNewest, 20, 40)
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+	assert.Equal(t, "de", *reviews[0].Language)
+
+	count, err := repo.CountByProductIDAndLanguage(context.Background(), productID, "de")
+	require.NoError(t, err)
+	assert.Equal(t, 41, count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Delete_BumpsUpdatedAt(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	id := uuid.New()
+
+	// The changes feed relies on deletions moving updated_at forward
+	mock.ExpectExec(`SET deleted_at = \$1, updated_at = \$1`).
+		WithArgs(sqlmock.AnyArg(), id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	err := repo.Delete(context.Background(), id)
+
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_DeleteBatch_ReturnsPreDeleteRows(t *testing.T) {
+	repo, mock := newTestReviewRepository(t)
+	first, second := uuid.New(), uuid.New()
+	productID := uuid.New()
+	now := time.Now()
+
+	columns := []string{"id", "product_id", "first_name", "last_name", "review_text", "rating", "source", "created_at", "updated_at", "deleted_at"}
+	mock.ExpectQuery(`SET deleted_at = \$1, updated_at = \$1\s+FROM reviews old\s+WHERE r.id = old.id AND r.id = ANY\(\$2\) AND r.deleted_at IS NULL\s+RETURNING old.id`).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(first, productID, "Ann", "Lee", "Spam", 1, "web", now, now, nil))
+
+	reviews, err := repo.DeleteBatch(context.Background(), []uuid.UUID{first, second})
+
+	require.NoError(t, err)
+	require.Len(t, reviews, 1, "already deleted or unknown IDs are skipped")
+	assert.Equal(t, first, reviews[0].ID)
+	assert.Nil(t, reviews[0].DeletedAt, "the audit needs the row as it was before the delete")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewRepository_Anonymize(t *testing.T) {
+	t.Run("replaces names and keeps the review live", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+		id := uuid.New()
+		now := time.Now()
+
+		columns := []string{"id", "product_id", "first_name", "last_name", "review_text", "rating", "source", "created_at", "updated_at", "deleted_at"}
+		mock.ExpectQuery(`SET first_name = \$1, last_name = \$1, email_hash = NULL, updated_at = \$2`).
+			WithArgs(domain.AnonymousName, sqlmock.AnyArg(), id).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(id, uuid.New(), domain.AnonymousName, domain.AnonymousName, "Great", 5, "web", now, now, nil))
+
+		review, err := repo.Anonymize(context.Background(), id)
+
+		require.NoError(t, err)
+		assert.Equal(t, domain.AnonymousName, review.FirstName)
+		assert.Equal(t, 5.0, review.Rating)
+		assert.Nil(t, review.DeletedAt)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("missing or deleted review", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+		mock.ExpectQuery("UPDATE reviews").WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.Anonymize(context.Background(), uuid.New())
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestReviewRepository_Flag(t *testing.T) {
+	columns := []string{"id", "product_id", "first_name", "last_name", "review_text", "rating", "source", "created_at", "updated_at", "deleted_at", "flag_count"}
+
+	t.Run("records the flag and returns the new count", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+		id := uuid.New()
+		now := time.Now()
+
+		mock.ExpectQuery(`INSERT INTO review_flags .* ON CONFLICT \(review_id, client_ip\) DO NOTHING .* SET flag_count = r.flag_count \+ 1`).
+			WithArgs(id, "Spam", sql.NullString{String: "203.0.113.7", Valid: true}).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(id, uuid.New(), "John", "Doe", "Buy now", 1, "web", now, now, nil, 3))
+
+		review, err := repo.Flag(context.Background(), &domain.ReviewFlag{ReviewID: id, Reason: "Spam", ClientIP: "203.0.113.7"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 3, review.FlagCount)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("stores an unknown client IP as NULL", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+		id := uuid.New()
+		now := time.Now()
+
+		mock.ExpectQuery("INSERT INTO review_flags").
+			WithArgs(id, "Spam", sql.NullString{}).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(id, uuid.New(), "John", "Doe", "Buy now", 1, "web", now, now, nil, 1))
+
+		_, err := repo.Flag(context.Background(), &domain.ReviewFlag{ReviewID: id, Reason: "Spam"})
+
+		require.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	t.Run("already flagged from this IP", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+		mock.ExpectQuery("INSERT INTO review_flags").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+		_, err := repo.Flag(context.Background(), &domain.ReviewFlag{ReviewID: uuid.New(), Reason: "Spam", ClientIP: "203.0.113.7"})
+
+		assert.ErrorIs(t, err, domain.ErrAlreadyExists)
+	})
+
+	t.Run("missing or deleted review", func(t *testing.T) {
+		repo, mock := newTestReviewRepository(t)
+		mock.ExpectQuery("INSERT INTO review_flags").WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery("SELECT EXISTS").WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+
+		_, err := repo.Flag(context.Background(), &domain.ReviewFlag{ReviewID: uuid.New(), Reason: "Spam"})
+
+		assert.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
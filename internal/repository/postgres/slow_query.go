@@ -0,0 +1,49 @@
+package postgres
+
+import (
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// SlowQueryLogger warns about repository queries that exceed a threshold.
+// Shows which product/review queries degrade under load without the noise
+// of full query logging.
+type SlowQueryLogger struct {
+	threshold time.Duration
+	logger    *logger.Logger
+}
+
+// NewSlowQueryLogger creates a slow-query logger; a zero threshold disables it
+func NewSlowQueryLogger(threshold time.Duration, log *logger.Logger) *SlowQueryLogger {
+	return &SlowQueryLogger{
+		threshold: threshold,
+		logger:    log,
+	}
+}
+
+// track starts timing a query and returns a func to defer at the call site.
+// Safe to call on a nil receiver so repositories can run without slow-query logging.
+func (s *SlowQueryLogger) track(name string, fields map[string]any) func() {
+	if s == nil || s.threshold <= 0 || s.logger == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	return func() {
+		duration := time.Since(start)
+		if duration < s.threshold {
+			return
+		}
+
+		logFields := map[string]any{
+			"query":       name,
+			"duration_ms": duration.Milliseconds(),
+		}
+		for k, v := range fields {
+			logFields[k] = v
+		}
+
+		s.logger.WithFields(logFields).Warnf("Slow query %s took %s", name, duration)
+	}
+}
@@ -0,0 +1,80 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
+)
+
+// txKey carries the transaction started by Transactor.WithinTx on the context
+type txKey struct{}
+
+// querier is the subset of *sqlx.DB and *sqlx.Tx the repositories use,
+// so a repository method runs the same whether or not it's inside a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+	QueryRowxContext(ctx context.Context, query string, args ...any) *sqlx.Row
+}
+
+// conn returns the transaction on ctx, or db when the call isn't part of one
+func conn(ctx context.Context, db *sqlx.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// withinTx runs fn in a transaction carried on its context, committing when fn succeeds.
+// If ctx already carries a transaction fn joins it, and the outermost caller commits.
+//
+// It stays at READ COMMITTED: review writes to one product queue on its stats row (see
+// migration 000010) and must wait for each other, not fail as they would at REPEATABLE READ.
+func withinTx(ctx context.Context, db *sqlx.DB, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	// Serializable transactions can fail on commit, which is a conflict like any other
+	return classifyError(tx.Commit())
+}
+
+// Transactor implements domain.Transactor for PostgreSQL.
+// Repositories built on the same *sqlx.DB pick the transaction up from the context.
+type Transactor struct {
+	db                   *sqlx.DB
+	serializationRetries int
+}
+
+// NewTransactor creates a new PostgreSQL transactor.
+// serializationRetries is how many times a transaction that hit a serialization failure is rerun.
+func NewTransactor(db *sqlx.DB, serializationRetries int) *Transactor {
+	return &Transactor{db: db, serializationRetries: serializationRetries}
+}
+
+// WithinTx runs fn in a single transaction; see withinTx.
+// A transaction that fails to serialize is rerun from the start, so fn may run more than
+// once and must not have effects outside the database. Nested calls aren't retried on their
+// own: the failure aborts the outer transaction, and the outermost call reruns all of it.
+func (t *Transactor) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sqlx.Tx); ok {
+		return fn(ctx)
+	}
+	return database.RetrySerializable(ctx, t.serializationRetries, func() error {
+		return withinTx(ctx, t.db, fn)
+	})
+}
@@ -0,0 +1,50 @@
+// Package fakes holds in-memory stand-ins for domain interfaces that unit tests across
+// packages share. Package-specific mocks stay next to their tests.
+package fakes
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+)
+
+// PassthroughTx is a domain.Transactor that runs fn without a real transaction
+type PassthroughTx struct{}
+
+func (PassthroughTx) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// AuditRepository records audit entries and redactions; every call returns Err
+type AuditRepository struct {
+	Entries  []*domain.AuditEntry
+	Redacted []uuid.UUID
+	Err      error
+}
+
+func (f *AuditRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	f.Entries = append(f.Entries, entry)
+	return f.Err
+}
+
+func (f *AuditRepository) ListByEntityID(ctx context.Context, entityID uuid.UUID, limit, offset int) ([]*domain.AuditEntry, error) {
+	var entries []*domain.AuditEntry
+	for _, entry := range f.Entries {
+		if entry.EntityID == entityID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, f.Err
+}
+
+func (f *AuditRepository) CountByEntityID(ctx context.Context, entityID uuid.UUID) (int, error) {
+	entries, err := f.ListByEntityID(ctx, entityID, 0, 0)
+	return len(entries), err
+}
+
+func (f *AuditRepository) Redact(ctx context.Context, entityID uuid.UUID, fields map[string]any) error {
+	f.Redacted = append(f.Redacted, entityID)
+	return f.Err
+}
@@ -0,0 +1,194 @@
+// Package testutil starts throwaway Postgres, Redis and NATS containers for integration tests
+// with testcontainers-go, so `go test -tags=integration ./tests/...` needs only a Docker daemon,
+// not a provisioned environment.
+package testutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
+)
+
+// UseExistingEnv makes Start skip the containers and use the environment from config.Load,
+// for CI jobs that already provide service containers
+const UseExistingEnv = "INTEGRATION_USE_ENV"
+
+// Images match docker-compose.yml so tests run against the versions we deploy
+const (
+	postgresImage = "postgres:15-alpine"
+	redisImage    = "redis:7-alpine"
+	natsImage     = "nats:2.10-alpine"
+)
+
+// startupTimeout bounds how long each container may take to pass its wait strategy
+const startupTimeout = time.Minute
+
+// readyRetry is how long to wait for Postgres to accept connections once its container is up
+var readyRetry = config.ConnectRetryConfig{
+	MaxRetries: 30,
+	Delay:      time.Second,
+	Backoff:    config.ConnectBackoffFixed,
+	MaxDelay:   time.Second,
+}
+
+// Environment is a running set of dependencies and the config pointing at them
+type Environment struct {
+	Config     *config.Config
+	containers []testcontainers.Container
+}
+
+// Start launches the containers, waits for them to accept connections, applies
+// migrations/*.up.sql and returns a config pointing at them. Call Close when done,
+// typically from TestMain.
+func Start(ctx context.Context) (*Environment, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+
+	env := &Environment{Config: cfg}
+	if os.Getenv(UseExistingEnv) == "true" {
+		return env, nil
+	}
+
+	if err := env.start(ctx); err != nil {
+		env.Close()
+		return nil, err
+	}
+
+	return env, nil
+}
+
+func (e *Environment) start(ctx context.Context) error {
+	cfg := e.Config
+
+	pgHost, pgPort, err := e.run(ctx, testcontainers.ContainerRequest{
+		Image:        postgresImage,
+		ExposedPorts: []string{"5432/tcp"},
+		Env: map[string]string{
+			"POSTGRES_USER":     cfg.Database.User,
+			"POSTGRES_PASSWORD": cfg.Database.Password,
+			"POSTGRES_DB":       cfg.Database.Name,
+		},
+		// The entrypoint restarts the server once after initdb, so the first "ready" is too early
+		WaitingFor: wait.ForLog("database system is ready to accept connections").
+			WithOccurrence(2).
+			WithStartupTimeout(startupTimeout),
+	}, "5432/tcp")
+	if err != nil {
+		return err
+	}
+	redisHost, redisPort, err := e.run(ctx, testcontainers.ContainerRequest{
+		Image:        redisImage,
+		ExposedPorts: []string{"6379/tcp"},
+		WaitingFor:   wait.ForLog("Ready to accept connections").WithStartupTimeout(startupTimeout),
+	}, "6379/tcp")
+	if err != nil {
+		return err
+	}
+	natsHost, natsPort, err := e.run(ctx, testcontainers.ContainerRequest{
+		Image:        natsImage,
+		ExposedPorts: []string{"4222/tcp"},
+		Cmd:          []string{"-js"},
+		WaitingFor:   wait.ForLog("Server is ready").WithStartupTimeout(startupTimeout),
+	}, "4222/tcp")
+	if err != nil {
+		return err
+	}
+
+	cfg.Database.Host = pgHost
+	cfg.Database.Port = pgPort
+	cfg.Redis.Host = redisHost
+	cfg.Redis.Port = redisPort
+	cfg.NATS.URL = "nats://" + net.JoinHostPort(natsHost, natsPort)
+
+	return migrate(cfg)
+}
+
+// run starts the container described by req and returns the host and port that
+// containerPort is published on
+func (e *Environment) run(ctx context.Context, req testcontainers.ContainerRequest, containerPort nat.Port) (string, string, error) {
+	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	// A container that failed its wait strategy is still returned and must be removed
+	if c != nil {
+		e.containers = append(e.containers, c)
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("start %s: %w", req.Image, err)
+	}
+
+	host, err := c.Host(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("inspect %s host: %w", req.Image, err)
+	}
+	port, err := c.MappedPort(ctx, containerPort)
+	if err != nil {
+		return "", "", fmt.Errorf("inspect %s port: %w", req.Image, err)
+	}
+
+	return host, port.Port(), nil
+}
+
+// Close removes the containers; it is safe to call on a partially started environment
+func (e *Environment) Close() {
+	var errs []error
+	for _, c := range e.containers {
+		errs = append(errs, testcontainers.TerminateContainer(c))
+	}
+	e.containers = nil
+	if err := errors.Join(errs...); err != nil {
+		fmt.Fprintf(os.Stderr, "testutil: remove containers: %v\n", err)
+	}
+}
+
+// migrate applies every up migration in order, the same files `make migrate-up` runs
+func migrate(cfg *config.Config) error {
+	db, err := database.WaitForDB(cfg, readyRetry)
+	if err != nil {
+		return fmt.Errorf("wait for postgres: %w", err)
+	}
+	defer db.Close()
+
+	files, err := filepath.Glob(filepath.Join(migrationsDir(), "*.up.sql"))
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no migrations found in %s", migrationsDir())
+	}
+	sort.Strings(files)
+
+	for _, file := range files {
+		query, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(string(query)); err != nil {
+			return fmt.Errorf("apply %s: %w", filepath.Base(file), err)
+		}
+	}
+
+	return nil
+}
+
+// migrationsDir locates migrations/ relative to this file, so it works from any test package
+func migrationsDir() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Join(filepath.Dir(file), "..", "..", "migrations")
+}
@@ -0,0 +1,11 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationsDir(t *testing.T) {
+	assert.FileExists(t, migrationsDir()+"/000001_create_schema.up.sql")
+}
@@ -3,41 +3,97 @@ package product
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/audit"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
 	pkgValidator "github.com/Pesokrava/product_reviewer/internal/pkg/validator"
 )
 
+// ProductCache drops a product's cached rating and review entries
+type ProductCache interface {
+	InvalidateAllProductCache(ctx context.Context, productID uuid.UUID) error
+}
+
 // Service handles product business logic
 type Service struct {
 	repo       domain.ProductRepository
 	reviewRepo domain.ReviewRepository
-	validate   *validator.Validate
-	logger     *logger.Logger
+	tx         domain.Transactor
+	audits     domain.AuditRepository
+	cache      ProductCache
+	// enforceUniqueName checks for a live product with the same name before writing, so the
+	// conflict is reported by name rather than as a bare index violation
+	enforceUniqueName bool
+	validate          *validator.Validate
+	logger            *logger.Logger
 }
 
-// NewService creates a new product service
-func NewService(repo domain.ProductRepository, reviewRepo domain.ReviewRepository, log *logger.Logger) *Service {
+// NewService creates a new product service.
+// Every mutation is written to audits in the same transaction as the change.
+// cache may be nil, in which case a deleted product's cached review pages are served
+// until they expire.
+// enforceUniqueName enables ENFORCE_UNIQUE_PRODUCT_NAME.
+func NewService(
+	repo domain.ProductRepository,
+	reviewRepo domain.ReviewRepository,
+	tx domain.Transactor,
+	audits domain.AuditRepository,
+	cache ProductCache,
+	enforceUniqueName bool,
+	log *logger.Logger,
+) *Service {
 	return &Service{
-		repo:       repo,
-		reviewRepo: reviewRepo,
-		validate:   pkgValidator.Get(),
-		logger:     log,
+		repo:              repo,
+		reviewRepo:        reviewRepo,
+		tx:                tx,
+		audits:            audits,
+		cache:             cache,
+		enforceUniqueName: enforceUniqueName,
+		validate:          pkgValidator.Get(),
+		logger:            log,
+	}
+}
+
+// checkNameAvailable rejects a name another live product already uses, when
+// ENFORCE_UNIQUE_PRODUCT_NAME is on. NameTaken holds the name until the transaction ends,
+// so two concurrent writes of one name can't both pass the check.
+func (s *Service) checkNameAvailable(ctx context.Context, product *domain.Product) error {
+	if !s.enforceUniqueName {
+		return nil
+	}
+
+	taken, err := s.repo.NameTaken(ctx, product.Name, product.ID)
+	if err != nil {
+		return err
 	}
+	if taken {
+		return fmt.Errorf("%w: a product named %q already exists", domain.ErrAlreadyExists, product.Name)
+	}
+	return nil
 }
 
 // Create creates a new product
 func (s *Service) Create(ctx context.Context, product *domain.Product) error {
 	if err := s.validate.Struct(product); err != nil {
 		s.logger.Error("Product validation failed", err)
-		return domain.ErrInvalidInput
+		return pkgValidator.InvalidInput(err)
 	}
 
-	if err := s.repo.Create(ctx, product); err != nil {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.checkNameAvailable(ctx, product); err != nil {
+			return err
+		}
+		if err := s.repo.Create(ctx, product); err != nil {
+			return err
+		}
+		return audit.Record(ctx, s.audits, domain.AuditActionCreate, domain.AuditEntityProduct, product.ID, nil, product)
+	})
+	if err != nil {
 		s.logger.Error("Failed to create product", err)
 		return err
 	}
@@ -67,36 +123,116 @@ func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, e
 
 // List retrieves a paginated list of products
 func (s *Service) List(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20
+	if err := domain.CheckPageSize(limit); err != nil {
+		return nil, 0, err
 	}
 	if offset < 0 {
 		offset = 0
 	}
 
-	products, err := s.repo.List(ctx, limit, offset)
+	products, total, err := s.repo.ListWithTotal(ctx, limit, offset)
 	if err != nil {
 		s.logger.Error("Failed to list products", err)
 		return nil, 0, err
 	}
 
-	total, err := s.repo.Count(ctx)
+	return products, total, nil
+}
+
+// ListUnreviewed retrieves a page of products that have no reviews yet, newest first,
+// for merchandising to solicit reviews for
+func (s *Service) ListUnreviewed(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
+	if err := domain.CheckPageSize(limit); err != nil {
+		return nil, 0, err
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	products, total, err := s.repo.ListUnreviewed(ctx, limit, offset)
 	if err != nil {
-		s.logger.Error("Failed to count products", err)
+		s.logger.Error("Failed to list unreviewed products", err)
 		return nil, 0, err
 	}
 
 	return products, total, nil
 }
 
-// Update updates an existing product
-func (s *Service) Update(ctx context.Context, product *domain.Product) error {
+// Compare retrieves several products with their rating distributions, in the order requested.
+// Two queries serve any number of products. Returns domain.ErrNotFound if any product is
+// missing, since a comparison with a silently dropped column would mislead the shopper.
+func (s *Service) Compare(ctx context.Context, ids []uuid.UUID) ([]*domain.ProductComparison, error) {
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: at least one product ID is required", domain.ErrInvalidInput)
+	}
+
+	products, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Error("Failed to get products for comparison", err)
+		return nil, err
+	}
+	for _, id := range ids {
+		if _, ok := products[id]; !ok {
+			s.logger.Debugf("Product not found for comparison: %s", id)
+			return nil, domain.ErrNotFound
+		}
+	}
+
+	distributions, err := s.reviewRepo.GetRatingDistributions(ctx, ids)
+	if err != nil {
+		s.logger.Error("Failed to get rating distributions for comparison", err)
+		return nil, err
+	}
+
+	comparisons := make([]*domain.ProductComparison, 0, len(ids))
+	for _, id := range ids {
+		// Report every star level, including those without reviews, so columns line up
+		distribution := make(map[int]int, domain.MaxRating())
+		for rating := 1; rating <= domain.MaxRating(); rating++ {
+			distribution[rating] = distributions[id][rating]
+		}
+
+		comparisons = append(comparisons, &domain.ProductComparison{
+			Product:            products[id],
+			RatingDistribution: distribution,
+		})
+	}
+
+	return comparisons, nil
+}
+
+// Update updates an existing product.
+// reviewsEnabled nil keeps the stored value, so a client that doesn't know the field can't
+// reopen reviews on a frozen product; product.ReviewsEnabled is ignored.
+func (s *Service) Update(ctx context.Context, product *domain.Product, reviewsEnabled *bool) error {
 	if err := s.validate.Struct(product); err != nil {
 		s.logger.Error("Product validation failed", err)
-		return domain.ErrInvalidInput
+		return pkgValidator.InvalidInput(err)
 	}
 
-	if err := s.repo.Update(ctx, product); err != nil {
+	// The transaction may be rerun after a serialization failure; every attempt must
+	// check the version the client sent, not the one a failed attempt read back
+	version := product.Version
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		product.Version = version
+		// Read inside the transaction so the audit snapshot is the row this update replaces
+		before, err := s.repo.GetByID(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+		product.ReviewsEnabled = before.ReviewsEnabled
+		if reviewsEnabled != nil {
+			product.ReviewsEnabled = *reviewsEnabled
+		}
+		if err := s.checkNameAvailable(ctx, product); err != nil {
+			return err
+		}
+		if err := s.repo.Update(ctx, product); err != nil {
+			return err
+		}
+		return audit.Record(ctx, s.audits, domain.AuditActionUpdate, domain.AuditEntityProduct, product.ID, before, product)
+	})
+	if err != nil {
 		s.logger.Error("Failed to update product", err)
 		return err
 	}
@@ -109,9 +245,20 @@ func (s *Service) Update(ctx context.Context, product *domain.Product) error {
 	return nil
 }
 
-// Delete soft-deletes a product and cascades to all its reviews
+// Delete soft-deletes a product and cascades to all its reviews.
+// Only the product delete is audited; the cascaded reviews follow from it.
 func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.DeleteWithReviews(ctx, id); err != nil {
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		before, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.repo.DeleteWithReviews(ctx, id); err != nil {
+			return err
+		}
+		return audit.Record(ctx, s.audits, domain.AuditActionDelete, domain.AuditEntityProduct, id, before, nil)
+	})
+	if err != nil {
 		s.logger.WithFields(map[string]any{
 			"product_id": id,
 			"error":      err.Error(),
@@ -119,6 +266,18 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
+	// The reviews list only checks that its product exists when it fills an empty page, so
+	// cached pages must go for the list to return 404 right away.
+	// Non-fatal: if cache is down, accept temporary staleness over API unavailability
+	if s.cache != nil {
+		if err := s.cache.InvalidateAllProductCache(ctx, id); err != nil {
+			s.logger.WithFields(map[string]any{
+				"product_id": id,
+				"error":      err.Error(),
+			}).Warn("Failed to invalidate cache, may serve stale data temporarily")
+		}
+	}
+
 	s.logger.WithFields(map[string]any{
 		"product_id": id,
 	}).Info("Product and reviews deleted successfully")
@@ -2,14 +2,18 @@ package product
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Pesokrava/product_reviewer/internal/domain"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
 )
 
 // MockProductRepository is a mock implementation of domain.ProductRepository
@@ -30,6 +34,14 @@ func (m *MockProductRepository) GetByID(ctx context.Context, id uuid.UUID) (*dom
 	return args.Get(0).(*domain.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) (map[uuid.UUID]*domain.Product, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]*domain.Product), args.Error(1)
+}
+
 func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]*domain.Product, error) {
 	args := m.Called(ctx, limit, offset)
 	if args.Get(0) == nil {
@@ -38,6 +50,22 @@ func (m *MockProductRepository) List(ctx context.Context, limit, offset int) ([]
 	return args.Get(0).([]*domain.Product), args.Error(1)
 }
 
+func (m *MockProductRepository) ListWithTotal(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Product), args.Int(1), args.Error(2)
+}
+
+func (m *MockProductRepository) ListUnreviewed(ctx context.Context, limit, offset int) ([]*domain.Product, int, error) {
+	args := m.Called(ctx, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Int(1), args.Error(2)
+	}
+	return args.Get(0).([]*domain.Product), args.Int(1), args.Error(2)
+}
+
 func (m *MockProductRepository) Update(ctx context.Context, product *domain.Product) error {
 	args := m.Called(ctx, product)
 	return args.Error(0)
@@ -58,6 +86,11 @@ func (m *MockProductRepository) Count(ctx context.Context) (int, error) {
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockProductRepository) NameTaken(ctx context.Context, name string, excludeID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, name, excludeID)
+	return args.Bool(0), args.Error(1)
+}
+
 // MockReviewRepository is a mock implementation of domain.ReviewRepository
 type MockReviewRepository struct {
 	mock.Mock
@@ -76,8 +109,16 @@ func (m *MockReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*doma
 	return args.Get(0).(*domain.Review), args.Error(1)
 }
 
-func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, error) {
-	args := m.Called(ctx, productID, limit, offset)
+func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, sort string, limit, offset int) ([]*domain.Review, error) {
+	args := m.Called(ctx, productID, sort, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language, sort string, limit, offset int) ([]*domain.Review, error) {
+	args := m.Called(ctx, productID, language, sort, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -94,6 +135,14 @@ func (m *MockReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockReviewRepository) DeleteBatch(ctx context.Context, ids []uuid.UUID) ([]*domain.Review, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
 func (m *MockReviewRepository) DeleteByProductID(ctx context.Context, productID uuid.UUID) error {
 	args := m.Called(ctx, productID)
 	return args.Error(0)
@@ -104,11 +153,88 @@ func (m *MockReviewRepository) CountByProductID(ctx context.Context, productID u
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockReviewRepository) CountByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language string) (int, error) {
+	args := m.Called(ctx, productID, language)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingDistribution(ctx context.Context, productID uuid.UUID) (map[int]int, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingDistributions(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]map[int]int, error) {
+	args := m.Called(ctx, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]map[int]int), args.Error(1)
+}
+
+func (m *MockReviewRepository) ChangesSince(ctx context.Context, since time.Time, afterID uuid.UUID, limit int) ([]*domain.Review, error) {
+	args := m.Called(ctx, since, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) Recent(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RecentReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) Anonymize(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) Flag(ctx context.Context, flag *domain.ReviewFlag) (*domain.FlaggedReview, error) {
+	args := m.Called(ctx, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FlaggedReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListFlagged(ctx context.Context, threshold, limit, offset int) ([]*domain.FlaggedReview, error) {
+	args := m.Called(ctx, threshold, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FlaggedReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) CountFlagged(ctx context.Context, threshold int) (int, error) {
+	args := m.Called(ctx, threshold)
+	return args.Int(0), args.Error(1)
+}
+
+// fakeProductCache records the products whose cache was invalidated
+type fakeProductCache struct {
+	invalidated []uuid.UUID
+	err         error
+}
+
+func (f *fakeProductCache) InvalidateAllProductCache(ctx context.Context, productID uuid.UUID) error {
+	f.invalidated = append(f.invalidated, productID)
+	return f.err
+}
+
 func TestService_Create_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	mockReviewRepo := new(MockReviewRepository)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockReviewRepo, log)
+	service := NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
 
 	product := &domain.Product{
 		Name:  "Test Product",
@@ -127,7 +253,7 @@ func TestService_Create_InvalidInput(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	mockReviewRepo := new(MockReviewRepository)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockReviewRepo, log)
+	service := NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
 
 	product := &domain.Product{
 		Name:  "", // Invalid: empty name
@@ -137,15 +263,64 @@ func TestService_Create_InvalidInput(t *testing.T) {
 	err := service.Create(context.Background(), product)
 
 	assert.Error(t, err)
-	assert.Equal(t, domain.ErrInvalidInput, err)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
 	mockRepo.AssertNotCalled(t, "Create")
 }
 
+func TestService_Create_EnforceUniqueName(t *testing.T) {
+	tests := []struct {
+		name    string
+		taken   bool
+		wantErr error
+	}{
+		{name: "free name", taken: false},
+		{name: "name of a live product", taken: true, wantErr: domain.ErrAlreadyExists},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			service := NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, true, logger.New("test"))
+
+			product := &domain.Product{Name: "Widget", Price: 10}
+			mockRepo.On("NameTaken", mock.Anything, "Widget", uuid.Nil).Return(tc.taken, nil)
+			if !tc.taken {
+				mockRepo.On("Create", mock.Anything, product).Return(nil)
+			}
+
+			err := service.Create(context.Background(), product)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				assert.Contains(t, err.Error(), `"Widget"`)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockRepo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_Update_EnforceUniqueNameExcludesItself(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	service := NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, true, logger.New("test"))
+
+	productID := uuid.New()
+	product := &domain.Product{ID: productID, Name: "Widget", Price: 10, Version: 1}
+	mockRepo.On("GetByID", mock.Anything, productID).Return(&domain.Product{ID: productID, Name: "Widget", Version: 1}, nil)
+	mockRepo.On("NameTaken", mock.Anything, "Widget", productID).Return(false, nil)
+	mockRepo.On("Update", mock.Anything, product).Return(nil)
+
+	require.NoError(t, service.Update(context.Background(), product, nil))
+	mockRepo.AssertExpectations(t)
+}
+
 func TestService_GetByID_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	mockReviewRepo := new(MockReviewRepository)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockReviewRepo, log)
+	service := NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
 
 	productID := uuid.New()
 	expectedProduct := &domain.Product{
@@ -167,7 +342,7 @@ func TestService_GetByID_NotFound(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	mockReviewRepo := new(MockReviewRepository)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockReviewRepo, log)
+	service := NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
 
 	productID := uuid.New()
 
@@ -185,7 +360,7 @@ func TestService_List_Success(t *testing.T) {
 	mockRepo := new(MockProductRepository)
 	mockReviewRepo := new(MockReviewRepository)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockReviewRepo, log)
+	service := NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, log)
 
 	expectedProducts := []*domain.Product{
 		{ID: uuid.New(), Name: "Product 1", Price: 99.99},
@@ -193,8 +368,7 @@ func TestService_List_Success(t *testing.T) {
 	}
 	expectedTotal := 2
 
-	mockRepo.On("List", mock.Anything, 20, 0).Return(expectedProducts, nil)
-	mockRepo.On("Count", mock.Anything).Return(expectedTotal, nil)
+	mockRepo.On("ListWithTotal", mock.Anything, 20, 0).Return(expectedProducts, expectedTotal, nil)
 
 	products, total, err := service.List(context.Background(), 20, 0)
 
@@ -203,3 +377,81 @@ func TestService_List_Success(t *testing.T) {
 	assert.Equal(t, expectedTotal, total)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestService_Compare_KeepsRequestOrder(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	service := NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, logger.New("test"))
+
+	first, second := uuid.New(), uuid.New()
+	ids := []uuid.UUID{first, second}
+
+	mockRepo.On("GetByIDs", mock.Anything, ids).Return(map[uuid.UUID]*domain.Product{
+		second: {ID: second, Name: "Second"},
+		first:  {ID: first, Name: "First"},
+	}, nil)
+	// second has no reviews yet, so it is absent from the distributions
+	mockReviewRepo.On("GetRatingDistributions", mock.Anything, ids).Return(map[uuid.UUID]map[int]int{
+		first: {5: 3, 4: 1},
+	}, nil)
+
+	comparisons, err := service.Compare(context.Background(), ids)
+
+	require.NoError(t, err)
+	require.Len(t, comparisons, 2)
+	assert.Equal(t, "First", comparisons[0].Product.Name)
+	assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 0, 4: 1, 5: 3}, comparisons[0].RatingDistribution)
+	assert.Equal(t, "Second", comparisons[1].Product.Name)
+	assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 0}, comparisons[1].RatingDistribution)
+}
+
+func TestService_Compare_MissingProduct(t *testing.T) {
+	mockRepo := new(MockProductRepository)
+	mockReviewRepo := new(MockReviewRepository)
+	service := NewService(mockRepo, mockReviewRepo, fakes.PassthroughTx{}, new(fakes.AuditRepository), nil, false, logger.New("test"))
+
+	found, missing := uuid.New(), uuid.New()
+	ids := []uuid.UUID{found, missing}
+
+	mockRepo.On("GetByIDs", mock.Anything, ids).Return(map[uuid.UUID]*domain.Product{
+		found: {ID: found, Name: "Found"},
+	}, nil)
+
+	comparisons, err := service.Compare(context.Background(), ids)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.Nil(t, comparisons)
+	mockReviewRepo.AssertNotCalled(t, "GetRatingDistributions", mock.Anything, mock.Anything)
+}
+
+func TestService_Delete_InvalidatesCache(t *testing.T) {
+	productID := uuid.New()
+
+	for name, cacheErr := range map[string]error{
+		"invalidated":          nil,
+		"cache down non-fatal": errors.New("redis down"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockProductRepository)
+			cache := &fakeProductCache{err: cacheErr}
+			service := NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), cache, false, logger.New("test"))
+
+			mockRepo.On("GetByID", mock.Anything, productID).Return(&domain.Product{ID: productID}, nil)
+			mockRepo.On("DeleteWithReviews", mock.Anything, productID).Return(nil)
+
+			require.NoError(t, service.Delete(context.Background(), productID))
+			assert.Equal(t, []uuid.UUID{productID}, cache.invalidated)
+		})
+	}
+
+	t.Run("not invalidated when the delete fails", func(t *testing.T) {
+		mockRepo := new(MockProductRepository)
+		cache := &fakeProductCache{}
+		service := NewService(mockRepo, new(MockReviewRepository), fakes.PassthroughTx{}, new(fakes.AuditRepository), cache, false, logger.New("test"))
+
+		mockRepo.On("GetByID", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+
+		assert.ErrorIs(t, service.Delete(context.Background(), productID), domain.ErrNotFound)
+		assert.Empty(t, cache.invalidated)
+	})
+}
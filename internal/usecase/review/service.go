@@ -4,13 +4,22 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/audit"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clientip"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/langdetect"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/sanitize"
 	pkgValidator "github.com/Pesokrava/product_reviewer/internal/pkg/validator"
 )
 
@@ -19,54 +28,279 @@ type EventPublisher interface {
 	Publish(ctx context.Context, subject string, data []byte) error
 }
 
+// ProductLookup resolves the product a review belongs to, for the product name in events
+type ProductLookup interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+}
+
 // ReviewCache defines the interface for review caching operations
 type ReviewCache interface {
-	GetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, int, error)
-	SetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int, reviews []*domain.Review, total int) error
+	GetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int) ([]*domain.Review, int, error)
+	SetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int, reviews []*domain.Review, total int) error
+	GetReviewOverview(ctx context.Context, productID uuid.UUID, limit int) (*domain.ReviewOverview, error)
+	SetReviewOverview(ctx context.Context, productID uuid.UUID, limit int, overview *domain.ReviewOverview) error
+	GetReviewSummary(ctx context.Context, productID uuid.UUID) (*domain.ReviewSummary, error)
+	SetReviewSummary(ctx context.Context, productID uuid.UUID, summary *domain.ReviewSummary) error
+	GetRecentReviews(ctx context.Context, limit int) ([]*domain.RecentReview, error)
+	SetRecentReviews(ctx context.Context, limit int, reviews []*domain.RecentReview) error
 	InvalidateAllProductCache(ctx context.Context, productID uuid.UUID) error
+	// InvalidateProducts is InvalidateAllProductCache for many products in one round trip
+	InvalidateProducts(ctx context.Context, productIDs []uuid.UUID) error
+	IncrReviewAttempts(ctx context.Context, ip string, productID uuid.UUID, window time.Duration) (int64, error)
 }
 
+// Throttle limits how many reviews one client IP may submit for one product per Window,
+// to curb review bombing. A zero Limit disables it.
+type Throttle struct {
+	Limit  int
+	Window time.Duration
+}
+
+// EventSubject is the logical subject every review event is published on
+const EventSubject = "reviews.events"
+
+// EventTypeRatingRecalc asks the rating worker to recalculate one product.
+// Bulk writes publish it once instead of one review event per review; it carries no review.
+const EventTypeRatingRecalc = "product.rating.recalc"
+
+// DefaultPublishTimeout bounds an event publish when NewService is given no timeout
+const DefaultPublishTimeout = 5 * time.Second
+
+// MaxImportBatchSize caps the reviews accepted by a single Import call
+const MaxImportBatchSize = 1000
+
+// MaxDeleteBatchSize caps the reviews removed by a single DeleteBatch call
+const MaxDeleteBatchSize = 1000
+
 // ReviewEvent represents an event related to a review
 type ReviewEvent struct {
-	EventType string         `json:"event_type"`
-	Timestamp time.Time      `json:"timestamp"`
-	ProductID uuid.UUID      `json:"product_id"`
-	Review    *domain.Review `json:"review"`
+	EventType string    `json:"event_type"`
+	Timestamp time.Time `json:"timestamp"`
+	ProductID uuid.UUID `json:"product_id"`
+	// ProductName lets consumers show which product was reviewed without querying for it;
+	// omitted when the lookup fails, so consumers must fall back to product_id
+	ProductName string         `json:"product_name,omitempty"`
+	Review      *domain.Review `json:"review,omitempty"`
 }
 
 // Service handles review business logic with caching and event publishing
 type Service struct {
 	repo      domain.ReviewRepository
+	products  ProductLookup
 	cache     ReviewCache
 	publisher EventPublisher
-	validate  *validator.Validate
-	logger    *logger.Logger
+	tx        domain.Transactor
+	audits    domain.AuditRepository
+	// outbox, when set, receives events inside the mutation's transaction instead of them
+	// being published in the background after commit
+	outbox domain.OutboxRepository
+	clock  clock.Clock
+	// sanitizeText strips HTML from review text before it is validated and stored
+	sanitizeText bool
+	// detectLanguage tags reviews that arrive without a language with the detected one
+	detectLanguage bool
+	// throttle is swapped by SetThrottle on config reload
+	throttle atomic.Pointer[Throttle]
+	// flagThreshold is how many flags a review may collect before it enters the moderation queue
+	flagThreshold int
+	// defaultSort orders the reviews list and overview (one of the domain.ReviewSort values)
+	defaultSort string
+	// publishTimeout bounds each background publish, so a slow broker can't pile up goroutines
+	publishTimeout time.Duration
+	// publishWithinRequestDeadline also caps each publish at the deadline of the request that caused it
+	publishWithinRequestDeadline bool
+	validate                     *validator.Validate
+	logger                       *logger.Logger
+
+	// publishes tracks in-flight background publishes so Shutdown can drain them
+	publishes sync.WaitGroup
 }
 
-// NewService creates a new review service
+// Options are the review service's optional collaborators and settings.
+// The zero value publishes events in the background without product names, keeps review
+// text as given and doesn't throttle.
+type Options struct {
+	// Products resolves the product name carried by events; nil leaves it out
+	Products ProductLookup
+	// Outbox, when set, receives events inside the mutation's transaction. Nil publishes them
+	// in the background after commit, at the risk of losing them if the process dies in between.
+	Outbox domain.OutboxRepository
+	// Clock stamps events and times publishes; defaults to the system clock
+	Clock clock.Clock
+	// SanitizeText enables the SANITIZE_REVIEW_TEXT=store mode
+	SanitizeText bool
+	// DetectLanguage enables DETECT_REVIEW_LANGUAGE
+	DetectLanguage bool
+	// Throttle applies to Create only; imports and internal callers without a client IP are exempt
+	Throttle Throttle
+	// FlagThreshold is how many flags a review may collect before it is queued for moderation
+	FlagThreshold int
+	// DefaultSort orders review lists; "" uses domain.DefaultReviewSort
+	DefaultSort string
+	// PublishTimeout bounds each event publish; 0 uses DefaultPublishTimeout
+	PublishTimeout time.Duration
+	// PublishWithinRequestDeadline enables the EVENT_PUBLISH_DEADLINE=request mode
+	PublishWithinRequestDeadline bool
+}
+
+// NewService creates a new review service.
+// Every mutation is written to audits in the same transaction as the change.
 func NewService(
 	repo domain.ReviewRepository,
 	cache ReviewCache,
 	publisher EventPublisher,
+	tx domain.Transactor,
+	audits domain.AuditRepository,
+	opts Options,
 	log *logger.Logger,
 ) *Service {
-	return &Service{
-		repo:      repo,
-		cache:     cache,
-		publisher: publisher,
-		validate:  pkgValidator.Get(),
-		logger:    log,
+	if opts.Clock == nil {
+		opts.Clock = clock.New()
+	}
+	if opts.PublishTimeout <= 0 {
+		opts.PublishTimeout = DefaultPublishTimeout
 	}
+	if opts.DefaultSort == "" {
+		opts.DefaultSort = domain.DefaultReviewSort
+	}
+
+	s := &Service{
+		repo:                         repo,
+		products:                     opts.Products,
+		cache:                        cache,
+		publisher:                    publisher,
+		tx:                           tx,
+		audits:                       audits,
+		outbox:                       opts.Outbox,
+		clock:                        opts.Clock,
+		sanitizeText:                 opts.SanitizeText,
+		detectLanguage:               opts.DetectLanguage,
+		flagThreshold:                opts.FlagThreshold,
+		defaultSort:                  opts.DefaultSort,
+		publishTimeout:               opts.PublishTimeout,
+		publishWithinRequestDeadline: opts.PublishWithinRequestDeadline,
+		validate:                     pkgValidator.Get(),
+		logger:                       log,
+	}
+	s.SetThrottle(opts.Throttle)
+
+	return s
+}
+
+// SetThrottle replaces the review throttle. Counters already in Redis keep the window
+// they were started with, so a new window takes full effect once they expire.
+func (s *Service) SetThrottle(throttle Throttle) {
+	s.throttle.Store(&throttle)
+}
+
+// sanitize strips HTML from the review text and title when store mode is on, drops a
+// blank title so it is stored as NULL, and lowercases the language so "EN" is accepted.
+// It runs before validation so text that was nothing but markup fails the required check.
+func (s *Service) sanitize(review *domain.Review) {
+	if s.sanitizeText {
+		review.ReviewText = sanitize.StripTags(review.ReviewText)
+		if review.Title != nil {
+			title := sanitize.StripTags(*review.Title)
+			review.Title = &title
+		}
+	}
+	if review.Title != nil && strings.TrimSpace(*review.Title) == "" {
+		review.Title = nil
+	}
+	if review.Language != nil {
+		language := strings.ToLower(strings.TrimSpace(*review.Language))
+		review.Language = &language
+		if language == "" {
+			review.Language = nil
+		}
+	}
+}
+
+// tagLanguage detects the language of a review the client didn't tag, when detection is on.
+// A language the client gave is kept: they know better than a stopword count. Reviews the
+// detector can't place are left untagged.
+func (s *Service) tagLanguage(review *domain.Review) {
+	if !s.detectLanguage || review.Language != nil {
+		return
+	}
+	if language := langdetect.Detect(review.ReviewText); language != "" {
+		review.Language = &language
+	}
+}
+
+// hashEmail replaces the validated email with its Gravatar hash, so the address is gone
+// before the review is stored, audited or published. Without an email the hash is cleared:
+// like every field, update replaces it.
+func hashEmail(review *domain.Review) {
+	review.EmailHash = nil
+	if review.Email != "" {
+		hash := domain.HashEmail(review.Email)
+		review.EmailHash = &hash
+		review.Email = ""
+	}
+}
+
+// checkThrottle counts this submission against the client's limit for the product.
+// Fails open: if Redis is unavailable the review is accepted rather than blocking
+// every reviewer because of an abuse control.
+func (s *Service) checkThrottle(ctx context.Context, productID uuid.UUID) error {
+	throttle := s.throttle.Load()
+	ip := clientip.FromContext(ctx)
+	if throttle.Limit <= 0 || ip == "" {
+		return nil
+	}
+
+	attempts, err := s.cache.IncrReviewAttempts(ctx, ip, productID, throttle.Window)
+	if err != nil {
+		s.logger.WithFields(map[string]any{
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Warn("Failed to check review throttle, accepting review")
+		return nil
+	}
+
+	if attempts > int64(throttle.Limit) {
+		s.logger.WithFields(map[string]any{
+			"product_id": productID,
+			"client_ip":  ip,
+			"attempts":   attempts,
+		}).Warn("Review throttled")
+		return domain.ErrRateLimited
+	}
+
+	return nil
 }
 
 // Create creates a new review
 func (s *Service) Create(ctx context.Context, review *domain.Review) error {
+	// Match the column default so events and responses carry the stored source
+	if review.Source == "" {
+		review.Source = domain.ReviewSourceWeb
+	}
+	s.sanitize(review)
+
 	if err := s.validate.Struct(review); err != nil {
 		s.logger.Error("Review validation failed", err)
-		return domain.ErrInvalidInput
+		return pkgValidator.InvalidInput(err)
 	}
+	hashEmail(review)
+	s.tagLanguage(review)
 
-	if err := s.repo.Create(ctx, review); err != nil {
+	if err := s.checkThrottle(ctx, review.ProductID); err != nil {
+		return err
+	}
+
+	event := s.reviewEvent("review.created", review)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Create(ctx, review); err != nil {
+			return err
+		}
+		if err := audit.Record(ctx, s.audits, domain.AuditActionCreate, domain.AuditEntityReview, review.ID, nil, review); err != nil {
+			return err
+		}
+		return s.stageEvent(ctx, event)
+	})
+	if err != nil {
 		s.logger.Error("Failed to create review", err)
 		return err
 	}
@@ -80,17 +314,82 @@ func (s *Service) Create(ctx context.Context, review *domain.Review) error {
 		}).Warn("Failed to invalidate cache, may serve stale data temporarily")
 	}
 
-	s.publishEvent("review.created", review)
+	s.publish(ctx, event)
 
 	s.logger.WithFields(map[string]any{
 		"review_id":  review.ID,
 		"product_id": review.ProductID,
 		"rating":     review.Rating,
+		"source":     review.Source,
 	}).Info("Review created successfully")
 
 	return nil
 }
 
+// Import creates a batch of reviews for one product in a single transaction.
+// All reviews are validated before any is written, and instead of a review.created
+// event per review a single product.rating.recalc event is published, so a large
+// import costs the rating worker one recalculation and the stream one message.
+func (s *Service) Import(ctx context.Context, productID uuid.UUID, reviews []*domain.Review) error {
+	if len(reviews) == 0 || len(reviews) > MaxImportBatchSize {
+		return fmt.Errorf("%w: import must contain between 1 and %d reviews, got %d", domain.ErrInvalidInput, MaxImportBatchSize, len(reviews))
+	}
+
+	for i, review := range reviews {
+		review.ProductID = productID
+		if review.Source == "" {
+			review.Source = domain.ReviewSourceImport
+		}
+		s.sanitize(review)
+
+		if err := s.validate.Struct(review); err != nil {
+			s.logger.Errorf(err, "Review %d of import failed validation", i)
+			return pkgValidator.InvalidInput(pkgValidator.AtIndex(i, err))
+		}
+		hashEmail(review)
+		s.tagLanguage(review)
+	}
+
+	event := ReviewEvent{
+		EventType: EventTypeRatingRecalc,
+		Timestamp: s.clock.Now(),
+		ProductID: productID,
+	}
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		for _, review := range reviews {
+			if err := s.repo.Create(ctx, review); err != nil {
+				return err
+			}
+			if err := audit.Record(ctx, s.audits, domain.AuditActionCreate, domain.AuditEntityReview, review.ID, nil, review); err != nil {
+				return err
+			}
+		}
+		return s.stageEvent(ctx, event)
+	})
+	if err != nil {
+		s.logger.Error("Failed to import reviews", err)
+		return err
+	}
+
+	// Invalidate cache to prevent stale data, pipelined like bulk delete
+	// Non-fatal: if cache is down, accept temporary staleness over API unavailability
+	if err := s.cache.InvalidateProducts(ctx, []uuid.UUID{productID}); err != nil {
+		s.logger.WithFields(map[string]any{
+			"product_id": productID,
+			"error":      err.Error(),
+		}).Warn("Failed to invalidate cache, may serve stale data temporarily")
+	}
+
+	s.publish(ctx, event)
+
+	s.logger.WithFields(map[string]any{
+		"product_id": productID,
+		"count":      len(reviews),
+	}).Info("Reviews imported successfully")
+
+	return nil
+}
+
 // GetByID retrieves a review by ID
 func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
 	review, err := s.repo.GetByID(ctx, id)
@@ -108,42 +407,256 @@ func (s *Service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Review, er
 
 // GetByProductID retrieves reviews for a product with caching (includes total count in cache)
 func (s *Service) GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, int, error) {
-	if limit <= 0 || limit > 100 {
-		limit = 20
+	return s.GetByProductIDAndLanguage(ctx, productID, "", limit, offset)
+}
+
+// GetByProductIDAndLanguage is GetByProductID limited to reviews in one language; ""
+// returns every review. Each language's pages are cached separately.
+// An empty page of a product that doesn't exist returns domain.ErrNotFound, so clients can
+// tell it from a product without reviews; this needs a ProductLookup.
+func (s *Service) GetByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language string, limit, offset int) ([]*domain.Review, int, error) {
+	if err := domain.CheckPageSize(limit); err != nil {
+		return nil, 0, err
 	}
 	if offset < 0 {
 		offset = 0
 	}
+	if language != "" && !domain.IsValidLanguage(language) {
+		return nil, 0, fmt.Errorf("%w: language must be a two-letter ISO 639-1 code, got %q", domain.ErrInvalidInput, language)
+	}
 
 	// Try cache first - includes total count
-	reviews, total, err := s.cache.GetReviewsList(ctx, productID, limit, offset)
+	reviews, total, err := s.cache.GetReviewsList(ctx, productID, language, limit, offset)
 	if err == nil {
-		s.logger.Debugf("Cache hit for product %s reviews (limit=%d, offset=%d)", productID, limit, offset)
+		s.logger.Debugf("Cache hit for product %s reviews (language=%q, limit=%d, offset=%d)", productID, language, limit, offset)
 		return reviews, total, nil
 	}
 
 	// Cache miss - fetch from database
-	s.logger.Debugf("Cache miss for product %s reviews (limit=%d, offset=%d)", productID, limit, offset)
-	reviews, err = s.repo.GetByProductID(ctx, productID, limit, offset)
+	s.logger.Debugf("Cache miss for product %s reviews (language=%q, limit=%d, offset=%d)", productID, language, limit, offset)
+	if language == "" {
+		reviews, err = s.repo.GetByProductID(ctx, productID, s.defaultSort, limit, offset)
+	} else {
+		reviews, err = s.repo.GetByProductIDAndLanguage(ctx, productID, language, s.defaultSort, limit, offset)
+	}
 	if err != nil {
 		s.logger.Error("Failed to get reviews by product ID", err)
 		return nil, 0, err
 	}
 
-	total, err = s.repo.CountByProductID(ctx, productID)
+	if language == "" {
+		total, err = s.repo.CountByProductID(ctx, productID)
+	} else {
+		total, err = s.repo.CountByProductIDAndLanguage(ctx, productID, language)
+	}
 	if err != nil {
 		s.logger.Error("Failed to count reviews", err)
 		return nil, 0, err
 	}
 
+	// Only an empty list needs the product: deleting a product deletes its reviews, so a
+	// page with reviews proves it exists. That keeps the check off the hot path: cached pages
+	// were checked when they were filled, and product.Service drops them on delete.
+	if total == 0 && s.products != nil {
+		if _, err := s.products.GetByID(ctx, productID); err != nil {
+			if !errors.Is(err, domain.ErrNotFound) {
+				s.logger.Error("Failed to get product for reviews list", err)
+			}
+			return nil, 0, err
+		}
+	}
+
 	// Cache both reviews and total count together
-	if err := s.cache.SetReviewsList(ctx, productID, limit, offset, reviews, total); err != nil {
-		s.logger.Warnf("Failed to cache reviews for product %s (limit=%d, offset=%d): %v", productID, limit, offset, err)
+	if err := s.cache.SetReviewsList(ctx, productID, language, limit, offset, reviews, total); err != nil {
+		s.logger.Warnf("Failed to cache reviews for product %s (language=%q, limit=%d, offset=%d): %v", productID, language, limit, offset, err)
 	}
 
 	return reviews, total, nil
 }
 
+// GetOverview retrieves the first page of reviews, total count and rating distribution with caching
+// Cached as one entry so product detail pages cost a single cache read
+func (s *Service) GetOverview(ctx context.Context, productID uuid.UUID, limit int) (*domain.ReviewOverview, error) {
+	if err := domain.CheckPageSize(limit); err != nil {
+		return nil, err
+	}
+
+	overview, err := s.cache.GetReviewOverview(ctx, productID, limit)
+	if err == nil {
+		s.logger.Debugf("Cache hit for product %s review overview (limit=%d)", productID, limit)
+		return overview, nil
+	}
+
+	s.logger.Debugf("Cache miss for product %s review overview (limit=%d)", productID, limit)
+	reviews, err := s.repo.GetByProductID(ctx, productID, s.defaultSort, limit, 0)
+	if err != nil {
+		s.logger.Error("Failed to get reviews by product ID", err)
+		return nil, err
+	}
+
+	total, err := s.repo.CountByProductID(ctx, productID)
+	if err != nil {
+		s.logger.Error("Failed to count reviews", err)
+		return nil, err
+	}
+
+	counts, err := s.repo.GetRatingDistribution(ctx, productID)
+	if err != nil {
+		s.logger.Error("Failed to get rating distribution", err)
+		return nil, err
+	}
+
+	// Report every star level so clients can render the histogram without filling gaps
+	distribution := make(map[int]int, domain.MaxRating())
+	for rating := 1; rating <= domain.MaxRating(); rating++ {
+		distribution[rating] = counts[rating]
+	}
+
+	overview = &domain.ReviewOverview{
+		Reviews:            reviews,
+		Total:              total,
+		RatingDistribution: distribution,
+	}
+
+	if err := s.cache.SetReviewOverview(ctx, productID, limit, overview); err != nil {
+		s.logger.Warnf("Failed to cache review overview for product %s (limit=%d): %v", productID, limit, err)
+	}
+
+	return overview, nil
+}
+
+// SummaryExcerptLength caps the latest review excerpt in a summary, in characters
+const SummaryExcerptLength = 140
+
+// GetSummary returns a product's average rating, review count, rating distribution and an
+// excerpt of its newest review, cached as one entry so a listing grid costs one cache read
+// per card. Requires a ProductLookup; returns domain.ErrNotFound for unknown products.
+func (s *Service) GetSummary(ctx context.Context, productID uuid.UUID) (*domain.ReviewSummary, error) {
+	summary, err := s.cache.GetReviewSummary(ctx, productID)
+	if err == nil {
+		s.logger.Debugf("Cache hit for product %s review summary", productID)
+		return summary, nil
+	}
+
+	s.logger.Debugf("Cache miss for product %s review summary", productID)
+	if s.products == nil {
+		return nil, errors.New("review summary requires a product lookup")
+	}
+
+	product, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.logger.Error("Failed to get product for review summary", err)
+		}
+		return nil, err
+	}
+
+	counts, err := s.repo.GetRatingDistribution(ctx, productID)
+	if err != nil {
+		s.logger.Error("Failed to get rating distribution", err)
+		return nil, err
+	}
+
+	// Always the newest review, whatever order the reviews list uses
+	latest, err := s.repo.GetByProductID(ctx, productID, domain.ReviewSortNewest, 1, 0)
+	if err != nil {
+		s.logger.Error("Failed to get latest review", err)
+		return nil, err
+	}
+
+	distribution := make(map[int]int, domain.MaxRating())
+	for rating := 1; rating <= domain.MaxRating(); rating++ {
+		distribution[rating] = counts[rating]
+	}
+
+	summary = &domain.ReviewSummary{
+		AverageRating:      product.AverageRating,
+		ReviewCount:        product.ReviewCount,
+		RatingDistribution: distribution,
+	}
+	if len(latest) > 0 {
+		text := excerpt(latest[0].ReviewText, SummaryExcerptLength)
+		summary.LatestReviewExcerpt = &text
+	}
+
+	if err := s.cache.SetReviewSummary(ctx, productID, summary); err != nil {
+		s.logger.Warnf("Failed to cache review summary for product %s: %v", productID, err)
+	}
+
+	return summary, nil
+}
+
+// excerpt shortens text to at most maxRunes characters, cutting at the last word boundary
+// when there is one and marking the cut with an ellipsis
+func excerpt(text string, maxRunes int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return text
+	}
+
+	// Leave room for the ellipsis; only back up to a space when the cut splits a word
+	cut := string(runes[:maxRunes-1])
+	if runes[maxRunes-1] != ' ' {
+		if i := strings.LastIndex(cut, " "); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRight(cut, " .,;:!?") + "…"
+}
+
+// Recent feed sizes; the feed is a homepage widget, not a paging API
+const (
+	DefaultRecentLimit = 20
+	MaxRecentLimit     = 100
+)
+
+// Recent returns the newest reviews across all products with their product names.
+// Cached briefly rather than invalidated: every review write would otherwise clear it.
+func (s *Service) Recent(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	if limit <= 0 || limit > MaxRecentLimit {
+		limit = DefaultRecentLimit
+	}
+
+	reviews, err := s.cache.GetRecentReviews(ctx, limit)
+	if err == nil {
+		s.logger.Debugf("Cache hit for recent reviews (limit=%d)", limit)
+		return reviews, nil
+	}
+
+	s.logger.Debugf("Cache miss for recent reviews (limit=%d)", limit)
+	reviews, err = s.repo.Recent(ctx, limit)
+	if err != nil {
+		s.logger.Error("Failed to get recent reviews", err)
+		return nil, err
+	}
+
+	if err := s.cache.SetRecentReviews(ctx, limit, reviews); err != nil {
+		s.logger.Warnf("Failed to cache recent reviews (limit=%d): %v", limit, err)
+	}
+
+	return reviews, nil
+}
+
+// MaxChangesLimit caps a single ChangesSince page
+const MaxChangesLimit = 500
+
+// ChangesSince returns reviews created, updated or deleted after (since, afterID) for
+// incremental sync. Read straight from the database: sync consumers need exact data.
+func (s *Service) ChangesSince(ctx context.Context, since time.Time, afterID uuid.UUID, limit int) ([]*domain.Review, error) {
+	if limit <= 0 || limit > MaxChangesLimit {
+		limit = MaxChangesLimit
+	}
+
+	reviews, err := s.repo.ChangesSince(ctx, since, afterID, limit)
+	if err != nil {
+		s.logger.Error("Failed to list review changes", err)
+		return nil, err
+	}
+
+	return reviews, nil
+}
+
 // Update updates an existing review
 func (s *Service) Update(ctx context.Context, review *domain.Review) error {
 	// Product ID is needed for validation, cache invalidation, and events but not provided in update request
@@ -153,15 +666,30 @@ func (s *Service) Update(ctx context.Context, review *domain.Review) error {
 		return err
 	}
 
-	// Set product ID from existing review before validation
+	// Product and source are fixed at creation; carry them over before validation
 	review.ProductID = existingReview.ProductID
+	review.Source = existingReview.Source
+	s.sanitize(review)
 
 	if err := s.validate.Struct(review); err != nil {
 		s.logger.Error("Review validation failed", err)
-		return domain.ErrInvalidInput
+		return pkgValidator.InvalidInput(err)
 	}
+	hashEmail(review)
+	// The text may have changed, so an untagged update is detected afresh
+	s.tagLanguage(review)
 
-	if err := s.repo.Update(ctx, review); err != nil {
+	event := s.reviewEvent("review.updated", review)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Update(ctx, review); err != nil {
+			return err
+		}
+		if err := audit.Record(ctx, s.audits, domain.AuditActionUpdate, domain.AuditEntityReview, review.ID, existingReview, review); err != nil {
+			return err
+		}
+		return s.stageEvent(ctx, event)
+	})
+	if err != nil {
 		s.logger.Error("Failed to update review", err)
 		return err
 	}
@@ -175,7 +703,7 @@ func (s *Service) Update(ctx context.Context, review *domain.Review) error {
 		}).Warn("Failed to invalidate cache, may serve stale data temporarily")
 	}
 
-	s.publishEvent("review.updated", review)
+	s.publish(ctx, event)
 
 	s.logger.WithFields(map[string]any{
 		"review_id":  review.ID,
@@ -194,7 +722,17 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 		return err
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
+	event := s.reviewEvent("review.deleted", review)
+	err = s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			return err
+		}
+		if err := audit.Record(ctx, s.audits, domain.AuditActionDelete, domain.AuditEntityReview, id, review, nil); err != nil {
+			return err
+		}
+		return s.stageEvent(ctx, event)
+	})
+	if err != nil {
 		s.logger.Error("Failed to delete review", err)
 		return err
 	}
@@ -208,7 +746,7 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 		}).Warn("Failed to invalidate cache, may serve stale data temporarily")
 	}
 
-	s.publishEvent("review.deleted", review)
+	s.publish(ctx, event)
 
 	s.logger.WithFields(map[string]any{
 		"review_id":  id,
@@ -218,29 +756,315 @@ func (s *Service) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// publishEvent publishes a review event (non-blocking)
-func (s *Service) publishEvent(eventType string, review *domain.Review) {
-	event := ReviewEvent{
+// DeleteBatch soft-deletes several reviews in one transaction, for moderators clearing a
+// spam wave. IDs that don't exist or are already deleted are skipped; the deleted reviews
+// are returned. Caches are invalidated and a single product.rating.recalc event published
+// per affected product, so a wave of 500 reviews on one product costs one recalculation
+// rather than 500. No review.deleted events are sent for the individual reviews.
+func (s *Service) DeleteBatch(ctx context.Context, ids []uuid.UUID) ([]*domain.Review, error) {
+	if len(ids) == 0 || len(ids) > MaxDeleteBatchSize {
+		return nil, fmt.Errorf("%w: bulk delete must contain between 1 and %d review IDs, got %d", domain.ErrInvalidInput, MaxDeleteBatchSize, len(ids))
+	}
+
+	var (
+		deleted    []*domain.Review
+		productIDs []uuid.UUID
+		events     []ReviewEvent
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		deleted, err = s.repo.DeleteBatch(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, review := range deleted {
+			if err := audit.Record(ctx, s.audits, domain.AuditActionDelete, domain.AuditEntityReview, review.ID, review, nil); err != nil {
+				return err
+			}
+		}
+
+		// Group by product, keeping first-seen order so events go out deterministically
+		productIDs, events = nil, nil
+		seen := make(map[uuid.UUID]bool)
+		for _, review := range deleted {
+			if seen[review.ProductID] {
+				continue
+			}
+			seen[review.ProductID] = true
+			productIDs = append(productIDs, review.ProductID)
+
+			event := ReviewEvent{
+				EventType: EventTypeRatingRecalc,
+				Timestamp: s.clock.Now(),
+				ProductID: review.ProductID,
+			}
+			if err := s.stageEvent(ctx, event); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Error("Failed to bulk delete reviews", err)
+		return nil, err
+	}
+
+	// One pipelined round trip however many products the deleted reviews span
+	// Non-fatal: if cache is down, accept temporary staleness over API unavailability
+	if err := s.cache.InvalidateProducts(ctx, productIDs); err != nil {
+		s.logger.WithFields(map[string]any{
+			"products": len(productIDs),
+			"error":    err.Error(),
+		}).Warn("Failed to invalidate cache, may serve stale data temporarily")
+	}
+
+	for _, event := range events {
+		s.publish(ctx, event)
+	}
+
+	s.logger.WithFields(map[string]any{
+		"requested": len(ids),
+		"deleted":   len(deleted),
+		"products":  len(productIDs),
+	}).Info("Reviews bulk deleted successfully")
+
+	return deleted, nil
+}
+
+// Anonymize strips the reviewer's name from a review for right-to-be-forgotten requests.
+// Unlike Delete the review keeps counting toward the product rating.
+func (s *Service) Anonymize(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	var (
+		review *domain.Review
+		event  ReviewEvent
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if review, err = s.repo.Anonymize(ctx, id); err != nil {
+			return err
+		}
+
+		// Earlier snapshots still carry the name and email hash; scrub them, and leave out the
+		// before snapshot here, or the audit trail would keep exactly what was erased
+		anonymous := map[string]any{"first_name": domain.AnonymousName, "last_name": domain.AnonymousName, "email_hash": nil}
+		if err := s.audits.Redact(ctx, id, anonymous); err != nil {
+			return err
+		}
+		if err := audit.Record(ctx, s.audits, domain.AuditActionAnonymize, domain.AuditEntityReview, id, nil, review); err != nil {
+			return err
+		}
+
+		event = s.reviewEvent("review.anonymized", review)
+		return s.stageEvent(ctx, event)
+	})
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			s.logger.Error("Failed to anonymize review", err)
+		}
+		return nil, err
+	}
+
+	// Cached pages still carry the old name, so invalidation matters more than usual here
+	if err := s.cache.InvalidateAllProductCache(ctx, review.ProductID); err != nil {
+		s.logger.WithFields(map[string]any{
+			"product_id": review.ProductID,
+			"error":      err.Error(),
+		}).Warn("Failed to invalidate cache, anonymized review may be served with its old name until TTL expiry")
+	}
+
+	s.publish(ctx, event)
+
+	s.logger.WithFields(map[string]any{
+		"review_id":  review.ID,
+		"product_id": review.ProductID,
+	}).Info("Review anonymized successfully")
+
+	return review, nil
+}
+
+// Flag records a shopper's report against a review. The flag that takes the review past
+// the flag threshold puts it in the moderation queue and publishes review.flagged; later
+// flags only add to the count, so moderators aren't notified again for the same review.
+func (s *Service) Flag(ctx context.Context, flag *domain.ReviewFlag) (*domain.FlaggedReview, error) {
+	flag.Reason = strings.TrimSpace(flag.Reason)
+	flag.ClientIP = clientip.FromContext(ctx)
+
+	if err := s.validate.Struct(flag); err != nil {
+		return nil, pkgValidator.InvalidInput(err)
+	}
+	// Flags are deduplicated per client IP; without one a caller could flag a review
+	// into the moderation queue on its own
+	if flag.ClientIP == "" {
+		return nil, fmt.Errorf("%w: flagging a review needs a client IP", domain.ErrInvalidInput)
+	}
+
+	var (
+		review *domain.FlaggedReview
+		event  *ReviewEvent
+	)
+	err := s.tx.WithinTx(ctx, func(ctx context.Context) error {
+		var err error
+		if review, err = s.repo.Flag(ctx, flag); err != nil {
+			return err
+		}
+
+		event = nil
+		if review.FlagCount != s.flagThreshold+1 {
+			return nil
+		}
+		flagged := s.reviewEvent("review.flagged", &review.Review)
+		event = &flagged
+		return s.stageEvent(ctx, flagged)
+	})
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) && !errors.Is(err, domain.ErrAlreadyExists) {
+			s.logger.Error("Failed to flag review", err)
+		}
+		return nil, err
+	}
+
+	if event != nil {
+		s.publish(ctx, *event)
+
+		s.logger.WithFields(map[string]any{
+			"review_id":  review.ID,
+			"product_id": review.ProductID,
+			"flag_count": review.FlagCount,
+		}).Warn("Review flagged past threshold, queued for moderation")
+	}
+
+	return review, nil
+}
+
+// ListFlagged returns a page of the moderation queue: reviews flagged more than the
+// flag threshold, most flagged first, with the queue's total size
+func (s *Service) ListFlagged(ctx context.Context, limit, offset int) ([]*domain.FlaggedReview, int, error) {
+	reviews, err := s.repo.ListFlagged(ctx, s.flagThreshold, limit, offset)
+	if err != nil {
+		s.logger.Error("Failed to list flagged reviews", err)
+		return nil, 0, err
+	}
+
+	total, err := s.repo.CountFlagged(ctx, s.flagThreshold)
+	if err != nil {
+		s.logger.Error("Failed to count flagged reviews", err)
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+// reviewEvent builds an event about review. The review is serialized when the event is
+// staged or published, so fields set by the repository (the ID on create) are included.
+func (s *Service) reviewEvent(eventType string, review *domain.Review) ReviewEvent {
+	return ReviewEvent{
 		EventType: eventType,
-		Timestamp: time.Now(),
+		Timestamp: s.clock.Now(),
 		ProductID: review.ProductID,
 		Review:    review,
 	}
+}
+
+// stageEvent writes event to the outbox; ctx must carry the mutation's transaction so the
+// event commits or rolls back with it. Without an outbox it does nothing, and publish
+// sends the event after commit instead.
+func (s *Service) stageEvent(ctx context.Context, event ReviewEvent) error {
+	if s.outbox == nil {
+		return nil
+	}
 
+	event.ProductName = s.productName(ctx, event.ProductID)
 	data, err := json.Marshal(event)
 	if err != nil {
-		s.logger.Errorf(err, "Failed to marshal event for review %s", review.ID)
+		return fmt.Errorf("failed to marshal %s event: %w", event.EventType, err)
+	}
+	return s.outbox.Add(ctx, EventSubject, data)
+}
+
+// publish sends an event in the background (non-blocking); events already staged in the
+// outbox are left to the relay.
+// ctx is the request's; see publishContext for how much of it the publish keeps.
+func (s *Service) publish(ctx context.Context, event ReviewEvent) {
+	if s.outbox != nil {
+		return
+	}
+
+	publishCtx, cancel := s.publishContext(ctx)
+	if publishCtx.Err() != nil {
+		cancel()
+		s.logger.Warnf("Request deadline passed, dropping %s event for product %s", event.EventType, event.ProductID)
 		return
 	}
 
 	// Publish in background to avoid blocking the HTTP response
-	// Use detached context with timeout to prevent cancellation when HTTP request completes
+	s.publishes.Add(1)
 	go func() {
-		publishCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer s.publishes.Done()
 		defer cancel()
 
-		if err := s.publisher.Publish(publishCtx, "reviews.events", data); err != nil {
-			s.logger.Errorf(err, "Failed to publish event for review %s", review.ID)
+		// Looked up here rather than in the request so the extra query doesn't add latency
+		event.ProductName = s.productName(publishCtx, event.ProductID)
+
+		data, err := json.Marshal(event)
+		if err != nil {
+			s.logger.Errorf(err, "Failed to marshal %s event for product %s", event.EventType, event.ProductID)
+			return
+		}
+
+		if err := s.publisher.Publish(publishCtx, EventSubject, data); err != nil {
+			s.logger.Errorf(err, "Failed to publish %s event for product %s", event.EventType, event.ProductID)
 		}
 	}()
 }
+
+// publishContext returns the context for a background publish, bounded by publishTimeout.
+// It never inherits the request's cancellation, which fires as soon as the response is
+// written. By default it is detached from the request entirely, so an event goes out even
+// when the request was about to time out; with publishWithinRequestDeadline the request's
+// deadline caps it too, and a request past its deadline publishes nothing.
+func (s *Service) publishContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if !s.publishWithinRequestDeadline {
+		return context.WithTimeout(context.Background(), s.publishTimeout)
+	}
+
+	deadline := s.clock.Now().Add(s.publishTimeout)
+	if requestDeadline, ok := ctx.Deadline(); ok && requestDeadline.Before(deadline) {
+		deadline = requestDeadline
+	}
+	return context.WithDeadline(context.WithoutCancel(ctx), deadline)
+}
+
+// productName returns the product's name, or "" when it can't be resolved.
+// Best effort: a missing name must not cost the event.
+func (s *Service) productName(ctx context.Context, productID uuid.UUID) string {
+	if s.products == nil {
+		return ""
+	}
+
+	product, err := s.products.GetByID(ctx, productID)
+	if err != nil {
+		s.logger.Warnf("Failed to look up product %s for event, publishing without its name: %v", productID, err)
+		return ""
+	}
+
+	return product.Name
+}
+
+// Shutdown waits for background event publishes to finish, or until ctx is done.
+// Call it after the HTTP server has stopped accepting requests and before closing
+// the publisher, otherwise events from the last requests are lost.
+func (s *Service) Shutdown(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		s.publishes.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("gave up waiting for event publishes: %w", ctx.Err())
+	}
+}
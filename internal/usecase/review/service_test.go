@@ -2,14 +2,24 @@ package review
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/audit"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clientip"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	pkgValidator "github.com/Pesokrava/product_reviewer/internal/pkg/validator"
+	"github.com/Pesokrava/product_reviewer/internal/testutil/fakes"
 )
 
 // MockReviewRepository is a mock implementation of domain.ReviewRepository
@@ -30,8 +40,16 @@ func (m *MockReviewRepository) GetByID(ctx context.Context, id uuid.UUID) (*doma
 	return args.Get(0).(*domain.Review), args.Error(1)
 }
 
-func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, error) {
-	args := m.Called(ctx, productID, limit, offset)
+func (m *MockReviewRepository) GetByProductID(ctx context.Context, productID uuid.UUID, sort string, limit, offset int) ([]*domain.Review, error) {
+	args := m.Called(ctx, productID, sort, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language, sort string, limit, offset int) ([]*domain.Review, error) {
+	args := m.Called(ctx, productID, language, sort, limit, offset)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
@@ -48,6 +66,14 @@ func (m *MockReviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+func (m *MockReviewRepository) DeleteBatch(ctx context.Context, ids []uuid.UUID) ([]*domain.Review, error) {
+	args := m.Called(ctx, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
 func (m *MockReviewRepository) DeleteByProductID(ctx context.Context, productID uuid.UUID) error {
 	args := m.Called(ctx, productID)
 	return args.Error(0)
@@ -58,21 +84,126 @@ func (m *MockReviewRepository) CountByProductID(ctx context.Context, productID u
 	return args.Int(0), args.Error(1)
 }
 
+func (m *MockReviewRepository) CountByProductIDAndLanguage(ctx context.Context, productID uuid.UUID, language string) (int, error) {
+	args := m.Called(ctx, productID, language)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingDistribution(ctx context.Context, productID uuid.UUID) (map[int]int, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[int]int), args.Error(1)
+}
+
+func (m *MockReviewRepository) GetRatingDistributions(ctx context.Context, productIDs []uuid.UUID) (map[uuid.UUID]map[int]int, error) {
+	args := m.Called(ctx, productIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uuid.UUID]map[int]int), args.Error(1)
+}
+
+func (m *MockReviewRepository) ChangesSince(ctx context.Context, since time.Time, afterID uuid.UUID, limit int) ([]*domain.Review, error) {
+	args := m.Called(ctx, since, afterID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) Recent(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RecentReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) Anonymize(ctx context.Context, id uuid.UUID) (*domain.Review, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.Review), args.Error(1)
+}
+
+func (m *MockReviewRepository) Flag(ctx context.Context, flag *domain.ReviewFlag) (*domain.FlaggedReview, error) {
+	args := m.Called(ctx, flag)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.FlaggedReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) ListFlagged(ctx context.Context, threshold, limit, offset int) ([]*domain.FlaggedReview, error) {
+	args := m.Called(ctx, threshold, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.FlaggedReview), args.Error(1)
+}
+
+func (m *MockReviewRepository) CountFlagged(ctx context.Context, threshold int) (int, error) {
+	args := m.Called(ctx, threshold)
+	return args.Int(0), args.Error(1)
+}
+
 // MockRedisCache is a mock implementation of cache.RedisCache
 type MockRedisCache struct {
 	mock.Mock
 }
 
-func (m *MockRedisCache) GetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, int, error) {
-	args := m.Called(ctx, productID, limit, offset)
+func (m *MockRedisCache) GetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int) ([]*domain.Review, int, error) {
+	args := m.Called(ctx, productID, language, limit, offset)
 	if args.Get(0) == nil {
 		return nil, 0, args.Error(2)
 	}
 	return args.Get(0).([]*domain.Review), args.Int(1), args.Error(2)
 }
 
-func (m *MockRedisCache) SetReviewsList(ctx context.Context, productID uuid.UUID, limit, offset int, reviews []*domain.Review, total int) error {
-	args := m.Called(ctx, productID, limit, offset, reviews, total)
+func (m *MockRedisCache) SetReviewsList(ctx context.Context, productID uuid.UUID, language string, limit, offset int, reviews []*domain.Review, total int) error {
+	args := m.Called(ctx, productID, language, limit, offset, reviews, total)
+	return args.Error(0)
+}
+
+func (m *MockRedisCache) GetReviewOverview(ctx context.Context, productID uuid.UUID, limit int) (*domain.ReviewOverview, error) {
+	args := m.Called(ctx, productID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewOverview), args.Error(1)
+}
+
+func (m *MockRedisCache) SetReviewOverview(ctx context.Context, productID uuid.UUID, limit int, overview *domain.ReviewOverview) error {
+	args := m.Called(ctx, productID, limit, overview)
+	return args.Error(0)
+}
+
+func (m *MockRedisCache) GetReviewSummary(ctx context.Context, productID uuid.UUID) (*domain.ReviewSummary, error) {
+	args := m.Called(ctx, productID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*domain.ReviewSummary), args.Error(1)
+}
+
+func (m *MockRedisCache) SetReviewSummary(ctx context.Context, productID uuid.UUID, summary *domain.ReviewSummary) error {
+	args := m.Called(ctx, productID, summary)
+	return args.Error(0)
+}
+
+func (m *MockRedisCache) GetRecentReviews(ctx context.Context, limit int) ([]*domain.RecentReview, error) {
+	args := m.Called(ctx, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*domain.RecentReview), args.Error(1)
+}
+
+func (m *MockRedisCache) SetRecentReviews(ctx context.Context, limit int, reviews []*domain.RecentReview) error {
+	args := m.Called(ctx, limit, reviews)
 	return args.Error(0)
 }
 
@@ -81,6 +212,16 @@ func (m *MockRedisCache) InvalidateAllProductCache(ctx context.Context, productI
 	return args.Error(0)
 }
 
+func (m *MockRedisCache) InvalidateProducts(ctx context.Context, productIDs []uuid.UUID) error {
+	args := m.Called(ctx, productIDs)
+	return args.Error(0)
+}
+
+func (m *MockRedisCache) IncrReviewAttempts(ctx context.Context, ip string, productID uuid.UUID, window time.Duration) (int64, error) {
+	args := m.Called(ctx, ip, productID, window)
+	return args.Get(0).(int64), args.Error(1)
+}
+
 // MockEventPublisher is a mock implementation of EventPublisher
 type MockEventPublisher struct {
 	mock.Mock
@@ -91,12 +232,48 @@ func (m *MockEventPublisher) Publish(ctx context.Context, subject string, data [
 	return args.Error(0)
 }
 
+// fakeOutboxRepository records staged events
+type fakeOutboxRepository struct {
+	subjects []string
+	payloads [][]byte
+	err      error
+}
+
+func (f *fakeOutboxRepository) Add(ctx context.Context, subject string, payload []byte) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.subjects = append(f.subjects, subject)
+	f.payloads = append(f.payloads, payload)
+	return nil
+}
+
+func (f *fakeOutboxRepository) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	return nil, nil
+}
+
+func (f *fakeOutboxRepository) MarkSent(ctx context.Context, ids []int64) error {
+	return nil
+}
+
+func (f *fakeOutboxRepository) Release(ctx context.Context, ids []int64) error {
+	return nil
+}
+
+func (f *fakeOutboxRepository) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (f *fakeOutboxRepository) Lag(ctx context.Context) (*domain.OutboxLag, error) {
+	return &domain.OutboxLag{}, nil
+}
+
 func TestService_Create_Success(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	productID := uuid.New()
 	review := &domain.Review{
@@ -118,12 +295,298 @@ func TestService_Create_Success(t *testing.T) {
 	mockCache.AssertExpectations(t)
 }
 
+func TestService_Create_SanitizesTextInStoreMode(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{SanitizeText: true}, logger.New("test"))
+
+	productID := uuid.New()
+	review := &domain.Review{
+		ProductID:  productID,
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "<b>Great</b> product<script>alert(1)</script>",
+		Rating:     5,
+	}
+
+	mockRepo.On("Create", mock.Anything, review).Return(nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, productID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+	require.NoError(t, service.Create(context.Background(), review))
+	assert.Equal(t, "Great product", review.ReviewText)
+}
+
+func TestService_Create_MarkupOnlyTextRejectedInStoreMode(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	service := NewService(mockRepo, new(MockRedisCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{SanitizeText: true}, logger.New("test"))
+
+	err := service.Create(context.Background(), &domain.Review{
+		ProductID:  uuid.New(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "<img src=x onerror=alert(1)>",
+		Rating:     5,
+	})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestService_Create_Title(t *testing.T) {
+	newReview := func(title string) *domain.Review {
+		return &domain.Review{ProductID: uuid.New(), FirstName: "John", LastName: "Doe", Title: &title, ReviewText: "Great", Rating: 5}
+	}
+
+	t.Run("blank title is stored as null", func(t *testing.T) {
+		mockRepo := new(MockReviewRepository)
+		mockCache := new(MockRedisCache)
+		mockPublisher := new(MockEventPublisher)
+		service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+		review := newReview("   ")
+		mockRepo.On("Create", mock.Anything, review).Return(nil)
+		mockCache.On("InvalidateAllProductCache", mock.Anything, review.ProductID).Return(nil)
+		mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+		require.NoError(t, service.Create(context.Background(), review))
+		require.NoError(t, service.Shutdown(context.Background()))
+		assert.Nil(t, review.Title)
+	})
+
+	t.Run("sanitized in store mode", func(t *testing.T) {
+		mockRepo := new(MockReviewRepository)
+		mockCache := new(MockRedisCache)
+		mockPublisher := new(MockEventPublisher)
+		service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{SanitizeText: true}, logger.New("test"))
+
+		review := newReview("<b>Loud</b> fan")
+		mockRepo.On("Create", mock.Anything, review).Return(nil)
+		mockCache.On("InvalidateAllProductCache", mock.Anything, review.ProductID).Return(nil)
+		mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+		require.NoError(t, service.Create(context.Background(), review))
+		require.NoError(t, service.Shutdown(context.Background()))
+		require.NotNil(t, review.Title)
+		assert.Equal(t, "Loud fan", *review.Title)
+	})
+
+	t.Run("longer than 200 characters is rejected", func(t *testing.T) {
+		mockRepo := new(MockReviewRepository)
+		service := NewService(mockRepo, new(MockRedisCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+		err := service.Create(context.Background(), newReview(strings.Repeat("a", 201)))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Create_Email(t *testing.T) {
+	newReview := func(email string) *domain.Review {
+		return &domain.Review{ProductID: uuid.New(), FirstName: "John", LastName: "Doe", Email: email, ReviewText: "Great", Rating: 5}
+	}
+
+	t.Run("only the hash is stored and published", func(t *testing.T) {
+		mockRepo := new(MockReviewRepository)
+		mockCache := new(MockRedisCache)
+		mockPublisher := new(MockEventPublisher)
+		audits := new(fakes.AuditRepository)
+		service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, audits, Options{}, logger.New("test"))
+
+		review := newReview("Jane@Example.com")
+		mockRepo.On("Create", mock.Anything, mock.MatchedBy(func(r *domain.Review) bool {
+			return r.Email == "" && r.EmailHash != nil
+		})).Return(nil)
+		mockCache.On("InvalidateAllProductCache", mock.Anything, review.ProductID).Return(nil)
+		mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+		require.NoError(t, service.Create(context.Background(), review))
+		require.NoError(t, service.Shutdown(context.Background()))
+
+		require.NotNil(t, review.EmailHash)
+		assert.Equal(t, domain.HashEmail("jane@example.com"), *review.EmailHash)
+		payload := string(mockPublisher.Calls[0].Arguments.Get(2).([]byte))
+		assert.NotContains(t, strings.ToLower(payload), "jane@example.com")
+		require.Len(t, audits.Entries, 1)
+		assert.NotContains(t, strings.ToLower(string(*audits.Entries[0].After)), "jane@example.com")
+	})
+
+	t.Run("invalid email is rejected", func(t *testing.T) {
+		mockRepo := new(MockReviewRepository)
+		service := NewService(mockRepo, new(MockRedisCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+		err := service.Create(context.Background(), newReview("not-an-email"))
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Create_Language(t *testing.T) {
+	english := "This is a great product and it was very easy to set up."
+	ptr := func(s string) *string { return &s }
+
+	for name, tc := range map[string]struct {
+		detect   bool
+		language *string
+		want     *string
+	}{
+		"detected when omitted":         {detect: true, want: ptr("en")},
+		"given language wins":           {detect: true, language: ptr("DE"), want: ptr("de")},
+		"blank language is detected":    {detect: true, language: ptr(" "), want: ptr("en")},
+		"left untagged with detect off": {detect: false},
+		"given without detection":       {detect: false, language: ptr("fr"), want: ptr("fr")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockCache := new(MockRedisCache)
+			mockPublisher := new(MockEventPublisher)
+			service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{DetectLanguage: tc.detect}, logger.New("test"))
+
+			review := &domain.Review{ProductID: uuid.New(), FirstName: "John", LastName: "Doe", Language: tc.language, ReviewText: english, Rating: 5}
+			mockRepo.On("Create", mock.Anything, review).Return(nil)
+			mockCache.On("InvalidateAllProductCache", mock.Anything, review.ProductID).Return(nil)
+			mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+			require.NoError(t, service.Create(context.Background(), review))
+			require.NoError(t, service.Shutdown(context.Background()))
+
+			assert.Equal(t, tc.want, review.Language)
+		})
+	}
+
+	t.Run("invalid language is rejected", func(t *testing.T) {
+		mockRepo := new(MockReviewRepository)
+		service := NewService(mockRepo, new(MockRedisCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{DetectLanguage: true}, logger.New("test"))
+
+		err := service.Create(context.Background(), &domain.Review{ProductID: uuid.New(), FirstName: "John", LastName: "Doe", Language: ptr("english"), ReviewText: english, Rating: 5})
+
+		assert.ErrorIs(t, err, domain.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	})
+}
+
+func TestService_Create_Throttle(t *testing.T) {
+	throttle := Throttle{Limit: 2, Window: time.Hour}
+	ctx := clientip.WithIP(context.Background(), "203.0.113.7")
+
+	for name, tc := range map[string]struct {
+		ctx       context.Context
+		attempts  int64
+		cacheErr  error
+		wantErr   error
+		wantCount bool
+	}{
+		"under limit":            {ctx: ctx, attempts: 2, wantCount: true},
+		"over limit":             {ctx: ctx, attempts: 3, wantErr: domain.ErrRateLimited, wantCount: true},
+		"redis down fails open":  {ctx: ctx, cacheErr: errors.New("connection refused"), wantCount: true},
+		"no client IP is exempt": {ctx: context.Background()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockCache := new(MockRedisCache)
+			mockPublisher := new(MockEventPublisher)
+			service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Throttle: throttle}, logger.New("test"))
+
+			productID := uuid.New()
+			review := &domain.Review{ProductID: productID, FirstName: "John", LastName: "Doe", ReviewText: "Great", Rating: 5}
+
+			if tc.wantCount {
+				mockCache.On("IncrReviewAttempts", mock.Anything, "203.0.113.7", productID, time.Hour).Return(tc.attempts, tc.cacheErr)
+			}
+			if tc.wantErr == nil {
+				mockRepo.On("Create", mock.Anything, review).Return(nil)
+				mockCache.On("InvalidateAllProductCache", mock.Anything, productID).Return(nil)
+				mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+			}
+
+			err := service.Create(tc.ctx, review)
+			require.NoError(t, service.Shutdown(context.Background()))
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockCache.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_SetThrottle(t *testing.T) {
+	ctx := clientip.WithIP(context.Background(), "203.0.113.7")
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	productID := uuid.New()
+	review := &domain.Review{ProductID: productID, FirstName: "John", LastName: "Doe", ReviewText: "Great", Rating: 5}
+
+	service.SetThrottle(Throttle{Limit: 1, Window: time.Minute})
+	mockCache.On("IncrReviewAttempts", mock.Anything, "203.0.113.7", productID, time.Minute).Return(int64(2), nil)
+
+	err := service.Create(ctx, review)
+
+	assert.ErrorIs(t, err, domain.ErrRateLimited)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockCache.AssertExpectations(t)
+}
+
+// fakeProductLookup returns a product with a fixed name, or err
+type fakeProductLookup struct {
+	name string
+	err  error
+}
+
+func (f fakeProductLookup) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.Product{ID: id, Name: f.name}, nil
+}
+
+func TestService_Create_EventCarriesProductName(t *testing.T) {
+	for name, tc := range map[string]struct {
+		products ProductLookup
+		want     string
+	}{
+		"resolved":        {products: fakeProductLookup{name: "Widget"}, want: "Widget"},
+		"lookup fails":    {products: fakeProductLookup{err: domain.ErrNotFound}, want: ""},
+		"no lookup wired": {products: nil, want: ""},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockCache := new(MockRedisCache)
+			mockPublisher := new(MockEventPublisher)
+			service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Products: tc.products}, logger.New("test"))
+
+			review := &domain.Review{ProductID: uuid.New(), FirstName: "John", LastName: "Doe", ReviewText: "Great", Rating: 5}
+			mockRepo.On("Create", mock.Anything, review).Return(nil)
+			mockCache.On("InvalidateAllProductCache", mock.Anything, review.ProductID).Return(nil)
+			mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil).Once()
+
+			require.NoError(t, service.Create(context.Background(), review))
+			require.NoError(t, service.Shutdown(context.Background()))
+
+			// A failed lookup still publishes, just without the name
+			mockPublisher.AssertExpectations(t)
+			var event ReviewEvent
+			require.NoError(t, json.Unmarshal(mockPublisher.Calls[0].Arguments.Get(2).([]byte), &event))
+			assert.Equal(t, tc.want, event.ProductName)
+			assert.Equal(t, review.ProductID, event.ProductID)
+		})
+	}
+}
+
 func TestService_Create_InvalidInput(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	review := &domain.Review{
 		ProductID:  uuid.New(),
@@ -136,7 +599,7 @@ func TestService_Create_InvalidInput(t *testing.T) {
 	err := service.Create(context.Background(), review)
 
 	assert.Error(t, err)
-	assert.Equal(t, domain.ErrInvalidInput, err)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
 	mockRepo.AssertNotCalled(t, "Create")
 	mockCache.AssertNotCalled(t, "InvalidateAllProductCache")
 }
@@ -146,7 +609,7 @@ func TestService_Create_CacheInvalidationFailure(t *testing.T) {
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	productID := uuid.New()
 	review := &domain.Review{
@@ -174,7 +637,7 @@ func TestService_GetByID_Success(t *testing.T) {
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	reviewID := uuid.New()
 	expectedReview := &domain.Review{
@@ -200,7 +663,7 @@ func TestService_GetByID_NotFound(t *testing.T) {
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	reviewID := uuid.New()
 
@@ -219,7 +682,7 @@ func TestService_GetByProductID_CacheHit(t *testing.T) {
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	productID := uuid.New()
 	expectedReviews := []*domain.Review{
@@ -228,7 +691,7 @@ func TestService_GetByProductID_CacheHit(t *testing.T) {
 	}
 	expectedTotal := 2
 
-	mockCache.On("GetReviewsList", mock.Anything, productID, 20, 0).Return(expectedReviews, expectedTotal, nil)
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(expectedReviews, expectedTotal, nil)
 
 	reviews, total, err := service.GetByProductID(context.Background(), productID, 20, 0)
 
@@ -245,7 +708,7 @@ func TestService_GetByProductID_CacheMiss(t *testing.T) {
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	productID := uuid.New()
 	expectedReviews := []*domain.Review{
@@ -254,10 +717,10 @@ func TestService_GetByProductID_CacheMiss(t *testing.T) {
 	}
 	expectedTotal := 2
 
-	mockCache.On("GetReviewsList", mock.Anything, productID, 20, 0).Return(nil, 0, assert.AnError)
-	mockRepo.On("GetByProductID", mock.Anything, productID, 20, 0).Return(expectedReviews, nil)
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(nil, 0, assert.AnError)
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 20, 0).Return(expectedReviews, nil)
 	mockRepo.On("CountByProductID", mock.Anything, productID).Return(expectedTotal, nil)
-	mockCache.On("SetReviewsList", mock.Anything, productID, 20, 0, expectedReviews, expectedTotal).Return(nil)
+	mockCache.On("SetReviewsList", mock.Anything, productID, "", 20, 0, expectedReviews, expectedTotal).Return(nil)
 
 	reviews, total, err := service.GetByProductID(context.Background(), productID, 20, 0)
 
@@ -268,12 +731,210 @@ func TestService_GetByProductID_CacheMiss(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestService_GetByProductIDAndLanguage(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	productID := uuid.New()
+	expectedReviews := []*domain.Review{{ID: uuid.New(), ProductID: productID, FirstName: "Jana", LastName: "Novak", Rating: 5}}
+
+	mockCache.On("GetReviewsList", mock.Anything, productID, "de", 20, 0).Return(nil, 0, assert.AnError)
+	mockRepo.On("GetByProductIDAndLanguage", mock.Anything, productID, "de", domain.ReviewSortNewest, 20, 0).Return(expectedReviews, nil)
+	mockRepo.On("CountByProductIDAndLanguage", mock.Anything, productID, "de").Return(1, nil)
+	mockCache.On("SetReviewsList", mock.Anything, productID, "de", 20, 0, expectedReviews, 1).Return(nil)
+
+	reviews, total, err := service.GetByProductIDAndLanguage(context.Background(), productID, "de", 20, 0)
+
+	require.NoError(t, err)
+	assert.Equal(t, expectedReviews, reviews)
+	assert.Equal(t, 1, total)
+	mockRepo.AssertNotCalled(t, "GetByProductID")
+	mockRepo.AssertNotCalled(t, "CountByProductID")
+
+	_, _, err = service.GetByProductIDAndLanguage(context.Background(), productID, "deu", 20, 0)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestService_GetByProductID_EmptyList(t *testing.T) {
+	productID := uuid.New()
+
+	for name, tc := range map[string]struct {
+		products ProductLookup
+		wantErr  error
+	}{
+		"unknown product":         {products: summaryProductLookup{}, wantErr: domain.ErrNotFound},
+		"product without reviews": {products: summaryProductLookup{product: &domain.Product{ID: productID}}},
+		"no lookup":               {products: nil},
+	} {
+		t.Run(name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockCache := new(MockRedisCache)
+			service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Products: tc.products}, logger.New("test"))
+
+			mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(nil, 0, assert.AnError)
+			mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 20, 0).Return([]*domain.Review{}, nil)
+			mockRepo.On("CountByProductID", mock.Anything, productID).Return(0, nil)
+			mockCache.On("SetReviewsList", mock.Anything, productID, "", 20, 0, mock.Anything, 0).Return(nil)
+
+			reviews, total, err := service.GetByProductID(context.Background(), productID, 20, 0)
+
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				mockCache.AssertNotCalled(t, "SetReviewsList", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+				return
+			}
+			require.NoError(t, err)
+			assert.Empty(t, reviews)
+			assert.Zero(t, total)
+		})
+	}
+}
+
+func TestService_GetByProductID_ConfiguredDefaultSort(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{DefaultSort: domain.ReviewSortHighestRating}, logger.New("test"))
+
+	productID := uuid.New()
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 20, 0).Return(nil, 0, assert.AnError)
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortHighestRating, 20, 0).Return([]*domain.Review{}, nil)
+	mockRepo.On("CountByProductID", mock.Anything, productID).Return(0, nil)
+	mockCache.On("SetReviewsList", mock.Anything, productID, "", 20, 0, mock.Anything, 0).Return(nil)
+
+	_, _, err := service.GetByProductID(context.Background(), productID, 20, 0)
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestService_GetOverview_CacheHit(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
+
+	productID := uuid.New()
+	cached := &domain.ReviewOverview{
+		Reviews:            []*domain.Review{{ID: uuid.New(), ProductID: productID, Rating: 5}},
+		Total:              1,
+		RatingDistribution: map[int]int{1: 0, 2: 0, 3: 0, 4: 0, 5: 1},
+	}
+
+	mockCache.On("GetReviewOverview", mock.Anything, productID, 10).Return(cached, nil)
+
+	overview, err := service.GetOverview(context.Background(), productID, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cached, overview)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "GetByProductID")
+}
+
+func TestService_Recent_CacheMiss(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	recent := []*domain.RecentReview{
+		{Review: domain.Review{ID: uuid.New(), Rating: 5}, ProductName: "Widget"},
+	}
+
+	// An out-of-range limit falls back to the default before reaching cache or database
+	mockCache.On("GetRecentReviews", mock.Anything, DefaultRecentLimit).Return(nil, domain.ErrNotFound)
+	mockRepo.On("Recent", mock.Anything, DefaultRecentLimit).Return(recent, nil)
+	mockCache.On("SetRecentReviews", mock.Anything, DefaultRecentLimit, recent).Return(nil)
+
+	reviews, err := service.Recent(context.Background(), MaxRecentLimit+1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, recent, reviews)
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestService_Recent_CacheHit(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	cached := []*domain.RecentReview{
+		{Review: domain.Review{ID: uuid.New(), Rating: 4}, ProductName: "Gadget"},
+	}
+	mockCache.On("GetRecentReviews", mock.Anything, 5).Return(cached, nil)
+
+	reviews, err := service.Recent(context.Background(), 5)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cached, reviews)
+	mockRepo.AssertNotCalled(t, "Recent")
+}
+
+func TestService_GetOverview_CacheMiss(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	log := logger.New("test")
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
+
+	productID := uuid.New()
+	reviews := []*domain.Review{
+		{ID: uuid.New(), ProductID: productID, Rating: 5},
+		{ID: uuid.New(), ProductID: productID, Rating: 3},
+	}
+
+	mockCache.On("GetReviewOverview", mock.Anything, productID, 10).Return(nil, domain.ErrNotFound)
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 10, 0).Return(reviews, nil)
+	mockRepo.On("CountByProductID", mock.Anything, productID).Return(2, nil)
+	mockRepo.On("GetRatingDistribution", mock.Anything, productID).Return(map[int]int{3: 1, 5: 1}, nil)
+	mockCache.On("SetReviewOverview", mock.Anything, productID, 10, mock.Anything).Return(nil)
+
+	overview, err := service.GetOverview(context.Background(), productID, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, reviews, overview.Reviews)
+	assert.Equal(t, 2, overview.Total)
+	// Missing star levels are zero-filled
+	assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 1, 4: 0, 5: 1}, overview.RatingDistribution)
+	mockCache.AssertExpectations(t)
+	mockRepo.AssertExpectations(t)
+}
+
+// The handler applies the configured page sizes; the service takes them as given
+func TestService_PageSizes(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+	productID := uuid.New()
+
+	cached := []*domain.Review{{ID: uuid.New(), ProductID: productID, Rating: 4}}
+	mockCache.On("GetReviewsList", mock.Anything, productID, "", 250, 0).Return(cached, 1, nil)
+	mockCache.On("GetReviewOverview", mock.Anything, productID, 150).Return(&domain.ReviewOverview{Reviews: cached, Total: 1}, nil)
+
+	reviews, _, err := service.GetByProductID(context.Background(), productID, 250, 0)
+	require.NoError(t, err)
+	assert.Equal(t, cached, reviews)
+
+	overview, err := service.GetOverview(context.Background(), productID, 150)
+	require.NoError(t, err)
+	assert.Equal(t, cached, overview.Reviews)
+
+	for _, limit := range []int{0, -1, domain.MaxPageSize + 1} {
+		_, _, err = service.GetByProductID(context.Background(), productID, limit, 0)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput, "limit %d", limit)
+		_, err = service.GetOverview(context.Background(), productID, limit)
+		assert.ErrorIs(t, err, domain.ErrInvalidInput, "limit %d", limit)
+	}
+	mockRepo.AssertNotCalled(t, "GetByProductID", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestService_Update_Success(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	reviewID := uuid.New()
 	productID := uuid.New()
@@ -307,12 +968,190 @@ func TestService_Update_Success(t *testing.T) {
 	mockCache.AssertExpectations(t)
 }
 
+func TestService_Update_RecordsAudit(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	audits := new(fakes.AuditRepository)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, audits, Options{}, logger.New("test"))
+
+	reviewID := uuid.New()
+	existingReview := &domain.Review{ID: reviewID, ProductID: uuid.New(), FirstName: "John", LastName: "Doe", ReviewText: "Great", Rating: 5}
+	updatedReview := &domain.Review{ID: reviewID, FirstName: "John", LastName: "Doe", ReviewText: "Meh", Rating: 2}
+
+	mockRepo.On("GetByID", mock.Anything, reviewID).Return(existingReview, nil)
+	mockRepo.On("Update", mock.Anything, updatedReview).Return(nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, existingReview.ProductID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+	ctx := audit.WithActor(context.Background(), audit.ActorAdmin)
+	require.NoError(t, service.Update(ctx, updatedReview))
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	require.Len(t, audits.Entries, 1)
+	entry := audits.Entries[0]
+	assert.Equal(t, audit.ActorAdmin, entry.Actor)
+	assert.Equal(t, domain.AuditActionUpdate, entry.Action)
+	assert.Equal(t, domain.AuditEntityReview, entry.EntityType)
+	assert.Equal(t, reviewID, entry.EntityID)
+	assert.Contains(t, string(*entry.Before), `"rating":5`)
+	assert.Contains(t, string(*entry.After), `"rating":2`)
+}
+
+func TestService_Create_AuditFailure(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	audits := &fakes.AuditRepository{Err: errors.New("audit_log unavailable")}
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, audits, Options{}, logger.New("test"))
+
+	review := &domain.Review{ProductID: uuid.New(), FirstName: "John", LastName: "Doe", ReviewText: "Great", Rating: 5}
+	mockRepo.On("Create", mock.Anything, review).Return(nil)
+
+	// The transaction rolls back, so the review never existed: nothing to invalidate or announce
+	err := service.Create(context.Background(), review)
+
+	assert.Error(t, err)
+	mockCache.AssertNotCalled(t, "InvalidateAllProductCache", mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Create_StagesEventInOutbox(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	outbox := new(fakeOutboxRepository)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Products: fakeProductLookup{name: "Widget"}, Outbox: outbox}, logger.New("test"))
+
+	review := &domain.Review{ProductID: uuid.New(), FirstName: "John", LastName: "Doe", ReviewText: "Great", Rating: 5}
+	mockRepo.On("Create", mock.Anything, review).Return(nil).Run(func(args mock.Arguments) {
+		args.Get(1).(*domain.Review).ID = uuid.New()
+	})
+	mockCache.On("InvalidateAllProductCache", mock.Anything, review.ProductID).Return(nil)
+
+	require.NoError(t, service.Create(context.Background(), review))
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	// The relay publishes it, not the service
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+	require.Equal(t, []string{EventSubject}, outbox.subjects)
+	var event ReviewEvent
+	require.NoError(t, json.Unmarshal(outbox.payloads[0], &event))
+	assert.Equal(t, "review.created", event.EventType)
+	assert.Equal(t, "Widget", event.ProductName)
+	require.NotNil(t, event.Review)
+	assert.Equal(t, review.ID, event.Review.ID, "staged after the insert assigned the ID")
+}
+
+func TestService_Delete_OutboxFailure(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	outbox := &fakeOutboxRepository{err: errors.New("events_outbox unavailable")}
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Outbox: outbox}, logger.New("test"))
+
+	review := &domain.Review{ID: uuid.New(), ProductID: uuid.New(), Rating: 3}
+	mockRepo.On("GetByID", mock.Anything, review.ID).Return(review, nil)
+	mockRepo.On("Delete", mock.Anything, review.ID).Return(nil)
+
+	// Without its event the delete would never reach the rating worker, so it rolls back
+	err := service.Delete(context.Background(), review.ID)
+
+	assert.Error(t, err)
+	mockCache.AssertNotCalled(t, "InvalidateAllProductCache", mock.Anything, mock.Anything)
+}
+
+func TestService_Anonymize_RedactsAudit(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	audits := new(fakes.AuditRepository)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, audits, Options{}, logger.New("test"))
+
+	reviewID := uuid.New()
+	anonymized := &domain.Review{ID: reviewID, ProductID: uuid.New(), FirstName: domain.AnonymousName, LastName: domain.AnonymousName, Rating: 4}
+
+	mockRepo.On("Anonymize", mock.Anything, reviewID).Return(anonymized, nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, anonymized.ProductID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil)
+
+	_, err := service.Anonymize(context.Background(), reviewID)
+	require.NoError(t, err)
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	assert.Equal(t, []uuid.UUID{reviewID}, audits.Redacted)
+	require.Len(t, audits.Entries, 1)
+	assert.Equal(t, domain.AuditActionAnonymize, audits.Entries[0].Action)
+	assert.Nil(t, audits.Entries[0].Before, "before snapshot would keep the erased name")
+}
+
+func TestService_Flag(t *testing.T) {
+	tests := []struct {
+		name        string
+		flagCount   int
+		wantPublish bool
+	}{
+		{name: "below threshold", flagCount: 2},
+		{name: "first flag past threshold", flagCount: 3, wantPublish: true},
+		{name: "already queued", flagCount: 4},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockPublisher := new(MockEventPublisher)
+			service := NewService(mockRepo, new(MockRedisCache), mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{FlagThreshold: 2}, logger.New("test"))
+
+			reviewID := uuid.New()
+			flagged := &domain.FlaggedReview{Review: domain.Review{ID: reviewID, ProductID: uuid.New()}, FlagCount: tc.flagCount}
+			mockRepo.On("Flag", mock.Anything, &domain.ReviewFlag{ReviewID: reviewID, Reason: "Spam", ClientIP: "203.0.113.7"}).Return(flagged, nil)
+			if tc.wantPublish {
+				mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.MatchedBy(func(data []byte) bool {
+					var event ReviewEvent
+					return json.Unmarshal(data, &event) == nil && event.EventType == "review.flagged"
+				})).Return(nil)
+			}
+
+			ctx := clientip.WithIP(context.Background(), "203.0.113.7")
+			got, err := service.Flag(ctx, &domain.ReviewFlag{ReviewID: reviewID, Reason: "  Spam "})
+			require.NoError(t, err)
+			require.NoError(t, service.Shutdown(context.Background()))
+
+			assert.Equal(t, tc.flagCount, got.FlagCount)
+			mockRepo.AssertExpectations(t)
+			mockPublisher.AssertExpectations(t)
+			if !tc.wantPublish {
+				mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestService_Flag_RequiresReason(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	service := NewService(mockRepo, new(MockRedisCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	_, err := service.Flag(context.Background(), &domain.ReviewFlag{ReviewID: uuid.New(), Reason: "   "})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	mockRepo.AssertNotCalled(t, "Flag", mock.Anything, mock.Anything)
+}
+
+func TestService_Flag_RequiresClientIP(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	service := NewService(mockRepo, new(MockRedisCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	_, err := service.Flag(context.Background(), &domain.ReviewFlag{ReviewID: uuid.New(), Reason: "Spam"})
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	mockRepo.AssertNotCalled(t, "Flag", mock.Anything, mock.Anything)
+}
+
 func TestService_Delete_Success(t *testing.T) {
 	mockRepo := new(MockReviewRepository)
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	reviewID := uuid.New()
 	productID := uuid.New()
@@ -342,7 +1181,7 @@ func TestService_Update_CacheInvalidationFailure(t *testing.T) {
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	reviewID := uuid.New()
 	productID := uuid.New()
@@ -380,7 +1219,7 @@ func TestService_Delete_CacheInvalidationFailure(t *testing.T) {
 	mockCache := new(MockRedisCache)
 	mockPublisher := new(MockEventPublisher)
 	log := logger.New("test")
-	service := NewService(mockRepo, mockCache, mockPublisher, log)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, log)
 
 	reviewID := uuid.New()
 	productID := uuid.New()
@@ -405,3 +1244,406 @@ func TestService_Delete_CacheInvalidationFailure(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 	mockCache.AssertExpectations(t)
 }
+
+func TestService_Shutdown_DrainsPublishes(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	productID := uuid.New()
+	review := &domain.Review{
+		ProductID:  productID,
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Great product!",
+		Rating:     5,
+	}
+
+	release := make(chan time.Time)
+	mockRepo.On("Create", mock.Anything, review).Return(nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, productID).Return(nil)
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).
+		WaitUntil(release).
+		Return(nil)
+
+	require.NoError(t, service.Create(context.Background(), review))
+
+	// Still publishing: a short drain gives up
+	shortCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, service.Shutdown(shortCtx), context.DeadlineExceeded)
+
+	close(release)
+	assert.NoError(t, service.Shutdown(context.Background()))
+	mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+}
+
+func TestService_PublishTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		publishTimeout time.Duration
+		want           time.Duration
+	}{
+		{name: "configured", publishTimeout: 200 * time.Millisecond, want: 200 * time.Millisecond},
+		{name: "unset falls back to the default", want: DefaultPublishTimeout},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockCache := new(MockRedisCache)
+			mockPublisher := new(MockEventPublisher)
+			service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{PublishTimeout: tc.publishTimeout}, logger.New("test"))
+
+			reviewID := uuid.New()
+			anonymized := &domain.Review{ID: reviewID, ProductID: uuid.New()}
+			mockRepo.On("Anonymize", mock.Anything, reviewID).Return(anonymized, nil)
+			mockCache.On("InvalidateAllProductCache", mock.Anything, anonymized.ProductID).Return(nil)
+
+			var (
+				remaining   time.Duration
+				hasDeadline bool
+			)
+			mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).
+				Run(func(args mock.Arguments) {
+					var deadline time.Time
+					deadline, hasDeadline = args.Get(0).(context.Context).Deadline()
+					remaining = time.Until(deadline)
+				}).
+				Return(nil)
+
+			_, err := service.Anonymize(context.Background(), reviewID)
+			require.NoError(t, err)
+			require.NoError(t, service.Shutdown(context.Background()))
+
+			require.True(t, hasDeadline, "publishes must be bounded")
+			assert.LessOrEqual(t, remaining, tc.want)
+			assert.Greater(t, remaining, tc.want-100*time.Millisecond)
+		})
+	}
+}
+
+func TestService_PublishDeadline(t *testing.T) {
+	tests := []struct {
+		name                 string
+		withinRequest        bool
+		requestTimeout       time.Duration
+		wantRemainingAtLeast time.Duration
+		wantRemainingAtMost  time.Duration
+	}{
+		{name: "detached ignores the request deadline", requestTimeout: 50 * time.Millisecond, wantRemainingAtLeast: 900 * time.Millisecond, wantRemainingAtMost: time.Second},
+		{name: "request deadline caps the publish", withinRequest: true, requestTimeout: 300 * time.Millisecond, wantRemainingAtLeast: 100 * time.Millisecond, wantRemainingAtMost: 300 * time.Millisecond},
+		{name: "publish timeout still applies before a distant request deadline", withinRequest: true, requestTimeout: time.Minute, wantRemainingAtLeast: 900 * time.Millisecond, wantRemainingAtMost: time.Second},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			mockRepo := new(MockReviewRepository)
+			mockCache := new(MockRedisCache)
+			mockPublisher := new(MockEventPublisher)
+			service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{PublishTimeout: time.Second, PublishWithinRequestDeadline: tc.withinRequest}, logger.New("test"))
+
+			reviewID := uuid.New()
+			anonymized := &domain.Review{ID: reviewID, ProductID: uuid.New()}
+			mockRepo.On("Anonymize", mock.Anything, reviewID).Return(anonymized, nil)
+			mockCache.On("InvalidateAllProductCache", mock.Anything, anonymized.ProductID).Return(nil)
+
+			var remaining time.Duration
+			mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).
+				Run(func(args mock.Arguments) {
+					deadline, _ := args.Get(0).(context.Context).Deadline()
+					remaining = time.Until(deadline)
+				}).
+				Return(nil)
+
+			ctx, cancel := context.WithTimeout(context.Background(), tc.requestTimeout)
+			_, err := service.Anonymize(ctx, reviewID)
+			// The response has been written: the request context is cancelled, the publish isn't
+			cancel()
+			require.NoError(t, err)
+			require.NoError(t, service.Shutdown(context.Background()))
+
+			mockPublisher.AssertNumberOfCalls(t, "Publish", 1)
+			assert.GreaterOrEqual(t, remaining, tc.wantRemainingAtLeast)
+			assert.LessOrEqual(t, remaining, tc.wantRemainingAtMost)
+		})
+	}
+}
+
+func TestService_PublishDeadline_UsesClock(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Clock: clock.NewFake(now), PublishTimeout: 5 * time.Second, PublishWithinRequestDeadline: true}, logger.New("test"))
+
+	reviewID := uuid.New()
+	anonymized := &domain.Review{ID: reviewID, ProductID: uuid.New()}
+	mockRepo.On("Anonymize", mock.Anything, reviewID).Return(anonymized, nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, anonymized.ProductID).Return(nil)
+
+	var deadline time.Time
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).
+		Run(func(args mock.Arguments) {
+			deadline, _ = args.Get(0).(context.Context).Deadline()
+		}).
+		Return(nil)
+
+	ctx, cancel := context.WithDeadline(context.Background(), now.Add(time.Minute))
+	defer cancel()
+	_, err := service.Anonymize(ctx, reviewID)
+	require.NoError(t, err)
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	assert.Equal(t, now.Add(5*time.Second), deadline, "the publish timeout counts from the service clock")
+}
+
+func TestService_PublishDeadline_RequestPastDeadlineDropsEvent(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{PublishTimeout: time.Second, PublishWithinRequestDeadline: true}, logger.New("test"))
+
+	reviewID := uuid.New()
+	anonymized := &domain.Review{ID: reviewID, ProductID: uuid.New()}
+	// The repository finishes after the request deadline has passed
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	mockRepo.On("Anonymize", mock.Anything, reviewID).
+		Run(func(mock.Arguments) { <-ctx.Done() }).
+		Return(anonymized, nil)
+	mockCache.On("InvalidateAllProductCache", mock.Anything, anonymized.ProductID).Return(nil)
+
+	_, err := service.Anonymize(ctx, reviewID)
+	require.NoError(t, err)
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_Import_PublishesSingleRecalc(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	audits := new(fakes.AuditRepository)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, audits, Options{Clock: clock.NewFake(now)}, logger.New("test"))
+
+	productID := uuid.New()
+	reviews := []*domain.Review{
+		{FirstName: "Ann", LastName: "Lee", ReviewText: "Good", Rating: 4},
+		{FirstName: "Bo", LastName: "Kim", ReviewText: "Bad", Rating: 1, Source: domain.ReviewSourceWeb},
+		{FirstName: "Cy", LastName: "Ng", ReviewText: "Fine", Rating: 3},
+	}
+
+	mockRepo.On("Create", mock.Anything, mock.Anything).Return(nil).Times(3)
+	mockCache.On("InvalidateProducts", mock.Anything, []uuid.UUID{productID}).Return(nil).Once()
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil).Once()
+
+	require.NoError(t, service.Import(context.Background(), productID, reviews))
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+	assert.Len(t, audits.Entries, 3)
+
+	for _, review := range reviews {
+		assert.Equal(t, productID, review.ProductID)
+	}
+	assert.Equal(t, domain.ReviewSourceImport, reviews[0].Source)
+	assert.Equal(t, domain.ReviewSourceWeb, reviews[1].Source)
+
+	var event ReviewEvent
+	require.NoError(t, json.Unmarshal(mockPublisher.Calls[0].Arguments.Get(2).([]byte), &event))
+	assert.Equal(t, EventTypeRatingRecalc, event.EventType)
+	assert.Equal(t, productID, event.ProductID)
+	assert.True(t, now.Equal(event.Timestamp))
+	assert.Nil(t, event.Review)
+}
+
+func TestService_Import_InvalidReviewWritesNothing(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	reviews := []*domain.Review{
+		{FirstName: "Ann", LastName: "Lee", ReviewText: "Good", Rating: 4},
+		{FirstName: "Bo", LastName: "Kim", ReviewText: "Bad", Rating: 9},
+	}
+
+	err := service.Import(context.Background(), uuid.New(), reviews)
+
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+	assert.Contains(t, pkgValidator.TranslateErrors(err, "en"), "[1].rating", "the failing review is named by its index")
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+	mockPublisher.AssertNotCalled(t, "Publish", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestService_DeleteBatch_OneRecalcPerProduct(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	mockPublisher := new(MockEventPublisher)
+	audits := new(fakes.AuditRepository)
+	service := NewService(mockRepo, mockCache, mockPublisher, fakes.PassthroughTx{}, audits, Options{}, logger.New("test"))
+
+	productA, productB := uuid.New(), uuid.New()
+	deleted := []*domain.Review{
+		{ID: uuid.New(), ProductID: productA, Rating: 1},
+		{ID: uuid.New(), ProductID: productB, Rating: 1},
+		{ID: uuid.New(), ProductID: productA, Rating: 1},
+	}
+	ids := []uuid.UUID{deleted[0].ID, deleted[1].ID, deleted[2].ID, uuid.New()}
+
+	mockRepo.On("DeleteBatch", mock.Anything, ids).Return(deleted, nil).Once()
+	// One pipelined invalidation for both products, in first-seen order
+	mockCache.On("InvalidateProducts", mock.Anything, []uuid.UUID{productA, productB}).Return(errors.New("redis down")).Once()
+	mockPublisher.On("Publish", mock.Anything, "reviews.events", mock.Anything).Return(nil).Twice()
+
+	got, err := service.DeleteBatch(context.Background(), ids)
+	require.NoError(t, err, "a failed cache invalidation must not fail the delete")
+	require.NoError(t, service.Shutdown(context.Background()))
+
+	assert.Equal(t, deleted, got)
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+	mockPublisher.AssertExpectations(t)
+	assert.Len(t, audits.Entries, 3)
+
+	recalculated := make(map[uuid.UUID]bool)
+	for _, call := range mockPublisher.Calls {
+		var event ReviewEvent
+		require.NoError(t, json.Unmarshal(call.Arguments.Get(2).([]byte), &event))
+		assert.Equal(t, EventTypeRatingRecalc, event.EventType)
+		recalculated[event.ProductID] = true
+	}
+	assert.Equal(t, map[uuid.UUID]bool{productA: true, productB: true}, recalculated)
+}
+
+func TestService_DeleteBatch_RejectsBatchSize(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	service := NewService(mockRepo, new(MockRedisCache), new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{}, logger.New("test"))
+
+	_, err := service.DeleteBatch(context.Background(), nil)
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = service.DeleteBatch(context.Background(), make([]uuid.UUID, MaxDeleteBatchSize+1))
+	assert.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	mockRepo.AssertNotCalled(t, "DeleteBatch", mock.Anything, mock.Anything)
+}
+
+// summaryProductLookup returns a product with fixed rating aggregates
+type summaryProductLookup struct {
+	product *domain.Product
+}
+
+func (f summaryProductLookup) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	if f.product == nil || f.product.ID != id {
+		return nil, domain.ErrNotFound
+	}
+	return f.product, nil
+}
+
+func TestService_GetSummary_CacheMiss(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	productID := uuid.New()
+	products := summaryProductLookup{product: &domain.Product{ID: productID, AverageRating: 4.5, ReviewCount: 2}}
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Products: products}, logger.New("test"))
+
+	latest := []*domain.Review{{ID: uuid.New(), ProductID: productID, ReviewText: "Works  great,\nwould buy again", Rating: 5}}
+
+	mockCache.On("GetReviewSummary", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+	mockRepo.On("GetRatingDistribution", mock.Anything, productID).Return(map[int]int{4: 1, 5: 1}, nil)
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 1, 0).Return(latest, nil)
+	mockCache.On("SetReviewSummary", mock.Anything, productID, mock.Anything).Return(nil)
+
+	summary, err := service.GetSummary(context.Background(), productID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4.5, summary.AverageRating)
+	assert.Equal(t, 2, summary.ReviewCount)
+	assert.Equal(t, map[int]int{1: 0, 2: 0, 3: 0, 4: 1, 5: 1}, summary.RatingDistribution)
+	if assert.NotNil(t, summary.LatestReviewExcerpt) {
+		assert.Equal(t, "Works great, would buy again", *summary.LatestReviewExcerpt)
+	}
+	mockRepo.AssertExpectations(t)
+	mockCache.AssertExpectations(t)
+}
+
+func TestService_GetSummary_NoReviews(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	productID := uuid.New()
+	products := summaryProductLookup{product: &domain.Product{ID: productID}}
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Products: products}, logger.New("test"))
+
+	mockCache.On("GetReviewSummary", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+	mockRepo.On("GetRatingDistribution", mock.Anything, productID).Return(map[int]int{}, nil)
+	mockRepo.On("GetByProductID", mock.Anything, productID, domain.ReviewSortNewest, 1, 0).Return([]*domain.Review{}, nil)
+	mockCache.On("SetReviewSummary", mock.Anything, productID, mock.Anything).Return(nil)
+
+	summary, err := service.GetSummary(context.Background(), productID)
+
+	assert.NoError(t, err)
+	assert.Zero(t, summary.ReviewCount)
+	assert.Nil(t, summary.LatestReviewExcerpt)
+}
+
+func TestService_GetSummary_CacheHit(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Products: summaryProductLookup{}}, logger.New("test"))
+
+	productID := uuid.New()
+	cached := &domain.ReviewSummary{AverageRating: 3.0, ReviewCount: 1, RatingDistribution: map[int]int{3: 1}}
+	mockCache.On("GetReviewSummary", mock.Anything, productID).Return(cached, nil)
+
+	summary, err := service.GetSummary(context.Background(), productID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, cached, summary)
+	mockRepo.AssertNotCalled(t, "GetRatingDistribution")
+}
+
+func TestService_GetSummary_ProductNotFound(t *testing.T) {
+	mockRepo := new(MockReviewRepository)
+	mockCache := new(MockRedisCache)
+	service := NewService(mockRepo, mockCache, new(MockEventPublisher), fakes.PassthroughTx{}, new(fakes.AuditRepository), Options{Products: summaryProductLookup{}}, logger.New("test"))
+
+	productID := uuid.New()
+	mockCache.On("GetReviewSummary", mock.Anything, productID).Return(nil, domain.ErrNotFound)
+
+	_, err := service.GetSummary(context.Background(), productID)
+
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	mockCache.AssertNotCalled(t, "SetReviewSummary")
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxRunes int
+		want     string
+	}{
+		{name: "short text unchanged", text: "Great product", maxRunes: 20, want: "Great product"},
+		{name: "whitespace collapsed", text: "  Great \n\n product ", maxRunes: 20, want: "Great product"},
+		{name: "cut at word boundary", text: "Great product, would buy again", maxRunes: 21, want: "Great product, would…"},
+		{name: "partial word dropped", text: "Great product, would buy again", maxRunes: 20, want: "Great product…"},
+		{name: "trailing punctuation dropped", text: "Great product. Would buy again", maxRunes: 16, want: "Great product…"},
+		{name: "single long word", text: "Supercalifragilistic", maxRunes: 10, want: "Supercali…"},
+		{name: "multibyte characters", text: "Très très très bon produit", maxRunes: 12, want: "Très très…"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := excerpt(tt.text, tt.maxRunes)
+			assert.Equal(t, tt.want, got)
+			assert.LessOrEqual(t, len([]rune(got)), tt.maxRunes)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package warmer
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// ReviewCountRanker ranks products by review count. The API doesn't record traffic, and
+// the most reviewed products are in practice the most visited ones.
+type ReviewCountRanker struct {
+	db *sqlx.DB
+}
+
+// NewReviewCountRanker creates a ranker reading from the products table
+func NewReviewCountRanker(db *sqlx.DB) *ReviewCountRanker {
+	return &ReviewCountRanker{db: db}
+}
+
+// TopReviewed returns the IDs of the n non-deleted products with the most reviews
+func (r *ReviewCountRanker) TopReviewed(ctx context.Context, n int) ([]uuid.UUID, error) {
+	query := `
+		SELECT id
+		FROM products
+		WHERE deleted_at IS NULL
+		ORDER BY review_count DESC, id
+		LIMIT $1
+	`
+
+	var ids []uuid.UUID
+	if err := r.db.SelectContext(ctx, &ids, query, n); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,236 @@
+// Package warmer refills the Redis cache for recently changed products, so the first
+// storefront request after a review write is a cache hit instead of a database read
+package warmer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+// detailReviewsLimit matches the first page the product detail endpoint serves by default
+const detailReviewsLimit = 10
+
+// ReviewReader is the read-through review service; reading a page on a cache miss is what fills the cache
+type ReviewReader interface {
+	GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, int, error)
+	GetOverview(ctx context.Context, productID uuid.UUID, limit int) (*domain.ReviewOverview, error)
+}
+
+// ProductReader reads products fresh from the database
+type ProductReader interface {
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error)
+}
+
+// RatingCache stores product ratings
+type RatingCache interface {
+	SetProductRating(ctx context.Context, productID uuid.UUID, rating float64) error
+}
+
+// Ranker picks the products worth keeping warm
+type Ranker interface {
+	TopReviewed(ctx context.Context, n int) ([]uuid.UUID, error)
+}
+
+// event is the part of a review event the warmer needs
+type event struct {
+	Type      string    `json:"event_type"`
+	ProductID uuid.UUID `json:"product_id"`
+}
+
+// Warmer re-populates a product's rating, first review page and detail overview shortly
+// after an event for it. The delay lets the rating worker recalculate first: it
+// invalidates the product's cache when done, which would throw away an earlier warm-up.
+type Warmer struct {
+	reviews  ReviewReader
+	products ProductReader
+	ratings  RatingCache
+	ranker   Ranker
+	delay    time.Duration
+	topN     int
+	pageSize int
+	clock    clock.Clock
+	logger   *logger.Logger
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]clock.Timer
+	// hot is the current top-N; nil until RefreshHot succeeds, and unused when topN is 0
+	hot    map[uuid.UUID]bool
+	wg     sync.WaitGroup
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// NewWarmer creates a cache warmer.
+// topN limits warming to the topN most reviewed products (refreshed by RefreshHot);
+// 0 warms every product that has an event. pageSize is the review page the API serves
+// when no limit is given.
+func NewWarmer(
+	reviews ReviewReader,
+	products ProductReader,
+	ratings RatingCache,
+	ranker Ranker,
+	delay time.Duration,
+	topN, pageSize int,
+	clk clock.Clock,
+	log *logger.Logger,
+) *Warmer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Warmer{
+		reviews:  reviews,
+		products: products,
+		ratings:  ratings,
+		ranker:   ranker,
+		delay:    delay,
+		topN:     topN,
+		pageSize: pageSize,
+		clock:    clk,
+		logger:   log,
+		pending:  make(map[uuid.UUID]clock.Timer),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// RefreshHot reloads the set of products worth warming. A failed refresh keeps the previous set.
+func (w *Warmer) RefreshHot(ctx context.Context) error {
+	if w.topN <= 0 {
+		return nil
+	}
+
+	ids, err := w.ranker.TopReviewed(ctx, w.topN)
+	if err != nil {
+		return fmt.Errorf("failed to rank products: %w", err)
+	}
+
+	hot := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		hot[id] = true
+	}
+
+	w.mu.Lock()
+	w.hot = hot
+	w.mu.Unlock()
+
+	return nil
+}
+
+// WarmHot warms every product in the current hot set, e.g. at startup or after a cache flush
+func (w *Warmer) WarmHot(ctx context.Context) {
+	w.mu.Lock()
+	ids := make([]uuid.UUID, 0, len(w.hot))
+	for id := range w.hot {
+		ids = append(ids, id)
+	}
+	w.mu.Unlock()
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return
+		}
+		w.warm(ctx, id)
+	}
+
+	w.logger.Infof("Warmed cache for %d popular products", len(ids))
+}
+
+// HandleEvent schedules a warm-up for the event's product. Several events for one product
+// within the delay share a single warm-up.
+func (w *Warmer) HandleEvent(data []byte) error {
+	var evt event
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return fmt.Errorf("failed to unmarshal event: %w", err)
+	}
+	if evt.ProductID == uuid.Nil {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.ctx.Err() != nil {
+		return nil
+	}
+	if w.topN > 0 && !w.hot[evt.ProductID] {
+		w.logger.Debugf("Skipping cache warm-up for product %s outside the top %d", evt.ProductID, w.topN)
+		return nil
+	}
+	if _, scheduled := w.pending[evt.ProductID]; scheduled {
+		return nil
+	}
+
+	productID := evt.ProductID
+	w.wg.Add(1)
+	w.pending[productID] = w.clock.AfterFunc(w.delay, func() {
+		defer w.wg.Done()
+
+		w.mu.Lock()
+		delete(w.pending, productID)
+		w.mu.Unlock()
+
+		w.warm(w.ctx, productID)
+	})
+
+	return nil
+}
+
+// warm fills the caches for one product. Failures are logged and skipped: the API
+// falls back to the database, so a missed warm-up only costs latency.
+func (w *Warmer) warm(ctx context.Context, productID uuid.UUID) {
+	product, err := w.products.GetByID(ctx, productID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			w.logger.Warnf("Failed to load product %s for cache warm-up: %v", productID, err)
+		}
+		return
+	}
+
+	if err := w.ratings.SetProductRating(ctx, productID, product.AverageRating); err != nil {
+		w.logger.Warnf("Failed to warm rating cache for product %s: %v", productID, err)
+	}
+	if _, _, err := w.reviews.GetByProductID(ctx, productID, w.pageSize, 0); err != nil {
+		w.logger.Warnf("Failed to warm reviews cache for product %s: %v", productID, err)
+	}
+	if _, err := w.reviews.GetOverview(ctx, productID, detailReviewsLimit); err != nil {
+		w.logger.Warnf("Failed to warm overview cache for product %s: %v", productID, err)
+	}
+
+	w.logger.Debugf("Warmed cache for product %s", productID)
+}
+
+// Shutdown drops scheduled warm-ups and waits for running ones until ctx expires
+func (w *Warmer) Shutdown(ctx context.Context) error {
+	w.cancel()
+
+	w.mu.Lock()
+	for productID, timer := range w.pending {
+		// A timer that already fired owns its wg slot and releases it itself
+		if timer.Stop() {
+			w.wg.Done()
+		}
+		delete(w.pending, productID)
+	}
+	w.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("timed out waiting for cache warm-ups: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,216 @@
+package warmer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+)
+
+const testDelay = 5 * time.Second
+
+// fakeStore stands in for every dependency and counts the warm-up reads per product
+type fakeStore struct {
+	mu        sync.Mutex
+	products  map[uuid.UUID]*domain.Product
+	ratings   map[uuid.UUID]float64
+	pages     map[uuid.UUID]int
+	overviews map[uuid.UUID]int
+	top       []uuid.UUID
+	rankErr   error
+}
+
+func newFakeStore(products ...*domain.Product) *fakeStore {
+	f := &fakeStore{
+		products:  make(map[uuid.UUID]*domain.Product),
+		ratings:   make(map[uuid.UUID]float64),
+		pages:     make(map[uuid.UUID]int),
+		overviews: make(map[uuid.UUID]int),
+	}
+	for _, p := range products {
+		f.products[p.ID] = p
+	}
+	return f
+}
+
+func (f *fakeStore) GetByID(ctx context.Context, id uuid.UUID) (*domain.Product, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	p, ok := f.products[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return p, nil
+}
+
+func (f *fakeStore) SetProductRating(ctx context.Context, productID uuid.UUID, rating float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ratings[productID] = rating
+	return nil
+}
+
+func (f *fakeStore) GetByProductID(ctx context.Context, productID uuid.UUID, limit, offset int) ([]*domain.Review, int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pages[productID]++
+	return nil, 0, nil
+}
+
+func (f *fakeStore) GetOverview(ctx context.Context, productID uuid.UUID, limit int) (*domain.ReviewOverview, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.overviews[productID]++
+	return &domain.ReviewOverview{}, nil
+}
+
+func (f *fakeStore) TopReviewed(ctx context.Context, n int) ([]uuid.UUID, error) {
+	if f.rankErr != nil {
+		return nil, f.rankErr
+	}
+	return f.top[:min(n, len(f.top))], nil
+}
+
+func (f *fakeStore) warmed(productID uuid.UUID) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pages[productID]
+}
+
+func newTestWarmer(store *fakeStore, topN int) (*Warmer, *clock.Fake) {
+	clk := clock.NewFake(time.Now())
+	w := NewWarmer(store, store, store, store, testDelay, topN, 20, clk, logger.New("test"))
+	return w, clk
+}
+
+func eventFor(productID uuid.UUID) []byte {
+	return []byte(fmt.Sprintf(`{"event_type":"review.created","product_id":%q}`, productID))
+}
+
+func TestWarmer_HandleEvent_WarmsAfterDelay(t *testing.T) {
+	p := &domain.Product{ID: uuid.New(), AverageRating: 4.5}
+	store := newFakeStore(p)
+	w, clk := newTestWarmer(store, 0)
+
+	require.NoError(t, w.HandleEvent(eventFor(p.ID)))
+
+	clk.Advance(testDelay - time.Second)
+	assert.Equal(t, 0, store.warmed(p.ID), "should wait for the rating worker")
+
+	clk.Advance(time.Second)
+	assert.Equal(t, 1, store.warmed(p.ID))
+	assert.Equal(t, 1, store.overviews[p.ID])
+	assert.Equal(t, 4.5, store.ratings[p.ID])
+}
+
+func TestWarmer_HandleEvent_CoalescesEvents(t *testing.T) {
+	p := &domain.Product{ID: uuid.New()}
+	store := newFakeStore(p)
+	w, clk := newTestWarmer(store, 0)
+
+	for range 3 {
+		require.NoError(t, w.HandleEvent(eventFor(p.ID)))
+	}
+	assert.Equal(t, 1, clk.PendingTimers())
+
+	clk.Advance(testDelay)
+	assert.Equal(t, 1, store.warmed(p.ID))
+
+	// A later event schedules a fresh warm-up
+	require.NoError(t, w.HandleEvent(eventFor(p.ID)))
+	clk.Advance(testDelay)
+	assert.Equal(t, 2, store.warmed(p.ID))
+}
+
+func TestWarmer_HandleEvent_OnlyHotProducts(t *testing.T) {
+	hot := &domain.Product{ID: uuid.New()}
+	cold := &domain.Product{ID: uuid.New()}
+	store := newFakeStore(hot, cold)
+	store.top = []uuid.UUID{hot.ID}
+	w, clk := newTestWarmer(store, 1)
+	require.NoError(t, w.RefreshHot(context.Background()))
+
+	require.NoError(t, w.HandleEvent(eventFor(hot.ID)))
+	require.NoError(t, w.HandleEvent(eventFor(cold.ID)))
+	clk.Advance(testDelay)
+
+	assert.Equal(t, 1, store.warmed(hot.ID))
+	assert.Equal(t, 0, store.warmed(cold.ID))
+}
+
+func TestWarmer_HandleEvent_InvalidJSON(t *testing.T) {
+	w, clk := newTestWarmer(newFakeStore(), 0)
+
+	assert.Error(t, w.HandleEvent([]byte("not json")))
+	assert.Equal(t, 0, clk.PendingTimers())
+}
+
+func TestWarmer_HandleEvent_DeletedProduct(t *testing.T) {
+	store := newFakeStore()
+	w, clk := newTestWarmer(store, 0)
+	productID := uuid.New()
+
+	require.NoError(t, w.HandleEvent(eventFor(productID)))
+	clk.Advance(testDelay)
+
+	assert.Equal(t, 0, store.warmed(productID))
+	_, cached := store.ratings[productID]
+	assert.False(t, cached)
+}
+
+func TestWarmer_RefreshHot_KeepsPreviousSetOnError(t *testing.T) {
+	p := &domain.Product{ID: uuid.New()}
+	store := newFakeStore(p)
+	store.top = []uuid.UUID{p.ID}
+	w, clk := newTestWarmer(store, 10)
+	require.NoError(t, w.RefreshHot(context.Background()))
+
+	store.rankErr = errors.New("db down")
+	assert.Error(t, w.RefreshHot(context.Background()))
+
+	require.NoError(t, w.HandleEvent(eventFor(p.ID)))
+	clk.Advance(testDelay)
+	assert.Equal(t, 1, store.warmed(p.ID))
+}
+
+func TestWarmer_WarmHot(t *testing.T) {
+	a := &domain.Product{ID: uuid.New()}
+	b := &domain.Product{ID: uuid.New()}
+	store := newFakeStore(a, b)
+	store.top = []uuid.UUID{a.ID, b.ID}
+	w, _ := newTestWarmer(store, 10)
+	require.NoError(t, w.RefreshHot(context.Background()))
+
+	w.WarmHot(context.Background())
+
+	assert.Equal(t, 1, store.warmed(a.ID))
+	assert.Equal(t, 1, store.warmed(b.ID))
+}
+
+func TestWarmer_Shutdown_DropsPendingWarmUps(t *testing.T) {
+	p := &domain.Product{ID: uuid.New()}
+	store := newFakeStore(p)
+	w, clk := newTestWarmer(store, 0)
+
+	require.NoError(t, w.HandleEvent(eventFor(p.ID)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.NoError(t, w.Shutdown(ctx))
+	assert.Equal(t, 0, clk.PendingTimers())
+
+	// Events arriving after shutdown are ignored
+	require.NoError(t, w.HandleEvent(eventFor(p.ID)))
+	assert.Equal(t, 0, clk.PendingTimers())
+	assert.Equal(t, 0, store.warmed(p.ID))
+}
@@ -3,9 +3,12 @@ package worker
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -13,62 +16,224 @@ import (
 
 // Calculator handles rating calculation and database updates
 type Calculator struct {
-	db     *sqlx.DB
-	logger *logger.Logger
+	db                   *sqlx.DB
+	roundingMode         string
+	serializationRetries int
+	logger               *logger.Logger
 }
 
 // NewCalculator creates a new rating calculator
-func NewCalculator(db *sqlx.DB, logger *logger.Logger) *Calculator {
+// roundingMode is a domain.RatingRounding* mode; anything else rounds half up
+// serializationRetries is how many times a recalculation that failed to serialize is rerun
+func NewCalculator(db *sqlx.DB, roundingMode string, serializationRetries int, logger *logger.Logger) *Calculator {
 	return &Calculator{
-		db:     db,
-		logger: logger,
+		db:                   db,
+		roundingMode:         roundingMode,
+		serializationRetries: serializationRetries,
+		logger:               logger,
 	}
 }
 
-// CalculateAndUpdate recalculates average rating for a product and updates the database
-// Uses most recent reviews (up to 10,000) for performance on products with many reviews
-func (c *Calculator) CalculateAndUpdate(ctx context.Context, productID uuid.UUID) error {
-	query := `
+// ReviewStats is a product's running rating totals from product_review_stats
+type ReviewStats struct {
+	SumRatings   float64 `db:"sum_ratings"`
+	CountRatings int     `db:"count_ratings"`
+}
+
+// Reconciliation reports what Reconcile found and what it stored
+type Reconciliation struct {
+	// Stored is the incremental totals before reconciling, Actual the full recount now stored
+	Stored ReviewStats
+	Actual ReviewStats
+	// PreviousRating and PreviousCount are the product row's values before reconciling
+	PreviousRating float64
+	PreviousCount  int
+	// Rating is the average_rating persisted from Actual
+	Rating float64
+}
+
+// Drifted reports whether the incremental totals disagreed with the reviews table
+func (r Reconciliation) Drifted() bool {
+	return r.Stored != r.Actual
+}
+
+// CalculateAndUpdate derives average rating and review count for a product from its
+// incremental totals in product_review_stats and writes them to the product row.
+// The totals are kept by a trigger on reviews, so this never scans the product's reviews;
+// a product without a stats row has no live reviews.
+// Returns the persisted rating so callers can warm caches; updated is false when the product is missing
+//
+// The average is rounded here rather than with ROUND in SQL so RATING_ROUNDING_MODE can
+// pick the rule, and so the rule is plain Go that tests can pin down without a database.
+// The cost is a second round trip and a transaction: the totals are read FOR SHARE, which
+// holds off review writes to the product until the rating is stored, so a slower
+// recalculation can't overwrite a newer rating with totals it read earlier.
+//
+// version is deliberately left alone: it is the optimistic lock for user edits, and the
+// rating is derived data. Bumping it here would race with ProductRepository.Update and
+// fail clients' in-flight PUTs with 409 even though nothing they can edit changed.
+//
+// The transaction runs at REPEATABLE READ, so a concurrent recalculation or review write
+// to the same product fails it with a serialization failure rather than being overwritten.
+// That reruns the whole transaction (see database.RetrySerializable), so concurrent
+// recalculations of a hot product settle here instead of bouncing the event.
+func (c *Calculator) CalculateAndUpdate(ctx context.Context, productID uuid.UUID) (rating float64, updated bool, err error) {
+	err = database.RetrySerializable(ctx, c.serializationRetries, func() error {
+		rating, updated, err = c.calculateAndUpdate(ctx, productID)
+		return err
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	if updated {
+		c.logger.WithFields(map[string]any{
+			"product_id":     productID.String(),
+			"average_rating": rating,
+		}).Info("Successfully updated product rating")
+	}
+
+	return rating, updated, nil
+}
+
+// calculateAndUpdate is one attempt of CalculateAndUpdate
+func (c *Calculator) calculateAndUpdate(ctx context.Context, productID uuid.UUID) (rating float64, updated bool, err error) {
+	tx, err := c.db.BeginTxx(ctx, &database.RetryableTx)
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to begin rating transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	statsQuery := `
+		SELECT sum_ratings, count_ratings
+		FROM product_review_stats
+		WHERE product_id = $1
+		FOR SHARE
+	`
+
+	var stats ReviewStats
+	if err := tx.GetContext(ctx, &stats, statsQuery, productID); err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, false, fmt.Errorf("failed to read review stats: %w", err)
+	}
+
+	updateQuery := `
 		UPDATE products
-		SET
-			average_rating = COALESCE(
-				(SELECT ROUND(AVG(rating)::numeric, 1)
-				 FROM (
-					SELECT rating
-					FROM reviews
-					WHERE product_id = $1 AND deleted_at IS NULL
-					ORDER BY created_at DESC
-					LIMIT 10000
-				 ) recent_reviews),
-				0
-			),
-			updated_at = $2
+		SET average_rating = $2, review_count = $3, updated_at = $4
 		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING average_rating
 	`
 
-	result, err := c.db.ExecContext(ctx, query, productID, time.Now())
+	rating = domain.RoundRating(stats.SumRatings, stats.CountRatings, c.roundingMode)
+	err = tx.QueryRowxContext(ctx, updateQuery, productID, rating, stats.CountRatings, time.Now()).Scan(&rating)
 	if err != nil {
-		return fmt.Errorf("failed to update product rating: %w", err)
+		// Product not found or deleted - not an error, just log
+		if errors.Is(err, sql.ErrNoRows) {
+			c.logger.WithFields(map[string]any{
+				"product_id": productID.String(),
+			}).Info("Product not found or deleted, skipping rating update")
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("failed to update product rating: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		return 0, false, fmt.Errorf("failed to commit rating update: %w", err)
+	}
+
+	return rating, true, nil
+}
+
+// Reconcile rebuilds a product's incremental totals with a full scan of its live reviews,
+// then updates the product row from them. It is the self-correcting path for totals that
+// drifted (a manual fix in the database, a bug in the trigger, a lost event); drift is
+// logged as a warning. updated is false when the product is missing or deleted.
+//
+// The stats row is locked before counting, so reviews written concurrently either commit
+// first and are counted, or wait and apply their change on top of the rebuilt totals.
+func (c *Calculator) Reconcile(ctx context.Context, productID uuid.UUID) (result Reconciliation, updated bool, err error) {
+	tx, err := c.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return result, false, fmt.Errorf("failed to begin reconcile transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// Products without reviews have no stats row yet; create one so there is something to lock
+	ensureQuery := `
+		INSERT INTO product_review_stats (product_id)
+		SELECT id FROM products WHERE id = $1 AND deleted_at IS NULL
+		ON CONFLICT (product_id) DO NOTHING
+	`
+	if _, err := tx.ExecContext(ctx, ensureQuery, productID); err != nil {
+		return result, false, fmt.Errorf("failed to create review stats: %w", err)
+	}
+
+	lockQuery := `
+		SELECT s.sum_ratings, s.count_ratings, COALESCE(p.average_rating, 0), p.review_count
+		FROM product_review_stats s
+		JOIN products p ON p.id = s.product_id
+		WHERE s.product_id = $1 AND p.deleted_at IS NULL
+		FOR UPDATE OF s
+	`
+	err = tx.QueryRowxContext(ctx, lockQuery, productID).Scan(
+		&result.Stored.SumRatings, &result.Stored.CountRatings, &result.PreviousRating, &result.PreviousCount,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			c.logger.WithFields(map[string]any{
+				"product_id": productID.String(),
+			}).Info("Product not found or deleted, skipping reconciliation")
+			return result, false, nil
+		}
+		return result, false, fmt.Errorf("failed to lock review stats: %w", err)
 	}
 
-	// Product not found or deleted - not an error, just log
-	if rowsAffected == 0 {
+	// A separate statement so its snapshot includes reviews committed while waiting for the lock
+	result.Actual, err = recount(ctx, tx, productID)
+	if err != nil {
+		return result, false, err
+	}
+
+	if result.Drifted() {
 		c.logger.WithFields(map[string]any{
-			"product_id": productID.String(),
-		}).Info("Product not found or deleted, skipping rating update")
-		return nil
+			"product_id":   productID.String(),
+			"stored_sum":   result.Stored.SumRatings,
+			"stored_count": result.Stored.CountRatings,
+			"actual_sum":   result.Actual.SumRatings,
+			"actual_count": result.Actual.CountRatings,
+		}).Warn("Review stats drifted from reviews, correcting")
+
+		updateQuery := `
+			UPDATE product_review_stats
+			SET sum_ratings = $2, count_ratings = $3, updated_at = $4
+			WHERE product_id = $1
+		`
+		if _, err := tx.ExecContext(ctx, updateQuery, productID, result.Actual.SumRatings, result.Actual.CountRatings, time.Now()); err != nil {
+			return result, false, fmt.Errorf("failed to correct review stats: %w", err)
+		}
 	}
 
-	c.logger.WithFields(map[string]any{
-		"product_id": productID.String(),
-	}).Info("Successfully updated product rating")
+	if err := tx.Commit(); err != nil {
+		return result, false, fmt.Errorf("failed to commit reconciliation: %w", err)
+	}
 
-	return nil
+	result.Rating, updated, err = c.CalculateAndUpdate(ctx, productID)
+	return result, updated, err
+}
+
+// recount computes a product's totals from scratch with a full scan of its live reviews.
+// It writes nothing, so it doubles as a dry run of what reconciliation would store.
+func recount(ctx context.Context, q sqlx.QueryerContext, productID uuid.UUID) (ReviewStats, error) {
+	query := `
+		SELECT COALESCE(SUM(rating), 0) AS sum_ratings, COUNT(*) AS count_ratings
+		FROM reviews
+		WHERE product_id = $1 AND deleted_at IS NULL
+	`
+
+	var stats ReviewStats
+	if err := sqlx.GetContext(ctx, q, &stats, query, productID); err != nil {
+		return stats, fmt.Errorf("failed to count reviews: %w", err)
+	}
+	return stats, nil
 }
 
 // GetCurrentRating retrieves the current average rating for verification (used in tests)
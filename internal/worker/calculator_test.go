@@ -2,17 +2,50 @@ package worker
 
 import (
 	"context"
+	"database/sql"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+// ratingRow builds the RETURNING row produced by the rating UPDATE
+func ratingRow(rating float64) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"average_rating"}).AddRow(rating)
+}
+
+// statsRow builds a (sum_ratings, count_ratings) row for the stats read and full-count queries
+func statsRow(sum float64, count int) *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"sum_ratings", "count_ratings"}).AddRow(sum, count)
+}
+
+// expectRatingUpdate expects one successful CalculateAndUpdate for productID returning rating
+func expectRatingUpdate(mock sqlmock.Sqlmock, productID uuid.UUID, rating float64) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM product_review_stats").WithArgs(productID).WillReturnRows(statsRow(9, 2))
+	mock.ExpectQuery("UPDATE products").
+		WithArgs(productID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(ratingRow(rating))
+	mock.ExpectCommit()
+}
+
+// expectRatingUpdateError expects a CalculateAndUpdate for productID whose product UPDATE fails
+func expectRatingUpdateError(mock sqlmock.Sqlmock, productID uuid.UUID, err error) {
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM product_review_stats").WithArgs(productID).WillReturnRows(statsRow(9, 2))
+	mock.ExpectQuery("UPDATE products").
+		WithArgs(productID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnError(err)
+	mock.ExpectRollback()
+}
+
 func TestCalculator_CalculateAndUpdate_Success(t *testing.T) {
 	// Setup
 	db, mock, err := sqlmock.New()
@@ -23,21 +56,89 @@ func TestCalculator_CalculateAndUpdate_Success(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	log := logger.New("test")
-	calculator := NewCalculator(sqlxDB, log)
+	calculator := NewCalculator(sqlxDB, domain.RatingRoundingHalfUp, 0, log)
 
 	productID := uuid.New()
 	ctx := context.Background()
 
-	// Expect UPDATE query
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	// The rating is rounded from the totals in Go, then stored
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM product_review_stats").WithArgs(productID).WillReturnRows(statsRow(17, 4))
+	mock.ExpectQuery("UPDATE products").
+		WithArgs(productID, 4.3, 4, sqlmock.AnyArg()).
+		WillReturnRows(ratingRow(4.3))
+	mock.ExpectCommit()
 
 	// Execute
-	err = calculator.CalculateAndUpdate(ctx, productID)
+	rating, updated, err := calculator.CalculateAndUpdate(ctx, productID)
 
 	// Assert
 	assert.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, 4.3, rating)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalculator_CalculateAndUpdate_RoundingMode(t *testing.T) {
+	tests := []struct {
+		mode string
+		want float64
+	}{
+		{mode: domain.RatingRoundingHalfUp, want: 4.3},
+		{mode: domain.RatingRoundingHalfEven, want: 4.2},
+		{mode: domain.RatingRoundingFloor, want: 4.2},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.mode, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				_ = db.Close()
+			}()
+
+			calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), tc.mode, 0, logger.New("test"))
+			productID := uuid.New()
+
+			// 17 / 4 = 4.25, a tie at one decimal
+			mock.ExpectBegin()
+			mock.ExpectQuery("FROM product_review_stats").WithArgs(productID).WillReturnRows(statsRow(17, 4))
+			mock.ExpectQuery("UPDATE products").
+				WithArgs(productID, tc.want, 4, sqlmock.AnyArg()).
+				WillReturnRows(ratingRow(tc.want))
+			mock.ExpectCommit()
+
+			_, _, err = calculator.CalculateAndUpdate(context.Background(), productID)
+
+			require.NoError(t, err)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCalculator_CalculateAndUpdate_NoStatsRow(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), domain.RatingRoundingHalfUp, 0, logger.New("test"))
+	productID := uuid.New()
+
+	// No stats row means no live reviews yet
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM product_review_stats").WithArgs(productID).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("UPDATE products").
+		WithArgs(productID, 0.0, 0, sqlmock.AnyArg()).
+		WillReturnRows(ratingRow(0))
+	mock.ExpectCommit()
+
+	rating, updated, err := calculator.CalculateAndUpdate(context.Background(), productID)
+
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, 0.0, rating)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -51,21 +152,25 @@ func TestCalculator_CalculateAndUpdate_ProductNotFound(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	log := logger.New("test")
-	calculator := NewCalculator(sqlxDB, log)
+	calculator := NewCalculator(sqlxDB, domain.RatingRoundingHalfUp, 0, log)
 
 	productID := uuid.New()
 	ctx := context.Background()
 
-	// Product not found (0 rows affected)
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 0))
+	// Product not found (no row returned)
+	mock.ExpectBegin()
+	mock.ExpectQuery("FROM product_review_stats").WithArgs(productID).WillReturnError(sql.ErrNoRows)
+	mock.ExpectQuery("UPDATE products").
+		WithArgs(productID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"average_rating"}))
+	mock.ExpectRollback()
 
 	// Execute
-	err = calculator.CalculateAndUpdate(ctx, productID)
+	_, updated, err := calculator.CalculateAndUpdate(ctx, productID)
 
 	// Assert - should not return error for missing product
 	assert.NoError(t, err)
+	assert.False(t, updated)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
@@ -79,29 +184,67 @@ func TestCalculator_CalculateAndUpdate_ContextTimeout(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	log := logger.New("test")
-	calculator := NewCalculator(sqlxDB, log)
+	calculator := NewCalculator(sqlxDB, domain.RatingRoundingHalfUp, 0, log)
 
 	productID := uuid.New()
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond)
 	defer cancel()
 
-	// Simulate slow query
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillDelayFor(100 * time.Millisecond).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	// Simulate slow transaction start
+	mock.ExpectBegin().WillDelayFor(100 * time.Millisecond)
 
 	// Wait for context to timeout
 	time.Sleep(10 * time.Millisecond)
 
 	// Execute
-	err = calculator.CalculateAndUpdate(ctx, productID)
+	_, _, err = calculator.CalculateAndUpdate(ctx, productID)
 
 	// Assert - should return context timeout error
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "context")
 }
 
+func TestCalculator_CalculateAndUpdate_RetriesSerializationFailure(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), domain.RatingRoundingHalfUp, 1, logger.New("test"))
+	productID := uuid.New()
+
+	expectRatingUpdateError(mock, productID, &pq.Error{Code: "40001"})
+	expectRatingUpdate(mock, productID, 4.5)
+
+	rating, updated, err := calculator.CalculateAndUpdate(context.Background(), productID)
+
+	require.NoError(t, err)
+	assert.True(t, updated)
+	assert.Equal(t, 4.5, rating)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalculator_CalculateAndUpdate_GivesUpAfterSerializationRetries(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), domain.RatingRoundingHalfUp, 1, logger.New("test"))
+	productID := uuid.New()
+
+	expectRatingUpdateError(mock, productID, &pq.Error{Code: "40001"})
+	expectRatingUpdateError(mock, productID, &pq.Error{Code: "40001"})
+
+	_, updated, err := calculator.CalculateAndUpdate(context.Background(), productID)
+
+	require.Error(t, err)
+	assert.False(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestCalculator_GetCurrentRating_Success(t *testing.T) {
 	// Setup
 	db, mock, err := sqlmock.New()
@@ -112,7 +255,7 @@ func TestCalculator_GetCurrentRating_Success(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	log := logger.New("test")
-	calculator := NewCalculator(sqlxDB, log)
+	calculator := NewCalculator(sqlxDB, domain.RatingRoundingHalfUp, 0, log)
 
 	productID := uuid.New()
 	expectedRating := 4.5
@@ -144,7 +287,7 @@ func TestCalculator_GetCurrentRating_NullRating(t *testing.T) {
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	log := logger.New("test")
-	calculator := NewCalculator(sqlxDB, log)
+	calculator := NewCalculator(sqlxDB, domain.RatingRoundingHalfUp, 0, log)
 
 	productID := uuid.New()
 	ctx := context.Background()
@@ -164,3 +307,135 @@ func TestCalculator_GetCurrentRating_NullRating(t *testing.T) {
 	assert.Equal(t, 0.0, rating)
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestCalculator_CalculateAndUpdate_LeavesVersionAlone(t *testing.T) {
+	var executed []string
+	recordQuery := sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		executed = append(executed, actualSQL)
+		return nil
+	})
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(recordQuery))
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), domain.RatingRoundingHalfUp, 0, logger.New("test"))
+
+	productID := uuid.New()
+	expectRatingUpdate(mock, productID, 4.0)
+
+	_, _, err = calculator.CalculateAndUpdate(context.Background(), productID)
+
+	require.NoError(t, err)
+	// The optimistic lock belongs to user edits; a recalculation must not invalidate them
+	for _, query := range executed {
+		assert.NotContains(t, query, "version")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalculator_CalculateAndUpdate_ReadsIncrementalStats(t *testing.T) {
+	var executed []string
+	recordQuery := sqlmock.QueryMatcherFunc(func(expectedSQL, actualSQL string) error {
+		executed = append(executed, actualSQL)
+		return nil
+	})
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(recordQuery))
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), domain.RatingRoundingHalfUp, 0, logger.New("test"))
+
+	productID := uuid.New()
+	expectRatingUpdate(mock, productID, 4.0)
+
+	_, _, err = calculator.CalculateAndUpdate(context.Background(), productID)
+
+	require.NoError(t, err)
+	require.NotEmpty(t, executed)
+	assert.Contains(t, executed[0], "product_review_stats")
+	// Scanning reviews is what the incremental totals exist to avoid
+	for _, query := range executed {
+		assert.NotContains(t, query, "FROM reviews")
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCalculator_Reconcile(t *testing.T) {
+	tests := []struct {
+		name        string
+		stored      ReviewStats
+		actual      ReviewStats
+		wantDrifted bool
+	}{
+		{name: "in sync", stored: ReviewStats{SumRatings: 9, CountRatings: 2}, actual: ReviewStats{SumRatings: 9, CountRatings: 2}},
+		{name: "drifted", stored: ReviewStats{SumRatings: 5, CountRatings: 1}, actual: ReviewStats{SumRatings: 9, CountRatings: 2}, wantDrifted: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			require.NoError(t, err)
+			defer func() {
+				_ = db.Close()
+			}()
+
+			calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), domain.RatingRoundingHalfUp, 0, logger.New("test"))
+			productID := uuid.New()
+
+			mock.ExpectBegin()
+			mock.ExpectExec("INSERT INTO product_review_stats").WithArgs(productID).WillReturnResult(sqlmock.NewResult(0, 0))
+			mock.ExpectQuery("FOR UPDATE").WithArgs(productID).
+				WillReturnRows(sqlmock.NewRows([]string{"sum_ratings", "count_ratings", "average_rating", "review_count"}).
+					AddRow(tc.stored.SumRatings, tc.stored.CountRatings, 5.0, 1))
+			mock.ExpectQuery("FROM reviews").WithArgs(productID).
+				WillReturnRows(statsRow(tc.actual.SumRatings, tc.actual.CountRatings))
+			if tc.wantDrifted {
+				mock.ExpectExec("UPDATE product_review_stats").
+					WithArgs(productID, tc.actual.SumRatings, tc.actual.CountRatings, sqlmock.AnyArg()).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			mock.ExpectCommit()
+			expectRatingUpdate(mock, productID, 4.5)
+
+			result, updated, err := calculator.Reconcile(context.Background(), productID)
+
+			require.NoError(t, err)
+			assert.True(t, updated)
+			assert.Equal(t, tc.wantDrifted, result.Drifted())
+			assert.Equal(t, tc.stored, result.Stored)
+			assert.Equal(t, tc.actual, result.Actual)
+			assert.Equal(t, 5.0, result.PreviousRating)
+			assert.Equal(t, 1, result.PreviousCount)
+			assert.Equal(t, 4.5, result.Rating)
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestCalculator_Reconcile_ProductNotFound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() {
+		_ = db.Close()
+	}()
+
+	calculator := NewCalculator(sqlx.NewDb(db, "sqlmock"), domain.RatingRoundingHalfUp, 0, logger.New("test"))
+	productID := uuid.New()
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO product_review_stats").WithArgs(productID).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("FOR UPDATE").WithArgs(productID).WillReturnError(sql.ErrNoRows)
+	mock.ExpectRollback()
+
+	_, updated, err := calculator.Reconcile(context.Background(), productID)
+
+	require.NoError(t, err)
+	assert.False(t, updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
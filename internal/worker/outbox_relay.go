@@ -0,0 +1,250 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
+)
+
+// outboxCleanupInterval is how often the relay deletes sent events past retention.
+// Sent rows are only kept for debugging, so there's no need to check on every poll.
+const outboxCleanupInterval = time.Hour
+
+// maxOutboxBackoff caps the poll interval doubling after consecutive relay failures, so a
+// NATS outage doesn't flood the logs but publishing resumes soon after it ends
+const maxOutboxBackoff = 30 * time.Second
+
+// outboxClaimLease is how long a claimed batch is left to its relay. A relay stops
+// publishing a batch once half of it is gone and hands the rest back, so the lease only
+// runs out on a relay that died or hung, and its events go to another relay.
+const outboxClaimLease = time.Minute
+
+// outboxLagMaxAge is how long one lag query serves scrapes, so the two outbox gauges
+// share a query and a tight scrape interval can't pile load onto the database
+const outboxLagMaxAge = time.Second
+
+// outboxLagTimeout bounds the lag query a scrape runs
+const outboxLagTimeout = 2 * time.Second
+
+// OutboxPublisher sends a relayed event
+type OutboxPublisher interface {
+	Publish(ctx context.Context, subject string, data []byte) error
+}
+
+// OutboxRelay publishes events the review service committed to the outbox and marks them
+// sent. Each batch is leased to one relay for outboxClaimLease, so relays running side by
+// side (the API's and cmd/outbox-relay replicas) never publish the same event twice while
+// they're healthy. No transaction is open while publishing. Delivery is still at least
+// once: a relay that crashes after publishing, or fails to mark the batch, leaves it to be
+// published again once the lease runs out. The rating worker recalculates from the
+// database, so a repeated event only costs a query.
+type OutboxRelay struct {
+	outbox    domain.OutboxRepository
+	publisher OutboxPublisher
+	batchSize int
+	retention time.Duration
+	clock     clock.Clock
+	logger    *logger.Logger
+
+	lastCleanup time.Time
+}
+
+// NewOutboxRelay creates a relay that claims up to batchSize events at a time and keeps
+// sent events for retention
+func NewOutboxRelay(
+	outbox domain.OutboxRepository,
+	publisher OutboxPublisher,
+	batchSize int,
+	retention time.Duration,
+	clk clock.Clock,
+	logger *logger.Logger,
+) *OutboxRelay {
+	return &OutboxRelay{
+		outbox:    outbox,
+		publisher: publisher,
+		batchSize: batchSize,
+		retention: retention,
+		clock:     clk,
+		logger:    logger,
+	}
+}
+
+// Run relays once immediately and then every interval, until ctx is cancelled.
+// After a failure the wait doubles, up to maxOutboxBackoff, until a relay succeeds.
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	wait := interval
+	for {
+		if _, err := r.Relay(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			wait = max(min(wait*2, maxOutboxBackoff), interval)
+			r.logger.WithFields(map[string]any{
+				"error":    err.Error(),
+				"retry_in": wait.String(),
+			}).Warn("Failed to relay outbox events")
+		} else {
+			wait = interval
+		}
+
+		if err := r.cleanup(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("Failed to delete sent outbox events", err)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Relay publishes pending events oldest first, batch after batch, until none are left.
+// It stops at the first failed publish so a relay doesn't reorder its own batch; that event
+// and the ones after it are retried on the next poll. Returns the number of events sent.
+func (r *OutboxRelay) Relay(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return sent, err
+		}
+
+		claimed, published, err := r.relayBatch(ctx)
+		sent += published
+		if err != nil {
+			return sent, err
+		}
+
+		if claimed < r.batchSize {
+			return sent, nil
+		}
+	}
+}
+
+// relayBatch claims one batch, publishes it and marks what was sent. A failed publish is
+// returned once the events before it are marked; it and the events after it are released
+// for the next poll. A batch still publishing after half the lease is released the same
+// way, without an error, so the relay moves on instead of racing the lease.
+func (r *OutboxRelay) relayBatch(ctx context.Context) (claimed, published int, err error) {
+	events, err := r.outbox.Claim(ctx, r.batchSize, outboxClaimLease)
+	if err != nil {
+		return 0, 0, err
+	}
+	stopAt := r.clock.Now().Add(outboxClaimLease / 2)
+
+	var publishErr error
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		if !r.clock.Now().Before(stopAt) {
+			r.logger.WithFields(map[string]any{
+				"published": len(ids),
+				"released":  len(events) - len(ids),
+			}).Warn("Outbox batch is taking too long to publish, releasing the rest")
+			break
+		}
+		if publishErr = r.publisher.Publish(ctx, event.Subject, event.Payload); publishErr != nil {
+			publishErr = fmt.Errorf("failed to publish outbox event %d: %w", event.ID, publishErr)
+			break
+		}
+		ids = append(ids, event.ID)
+	}
+
+	// The events are out; mark them even if ctx was cancelled meanwhile
+	markCtx := context.WithoutCancel(ctx)
+	if len(ids) > 0 {
+		if err := r.outbox.MarkSent(markCtx, ids); err != nil {
+			return len(events), 0, err
+		}
+	}
+	if unsent := events[len(ids):]; len(unsent) > 0 {
+		unsentIDs := make([]int64, len(unsent))
+		for i, event := range unsent {
+			unsentIDs[i] = event.ID
+		}
+		// Left alone, they're claimed again once the lease runs out
+		if err := r.outbox.Release(markCtx, unsentIDs); err != nil {
+			r.logger.Error("Failed to release unpublished outbox events", err)
+		}
+	}
+
+	return len(events), len(ids), publishErr
+}
+
+// cleanup deletes events sent more than retention ago, at most once per outboxCleanupInterval
+func (r *OutboxRelay) cleanup(ctx context.Context) error {
+	now := r.clock.Now()
+	if now.Sub(r.lastCleanup) < outboxCleanupInterval {
+		return nil
+	}
+
+	deleted, err := r.outbox.DeleteSentBefore(ctx, now.Add(-r.retention))
+	if err != nil {
+		return err
+	}
+	r.lastCleanup = now
+
+	if deleted > 0 {
+		r.logger.WithFields(map[string]any{
+			"deleted": deleted,
+		}).Info("Deleted sent outbox events")
+	}
+	return nil
+}
+
+// OutboxLagReader reports the outbox backlog
+type OutboxLagReader interface {
+	Lag(ctx context.Context) (*domain.OutboxLag, error)
+}
+
+// RegisterOutboxMetrics exposes the outbox backlog on reg, queried at scrape time.
+// Alert on the oldest age: a growing pending count alone can be a burst the relays are
+// keeping up with. When the query fails the last sample is reported again.
+func RegisterOutboxMetrics(reg *metrics.Registry, outbox OutboxLagReader, clk clock.Clock, log *logger.Logger) {
+	sampler := &outboxLagSampler{outbox: outbox, clock: clk, logger: log}
+	reg.GaugeFunc("events_outbox_pending",
+		"Events in the transactional outbox that haven't been published yet.",
+		func() float64 { return float64(sampler.sample().Pending) })
+	reg.GaugeFunc("events_outbox_oldest_age_seconds",
+		"How long the oldest unpublished outbox event has waited; 0 when none are pending.",
+		func() float64 { return sampler.sample().OldestAge.Seconds() })
+}
+
+// outboxLagSampler caches the last lag query for outboxLagMaxAge
+type outboxLagSampler struct {
+	outbox OutboxLagReader
+	clock  clock.Clock
+	logger *logger.Logger
+
+	mu        sync.Mutex
+	lag       domain.OutboxLag
+	sampledAt time.Time
+}
+
+func (s *outboxLagSampler) sample() domain.OutboxLag {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.clock.Now()
+	if !s.sampledAt.IsZero() && now.Sub(s.sampledAt) < outboxLagMaxAge {
+		return s.lag
+	}
+	s.sampledAt = now
+
+	ctx, cancel := context.WithTimeout(context.Background(), outboxLagTimeout)
+	defer cancel()
+	lag, err := s.outbox.Lag(ctx)
+	if err != nil {
+		s.logger.Error("Failed to read outbox lag for metrics", err)
+		return s.lag
+	}
+	s.lag = *lag
+	return s.lag
+}
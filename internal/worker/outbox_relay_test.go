@@ -0,0 +1,222 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOutbox is an OutboxRepository on a slice; claimed events stay claimed until they're
+// marked or released
+type fakeOutbox struct {
+	events     []*domain.OutboxEvent
+	claimed    map[int64]bool
+	claims     int
+	sentCutoff time.Time
+}
+
+func (f *fakeOutbox) Add(ctx context.Context, subject string, payload []byte) error {
+	f.events = append(f.events, &domain.OutboxEvent{ID: int64(len(f.events) + 1), Subject: subject, Payload: payload})
+	return nil
+}
+
+func (f *fakeOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]*domain.OutboxEvent, error) {
+	if f.claimed == nil {
+		f.claimed = make(map[int64]bool)
+	}
+	f.claims++
+	var pending []*domain.OutboxEvent
+	for _, event := range f.events {
+		if event.SentAt == nil && !f.claimed[event.ID] && len(pending) < limit {
+			f.claimed[event.ID] = true
+			pending = append(pending, event)
+		}
+	}
+	return pending, nil
+}
+
+func (f *fakeOutbox) MarkSent(ctx context.Context, ids []int64) error {
+	now := time.Now()
+	for _, id := range ids {
+		f.events[id-1].SentAt = &now
+		delete(f.claimed, id)
+	}
+	return nil
+}
+
+func (f *fakeOutbox) Release(ctx context.Context, ids []int64) error {
+	for _, id := range ids {
+		delete(f.claimed, id)
+	}
+	return nil
+}
+
+func (f *fakeOutbox) DeleteSentBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	f.sentCutoff = cutoff
+	return 0, nil
+}
+
+func (f *fakeOutbox) Lag(ctx context.Context) (*domain.OutboxLag, error) {
+	return &domain.OutboxLag{}, nil
+}
+
+// fakeOutboxPublisher records payloads, failing once failOn is reached.
+// Each publish runs onPublish first when set.
+type fakeOutboxPublisher struct {
+	published []string
+	failOn    string
+	onPublish func()
+}
+
+func (p *fakeOutboxPublisher) Publish(ctx context.Context, subject string, data []byte) error {
+	if p.onPublish != nil {
+		p.onPublish()
+	}
+	if string(data) == p.failOn {
+		return errors.New("nats down")
+	}
+	p.published = append(p.published, string(data))
+	return nil
+}
+
+func newTestRelay(outbox *fakeOutbox, publisher *fakeOutboxPublisher, batchSize int, clk clock.Clock) *OutboxRelay {
+	return NewOutboxRelay(outbox, publisher, batchSize, 24*time.Hour, clk, logger.New("test"))
+}
+
+func addEvents(t *testing.T, outbox *fakeOutbox, payloads ...string) {
+	for _, payload := range payloads {
+		require.NoError(t, outbox.Add(context.Background(), "reviews.events", []byte(payload)))
+	}
+}
+
+func TestOutboxRelay_Relay_PublishesEveryBatch(t *testing.T) {
+	outbox := &fakeOutbox{}
+	addEvents(t, outbox, "1", "2", "3")
+	publisher := &fakeOutboxPublisher{}
+	relay := newTestRelay(outbox, publisher, 2, clock.New())
+
+	sent, err := relay.Relay(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+	assert.Equal(t, []string{"1", "2", "3"}, publisher.published)
+	assert.Equal(t, 2, outbox.claims, "one claim per batch")
+	pending, _ := outbox.Claim(context.Background(), 10, time.Minute)
+	assert.Empty(t, pending)
+}
+
+func TestOutboxRelay_Relay_StopsAtFirstFailure(t *testing.T) {
+	outbox := &fakeOutbox{}
+	addEvents(t, outbox, "1", "2", "3")
+	publisher := &fakeOutboxPublisher{failOn: "2"}
+	relay := newTestRelay(outbox, publisher, 10, clock.New())
+
+	sent, err := relay.Relay(context.Background())
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, []string{"1"}, publisher.published, "3 must wait for 2 to keep the order")
+	assert.Empty(t, outbox.claimed, "unpublished events are released for the next poll")
+
+	publisher.failOn = ""
+	sent, err = relay.Relay(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	assert.Equal(t, []string{"1", "2", "3"}, publisher.published)
+}
+
+func TestOutboxRelay_Relay_ReleasesBatchPastHalfTheLease(t *testing.T) {
+	clk := clock.NewFake(time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC))
+	outbox := &fakeOutbox{}
+	addEvents(t, outbox, "1", "2", "3")
+	// Each publish takes a fifth of the lease
+	publisher := &fakeOutboxPublisher{onPublish: func() { clk.Advance(outboxClaimLease / 5) }}
+	relay := newTestRelay(outbox, publisher, 3, clk)
+
+	claimed, published, err := relay.relayBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, claimed)
+	assert.Equal(t, 3, published, "the third publish starts before half the lease is gone")
+
+	addEvents(t, outbox, "4", "5", "6")
+	publisher.onPublish = func() { clk.Advance(outboxClaimLease / 3) }
+
+	claimed, published, err = relay.relayBatch(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, claimed)
+	assert.Equal(t, 2, published)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, publisher.published)
+	assert.Empty(t, outbox.claimed, "6 is handed back rather than left to the lease")
+	assert.Nil(t, outbox.events[5].SentAt)
+}
+
+func TestOutboxRelay_CleanupRunsHourly(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clk := clock.NewFake(now)
+	outbox := &fakeOutbox{}
+	relay := newTestRelay(outbox, &fakeOutboxPublisher{}, 10, clk)
+
+	require.NoError(t, relay.cleanup(context.Background()))
+	assert.Equal(t, now.Add(-24*time.Hour), outbox.sentCutoff)
+
+	clk.Advance(time.Minute)
+	require.NoError(t, relay.cleanup(context.Background()))
+	assert.Equal(t, now.Add(-24*time.Hour), outbox.sentCutoff, "too soon for another cleanup")
+
+	clk.Advance(time.Hour)
+	require.NoError(t, relay.cleanup(context.Background()))
+	assert.Equal(t, clk.Now().Add(-24*time.Hour), outbox.sentCutoff)
+}
+
+// lagOutbox reports a fixed lag and counts the queries
+type lagOutbox struct {
+	lag     domain.OutboxLag
+	err     error
+	queries int
+}
+
+func (o *lagOutbox) Lag(ctx context.Context) (*domain.OutboxLag, error) {
+	o.queries++
+	if o.err != nil {
+		return nil, o.err
+	}
+	lag := o.lag
+	return &lag, nil
+}
+
+func TestRegisterOutboxMetrics(t *testing.T) {
+	outbox := &lagOutbox{lag: domain.OutboxLag{Pending: 42, OldestAge: 90 * time.Second}}
+	clk := clock.NewFake(time.Now())
+	reg := metrics.NewRegistry()
+	RegisterOutboxMetrics(reg, outbox, clk, logger.New("test"))
+
+	scrape := func() string {
+		var b strings.Builder
+		_, err := reg.WriteTo(&b)
+		require.NoError(t, err)
+		return b.String()
+	}
+
+	body := scrape()
+	assert.Contains(t, body, "events_outbox_pending 42\n")
+	assert.Contains(t, body, "events_outbox_oldest_age_seconds 90\n")
+	assert.Equal(t, 1, outbox.queries, "both gauges share one query")
+
+	// A failed query keeps reporting the last sample
+	clk.Advance(2 * time.Second)
+	outbox.err = errors.New("connection refused")
+	assert.Contains(t, scrape(), "events_outbox_pending 42\n")
+	assert.Equal(t, 2, outbox.queries)
+}
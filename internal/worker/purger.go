@@ -0,0 +1,179 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+// Purger hard-deletes products and reviews that have been soft-deleted for longer than
+// the retention period, so the tables don't grow forever.
+// Deletes run in batches of batchSize, each in its own short transaction, to keep locks
+// and WAL bursts small on large backlogs.
+type Purger struct {
+	db        *sqlx.DB
+	retention time.Duration
+	batchSize int
+	clock     clock.Clock
+	logger    *logger.Logger
+}
+
+// NewPurger creates a purger for rows soft-deleted more than retention ago
+func NewPurger(db *sqlx.DB, retention time.Duration, batchSize int, clk clock.Clock, logger *logger.Logger) *Purger {
+	return &Purger{
+		db:        db,
+		retention: retention,
+		batchSize: batchSize,
+		clock:     clk,
+		logger:    logger,
+	}
+}
+
+// Run purges once immediately and then every interval, until ctx is cancelled
+func (p *Purger) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, _, err := p.Purge(ctx); err != nil && ctx.Err() == nil {
+			p.logger.Error("Failed to purge soft-deleted rows", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Purge hard-deletes every expired product (with all its reviews) and then every expired
+// review of a live product. Returns the number of rows deleted; on error or cancellation
+// the batches already committed stay deleted.
+func (p *Purger) Purge(ctx context.Context) (products, reviews int64, err error) {
+	cutoff := p.clock.Now().Add(-p.retention)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return products, reviews, err
+		}
+
+		purgedProducts, purgedReviews, err := p.purgeProductBatch(ctx, cutoff)
+		if err != nil {
+			return products, reviews, err
+		}
+		products += purgedProducts
+		reviews += purgedReviews
+
+		if purgedProducts < int64(p.batchSize) {
+			break
+		}
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return products, reviews, err
+		}
+
+		purged, err := p.purgeReviewBatch(ctx, cutoff)
+		if err != nil {
+			return products, reviews, err
+		}
+		reviews += purged
+
+		if purged < int64(p.batchSize) {
+			break
+		}
+	}
+
+	if products > 0 || reviews > 0 {
+		p.logger.WithFields(map[string]any{
+			"products": products,
+			"reviews":  reviews,
+			"cutoff":   cutoff,
+		}).Info("Purged soft-deleted rows")
+	}
+
+	return products, reviews, nil
+}
+
+// purgeProductBatch deletes up to batchSize expired products and all their reviews in one
+// transaction, so a product is never gone while its reviews remain (or the reverse).
+// Reviews are deleted explicitly rather than left to ON DELETE CASCADE so they're counted.
+func (p *Purger) purgeProductBatch(ctx context.Context, cutoff time.Time) (products, reviews int64, err error) {
+	tx, err := p.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to begin purge transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// SKIP LOCKED lets a concurrent purger (another worker replica) take the next batch
+	var ids []uuid.UUID
+	query := `
+		SELECT id
+		FROM products
+		WHERE deleted_at < $1
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`
+	if err := tx.SelectContext(ctx, &ids, query, cutoff, p.batchSize); err != nil {
+		return 0, 0, fmt.Errorf("failed to select expired products: %w", err)
+	}
+	if len(ids) == 0 {
+		return 0, 0, nil
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM reviews WHERE product_id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge reviews of expired products: %w", err)
+	}
+	reviews, err = result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count purged reviews: %w", err)
+	}
+
+	result, err = tx.ExecContext(ctx, `DELETE FROM products WHERE id = ANY($1)`, pq.Array(ids))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to purge expired products: %w", err)
+	}
+	products, err = result.RowsAffected()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count purged products: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("failed to commit purge transaction: %w", err)
+	}
+
+	return products, reviews, nil
+}
+
+// purgeReviewBatch deletes up to batchSize expired reviews
+func (p *Purger) purgeReviewBatch(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		DELETE FROM reviews
+		WHERE id IN (
+			SELECT id
+			FROM reviews
+			WHERE deleted_at < $1
+			LIMIT $2
+		)
+	`
+
+	result, err := p.db.ExecContext(ctx, query, cutoff, p.batchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge expired reviews: %w", err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count purged reviews: %w", err)
+	}
+	return purged, nil
+}
@@ -0,0 +1,134 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRetention = 30 * 24 * time.Hour
+
+func newTestPurger(t *testing.T, batchSize int) (*Purger, sqlmock.Sqlmock, time.Time) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	purger := NewPurger(sqlx.NewDb(db, "sqlmock"), testRetention, batchSize, clock.NewFake(now), logger.New("test"))
+
+	return purger, mock, now.Add(-testRetention)
+}
+
+func idRows(ids ...uuid.UUID) *sqlmock.Rows {
+	rows := sqlmock.NewRows([]string{"id"})
+	for _, id := range ids {
+		rows.AddRow(id)
+	}
+	return rows
+}
+
+func TestPurger_Purge_DeletesProductsWithTheirReviews(t *testing.T) {
+	purger, mock, cutoff := newTestPurger(t, 2)
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	// A full batch, so the purger comes back for more
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id\\s+FROM products\\s+WHERE deleted_at < \\$1").
+		WithArgs(cutoff, 2).
+		WillReturnRows(idRows(first, second))
+	mock.ExpectExec("DELETE FROM reviews WHERE product_id = ANY").
+		WillReturnResult(sqlmock.NewResult(0, 7))
+	mock.ExpectExec("DELETE FROM products WHERE id = ANY").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id\\s+FROM products").
+		WithArgs(cutoff, 2).
+		WillReturnRows(idRows(third))
+	mock.ExpectExec("DELETE FROM reviews WHERE product_id = ANY").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("DELETE FROM products WHERE id = ANY").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	mock.ExpectExec("DELETE FROM reviews\\s+WHERE id IN").
+		WithArgs(cutoff, 2).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	products, reviews, err := purger.Purge(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), products)
+	assert.Equal(t, int64(8), reviews)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurger_Purge_ReviewBatches(t *testing.T) {
+	purger, mock, cutoff := newTestPurger(t, 100)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id\\s+FROM products").
+		WithArgs(cutoff, 100).
+		WillReturnRows(idRows())
+	mock.ExpectRollback()
+
+	mock.ExpectExec("DELETE FROM reviews\\s+WHERE id IN").
+		WithArgs(cutoff, 100).
+		WillReturnResult(sqlmock.NewResult(0, 100))
+	mock.ExpectExec("DELETE FROM reviews\\s+WHERE id IN").
+		WithArgs(cutoff, 100).
+		WillReturnResult(sqlmock.NewResult(0, 40))
+
+	products, reviews, err := purger.Purge(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), products)
+	assert.Equal(t, int64(140), reviews)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurger_Purge_RollsBackFailedProductBatch(t *testing.T) {
+	purger, mock, cutoff := newTestPurger(t, 10)
+	dbErr := errors.New("connection reset")
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("SELECT id\\s+FROM products").
+		WithArgs(cutoff, 10).
+		WillReturnRows(idRows(uuid.New()))
+	mock.ExpectExec("DELETE FROM reviews WHERE product_id = ANY").
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("DELETE FROM products WHERE id = ANY").
+		WillReturnError(dbErr)
+	mock.ExpectRollback()
+
+	products, reviews, err := purger.Purge(context.Background())
+
+	assert.ErrorIs(t, err, dbErr)
+	assert.Equal(t, int64(0), products)
+	assert.Equal(t, int64(0), reviews)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestPurger_Purge_StopsWhenCancelled(t *testing.T) {
+	purger, mock, _ := newTestPurger(t, 10)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, _, err := purger.Purge(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
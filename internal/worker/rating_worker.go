@@ -3,17 +3,22 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
 	"github.com/google/uuid"
 )
 
 const (
-	// Debounce window - collect events for same product within this duration
-	debounceWindow = 1 * time.Second
+	// Default debounce window - collect events for same product within this duration.
+	// WORKER_DEBOUNCE_WINDOW overrides it through SetDebounceWindow.
+	defaultDebounceWindow = 1 * time.Second
 
 	// Retry configuration
 	maxRetries     = 3
@@ -23,18 +28,41 @@ const (
 	maxConcurrentCalculations = 10
 )
 
-// ReviewEvent represents a review event from NATS
+// ReviewEvent represents a review event from NATS.
+// Every event type, including the product.rating.recalc event published by bulk
+// imports, is handled the same way: it schedules one debounced rating update.
 type ReviewEvent struct {
-	Type      string    `json:"type"`
+	Type      string    `json:"event_type"`
 	ProductID uuid.UUID `json:"product_id"`
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// ErrPermanent marks a HandleEvent failure that redelivery can't fix, such as a payload
+// that isn't valid JSON. The JetStream fetch loop terminates these messages instead of
+// Nak'ing them, so a poison message doesn't use up its MaxDeliver attempts. Errors that
+// don't wrap it (a database outage, say) stay retryable.
+var ErrPermanent = errors.New("permanent event error")
+
+// ProductCache clears cached product data after the rating is recalculated
+// Without it the API keeps serving the old rating until the cache TTL expires
+type ProductCache interface {
+	InvalidateAllProductCache(ctx context.Context, productID uuid.UUID) error
+	SetProductRating(ctx context.Context, productID uuid.UUID, rating float64) error
+}
+
 // RatingWorker processes review events and updates product ratings asynchronously
 type RatingWorker struct {
 	calculator *Calculator
+	cache      ProductCache
+	clock      clock.Clock
 	logger     *logger.Logger
 
+	// warmRatingCache writes the fresh rating after invalidation so the next read is a cache hit
+	warmRatingCache bool
+
+	// debounceWindow is a time.Duration, atomic so it can be changed on config reload
+	debounceWindow atomic.Int64
+
 	// Debouncing state
 	mu             sync.Mutex
 	pendingUpdates map[uuid.UUID]*pendingUpdate
@@ -45,38 +73,79 @@ type RatingWorker struct {
 
 	// Concurrency control to prevent DB overload
 	concurrencySem chan struct{}
+
+	// Debounce effectiveness: events received vs recalculations that reached the database
+	eventsReceived       atomic.Uint64
+	ratingUpdates        atomic.Uint64
+	ratingUpdateFailures atomic.Uint64
+}
+
+// DebounceStats counts events against the rating recalculations they led to since the
+// worker started. EventsReceived / RatingUpdates is the debounce ratio that
+// WORKER_DEBOUNCE_WINDOW tuning is based on.
+type DebounceStats struct {
+	EventsReceived uint64
+	RatingUpdates  uint64
+	// RatingUpdateFailures counts updates abandoned after all retries
+	RatingUpdateFailures uint64
+	Pending              int
 }
 
 type pendingUpdate struct {
 	productID uuid.UUID
 	timestamp time.Time
-	timer     *time.Timer
+	timer     clock.Timer
 }
 
 // NewRatingWorker creates a new rating worker
-func NewRatingWorker(calculator *Calculator, logger *logger.Logger) *RatingWorker {
+// cache may be nil, in which case cached ratings refresh only on TTL expiry
+// clk drives the debounce timers; tests pass a clock.Fake to fire them without sleeping
+func NewRatingWorker(
+	calculator *Calculator,
+	cache ProductCache,
+	warmRatingCache bool,
+	clk clock.Clock,
+	logger *logger.Logger,
+) *RatingWorker {
 	ctx, cancel := context.WithCancel(context.Background())
 
-	return &RatingWorker{
-		calculator:     calculator,
-		logger:         logger,
-		pendingUpdates: make(map[uuid.UUID]*pendingUpdate),
-		shutdownCh:     make(chan struct{}),
-		ctx:            ctx,
-		cancel:         cancel,
-		concurrencySem: make(chan struct{}, maxConcurrentCalculations),
+	w := &RatingWorker{
+		calculator:      calculator,
+		cache:           cache,
+		warmRatingCache: warmRatingCache,
+		clock:           clk,
+		logger:          logger,
+		pendingUpdates:  make(map[uuid.UUID]*pendingUpdate),
+		shutdownCh:      make(chan struct{}),
+		ctx:             ctx,
+		cancel:          cancel,
+		concurrencySem:  make(chan struct{}, maxConcurrentCalculations),
 	}
+	w.debounceWindow.Store(int64(defaultDebounceWindow))
+
+	return w
+}
+
+// SetDebounceWindow changes how long events for a product are collected before its rating
+// is recalculated. Updates already pending keep the window they were scheduled with.
+func (w *RatingWorker) SetDebounceWindow(window time.Duration) {
+	w.debounceWindow.Store(int64(window))
 }
 
 // HandleEvent processes a review event
+// Malformed events return an error wrapping ErrPermanent; every other error is worth a redelivery
 func (w *RatingWorker) HandleEvent(data []byte) error {
 	var event ReviewEvent
 	if err := json.Unmarshal(data, &event); err != nil {
 		w.logger.WithFields(map[string]any{
 			"error": err.Error(),
 		}).Error("Failed to unmarshal review event", err)
-		return fmt.Errorf("failed to unmarshal event: %w", err)
+		return fmt.Errorf("%w: failed to unmarshal event: %w", ErrPermanent, err)
 	}
+	if event.ProductID == uuid.Nil {
+		return fmt.Errorf("%w: event has no product_id", ErrPermanent)
+	}
+	w.eventsReceived.Add(1)
 
 	w.logger.WithFields(map[string]any{
 		"type":       event.Type,
@@ -111,41 +180,51 @@ func (w *RatingWorker) scheduleUpdate(productID uuid.UUID, timestamp time.Time)
 		// Ignore stale events
 		if timestamp.Before(existing.timestamp) {
 			w.logger.WithFields(map[string]any{
-				"product_id":       productID.String(),
-				"existing_ts":      existing.timestamp,
-				"event_ts":         timestamp,
+				"product_id":  productID.String(),
+				"existing_ts": existing.timestamp,
+				"event_ts":    timestamp,
 			}).Debug("Ignoring stale event")
 			return
 		}
 
 		// Cancel existing timer (we'll create a new one)
-		existing.timer.Stop()
-		w.logger.WithFields(map[string]any{
-			"product_id": productID.String(),
-		}).Debug("Debouncing: resetting timer for product")
+		if existing.timer.Stop() {
+			w.logger.WithFields(map[string]any{
+				"product_id": productID.String(),
+			}).Debug("Debouncing: resetting timer for product")
+		} else {
+			// The timer already fired and its update is waiting for the lock; it runs and
+			// releases its own wait group slot, so the new timer needs one of its own
+			w.wg.Add(1)
+		}
 	} else {
 		// New product, increment wait group
 		w.wg.Add(1)
 	}
 
 	// Create new timer for debounced update
-	timer := time.AfterFunc(debounceWindow, func() {
-		w.processUpdate(productID)
-	})
-
-	w.pendingUpdates[productID] = &pendingUpdate{
+	update := &pendingUpdate{
 		productID: productID,
 		timestamp: timestamp,
-		timer:     timer,
 	}
+	update.timer = w.clock.AfterFunc(time.Duration(w.debounceWindow.Load()), func() {
+		w.processUpdate(update)
+	})
+
+	w.pendingUpdates[productID] = update
 }
 
 // processUpdate executes the rating calculation with retry logic
-func (w *RatingWorker) processUpdate(productID uuid.UUID) {
+func (w *RatingWorker) processUpdate(update *pendingUpdate) {
 	defer w.wg.Done()
 
+	productID := update.productID
+
 	w.mu.Lock()
-	delete(w.pendingUpdates, productID)
+	// A newer event may have replaced this update after its timer fired; leave that one pending
+	if w.pendingUpdates[productID] == update {
+		delete(w.pendingUpdates, productID)
+	}
 	w.mu.Unlock()
 
 	// Acquire semaphore to limit concurrent calculations
@@ -166,6 +245,13 @@ func (w *RatingWorker) processUpdate(productID uuid.UUID) {
 	backoff := initialBackoff
 
 	for attempt := range maxRetries {
+		// Shutdown may land while waiting for the semaphore or between attempts;
+		// don't start a query that would only be cancelled
+		if w.ctx.Err() != nil {
+			w.logger.Info("Worker context cancelled, aborting rating update")
+			return
+		}
+
 		if attempt > 0 {
 			w.logger.WithFields(map[string]any{
 				"product_id": productID.String(),
@@ -186,10 +272,12 @@ func (w *RatingWorker) processUpdate(productID uuid.UUID) {
 
 		// Create context with timeout for each attempt
 		ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
-		err := w.calculator.CalculateAndUpdate(ctx, productID)
+		rating, updated, err := w.calculator.CalculateAndUpdate(ctx, productID)
 		cancel()
 
 		if err == nil {
+			w.ratingUpdates.Add(1)
+			w.refreshCache(productID, rating, updated)
 			return
 		}
 
@@ -202,6 +290,7 @@ func (w *RatingWorker) processUpdate(productID uuid.UUID) {
 	}
 
 	// All retries exhausted
+	w.ratingUpdateFailures.Add(1)
 	w.logger.WithFields(map[string]any{
 		"product_id":  productID.String(),
 		"max_retries": maxRetries,
@@ -209,6 +298,39 @@ func (w *RatingWorker) processUpdate(productID uuid.UUID) {
 	}).Error("Rating update failed after all retries", lastErr)
 }
 
+// refreshCache drops cached product data so the API picks up the recalculated rating,
+// then optionally warms the rating key so the next read skips the database
+// Non-fatal: the rating is already persisted, stale cache only lasts until TTL expiry
+func (w *RatingWorker) refreshCache(productID uuid.UUID, rating float64, updated bool) {
+	// Cache is optional so the worker keeps updating ratings when Redis is unavailable
+	if w.cache == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(w.ctx, 5*time.Second)
+	defer cancel()
+
+	if err := w.cache.InvalidateAllProductCache(ctx, productID); err != nil {
+		w.logger.WithFields(map[string]any{
+			"product_id": productID.String(),
+			"error":      err.Error(),
+		}).Warn("Failed to invalidate product cache after rating update")
+		// Warming on top of a failed invalidation could pin a rating next to stale review pages
+		return
+	}
+
+	if !w.warmRatingCache || !updated {
+		return
+	}
+
+	if err := w.cache.SetProductRating(ctx, productID, rating); err != nil {
+		w.logger.WithFields(map[string]any{
+			"product_id": productID.String(),
+			"error":      err.Error(),
+		}).Warn("Failed to warm product rating cache")
+	}
+}
+
 // Shutdown gracefully shuts down the worker
 // Cancels pending timers and waits for in-flight updates to complete
 func (w *RatingWorker) Shutdown(ctx context.Context) error {
@@ -222,16 +344,19 @@ func (w *RatingWorker) Shutdown(ctx context.Context) error {
 
 	// Cancel all pending timers
 	w.mu.Lock()
-	pendingCount := len(w.pendingUpdates)
+	cancelledCount := 0
 	for _, update := range w.pendingUpdates {
-		update.timer.Stop()
-		w.wg.Done() // Decrement counter for cancelled updates
+		// A timer that already fired owns its wait group slot and exits on the cancelled context
+		if update.timer.Stop() {
+			w.wg.Done() // Decrement counter for cancelled updates
+			cancelledCount++
+		}
 	}
 	w.pendingUpdates = make(map[uuid.UUID]*pendingUpdate)
 	w.mu.Unlock()
 
 	w.logger.WithFields(map[string]any{
-		"cancelled_updates": pendingCount,
+		"cancelled_updates": cancelledCount,
 	}).Info("Cancelled pending updates")
 
 	// Wait for in-flight updates to complete or context timeout
@@ -257,3 +382,30 @@ func (w *RatingWorker) GetPendingCount() int {
 	defer w.mu.Unlock()
 	return len(w.pendingUpdates)
 }
+
+// DebounceStats returns the worker's event and update counters
+func (w *RatingWorker) DebounceStats() DebounceStats {
+	return DebounceStats{
+		EventsReceived:       w.eventsReceived.Load(),
+		RatingUpdates:        w.ratingUpdates.Load(),
+		RatingUpdateFailures: w.ratingUpdateFailures.Load(),
+		Pending:              w.GetPendingCount(),
+	}
+}
+
+// RegisterMetrics exposes DebounceStats on reg so operators can graph the debounce ratio,
+// e.g. rate(events_received_total) / rate(rating_updates_total)
+func (w *RatingWorker) RegisterMetrics(reg *metrics.Registry) {
+	reg.CounterFunc("rating_worker_events_received_total",
+		"Review events accepted by the rating worker.",
+		func() float64 { return float64(w.eventsReceived.Load()) })
+	reg.CounterFunc("rating_worker_rating_updates_total",
+		"Product rating recalculations written to the database.",
+		func() float64 { return float64(w.ratingUpdates.Load()) })
+	reg.CounterFunc("rating_worker_rating_update_failures_total",
+		"Product rating recalculations abandoned after all retries.",
+		func() float64 { return float64(w.ratingUpdateFailures.Load()) })
+	reg.GaugeFunc("rating_worker_pending_updates",
+		"Products waiting out the debounce window.",
+		func() float64 { return float64(w.GetPendingCount()) })
+}
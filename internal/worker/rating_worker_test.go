@@ -3,47 +3,89 @@ package worker
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/metrics"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-func setupTestWorker(t *testing.T) (*RatingWorker, sqlmock.Sqlmock, *sqlx.DB) {
+// fakeProductCache records invalidated products and warmed ratings for assertions
+type fakeProductCache struct {
+	mu          sync.Mutex
+	invalidated []uuid.UUID
+	ratings     map[uuid.UUID]float64
+	err         error
+}
+
+func (f *fakeProductCache) InvalidateAllProductCache(ctx context.Context, productID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.invalidated = append(f.invalidated, productID)
+	return f.err
+}
+
+func (f *fakeProductCache) SetProductRating(ctx context.Context, productID uuid.UUID, rating float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.ratings == nil {
+		f.ratings = make(map[uuid.UUID]float64)
+	}
+	f.ratings[productID] = rating
+	return nil
+}
+
+func (f *fakeProductCache) Invalidated() []uuid.UUID {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]uuid.UUID(nil), f.invalidated...)
+}
+
+func (f *fakeProductCache) Rating(productID uuid.UUID) (float64, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rating, ok := f.ratings[productID]
+	return rating, ok
+}
+
+// newTestWorker builds a worker on sqlmock whose debounce timers fire only when the
+// returned clock is advanced, so tests never wait out the real debounce window
+func newTestWorker(t *testing.T, cache ProductCache, warmRatingCache bool) (*RatingWorker, sqlmock.Sqlmock, *sqlx.DB, *clock.Fake) {
 	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
 	require.NoError(t, err)
 
 	sqlxDB := sqlx.NewDb(db, "sqlmock")
 	log := logger.New("test")
-	calculator := NewCalculator(sqlxDB, log)
-	worker := NewRatingWorker(calculator, log)
+	clk := clock.NewFake(time.Now())
+	worker := NewRatingWorker(NewCalculator(sqlxDB, domain.RatingRoundingHalfUp, 0, log), cache, warmRatingCache, clk, log)
 
-	return worker, mock, sqlxDB
+	return worker, mock, sqlxDB, clk
 }
 
-func setupTestWorkerUnordered(t *testing.T) (*RatingWorker, sqlmock.Sqlmock, *sqlx.DB) {
-	db, mock, err := sqlmock.New(
-		sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp),
-	)
-	require.NoError(t, err)
-	mock.MatchExpectationsInOrder(false)
+func setupTestWorker(t *testing.T) (*RatingWorker, sqlmock.Sqlmock, *sqlx.DB, *clock.Fake) {
+	return newTestWorker(t, nil, false)
+}
 
-	sqlxDB := sqlx.NewDb(db, "sqlmock")
-	log := logger.New("test")
-	calculator := NewCalculator(sqlxDB, log)
-	worker := NewRatingWorker(calculator, log)
+func setupTestWorkerUnordered(t *testing.T) (*RatingWorker, sqlmock.Sqlmock, *sqlx.DB, *clock.Fake) {
+	worker, mock, sqlxDB, clk := newTestWorker(t, nil, false)
+	mock.MatchExpectationsInOrder(false)
 
-	return worker, mock, sqlxDB
+	return worker, mock, sqlxDB, clk
 }
 
 func TestRatingWorker_HandleEvent_Success(t *testing.T) {
-	worker, mock, sqlxDB := setupTestWorker(t)
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -59,9 +101,7 @@ func TestRatingWorker_HandleEvent_Success(t *testing.T) {
 	require.NoError(t, err)
 
 	// Expect UPDATE query after debounce window
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRatingUpdate(mock, productID, 4.5)
 
 	// Handle event
 	err = worker.HandleEvent(eventData)
@@ -70,16 +110,64 @@ func TestRatingWorker_HandleEvent_Success(t *testing.T) {
 	// Verify pending update was scheduled
 	assert.Equal(t, 1, worker.GetPendingCount())
 
-	// Wait for debounce window + processing time
-	time.Sleep(debounceWindow + 100*time.Millisecond)
+	// Debounced updates run synchronously once the window has passed
+	clk.Advance(defaultDebounceWindow)
 
 	// Verify update was processed
 	assert.Equal(t, 0, worker.GetPendingCount())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestRatingWorker_HandleEvent_RatingRecalc(t *testing.T) {
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	productID := uuid.New()
+	// Shaped like the API's bulk-import event: no review attached
+	eventData := fmt.Appendf(nil,
+		`{"event_type":"product.rating.recalc","timestamp":%q,"product_id":%q}`,
+		time.Now().Format(time.RFC3339Nano), productID)
+
+	expectRatingUpdate(mock, productID, 3.5)
+
+	require.NoError(t, worker.HandleEvent(eventData))
+	assert.Equal(t, 1, worker.GetPendingCount())
+
+	// Debounced updates run synchronously once the window has passed
+	clk.Advance(defaultDebounceWindow)
+
+	assert.Equal(t, 0, worker.GetPendingCount())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRatingWorker_SetDebounceWindow(t *testing.T) {
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	worker.SetDebounceWindow(5 * time.Second)
+
+	productID := uuid.New()
+	eventData, err := json.Marshal(ReviewEvent{Type: "review.created", ProductID: productID, Timestamp: time.Now()})
+	require.NoError(t, err)
+
+	expectRatingUpdate(mock, productID, 4.0)
+
+	require.NoError(t, worker.HandleEvent(eventData))
+
+	clk.Advance(defaultDebounceWindow)
+	assert.Equal(t, 1, worker.GetPendingCount(), "update should wait for the new, longer window")
+
+	clk.Advance(4 * time.Second)
+	assert.Equal(t, 0, worker.GetPendingCount())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestRatingWorker_HandleEvent_InvalidJSON(t *testing.T) {
-	worker, _, sqlxDB := setupTestWorker(t)
+	worker, _, sqlxDB, _ := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -91,8 +179,37 @@ func TestRatingWorker_HandleEvent_InvalidJSON(t *testing.T) {
 	assert.Contains(t, err.Error(), "unmarshal")
 }
 
+func TestRatingWorker_HandleEvent_ErrorClassification(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		wantErr       bool
+		wantPermanent bool
+	}{
+		{name: "valid event", data: `{"event_type":"review.created","product_id":"` + uuid.New().String() + `","timestamp":"2024-01-01T00:00:00Z"}`},
+		{name: "invalid JSON", data: `{invalid json}`, wantErr: true, wantPermanent: true},
+		{name: "wrong field type", data: `{"product_id":42}`, wantErr: true, wantPermanent: true},
+		{name: "missing product_id", data: `{"event_type":"review.created","timestamp":"2024-01-01T00:00:00Z"}`, wantErr: true, wantPermanent: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			worker, _, sqlxDB, _ := setupTestWorker(t)
+			defer func() {
+				_ = sqlxDB.Close()
+			}()
+
+			err := worker.HandleEvent([]byte(tc.data))
+
+			assert.Equal(t, tc.wantErr, err != nil)
+			// A permanent error is terminated without redelivery, so it must never cover a retryable failure
+			assert.Equal(t, tc.wantPermanent, errors.Is(err, ErrPermanent))
+		})
+	}
+}
+
 func TestRatingWorker_Debouncing_MultipleEvents(t *testing.T) {
-	worker, mock, sqlxDB := setupTestWorker(t)
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -100,47 +217,82 @@ func TestRatingWorker_Debouncing_MultipleEvents(t *testing.T) {
 	productID := uuid.New()
 
 	// Expect only ONE database update despite multiple events
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRatingUpdate(mock, productID, 4.5)
 
-	// Send 10 events for the same product within debounce window
+	// Send 10 events for the same product, each within the debounce window of the last
 	for i := 0; i < 10; i++ {
 		event := ReviewEvent{
 			Type:      "review.created",
 			ProductID: productID,
-			Timestamp: time.Now(),
+			Timestamp: clk.Now(),
 		}
 		eventData, _ := json.Marshal(event)
 		err := worker.HandleEvent(eventData)
 		assert.NoError(t, err)
-		time.Sleep(50 * time.Millisecond) // Within debounce window
+		clk.Advance(defaultDebounceWindow / 2)
 	}
 
-	// Should still have 1 pending update (debounced)
+	// Every event reset the timer, so nothing has run yet
 	assert.Equal(t, 1, worker.GetPendingCount())
+	assert.Equal(t, 1, clk.PendingTimers())
 
-	// Wait for debounce window + processing time
-	time.Sleep(debounceWindow + 200*time.Millisecond)
+	// Once the window passes without events the update runs, synchronously in Advance
+	clk.Advance(defaultDebounceWindow)
 
 	// Verify only one update was executed
 	assert.Equal(t, 0, worker.GetPendingCount())
 	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, DebounceStats{EventsReceived: 10, RatingUpdates: 1}, worker.DebounceStats(), "a 10:1 debounce ratio")
+}
+
+func TestRatingWorker_RegisterMetrics(t *testing.T) {
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	reg := metrics.NewRegistry()
+	worker.RegisterMetrics(reg)
+
+	productID := uuid.New()
+	expectRatingUpdate(mock, productID, 4.5)
+
+	for range 3 {
+		eventData, _ := json.Marshal(ReviewEvent{Type: "review.created", ProductID: productID, Timestamp: clk.Now()})
+		require.NoError(t, worker.HandleEvent(eventData))
+	}
+	// Malformed events are rejected before they count as received
+	require.Error(t, worker.HandleEvent([]byte("not json")))
+
+	var before strings.Builder
+	_, err := reg.WriteTo(&before)
+	require.NoError(t, err)
+	assert.Contains(t, before.String(), "rating_worker_events_received_total 3\n")
+	assert.Contains(t, before.String(), "rating_worker_rating_updates_total 0\n")
+	assert.Contains(t, before.String(), "rating_worker_pending_updates 1\n")
+
+	clk.Advance(defaultDebounceWindow)
+
+	var after strings.Builder
+	_, err = reg.WriteTo(&after)
+	require.NoError(t, err)
+	assert.Contains(t, after.String(), "rating_worker_rating_updates_total 1\n")
+	assert.Contains(t, after.String(), "rating_worker_rating_update_failures_total 0\n")
+	assert.Contains(t, after.String(), "rating_worker_pending_updates 0\n")
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestRatingWorker_EventOrdering_IgnoreStaleEvents(t *testing.T) {
-	worker, mock, sqlxDB := setupTestWorker(t)
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
 
 	productID := uuid.New()
-	now := time.Now()
+	now := clk.Now()
 
 	// Expect only ONE update (for the newer event)
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRatingUpdate(mock, productID, 4.5)
 
 	// Send newer event first
 	newerEvent := ReviewEvent{
@@ -152,6 +304,8 @@ func TestRatingWorker_EventOrdering_IgnoreStaleEvents(t *testing.T) {
 	err := worker.HandleEvent(newerData)
 	assert.NoError(t, err)
 
+	clk.Advance(defaultDebounceWindow / 2)
+
 	// Send older event (should be ignored)
 	olderEvent := ReviewEvent{
 		Type:      "review.created",
@@ -165,15 +319,16 @@ func TestRatingWorker_EventOrdering_IgnoreStaleEvents(t *testing.T) {
 	// Should still have 1 pending update (stale event ignored)
 	assert.Equal(t, 1, worker.GetPendingCount())
 
-	// Wait for processing
-	time.Sleep(debounceWindow + 200*time.Millisecond)
+	// The stale event didn't reset the timer, so the rest of the first window is enough
+	clk.Advance(defaultDebounceWindow / 2)
 
 	// Verify only one update
+	assert.Equal(t, 0, worker.GetPendingCount())
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
 func TestRatingWorker_MultipleProducts(t *testing.T) {
-	worker, mock, sqlxDB := setupTestWorkerUnordered(t)
+	worker, mock, sqlxDB, clk := setupTestWorkerUnordered(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -183,15 +338,9 @@ func TestRatingWorker_MultipleProducts(t *testing.T) {
 	product3 := uuid.New()
 
 	// Expect 3 updates (one per product)
-	mock.ExpectExec("UPDATE products").
-		WithArgs(product1, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
-	mock.ExpectExec("UPDATE products").
-		WithArgs(product2, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
-	mock.ExpectExec("UPDATE products").
-		WithArgs(product3, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRatingUpdate(mock, product1, 4.5)
+	expectRatingUpdate(mock, product2, 4.5)
+	expectRatingUpdate(mock, product3, 4.5)
 
 	// Send events for different products
 	for _, productID := range []uuid.UUID{product1, product2, product3} {
@@ -208,8 +357,8 @@ func TestRatingWorker_MultipleProducts(t *testing.T) {
 	// Should have 3 pending updates
 	assert.Equal(t, 3, worker.GetPendingCount())
 
-	// Wait for processing (debounce + time for all 3 concurrent updates to complete)
-	time.Sleep(debounceWindow + 500*time.Millisecond)
+	// Debounced updates run synchronously once the window has passed
+	clk.Advance(defaultDebounceWindow)
 
 	// Verify all updates executed
 	assert.Equal(t, 0, worker.GetPendingCount())
@@ -217,7 +366,7 @@ func TestRatingWorker_MultipleProducts(t *testing.T) {
 }
 
 func TestRatingWorker_GracefulShutdown(t *testing.T) {
-	worker, mock, sqlxDB := setupTestWorker(t)
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -225,9 +374,7 @@ func TestRatingWorker_GracefulShutdown(t *testing.T) {
 	productID := uuid.New()
 
 	// Expect one update to complete
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRatingUpdate(mock, productID, 4.5)
 
 	event := ReviewEvent{
 		Type:      "review.created",
@@ -241,8 +388,8 @@ func TestRatingWorker_GracefulShutdown(t *testing.T) {
 	// Verify pending update
 	assert.Equal(t, 1, worker.GetPendingCount())
 
-	// Wait for processing to start
-	time.Sleep(debounceWindow + 50*time.Millisecond)
+	// Let the update run to completion before shutting down
+	clk.Advance(defaultDebounceWindow)
 
 	// Shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -257,7 +404,7 @@ func TestRatingWorker_GracefulShutdown(t *testing.T) {
 }
 
 func TestRatingWorker_ShutdownCancelsPendingUpdates(t *testing.T) {
-	worker, _, sqlxDB := setupTestWorker(t)
+	worker, _, sqlxDB, _ := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -289,7 +436,7 @@ func TestRatingWorker_ShutdownCancelsPendingUpdates(t *testing.T) {
 }
 
 func TestRatingWorker_ShutdownCancelsInFlightOperations(t *testing.T) {
-	worker, mock, sqlxDB := setupTestWorker(t)
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -298,9 +445,7 @@ func TestRatingWorker_ShutdownCancelsInFlightOperations(t *testing.T) {
 
 	// Simulate database update that respects context cancellation
 	// The query will be cancelled when shutdown is called
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnError(fmt.Errorf("canceling query due to user request"))
+	expectRatingUpdateError(mock, productID, fmt.Errorf("canceling query due to user request"))
 
 	event := ReviewEvent{
 		Type:      "review.created",
@@ -311,8 +456,11 @@ func TestRatingWorker_ShutdownCancelsInFlightOperations(t *testing.T) {
 	err := worker.HandleEvent(eventData)
 	assert.NoError(t, err)
 
-	// Wait for processing to start
-	time.Sleep(debounceWindow + 50*time.Millisecond)
+	// Fire the timer in the background: the failed attempt leaves the update waiting to retry
+	go clk.Advance(defaultDebounceWindow)
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, 5*time.Millisecond)
 
 	// Shutdown should complete successfully because in-flight operations are cancelled
 	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
@@ -323,7 +471,7 @@ func TestRatingWorker_ShutdownCancelsInFlightOperations(t *testing.T) {
 }
 
 func TestRatingWorker_RetryLogic(t *testing.T) {
-	worker, mock, sqlxDB := setupTestWorker(t)
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
 	defer func() {
 		_ = sqlxDB.Close()
 	}()
@@ -331,17 +479,11 @@ func TestRatingWorker_RetryLogic(t *testing.T) {
 	productID := uuid.New()
 
 	// Simulate 2 failures then success
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnError(assert.AnError)
+	expectRatingUpdateError(mock, productID, assert.AnError)
 
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnError(assert.AnError)
+	expectRatingUpdateError(mock, productID, assert.AnError)
 
-	mock.ExpectExec("UPDATE products").
-		WithArgs(productID, sqlmock.AnyArg()).
-		WillReturnResult(sqlmock.NewResult(0, 1))
+	expectRatingUpdate(mock, productID, 4.5)
 
 	event := ReviewEvent{
 		Type:      "review.created",
@@ -352,10 +494,194 @@ func TestRatingWorker_RetryLogic(t *testing.T) {
 	err := worker.HandleEvent(eventData)
 	assert.NoError(t, err)
 
-	// Wait for processing with retries (debounce + 3 attempts with backoff: 1s + 2s)
-	// Total: 1s (debounce) + 1s (retry 1) + 2s (retry 2) + buffer
-	time.Sleep(debounceWindow + 5*time.Second)
+	// The debounced update, including its retry backoff (1s + 2s), runs inside Advance
+	clk.Advance(defaultDebounceWindow)
 
 	// Verify all retries executed
 	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, DebounceStats{EventsReceived: 1, RatingUpdates: 1}, worker.DebounceStats(), "failed attempts that a retry recovers aren't failures")
+}
+
+func TestRatingWorker_InvalidatesCacheAfterUpdate(t *testing.T) {
+	cache := &fakeProductCache{}
+	worker, mock, sqlxDB, clk := newTestWorker(t, cache, false)
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	productID := uuid.New()
+	expectRatingUpdate(mock, productID, 4.5)
+
+	eventData, _ := json.Marshal(ReviewEvent{
+		Type:      "review.created",
+		ProductID: productID,
+		Timestamp: time.Now(),
+	})
+	assert.NoError(t, worker.HandleEvent(eventData))
+
+	// Debounced updates run synchronously once the window has passed
+	clk.Advance(defaultDebounceWindow)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, []uuid.UUID{productID}, cache.Invalidated())
+
+	// Warming disabled - rating must not be written
+	_, warmed := cache.Rating(productID)
+	assert.False(t, warmed)
+}
+
+func TestRatingWorker_CacheInvalidationFailureIsNonFatal(t *testing.T) {
+	cache := &fakeProductCache{err: assert.AnError}
+	worker, mock, sqlxDB, clk := newTestWorker(t, cache, true)
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	productID := uuid.New()
+
+	// Only one UPDATE expected: a cache failure must not trigger a DB retry
+	expectRatingUpdate(mock, productID, 4.5)
+
+	eventData, _ := json.Marshal(ReviewEvent{
+		Type:      "review.created",
+		ProductID: productID,
+		Timestamp: time.Now(),
+	})
+	assert.NoError(t, worker.HandleEvent(eventData))
+
+	// Debounced updates run synchronously once the window has passed
+	clk.Advance(defaultDebounceWindow)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Len(t, cache.Invalidated(), 1)
+
+	// Failed invalidation skips warming
+	_, warmed := cache.Rating(productID)
+	assert.False(t, warmed)
+}
+
+func TestRatingWorker_WarmsRatingCache(t *testing.T) {
+	cache := &fakeProductCache{}
+	worker, mock, sqlxDB, clk := newTestWorker(t, cache, true)
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	productID := uuid.New()
+	expectRatingUpdate(mock, productID, 3.7)
+
+	eventData, _ := json.Marshal(ReviewEvent{
+		Type:      "review.created",
+		ProductID: productID,
+		Timestamp: time.Now(),
+	})
+	assert.NoError(t, worker.HandleEvent(eventData))
+
+	// Debounced updates run synchronously once the window has passed
+	clk.Advance(defaultDebounceWindow)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+	rating, warmed := cache.Rating(productID)
+	assert.True(t, warmed)
+	assert.Equal(t, 3.7, rating)
+}
+
+func TestRatingWorker_ShutdownDuringRetryBackoffReturnsPromptly(t *testing.T) {
+	worker, mock, sqlxDB, clk := setupTestWorker(t)
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	productID := uuid.New()
+	expectRatingUpdateError(mock, productID, assert.AnError)
+
+	eventData, _ := json.Marshal(ReviewEvent{
+		Type:      "review.created",
+		ProductID: productID,
+		Timestamp: clk.Now(),
+	})
+	require.NoError(t, worker.HandleEvent(eventData))
+
+	// After the failed first attempt the update sits in its initialBackoff wait
+	go clk.Advance(defaultDebounceWindow)
+	require.Eventually(t, func() bool {
+		return mock.ExpectationsWereMet() == nil
+	}, time.Second, 5*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	require.NoError(t, worker.Shutdown(ctx))
+	assert.Less(t, time.Since(start), initialBackoff/2, "shutdown must not wait out the retry backoff")
+
+	// No retry query may run after shutdown
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// manualClock hands out timers the test fires by hand, to reproduce a timer that has
+// fired but whose callback hasn't run yet
+type manualClock struct {
+	clock.Clock
+	timers []*manualTimer
+}
+
+type manualTimer struct {
+	f     func()
+	fired bool
+}
+
+func (c *manualClock) AfterFunc(d time.Duration, f func()) clock.Timer {
+	timer := &manualTimer{f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+func (t *manualTimer) Stop() bool {
+	return !t.fired
+}
+
+func TestRatingWorker_EventAfterTimerFiredSchedulesNewUpdate(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	require.NoError(t, err)
+	sqlxDB := sqlx.NewDb(db, "sqlmock")
+	defer func() {
+		_ = sqlxDB.Close()
+	}()
+
+	log := logger.New("test")
+	clk := &manualClock{Clock: clock.New()}
+	worker := NewRatingWorker(NewCalculator(sqlxDB, domain.RatingRoundingHalfUp, 0, log), nil, false, clk, log)
+
+	productID := uuid.New()
+	expectRatingUpdate(mock, productID, 4.0)
+	expectRatingUpdate(mock, productID, 4.5)
+
+	send := func(ts time.Time) {
+		eventData, _ := json.Marshal(ReviewEvent{Type: "review.created", ProductID: productID, Timestamp: ts})
+		require.NoError(t, worker.HandleEvent(eventData))
+	}
+
+	now := time.Now()
+	send(now)
+	require.Len(t, clk.timers, 1)
+
+	// The first timer fires, and a second event arrives before its callback takes the lock
+	clk.timers[0].fired = true
+	send(now.Add(time.Second))
+	require.Len(t, clk.timers, 2)
+
+	// The late callback must not drop the newer pending update
+	clk.timers[0].f()
+	assert.Equal(t, 1, worker.GetPendingCount())
+
+	clk.timers[1].fired = true
+	clk.timers[1].f()
+	assert.Equal(t, 0, worker.GetPendingCount())
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// Each callback released its own wait group slot, so shutdown neither hangs nor panics
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, worker.Shutdown(ctx))
 }
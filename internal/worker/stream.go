@@ -1,6 +1,8 @@
 package worker
 
 import (
+	"time"
+
 	"github.com/nats-io/nats.go"
 
 	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
@@ -9,6 +11,6 @@ import (
 
 // NewStreamConfig creates a new stream configuration helper
 // This is a wrapper around events.NewStreamConfig for convenience
-func NewStreamConfig(js nats.JetStreamContext, log *logger.Logger) *events.StreamConfig {
-	return events.NewStreamConfig(js, log)
+func NewStreamConfig(js nats.JetStreamContext, ackWait time.Duration, subjectPrefix string, log *logger.Logger) *events.StreamConfig {
+	return events.NewStreamConfig(js, ackWait, subjectPrefix, log)
 }
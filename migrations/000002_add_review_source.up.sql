@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Track where a review was submitted from
+-- ============================================================================
+-- Lets store owners separate organic web reviews from bulk imports for
+-- analytics and moderation prioritization. Existing rows default to 'web'.
+-- ============================================================================
+
+ALTER TABLE reviews
+    ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'web'
+    CHECK (source IN ('web', 'mobile', 'import', 'api'));
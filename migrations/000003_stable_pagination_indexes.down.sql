@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_reviews_product_created_id;
+CREATE INDEX IF NOT EXISTS idx_reviews_product_deleted_created
+ON reviews(product_id, deleted_at, created_at DESC)
+WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_products_active_created_id;
+CREATE INDEX IF NOT EXISTS idx_products_deleted_at_created_at
+ON products(deleted_at, created_at DESC)
+WHERE deleted_at IS NULL;
@@ -0,0 +1,18 @@
+-- ============================================================================
+-- Stable pagination ordering
+-- ============================================================================
+-- List queries now order by (created_at DESC, id DESC) so rows sharing a
+-- created_at (bulk inserts) keep a deterministic order across pages.
+-- Rebuild the list indexes with id as the tie-breaker so the sort stays
+-- index-backed.
+-- ============================================================================
+
+DROP INDEX IF EXISTS idx_products_deleted_at_created_at;
+CREATE INDEX IF NOT EXISTS idx_products_active_created_id
+ON products(created_at DESC, id DESC)
+WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_reviews_product_deleted_created;
+CREATE INDEX IF NOT EXISTS idx_reviews_product_created_id
+ON reviews(product_id, created_at DESC, id DESC)
+WHERE deleted_at IS NULL;
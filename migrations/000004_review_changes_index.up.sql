@@ -0,0 +1,15 @@
+-- ============================================================================
+-- Review changes feed
+-- ============================================================================
+-- GET /api/v1/reviews/changes pages through all reviews, including
+-- soft-deleted ones, by (updated_at, id). Soft deletes now bump updated_at
+-- so they show up in the feed; rows deleted before this migration keep
+-- their old updated_at, so backfill it from deleted_at.
+-- ============================================================================
+
+UPDATE reviews
+SET updated_at = deleted_at
+WHERE deleted_at IS NOT NULL AND deleted_at > updated_at;
+
+CREATE INDEX IF NOT EXISTS idx_reviews_updated_at_id
+ON reviews(updated_at, id);
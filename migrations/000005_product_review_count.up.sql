@@ -0,0 +1,19 @@
+-- ============================================================================
+-- Derived review count on products
+-- ============================================================================
+-- review_count sits next to average_rating as derived state maintained by
+-- the rating worker. Like average_rating it never changes products.version,
+-- which is reserved for optimistic locking of user-editable fields.
+-- ============================================================================
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
+
+UPDATE products p
+SET review_count = counts.review_count
+FROM (
+    SELECT product_id, COUNT(*) AS review_count
+    FROM reviews
+    WHERE deleted_at IS NULL
+    GROUP BY product_id
+) counts
+WHERE p.id = counts.product_id;
@@ -0,0 +1,21 @@
+-- ============================================================================
+-- Audit trail for product and review mutations
+-- ============================================================================
+-- Rows are written by the API in the same transaction as the change they
+-- describe. entity_id has no foreign key: entries must outlive the entity
+-- and cover both products and reviews.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor VARCHAR(255) NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    entity_type VARCHAR(50) NOT NULL,
+    entity_id UUID NOT NULL,
+    before_snapshot JSONB,
+    after_snapshot JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Serves GET /api/v1/admin/audit?entity_id=, newest first
+CREATE INDEX IF NOT EXISTS idx_audit_log_entity ON audit_log(entity_id, created_at DESC, id DESC);
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Recently reviewed feed
+-- ============================================================================
+-- GET /api/v1/reviews/recent lists the newest reviews across all products by
+-- (created_at DESC, id DESC). idx_reviews_product_created_id leads with
+-- product_id, so it can't serve a cross-product sort; add a matching index so
+-- the query reads the first rows instead of sorting every live review.
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_reviews_created_id
+ON reviews(created_at DESC, id DESC)
+WHERE deleted_at IS NULL;
@@ -0,0 +1,7 @@
+-- Fails if ratings above 5 were stored while MAX_RATING was raised
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_average_rating_check;
+ALTER TABLE products ALTER COLUMN average_rating TYPE DECIMAL(2, 1);
+ALTER TABLE products ADD CONSTRAINT products_average_rating_check CHECK (average_rating >= 0 AND average_rating <= 5);
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_rating_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_rating_check CHECK (rating >= 1 AND rating <= 5);
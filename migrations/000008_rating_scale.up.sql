@@ -0,0 +1,15 @@
+-- ============================================================================
+-- Configurable rating scale
+-- ============================================================================
+-- MAX_RATING lets a deployment rate on a scale other than 1-5, up to 1-10.
+-- The application validates against the configured scale; the database only
+-- enforces the widest scale it may be configured to. average_rating needs a
+-- third digit to hold 10.0.
+-- ============================================================================
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_rating_check;
+ALTER TABLE reviews ADD CONSTRAINT reviews_rating_check CHECK (rating >= 1 AND rating <= 10);
+
+ALTER TABLE products DROP CONSTRAINT IF EXISTS products_average_rating_check;
+ALTER TABLE products ALTER COLUMN average_rating TYPE DECIMAL(3, 1);
+ALTER TABLE products ADD CONSTRAINT products_average_rating_check CHECK (average_rating >= 0 AND average_rating <= 10);
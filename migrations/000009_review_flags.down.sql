@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_reviews_flag_count;
+ALTER TABLE reviews DROP COLUMN IF EXISTS flag_count;
+DROP TABLE IF EXISTS review_flags;
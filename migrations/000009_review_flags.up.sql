@@ -0,0 +1,26 @@
+-- ============================================================================
+-- Review flags
+-- ============================================================================
+-- Shoppers flag reviews they think break the rules. Each flag is kept in
+-- review_flags with its reason, and reviews.flag_count holds the running total
+-- so the moderation queue can be sorted without aggregating every flag.
+-- A client IP may flag a review once; flags without an IP are not deduplicated
+-- (NULLs never conflict in a unique constraint). Flags go with their review
+-- when it is purged.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS review_flags (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    client_ip TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT review_flags_review_client_ip_key UNIQUE (review_id, client_ip)
+);
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS flag_count INTEGER NOT NULL DEFAULT 0;
+
+-- Backs the moderation queue: most flagged live reviews first
+CREATE INDEX IF NOT EXISTS idx_reviews_flag_count
+ON reviews(flag_count DESC, id)
+WHERE deleted_at IS NULL AND flag_count > 0;
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS reviews_stats_update ON reviews;
+DROP TRIGGER IF EXISTS reviews_stats_insert_delete ON reviews;
+DROP FUNCTION IF EXISTS maintain_product_review_stats();
+DROP TABLE IF EXISTS product_review_stats;
@@ -0,0 +1,74 @@
+-- ============================================================================
+-- Incremental review statistics
+-- ============================================================================
+-- product_review_stats keeps a running sum and count of each product's live
+-- ratings, so the rating worker derives average_rating as sum / count instead
+-- of aggregating every review on each event.
+--
+-- The totals are maintained by a trigger on reviews rather than by each
+-- repository method: reviews are written from several places (create, edit,
+-- soft delete, product cascade delete, import) and the trigger runs in the
+-- writer's transaction, so a rolled-back write never touches the totals.
+-- Only live rows count: soft-deleting a review subtracts it, and hard-deleting
+-- an already soft-deleted row (the purger) leaves the totals alone.
+--
+-- Writers to one product serialize on its stats row until they commit. If the
+-- totals ever drift, Calculator.Reconcile rebuilds them from reviews.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS product_review_stats (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    sum_ratings BIGINT NOT NULL DEFAULT 0,
+    count_ratings INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE OR REPLACE FUNCTION maintain_product_review_stats() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') AND OLD.deleted_at IS NULL THEN
+        UPDATE product_review_stats
+        SET sum_ratings = sum_ratings - OLD.rating,
+            count_ratings = count_ratings - 1,
+            updated_at = NOW()
+        WHERE product_id = OLD.product_id;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        INSERT INTO product_review_stats (product_id, sum_ratings, count_ratings)
+        VALUES (NEW.product_id, NEW.rating, 1)
+        ON CONFLICT (product_id) DO UPDATE
+        SET sum_ratings = product_review_stats.sum_ratings + EXCLUDED.sum_ratings,
+            count_ratings = product_review_stats.count_ratings + 1,
+            updated_at = NOW();
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS reviews_stats_insert_delete ON reviews;
+CREATE TRIGGER reviews_stats_insert_delete
+AFTER INSERT OR DELETE ON reviews
+FOR EACH ROW EXECUTE FUNCTION maintain_product_review_stats();
+
+-- Name, text and flag edits don't move the totals, so skip them
+DROP TRIGGER IF EXISTS reviews_stats_update ON reviews;
+CREATE TRIGGER reviews_stats_update
+AFTER UPDATE OF rating, deleted_at, product_id ON reviews
+FOR EACH ROW
+WHEN (OLD.rating IS DISTINCT FROM NEW.rating
+   OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at
+   OR OLD.product_id IS DISTINCT FROM NEW.product_id)
+EXECUTE FUNCTION maintain_product_review_stats();
+
+-- Backfill every product, including those without reviews, so the rating
+-- worker always finds a row
+INSERT INTO product_review_stats (product_id, sum_ratings, count_ratings)
+SELECT p.id, COALESCE(SUM(r.rating), 0), COUNT(r.id)
+FROM products p
+LEFT JOIN reviews r ON r.product_id = p.id AND r.deleted_at IS NULL
+GROUP BY p.id
+ON CONFLICT (product_id) DO UPDATE
+SET sum_ratings = EXCLUDED.sum_ratings,
+    count_ratings = EXCLUDED.count_ratings,
+    updated_at = NOW();
@@ -0,0 +1,31 @@
+-- Half-star ratings are rounded to whole stars (4.5 -> 5), and the stats are rebuilt to match
+DROP TRIGGER IF EXISTS reviews_stats_update ON reviews;
+DROP TRIGGER IF EXISTS reviews_stats_insert_delete ON reviews;
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_rating_check;
+ALTER TABLE reviews ALTER COLUMN rating TYPE INTEGER USING rating::integer;
+ALTER TABLE reviews ADD CONSTRAINT reviews_rating_check CHECK (rating >= 1 AND rating <= 10);
+
+ALTER TABLE product_review_stats ALTER COLUMN sum_ratings TYPE BIGINT USING sum_ratings::bigint;
+
+UPDATE product_review_stats s
+SET sum_ratings = COALESCE(totals.sum_ratings, 0), updated_at = NOW()
+FROM (
+    SELECT p.id, SUM(r.rating) AS sum_ratings
+    FROM products p
+    LEFT JOIN reviews r ON r.product_id = p.id AND r.deleted_at IS NULL
+    GROUP BY p.id
+) totals
+WHERE s.product_id = totals.id;
+
+CREATE TRIGGER reviews_stats_insert_delete
+AFTER INSERT OR DELETE ON reviews
+FOR EACH ROW EXECUTE FUNCTION maintain_product_review_stats();
+
+CREATE TRIGGER reviews_stats_update
+AFTER UPDATE OF rating, deleted_at, product_id ON reviews
+FOR EACH ROW
+WHEN (OLD.rating IS DISTINCT FROM NEW.rating
+   OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at
+   OR OLD.product_id IS DISTINCT FROM NEW.product_id)
+EXECUTE FUNCTION maintain_product_review_stats();
@@ -0,0 +1,34 @@
+-- ============================================================================
+-- Half-star ratings
+-- ============================================================================
+-- HALF_STAR_RATINGS lets reviewers rate in steps of 0.5 (1.0, 1.5, ... 5.0).
+-- As with MAX_RATING, the application validates against the configured mode
+-- and the database only enforces the widest one: any multiple of 0.5 on the
+-- 1-10 scale. Whole-number ratings are unchanged by the new type.
+--
+-- The stats triggers name reviews.rating, so they are dropped while its type
+-- changes and recreated unchanged afterwards. sum_ratings becomes NUMERIC to
+-- hold half points.
+-- ============================================================================
+
+DROP TRIGGER IF EXISTS reviews_stats_update ON reviews;
+DROP TRIGGER IF EXISTS reviews_stats_insert_delete ON reviews;
+
+ALTER TABLE reviews DROP CONSTRAINT IF EXISTS reviews_rating_check;
+ALTER TABLE reviews ALTER COLUMN rating TYPE NUMERIC(3, 1);
+ALTER TABLE reviews ADD CONSTRAINT reviews_rating_check
+    CHECK (rating >= 1 AND rating <= 10 AND rating * 2 = TRUNC(rating * 2));
+
+ALTER TABLE product_review_stats ALTER COLUMN sum_ratings TYPE NUMERIC(14, 1);
+
+CREATE TRIGGER reviews_stats_insert_delete
+AFTER INSERT OR DELETE ON reviews
+FOR EACH ROW EXECUTE FUNCTION maintain_product_review_stats();
+
+CREATE TRIGGER reviews_stats_update
+AFTER UPDATE OF rating, deleted_at, product_id ON reviews
+FOR EACH ROW
+WHEN (OLD.rating IS DISTINCT FROM NEW.rating
+   OR OLD.deleted_at IS DISTINCT FROM NEW.deleted_at
+   OR OLD.product_id IS DISTINCT FROM NEW.product_id)
+EXECUTE FUNCTION maintain_product_review_stats();
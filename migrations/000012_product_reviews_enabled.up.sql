@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Per-product reviews switch
+-- ============================================================================
+-- reviews_enabled = false freezes a product's reviews: new reviews are
+-- rejected, existing ones stay listed and keep counting toward the rating.
+-- The review INSERT checks it in the same statement that checks the product
+-- is live, so a concurrent toggle can't let one slip through.
+-- ============================================================================
+
+ALTER TABLE products ADD COLUMN IF NOT EXISTS reviews_enabled BOOLEAN NOT NULL DEFAULT TRUE;
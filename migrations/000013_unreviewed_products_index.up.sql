@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Unreviewed products listing
+-- ============================================================================
+-- GET /api/v1/admin/products/unreviewed pages through live products with
+-- review_count = 0, newest first. A partial index keeps that index-backed
+-- without scanning past every reviewed product; it shrinks as products
+-- collect reviews.
+-- ============================================================================
+
+CREATE INDEX IF NOT EXISTS idx_products_unreviewed_created_id
+ON products(created_at DESC, id DESC)
+WHERE deleted_at IS NULL AND review_count = 0;
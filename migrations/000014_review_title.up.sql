@@ -0,0 +1,8 @@
+-- ============================================================================
+-- Optional review title
+-- ============================================================================
+-- A headline shown above the review text. Nullable so existing reviews and
+-- clients that don't send one are unaffected.
+-- ============================================================================
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS title VARCHAR(200);
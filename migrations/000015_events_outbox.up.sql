@@ -0,0 +1,22 @@
+-- ============================================================================
+-- Transactional outbox for review events
+-- ============================================================================
+-- With EVENT_OUTBOX=true the API inserts each event here in the same
+-- transaction as the review change it describes, and a relay publishes
+-- pending rows and stamps sent_at. An event is therefore committed exactly
+-- when its change is, and survives a crash before it is published.
+-- ============================================================================
+
+CREATE TABLE IF NOT EXISTS events_outbox (
+    id BIGSERIAL PRIMARY KEY,
+    subject VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP
+);
+
+-- The relay reads pending rows in insertion order; sent rows stay out of the index
+CREATE INDEX IF NOT EXISTS idx_events_outbox_pending ON events_outbox(id) WHERE sent_at IS NULL;
+
+-- Serves the cleanup of sent rows past EVENT_OUTBOX_RETENTION
+CREATE INDEX IF NOT EXISTS idx_events_outbox_sent_at ON events_outbox(sent_at) WHERE sent_at IS NOT NULL;
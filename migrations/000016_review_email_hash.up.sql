@@ -0,0 +1,10 @@
+-- ============================================================================
+-- Reviewer email hash for Gravatar avatars
+-- ============================================================================
+-- The API accepts an optional reviewer email but stores only its Gravatar hash
+-- (hex MD5 of the trimmed, lowercased address), so review UIs can show avatars
+-- without the raw address being kept. Nullable: existing reviews and reviewers
+-- who give no email have no avatar.
+-- ============================================================================
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS email_hash CHAR(32);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_reviews_product_language_created_id;
+ALTER TABLE reviews DROP COLUMN IF EXISTS language;
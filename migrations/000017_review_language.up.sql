@@ -0,0 +1,16 @@
+-- ============================================================================
+-- Review language
+-- ============================================================================
+-- The ISO 639-1 code of the language a review is written in, given by the
+-- client or detected from the text when DETECT_REVIEW_LANGUAGE is on.
+-- Nullable: existing reviews, and reviews whose language couldn't be
+-- detected, have none and only show up in the unfiltered list.
+-- The index keeps ?language= on the reviews list index-backed for the
+-- default newest-first order.
+-- ============================================================================
+
+ALTER TABLE reviews ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+
+CREATE INDEX IF NOT EXISTS idx_reviews_product_language_created_id
+ON reviews(product_id, language, created_at DESC, id DESC)
+WHERE deleted_at IS NULL AND language IS NOT NULL;
@@ -0,0 +1,12 @@
+-- ============================================================================
+-- Outbox claim leases
+-- ============================================================================
+-- The relay used to hold FOR UPDATE locks on a batch while it published it,
+-- keeping a transaction open across the NATS round trips. It now claims a
+-- batch by stamping claimed_until in a statement of its own, publishes with
+-- no transaction open, and stamps sent_at afterwards. Other relays skip rows
+-- whose claim hasn't expired, so an event is only published again when the
+-- relay that claimed it died or ran past the lease.
+-- ============================================================================
+
+ALTER TABLE events_outbox ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMP;
@@ -0,0 +1,17 @@
+#!/bin/bash
+
+# Show who changed a product or review, newest first
+# Usage: ./scripts/admin/audit.sh <entity_id> [admin_key]
+# Falls back to the ADMIN_API_KEY environment variable when no key is given
+
+BASE_URL="http://localhost:8080/api/v1"
+
+ENTITY_ID=$1
+ADMIN_KEY=${2:-$ADMIN_API_KEY}
+
+if [ -z "$ENTITY_ID" ] || [ -z "$ADMIN_KEY" ]; then
+    echo "Usage: $0 <entity_id> <admin_key> (or set ADMIN_API_KEY)" >&2
+    exit 1
+fi
+
+curl -s -H "X-Admin-Key: $ADMIN_KEY" "$BASE_URL/admin/audit?entity_id=$ENTITY_ID" | jq .
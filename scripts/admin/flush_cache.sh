@@ -0,0 +1,16 @@
+#!/bin/bash
+
+# Remove every product and review cache entry (e.g. after a bulk import)
+# Usage: ./scripts/admin/flush_cache.sh [admin_key]
+# Falls back to the ADMIN_API_KEY environment variable when no key is given
+
+BASE_URL="http://localhost:8080/api/v1"
+
+ADMIN_KEY=${1:-$ADMIN_API_KEY}
+
+if [ -z "$ADMIN_KEY" ]; then
+    echo "Usage: $0 <admin_key> (or set ADMIN_API_KEY)" >&2
+    exit 1
+fi
+
+curl -s -X POST -H "X-Admin-Key: $ADMIN_KEY" "$BASE_URL/admin/cache/flush" | jq .
@@ -0,0 +1,16 @@
+#!/bin/bash
+
+# Get per-dependency health and rating-worker event lag
+# Usage: ./scripts/admin/health_detailed.sh [admin_key]
+# Falls back to the ADMIN_API_KEY environment variable when no key is given
+
+BASE_URL="http://localhost:8080/api/v1"
+
+ADMIN_KEY=${1:-$ADMIN_API_KEY}
+
+if [ -z "$ADMIN_KEY" ]; then
+    echo "Usage: $0 <admin_key> (or set ADMIN_API_KEY)" >&2
+    exit 1
+fi
+
+curl -s -H "X-Admin-Key: $ADMIN_KEY" "$BASE_URL/admin/health/detailed" | jq .
@@ -0,0 +1,23 @@
+#!/bin/bash
+
+# Bulk import reviews for one product from a JSON array file
+# Usage: ./scripts/admin/import_reviews.sh <product_id> <reviews.json> [admin_key]
+# Each array item takes first_name, last_name, review_text, rating and optional source
+# Falls back to the ADMIN_API_KEY environment variable when no key is given
+
+BASE_URL="http://localhost:8080/api/v1"
+
+PRODUCT_ID=$1
+REVIEWS_FILE=$2
+ADMIN_KEY=${3:-$ADMIN_API_KEY}
+
+if [ -z "$PRODUCT_ID" ] || [ ! -f "$REVIEWS_FILE" ] || [ -z "$ADMIN_KEY" ]; then
+    echo "Usage: $0 <product_id> <reviews.json> <admin_key> (or set ADMIN_API_KEY)" >&2
+    exit 1
+fi
+
+curl -s -X POST \
+    -H "Content-Type: application/json" \
+    -H "X-Admin-Key: $ADMIN_KEY" \
+    --data-binary "@$REVIEWS_FILE" \
+    "$BASE_URL/products/$PRODUCT_ID/reviews/import" | jq .
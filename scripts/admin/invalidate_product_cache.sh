@@ -0,0 +1,17 @@
+#!/bin/bash
+
+# Remove one product's cached rating, review pages and review overview
+# Usage: ./scripts/admin/invalidate_product_cache.sh <product_id> [admin_key]
+# Falls back to the ADMIN_API_KEY environment variable when no key is given
+
+BASE_URL="http://localhost:8080/api/v1"
+
+PRODUCT_ID=$1
+ADMIN_KEY=${2:-$ADMIN_API_KEY}
+
+if [ -z "$PRODUCT_ID" ] || [ -z "$ADMIN_KEY" ]; then
+    echo "Usage: $0 <product_id> <admin_key> (or set ADMIN_API_KEY)" >&2
+    exit 1
+fi
+
+curl -s -o /dev/null -w "%{http_code}\n" -X DELETE -H "X-Admin-Key: $ADMIN_KEY" "$BASE_URL/admin/cache/products/$PRODUCT_ID"
@@ -0,0 +1,16 @@
+#!/bin/bash
+
+# Get JetStream stream and consumer statistics
+# Usage: ./scripts/admin/stream_info.sh [admin_key]
+# Falls back to the ADMIN_API_KEY environment variable when no key is given
+
+BASE_URL="http://localhost:8080/api/v1"
+
+ADMIN_KEY=${1:-$ADMIN_API_KEY}
+
+if [ -z "$ADMIN_KEY" ]; then
+    echo "Usage: $0 <admin_key> (or set ADMIN_API_KEY)" >&2
+    exit 1
+fi
+
+curl -s -H "X-Admin-Key: $ADMIN_KEY" "$BASE_URL/admin/stream-info" | jq .
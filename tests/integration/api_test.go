@@ -5,6 +5,7 @@ package integration
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -15,10 +16,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
-	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/client"
 	"github.com/Pesokrava/product_reviewer/internal/delivery/events"
 	httpDelivery "github.com/Pesokrava/product_reviewer/internal/delivery/http"
 	"github.com/Pesokrava/product_reviewer/internal/delivery/http/handler"
+	"github.com/Pesokrava/product_reviewer/internal/delivery/http/request"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/cache"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
@@ -26,92 +28,104 @@ import (
 	"github.com/Pesokrava/product_reviewer/internal/repository/postgres"
 	"github.com/Pesokrava/product_reviewer/internal/usecase/product"
 	"github.com/Pesokrava/product_reviewer/internal/usecase/review"
+	"github.com/Pesokrava/product_reviewer/internal/worker"
 )
 
 func setupTestServer(t *testing.T) http.Handler {
-	// Load config
-	cfg, err := config.Load()
-	require.NoError(t, err)
+	cfg := testConfig
 
 	// Setup logger
 	log := logger.New(cfg.Env)
 
 	// Connect to database
-	db, err := database.WaitForDB(cfg, 5, 2*time.Second)
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
 	require.NoError(t, err)
 
 	// Connect to Redis
-	redisClient, err := cache.WaitForRedis(cfg, 5, 2*time.Second)
+	redisClient, err := cache.WaitForRedis(cfg, cfg.Redis.ConnectRetry)
 	require.NoError(t, err)
 
 	// Connect to NATS
-	publisher, err := events.NewPublisher(cfg, log)
+	publisher, err := events.NewPublisher(cfg, nil, log)
 	require.NoError(t, err)
 
 	// Setup repositories
-	productRepo := postgres.NewProductRepository(db)
-	reviewRepo := postgres.NewReviewRepository(db)
+	slowQueries := postgres.NewSlowQueryLogger(cfg.Database.SlowQueryThreshold, log)
+	productRepo := postgres.NewProductRepository(db, slowQueries, nil)
+	reviewRepo := postgres.NewReviewRepository(db, slowQueries, nil)
+	auditRepo := postgres.NewAuditRepository(db, slowQueries)
+	transactor := postgres.NewTransactor(db, cfg.Database.SerializationRetries)
+	// CACHE_CODEC=gob runs the suite, TestPaginationCaching included, against the gob codec
+	cacheCodec, err := cacheRepo.NewCodec(cfg.Cache.Codec)
+	require.NoError(t, err)
 	redisCache := cacheRepo.NewRedisCache(
 		redisClient,
 		cfg.Cache.ProductRatingTTL,
 		cfg.Cache.ReviewsListTTL,
+		cfg.Cache.RecentReviewsTTL,
+		cfg.Cache.TTLJitter,
+		cfg.Cache.BreakerThreshold,
+		cfg.Cache.BreakerCooldown,
+		cacheCodec,
+		cfg.Cache.CompressThreshold,
+		log,
 	)
 
 	// Setup services
-	productService := product.NewService(productRepo, reviewRepo, log)
-	reviewService := review.NewService(reviewRepo, redisCache, publisher, log)
+	productService := product.NewService(productRepo, reviewRepo, transactor, auditRepo, redisCache, false, log)
+	reviewService := review.NewService(reviewRepo, redisCache, publisher, transactor, auditRepo, review.Options{Products: productRepo}, log)
 
 	// Setup handlers
-	productHandler := handler.NewProductHandler(productService, log)
-	reviewHandler := handler.NewReviewHandler(reviewService, log)
+	productHandler := handler.NewProductHandler(productService, request.DefaultPagination, cfg.Product.CompareMaxIDs, cfg.Product.MinReviewsForRating, log)
+	reviewHandler := handler.NewReviewHandler(reviewService, cfg.Review.DefaultSource, request.DefaultPagination, log)
+	detailHandler := handler.NewProductDetailHandler(productService, reviewService, cfg.Product.MinReviewsForRating, log)
+	adminHandler := handler.NewAdminHandler(
+		events.NewStreamConfig(publisher.JetStream(), cfg.NATS.AckWait, cfg.NATS.SubjectPrefix, log),
+		redisCache,
+		auditRepo,
+		worker.NewCalculator(db, cfg.Review.RatingRoundingMode, cfg.Database.SerializationRetries, log),
+		log,
+	)
+	healthHandler := handler.NewHealthHandler(
+		map[string]handler.HealthCheck{"postgres": db.PingContext},
+		nil,
+		cfg.NATS.LagDegradedThreshold,
+		nil,
+		0,
+		log,
+	)
 
 	// Setup router
-	router := httpDelivery.NewRouter(productHandler, reviewHandler, cfg, log)
+	router := httpDelivery.NewRouter(productHandler, reviewHandler, detailHandler, adminHandler, healthHandler, nil, cfg, log)
 	return router.Setup()
 }
 
+// newTestClient serves the test router over HTTP and returns a typed API client for it
+func newTestClient(t *testing.T) *client.Client {
+	server := httptest.NewServer(setupTestServer(t))
+	t.Cleanup(server.Close)
+	return client.NewClient(server.URL)
+}
+
 func TestProductCreateAndGet(t *testing.T) {
-	server := setupTestServer(t)
+	api := newTestClient(t)
+	ctx := context.Background()
 
 	// Create product
-	productJSON := `{
-		"name": "Test Product",
-		"description": "Test Description",
-		"price": 99.99
-	}`
-
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewBufferString(productJSON))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-
-	server.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusCreated, w.Code)
-
-	var createResp map[string]any
-	err := json.NewDecoder(w.Body).Decode(&createResp)
+	description := "Test Description"
+	created, err := api.CreateProduct(ctx, handler.CreateProductRequest{
+		Name:        "Test Product",
+		Description: &description,
+		Price:       99.99,
+	})
 	require.NoError(t, err)
 
-	assert.True(t, createResp["success"].(bool))
-	productData := createResp["data"].(map[string]any)
-	productID := productData["id"].(string)
-
 	// Get product
-	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s", productID), nil)
-	w = httptest.NewRecorder()
-
-	server.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var getResp map[string]any
-	err = json.NewDecoder(w.Body).Decode(&getResp)
+	product, err := api.GetProduct(ctx, created.ID)
 	require.NoError(t, err)
 
-	assert.True(t, getResp["success"].(bool))
-	getData := getResp["data"].(map[string]any)
-	assert.Equal(t, "Test Product", getData["name"])
-	assert.Equal(t, 99.99, getData["price"])
+	assert.Equal(t, "Test Product", product.Name)
+	assert.Equal(t, 99.99, product.Price)
 }
 
 func TestHealthCheck(t *testing.T) {
@@ -132,94 +146,48 @@ func TestHealthCheck(t *testing.T) {
 }
 
 func TestReviewCreateAndList(t *testing.T) {
-	server := setupTestServer(t)
+	api := newTestClient(t)
+	ctx := context.Background()
 
 	// Create a product first
-	productJSON := `{
-		"name": "Review Test Product",
-		"description": "Product for review testing",
-		"price": 149.99
-	}`
-
-	req := httptest.NewRequest(http.MethodPost, "/api/v1/products", bytes.NewBufferString(productJSON))
-	req.Header.Set("Content-Type", "application/json")
-	w := httptest.NewRecorder()
-	server.ServeHTTP(w, req)
-
-	var productResp map[string]any
-	err := json.NewDecoder(w.Body).Decode(&productResp)
+	description := "Product for review testing"
+	product, err := api.CreateProduct(ctx, handler.CreateProductRequest{
+		Name:        "Review Test Product",
+		Description: &description,
+		Price:       149.99,
+	})
 	require.NoError(t, err)
-	productData := productResp["data"].(map[string]any)
-	productID := productData["id"].(string)
 
 	// Create a review
-	reviewJSON := fmt.Sprintf(`{
-		"product_id": "%s",
-		"first_name": "John",
-		"last_name": "Doe",
-		"review_text": "Excellent product!",
-		"rating": 5
-	}`, productID)
-
-	req = httptest.NewRequest(http.MethodPost, "/api/v1/reviews", bytes.NewBufferString(reviewJSON))
-	req.Header.Set("Content-Type", "application/json")
-	w = httptest.NewRecorder()
-	server.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusCreated, w.Code)
-
-	var reviewResp map[string]any
-	err = json.NewDecoder(w.Body).Decode(&reviewResp)
+	review, err := api.CreateReview(ctx, handler.CreateReviewRequest{
+		ProductID:  product.ID.String(),
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Excellent product!",
+		Rating:     5,
+	})
 	require.NoError(t, err)
-	assert.True(t, reviewResp["success"].(bool))
-	reviewData := reviewResp["data"].(map[string]any)
-	reviewID := reviewData["id"].(string)
-	assert.Equal(t, "John", reviewData["first_name"])
-	assert.Equal(t, float64(5), reviewData["rating"])
+	assert.Equal(t, "John D.", review.DisplayName)
+	assert.Equal(t, 5.0, review.Rating)
 
 	// List reviews for the product
-	req = httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/v1/products/%s/reviews?limit=10&offset=0", productID), nil)
-	w = httptest.NewRecorder()
-	server.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var listResp map[string]any
-	err = json.NewDecoder(w.Body).Decode(&listResp)
+	reviews, _, err := api.ListReviews(ctx, product.ID, 10, 0)
 	require.NoError(t, err)
-	assert.True(t, listResp["success"].(bool))
-	reviews := listResp["data"].([]any)
 	assert.GreaterOrEqual(t, len(reviews), 1)
 
 	// Update the review (product_id not required in update)
-	updateJSON := `{
-		"first_name": "John",
-		"last_name": "Doe",
-		"review_text": "Updated: Still excellent!",
-		"rating": 4
-	}`
-
-	req = httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/v1/reviews/%s", reviewID), bytes.NewBufferString(updateJSON))
-	req.Header.Set("Content-Type", "application/json")
-	w = httptest.NewRecorder()
-	server.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusOK, w.Code)
-
-	var updateResp map[string]any
-	err = json.NewDecoder(w.Body).Decode(&updateResp)
+	updated, err := api.UpdateReview(ctx, review.ID, handler.UpdateReviewRequest{
+		FirstName:  "John",
+		LastName:   "Doe",
+		ReviewText: "Updated: Still excellent!",
+		Rating:     4,
+	})
 	require.NoError(t, err)
-	assert.True(t, updateResp["success"].(bool))
-	updatedData := updateResp["data"].(map[string]any)
-	assert.Equal(t, float64(4), updatedData["rating"])
-	assert.Equal(t, "Updated: Still excellent!", updatedData["review_text"])
+	assert.Equal(t, 4.0, updated.Rating)
+	assert.Equal(t, "Updated: Still excellent!", updated.ReviewText)
 
 	// Delete the review
-	req = httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/v1/reviews/%s", reviewID), nil)
-	w = httptest.NewRecorder()
-	server.ServeHTTP(w, req)
-
-	assert.Equal(t, http.StatusNoContent, w.Code)
+	require.NoError(t, api.DeleteReview(ctx, review.ID))
 }
 
 func TestProductRatingUpdate(t *testing.T) {
@@ -0,0 +1,30 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Pesokrava/product_reviewer/internal/config"
+	"github.com/Pesokrava/product_reviewer/internal/testutil"
+)
+
+// testConfig points at the dependencies started by TestMain, shared by every test in the package
+var testConfig *config.Config
+
+func TestMain(m *testing.M) {
+	env, err := testutil.Start(context.Background())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start test environment: %v\n", err)
+		os.Exit(1)
+	}
+	testConfig = env.Config
+
+	code := m.Run()
+	env.Close()
+	os.Exit(code)
+}
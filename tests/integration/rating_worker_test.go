@@ -11,8 +11,11 @@ import (
 
 	"github.com/Pesokrava/product_reviewer/internal/config"
 	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/cache"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/clock"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
 	"github.com/Pesokrava/product_reviewer/internal/pkg/logger"
+	cacheRepo "github.com/Pesokrava/product_reviewer/internal/repository/cache"
 	"github.com/Pesokrava/product_reviewer/internal/repository/postgres"
 	"github.com/Pesokrava/product_reviewer/internal/worker"
 	"github.com/google/uuid"
@@ -25,16 +28,33 @@ func strPtr(s string) *string {
 	return &s
 }
 
-func TestRatingWorker_EndToEnd(t *testing.T) {
-	// Load config
-	cfg, err := config.Load()
+func newTestRedisCache(t *testing.T, cfg *config.Config) *cacheRepo.RedisCache {
+	redisClient, err := cache.WaitForRedis(cfg, cfg.Redis.ConnectRetry)
 	require.NoError(t, err)
+	t.Cleanup(func() { _ = redisClient.Close() })
+
+	return cacheRepo.NewRedisCache(
+		redisClient,
+		cfg.Cache.ProductRatingTTL,
+		cfg.Cache.ReviewsListTTL,
+		cfg.Cache.RecentReviewsTTL,
+		cfg.Cache.TTLJitter,
+		cfg.Cache.BreakerThreshold,
+		cfg.Cache.BreakerCooldown,
+		nil,
+		cfg.Cache.CompressThreshold,
+		logger.New("test"),
+	)
+}
+
+func TestRatingWorker_EndToEnd(t *testing.T) {
+	cfg := testConfig
 
 	// Setup logger
 	log := logger.New(cfg.Env)
 
 	// Connect to database
-	db, err := database.WaitForDB(cfg, 5, 2*time.Second)
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
 	require.NoError(t, err)
 	defer db.Close()
 
@@ -44,8 +64,8 @@ func TestRatingWorker_EndToEnd(t *testing.T) {
 	defer nc.Close()
 
 	// Create calculator and worker
-	calculator := worker.NewCalculator(db, log)
-	ratingWorker := worker.NewRatingWorker(calculator, log)
+	calculator := worker.NewCalculator(db, cfg.Review.RatingRoundingMode, cfg.Database.SerializationRetries, log)
+	ratingWorker := worker.NewRatingWorker(calculator, newTestRedisCache(t, cfg), cfg.Worker.WarmRatingCache, clock.New(), log)
 
 	// Subscribe to review events
 	_, err = nc.Subscribe("reviews.events", func(msg *nats.Msg) {
@@ -54,20 +74,23 @@ func TestRatingWorker_EndToEnd(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create repositories
-	productRepo := postgres.NewProductRepository(db)
-	reviewRepo := postgres.NewReviewRepository(db)
+	productRepo := postgres.NewProductRepository(db, nil, nil)
+	reviewRepo := postgres.NewReviewRepository(db, nil, nil)
 
 	ctx := context.Background()
 
-	// Create test product
+	// Create test product; the repository keeps a caller-chosen ID
+	productID := uuid.New()
 	product := &domain.Product{
-		ID:          uuid.New(),
-		Name:        "Test Product for Rating Worker",
-		Description: strPtr("Integration test product"),
-		Price:       99.99,
+		ID:             productID,
+		Name:           "Test Product for Rating Worker",
+		Description:    strPtr("Integration test product"),
+		Price:          99.99,
+		ReviewsEnabled: true,
 	}
 	err = productRepo.Create(ctx, product)
 	require.NoError(t, err)
+	require.Equal(t, productID, product.ID)
 
 	// Cleanup function
 	defer func() {
@@ -78,12 +101,13 @@ func TestRatingWorker_EndToEnd(t *testing.T) {
 	}()
 
 	// Create reviews with different ratings
-	ratings := []int{5, 4, 5, 3, 5} // Average should be 4.4
+	ratings := []float64{5, 4, 5, 3, 5} // Average should be 4.4
 	reviewIDs := make([]uuid.UUID, len(ratings))
 
 	for i, rating := range ratings {
+		reviewID := uuid.New()
 		review := &domain.Review{
-			ID:         uuid.New(),
+			ID:         reviewID,
 			ProductID:  product.ID,
 			FirstName:  "Test",
 			LastName:   "User",
@@ -92,6 +116,7 @@ func TestRatingWorker_EndToEnd(t *testing.T) {
 		}
 		err = reviewRepo.Create(ctx, review)
 		require.NoError(t, err)
+		require.Equal(t, reviewID, review.ID)
 		reviewIDs[i] = review.ID
 
 		// Publish event
@@ -122,15 +147,13 @@ func TestRatingWorker_EndToEnd(t *testing.T) {
 }
 
 func TestRatingWorker_Debouncing(t *testing.T) {
-	// Load config
-	cfg, err := config.Load()
-	require.NoError(t, err)
+	cfg := testConfig
 
 	// Setup logger
 	log := logger.New(cfg.Env)
 
 	// Connect to database
-	db, err := database.WaitForDB(cfg, 5, 2*time.Second)
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
 	require.NoError(t, err)
 	defer db.Close()
 
@@ -140,8 +163,8 @@ func TestRatingWorker_Debouncing(t *testing.T) {
 	defer nc.Close()
 
 	// Create calculator and worker
-	calculator := worker.NewCalculator(db, log)
-	ratingWorker := worker.NewRatingWorker(calculator, log)
+	calculator := worker.NewCalculator(db, cfg.Review.RatingRoundingMode, cfg.Database.SerializationRetries, log)
+	ratingWorker := worker.NewRatingWorker(calculator, newTestRedisCache(t, cfg), cfg.Worker.WarmRatingCache, clock.New(), log)
 
 	// Subscribe to review events
 	_, err = nc.Subscribe("reviews.events", func(msg *nats.Msg) {
@@ -150,17 +173,18 @@ func TestRatingWorker_Debouncing(t *testing.T) {
 	require.NoError(t, err)
 
 	// Create repositories
-	productRepo := postgres.NewProductRepository(db)
-	reviewRepo := postgres.NewReviewRepository(db)
+	productRepo := postgres.NewProductRepository(db, nil, nil)
+	reviewRepo := postgres.NewReviewRepository(db, nil, nil)
 
 	ctx := context.Background()
 
 	// Create test product
 	product := &domain.Product{
-		ID:          uuid.New(),
-		Name:        "Popular Product",
-		Description: strPtr("High traffic product"),
-		Price:       49.99,
+		ID:             uuid.New(),
+		Name:           "Popular Product",
+		Description:    strPtr("High traffic product"),
+		Price:          49.99,
+		ReviewsEnabled: true,
 	}
 	err = productRepo.Create(ctx, product)
 	require.NoError(t, err)
@@ -182,7 +206,7 @@ func TestRatingWorker_Debouncing(t *testing.T) {
 			FirstName:  "Rapid",
 			LastName:   "User",
 			ReviewText: "Quick review",
-			Rating:     (i % 5) + 1, // Cycle through 1-5
+			Rating:     float64((i % 5) + 1), // Cycle through 1-5
 		}
 		err = reviewRepo.Create(ctx, review)
 		require.NoError(t, err)
@@ -0,0 +1,61 @@
+//go:build integration
+// +build integration
+
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Pesokrava/product_reviewer/internal/domain"
+	"github.com/Pesokrava/product_reviewer/internal/pkg/database"
+	"github.com/Pesokrava/product_reviewer/internal/repository/postgres"
+)
+
+// WithinTx runs at READ COMMITTED: a write that lands between the transaction's read and its
+// own write, like the rating calculator's, makes the write wait and apply on top of it rather
+// than fail the transaction, so an edit isn't bounced or rerun for an unrelated change
+func TestTransactor_WaitsForConcurrentWrite(t *testing.T) {
+	cfg := testConfig
+
+	db, err := database.WaitForDB(cfg, cfg.Database.ConnectRetry)
+	require.NoError(t, err)
+	defer db.Close()
+
+	productRepo := postgres.NewProductRepository(db, nil, nil)
+	transactor := postgres.NewTransactor(db, 3)
+	ctx := context.Background()
+
+	product := &domain.Product{Name: "Transactor Product", Price: 10, ReviewsEnabled: true}
+	require.NoError(t, productRepo.Create(ctx, product))
+	defer func() { _ = productRepo.Delete(ctx, product.ID) }()
+
+	attempts := 0
+	err = transactor.WithinTx(ctx, func(ctx context.Context) error {
+		attempts++
+		current, err := productRepo.GetByID(ctx, product.ID)
+		if err != nil {
+			return err
+		}
+
+		// The calculator stores a new rating between this transaction's read and its write
+		_, err = db.ExecContext(context.Background(),
+			`UPDATE products SET average_rating = 4.5, review_count = 2 WHERE id = $1`, product.ID)
+		require.NoError(t, err)
+
+		current.Name = "Transactor Product (renamed)"
+		return productRepo.Update(ctx, current)
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, attempts, "a concurrent write must not fail the transaction")
+
+	stored, err := productRepo.GetByID(ctx, product.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Transactor Product (renamed)", stored.Name)
+	assert.Equal(t, 4.5, stored.AverageRating, "the edit must not overwrite the concurrent rating")
+	assert.Equal(t, 2, stored.ReviewCount)
+}